@@ -13,6 +13,8 @@ type Challenge struct {
 	Validated timeString    `json:"validated,omitempty"`
 	Token     string        `json:"token"`
 	Error     *Error        `json:"error,omitempty"`
+	// dns-persist-01 only
+	IssuerDomainNames []string `json:"issuer-domain-names,omitempty"`
 }
 
 // Account response decoder
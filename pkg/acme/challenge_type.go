@@ -17,6 +17,10 @@ const (
 
 	ChallengeTypeHttp01 ChallengeType = "http-01"
 	ChallengeTypeDns01  ChallengeType = "dns-01"
+
+	// dns-persist-01 (draft-ietf-acme-dns-persist) uses a persistent record
+	// instead of a per token record (see DnsPersistRecord)
+	ChallengeTypeDnsPersist01 ChallengeType = "dns-persist-01"
 )
 
 // ValidationResource creates the resource name and content that are required
@@ -144,3 +144,9 @@ func (service *Service) TosUrl() string {
 func (service *Service) RequiresEAB() bool {
 	return service.dir.Meta.ExternalAccountRequired
 }
+
+// CaaIdentities returns the CAA identities (issuer domain names) of the ACME
+// server, if the server specifies any
+func (service *Service) CaaIdentities() []string {
+	return service.dir.Meta.CaaIdentities
+}
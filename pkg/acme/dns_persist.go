@@ -0,0 +1,32 @@
+package acme
+
+import (
+	"errors"
+	"strings"
+)
+
+var errDnsPersistMissingInfo = errors.New("dns-persist-01 record requires a domain, issuer domain name, and account uri")
+
+// dnsPersistLabel is prepended to the identifier to make the dns-persist-01 record name
+const dnsPersistLabel = "_validation-persist."
+
+// DnsPersistRecord returns the name and value of the persistent TXT record that
+// authorizes the account (accountUri) to validate the domain with the issuer
+// (issuerDomainName) using the dns-persist-01 challenge. The same record is
+// reused by every order, so it only needs to be created once. If wildcard is
+// true, the record also authorizes wildcard names of the domain.
+func DnsPersistRecord(domain string, issuerDomainName string, accountUri string, wildcard bool) (name string, value string, err error) {
+	if domain == "" || issuerDomainName == "" || accountUri == "" {
+		return "", "", errDnsPersistMissingInfo
+	}
+
+	// the record is at the base domain, even for a wildcard
+	name = dnsPersistLabel + strings.TrimPrefix(domain, "*.")
+
+	value = strings.ToLower(issuerDomainName) + "; accounturi=" + accountUri
+	if wildcard {
+		value += "; policy=wildcard"
+	}
+
+	return name, value, nil
+}
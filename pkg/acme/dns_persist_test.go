@@ -0,0 +1,53 @@
+package acme
+
+import "testing"
+
+const testAccountUri = "https://acme.example.com/acme/acct/12345"
+
+func TestDnsPersistRecord(t *testing.T) {
+	tests := []struct {
+		domain   string
+		issuer   string
+		wildcard bool
+		name     string
+		value    string
+	}{
+		{"example.com", "letsencrypt.org", false,
+			"_validation-persist.example.com", "letsencrypt.org; accounturi=" + testAccountUri},
+		{"www.example.com", "LetsEncrypt.org", false,
+			"_validation-persist.www.example.com", "letsencrypt.org; accounturi=" + testAccountUri},
+		// wildcard record is at the base domain and includes the wildcard policy
+		{"example.com", "letsencrypt.org", true,
+			"_validation-persist.example.com", "letsencrypt.org; accounturi=" + testAccountUri + "; policy=wildcard"},
+		{"*.example.com", "letsencrypt.org", true,
+			"_validation-persist.example.com", "letsencrypt.org; accounturi=" + testAccountUri + "; policy=wildcard"},
+	}
+
+	for _, test := range tests {
+		name, value, err := DnsPersistRecord(test.domain, test.issuer, testAccountUri, test.wildcard)
+		if err != nil {
+			t.Fatalf("domain '%s': %s", test.domain, err)
+		}
+		if name != test.name {
+			t.Errorf("domain '%s': name '%s', expected '%s'", test.domain, name, test.name)
+		}
+		if value != test.value {
+			t.Errorf("domain '%s': value '%s', expected '%s'", test.domain, value, test.value)
+		}
+	}
+}
+
+func TestDnsPersistRecord_MissingInfo(t *testing.T) {
+	tests := [][3]string{
+		{"", "letsencrypt.org", testAccountUri},
+		{"example.com", "", testAccountUri},
+		{"example.com", "letsencrypt.org", ""},
+	}
+
+	for _, test := range tests {
+		_, _, err := DnsPersistRecord(test[0], test[1], test[2], false)
+		if err != errDnsPersistMissingInfo {
+			t.Errorf("inputs %v: error '%v', expected '%v'", test, err, errDnsPersistMissingInfo)
+		}
+	}
+}
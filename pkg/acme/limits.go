@@ -0,0 +1,85 @@
+package acme
+
+import (
+	"errors"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+var ErrOrderLimitReached = errors.New("acme server is already processing the maximum number of concurrent orders")
+
+// Limits are the concurrency and request rate limits for an ACME server. A value
+// of 0 means unlimited.
+type Limits struct {
+	MaxConcurrentOrders int
+	RequestsPerSecond   float64
+	RequestBurst        int
+}
+
+// limiter enforces Limits for the service
+type limiter struct {
+	maxOrders    int
+	activeOrders int
+	requests     *rate.Limiter
+	mu           sync.Mutex
+}
+
+// newLimiter creates an unlimited limiter
+func newLimiter() *limiter {
+	return &limiter{
+		requests: rate.NewLimiter(rate.Inf, 0),
+	}
+}
+
+// SetLimits updates the service's limits, orders already running are not stopped
+// if the new concurrent order limit is lower
+func (service *Service) SetLimits(limits Limits) {
+	service.limiter.mu.Lock()
+	defer service.limiter.mu.Unlock()
+
+	service.limiter.maxOrders = limits.MaxConcurrentOrders
+
+	// token bucket (burst is at least 1 so requests can be made)
+	if limits.RequestsPerSecond <= 0 {
+		service.limiter.requests.SetLimit(rate.Inf)
+		service.limiter.requests.SetBurst(0)
+	} else {
+		burst := limits.RequestBurst
+		if burst < 1 {
+			burst = 1
+		}
+		service.limiter.requests.SetLimit(rate.Limit(limits.RequestsPerSecond))
+		service.limiter.requests.SetBurst(burst)
+	}
+}
+
+// AcquireOrderSlot reserves one of the service's concurrent order slots. If all
+// of the slots are in use, ErrOrderLimitReached is returned. release must be
+// called when the order is done being worked.
+func (service *Service) AcquireOrderSlot() (release func(), err error) {
+	service.limiter.mu.Lock()
+	defer service.limiter.mu.Unlock()
+
+	if service.limiter.maxOrders > 0 && service.limiter.activeOrders >= service.limiter.maxOrders {
+		return nil, ErrOrderLimitReached
+	}
+	service.limiter.activeOrders++
+
+	var once sync.Once
+	release = func() {
+		once.Do(func() {
+			service.limiter.mu.Lock()
+			defer service.limiter.mu.Unlock()
+			service.limiter.activeOrders--
+		})
+	}
+
+	return release, nil
+}
+
+// waitForRequest blocks until the request rate limit allows another request to
+// the ACME server (or the service is shutting down)
+func (service *Service) waitForRequest() error {
+	return service.limiter.requests.Wait(service.shutdownContext)
+}
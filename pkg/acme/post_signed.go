@@ -111,7 +111,11 @@ func (service *Service) postToUrlSigned(payload any, url string, accountKey Acco
 		// ACME to post (debugging)
 		service.logger.Debugf(string(messageJson))
 
-		// post to ACME
+		// post to ACME (wait if the request rate limit has been reached)
+		err = service.waitForRequest()
+		if err != nil {
+			return nil, nil, err
+		}
 		response, err = service.httpClient.Post(url, "application/jose+json", bytes.NewBuffer(messageJson))
 		if err != nil {
 			return nil, nil, err
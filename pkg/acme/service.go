@@ -20,11 +20,13 @@ type App interface {
 
 // Acme service struct
 type Service struct {
-	logger       *zap.SugaredLogger
-	httpClient   *httpclient.Client
-	dirUri       string
-	dir          *directory
-	nonceManager *nonces.Manager
+	shutdownContext context.Context
+	logger          *zap.SugaredLogger
+	httpClient      *httpclient.Client
+	dirUri          string
+	dir             *directory
+	nonceManager    *nonces.Manager
+	limiter         *limiter
 }
 
 // NewService creates a new service
@@ -37,9 +39,15 @@ func NewService(app App, dirUri string) (*Service, error) {
 		return nil, errors.New("acme: newservice requires valid logger")
 	}
 
+	// shutdown context
+	service.shutdownContext = app.GetShutdownContext()
+
 	// http client
 	service.httpClient = app.GetHttpClient()
 
+	// limits (unlimited until set)
+	service.limiter = newLimiter()
+
 	// acme directory
 	service.dirUri = dirUri
 	service.dir = new(directory)
@@ -0,0 +1,19 @@
+package certinfo
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+)
+
+var errDecodeCertPem = errors.New("failed to decode certificate pem")
+
+// LeafFromPem parses the first certificate of a pem chain (which is the leaf)
+func LeafFromPem(pemChain string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(pemChain))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errDecodeCertPem
+	}
+
+	return x509.ParseCertificate(block.Bytes)
+}
@@ -0,0 +1,13 @@
+package certinfo
+
+import (
+	"crypto/x509"
+	"fmt"
+)
+
+// SerialString returns the certificate's serial number as lowercase hex (without
+// separators), which is the format used anywhere a serial is output (e.g. in
+// webhook payloads)
+func SerialString(cert *x509.Certificate) string {
+	return fmt.Sprintf("%x", cert.SerialNumber)
+}
@@ -52,3 +52,8 @@ func (service *Service) CheckTXTWithRetry(fqdn string, recordValue string, maxTr
 	service.logger.Error(ErrDnsRecordNotFound)
 	return false, nil
 }
+
+// CheckTXT checks for the specified record once (without retrying)
+func (service *Service) CheckTXT(fqdn string, recordValue string) (exists bool, err error) {
+	return service.checkDnsRecordAllServices(fqdn, recordValue, txtRecord)
+}
@@ -0,0 +1,34 @@
+package dns_checker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// HealthCheck confirms each dns service can be reached (using either its primary or
+// secondary resolver). If the checker is configured to skip checking, there is nothing
+// to check.
+func (service *Service) HealthCheck() error {
+	for i, rPair := range service.dnsResolvers {
+		err := resolverHealthy(rPair.primary)
+		if err != nil && rPair.secondary != nil {
+			err = resolverHealthy(rPair.secondary)
+		}
+		if err != nil {
+			return fmt.Errorf("dns service %d unreachable (%s)", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// resolverHealthy returns an error if the resolver can't resolve a known name
+func resolverHealthy(r *net.Resolver) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeoutSeconds*time.Second)
+	defer cancel()
+
+	_, err := r.LookupIP(ctx, "ip", "google.com")
+	return err
+}
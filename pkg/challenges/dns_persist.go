@@ -0,0 +1,21 @@
+package challenges
+
+import (
+	"errors"
+)
+
+var errDnsCheckerNotRunning = errors.New("dns checker is not running (enable a dns challenge provider)")
+
+// CheckDnsPersistRecord returns true if the dns-persist-01 record (name and value) is
+// currently found by the dns checker
+func (service *Service) CheckDnsPersistRecord(name string, value string) (bool, error) {
+	service.mu.RLock()
+	dnsChecker := service.dnsChecker
+	service.mu.RUnlock()
+
+	if dnsChecker == nil {
+		return false, errDnsCheckerNotRunning
+	}
+
+	return dnsChecker.CheckTXT(name, value)
+}
@@ -0,0 +1,60 @@
+package challenges
+
+import (
+	"legocerthub-backend/pkg/output"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// DeleteProvider deletes a challenge provider from storage and removes the
+// live provider.
+func (service *Service) DeleteProvider(w http.ResponseWriter, r *http.Request) (err error) {
+	// get id from param
+	idParam := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// validation
+	// verify provider exists
+	provider, err := service.getProvider(id)
+	if err != nil {
+		return err
+	}
+
+	// do not allow delete if there are any certificates using the provider's method
+	if service.storage.ChallengeMethodInUse(provider.methodValue()) {
+		service.logger.Warn("cannot delete challenge provider (in use)")
+		return output.ErrDeleteInUse
+	}
+	// end validation
+
+	// delete from storage
+	err = service.storage.DeleteProvider(id)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// remove live provider
+	service.removeProvider(provider.methodValue())
+
+	// return response to client
+	response := output.JsonResponse{
+		Status:  http.StatusOK,
+		Message: "deleted",
+		ID:      id,
+	}
+
+	_, err = service.output.WriteJSON(w, response.Status, response, "response")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package challenges
+
+import (
+	"legocerthub-backend/pkg/output"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// allProvidersResponse provides the json response struct
+// to answer a query for all of the challenge providers
+type allProvidersResponse struct {
+	Providers []providerResponse `json:"challenge_providers"`
+}
+
+// GetAllProviders returns all of the challenge providers in storage
+func (service *Service) GetAllProviders(w http.ResponseWriter, r *http.Request) (err error) {
+	// get providers from storage
+	providers, err := service.storage.GetAllProviders()
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// assemble response
+	response := allProvidersResponse{
+		Providers: []providerResponse{},
+	}
+
+	for i := range providers {
+		providerResp, err := providers[i].response(service)
+		if err != nil {
+			service.logger.Error(err)
+			return output.ErrInternal
+		}
+
+		response.Providers = append(response.Providers, providerResp)
+	}
+
+	// return response to client
+	_, err = service.output.WriteJSON(w, http.StatusOK, response, "all_challenge_providers")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
+
+// GetOneProvider returns a single challenge provider
+func (service *Service) GetOneProvider(w http.ResponseWriter, r *http.Request) (err error) {
+	// params
+	idParam := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// get the provider from storage (and validate id)
+	provider, err := service.getProvider(id)
+	if err != nil {
+		return err
+	}
+
+	// make response
+	providerResp, err := provider.response(service)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrInternal
+	}
+
+	// return response to client
+	_, err = service.output.WriteJSON(w, http.StatusOK, providerResp, "challenge_provider")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package challenges
+
+import (
+	"encoding/json"
+	"legocerthub-backend/pkg/output"
+	"net/http"
+	"time"
+)
+
+// NewProviderPayload is used to post a new challenge provider to LeGo
+type NewProviderPayload struct {
+	Type            *MethodValue    `json:"type"`
+	Name            *string         `json:"name"`
+	Enabled         *bool           `json:"enabled"`
+	Config          json.RawMessage `json:"config"`
+	EncryptedConfig string          `json:"-"`
+	CreatedAt       int             `json:"-"`
+	UpdatedAt       int             `json:"-"`
+}
+
+// PostNewProvider creates a new challenge provider, saves it to storage, and (if
+// enabled) starts the provider
+func (service *Service) PostNewProvider(w http.ResponseWriter, r *http.Request) (err error) {
+	var payload NewProviderPayload
+
+	// decode body into payload
+	err = json.NewDecoder(r.Body).Decode(&payload)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// do validation
+	// type (required)
+	if payload.Type == nil {
+		service.logger.Debug(errProviderTypeBad)
+		return output.ErrValidationFailed
+	}
+	provType, ok := providerTypes[*payload.Type]
+	if !ok {
+		service.logger.Debug(errProviderTypeBad)
+		return output.ErrValidationFailed
+	}
+	// name (if none, set to blank) and type + name must be unique
+	if payload.Name == nil {
+		payload.Name = new(string)
+	}
+	if !providerNameValid(*payload.Name) {
+		service.logger.Debug(errProviderNameBad)
+		return output.ErrValidationFailed
+	}
+	if service.providerExists(*payload.Type, *payload.Name) {
+		service.logger.Debug(errProviderExists)
+		return output.ErrValidationFailed
+	}
+	// enabled (if none, set to false)
+	if payload.Enabled == nil {
+		payload.Enabled = new(bool)
+	}
+	// config (defaults are used for anything not specified)
+	cfg := provType.newConfig()
+	if payload.Config != nil {
+		err = json.Unmarshal(payload.Config, cfg)
+		if err != nil {
+			service.logger.Debug(err)
+			return output.ErrValidationFailed
+		}
+	}
+	// end validation
+
+	// encrypt config
+	payload.EncryptedConfig, err = service.encryptProviderConfig(cfg)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrInternal
+	}
+
+	// if enabled, build the provider to confirm the config works
+	var newProvider providerService
+	if *payload.Enabled {
+		newProvider, err = service.buildProvider(Provider{
+			Type:            *payload.Type,
+			Name:            *payload.Name,
+			Enabled:         *payload.Enabled,
+			EncryptedConfig: payload.EncryptedConfig,
+		})
+		if err != nil {
+			service.logger.Debugf("failed to configure challenge provider %s (%s)", instanceMethodValue(*payload.Type, *payload.Name), err)
+			return output.ErrBadProviderConfig
+		}
+	}
+
+	// add additional details to the payload before saving
+	payload.CreatedAt = int(time.Now().Unix())
+	payload.UpdatedAt = payload.CreatedAt
+
+	// save new provider to storage, which also returns the new provider id
+	id, err := service.storage.PostNewProvider(payload)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// make provider live
+	service.installProvider(instanceMethodValue(*payload.Type, *payload.Name), newProvider, nil)
+
+	// return response to client
+	response := output.JsonResponse{
+		Status:  http.StatusCreated,
+		Message: "created",
+		ID:      id,
+	}
+
+	_, err = service.output.WriteJSON(w, response.Status, response, "response")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
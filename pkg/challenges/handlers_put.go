@@ -0,0 +1,121 @@
+package challenges
+
+import (
+	"encoding/json"
+	"legocerthub-backend/pkg/output"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// UpdateProviderPayload is the struct for editing an existing challenge provider.
+// Type and Name are not editable as certificates refer to the provider's Method by them.
+// If Config is specified, it replaces the entire existing config. Any redacted
+// values in Config are kept as their existing values.
+type UpdateProviderPayload struct {
+	ID              int             `json:"-"`
+	Enabled         *bool           `json:"enabled"`
+	Config          json.RawMessage `json:"config"`
+	EncryptedConfig *string         `json:"-"`
+	UpdatedAt       int             `json:"-"`
+}
+
+// PutProviderUpdate updates a challenge provider that already exists in storage and
+// then rebuilds the live provider.
+func (service *Service) PutProviderUpdate(w http.ResponseWriter, r *http.Request) (err error) {
+	// parse payload
+	var payload UpdateProviderPayload
+	err = json.NewDecoder(r.Body).Decode(&payload)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// get id param
+	idParam := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	payload.ID, err = strconv.Atoi(idParam)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// validation
+	// id
+	provider, err := service.getProvider(payload.ID)
+	if err != nil {
+		return err
+	}
+	// config (optional - check if not nil)
+	if payload.Config != nil {
+		oldCfg, err := service.decryptProviderConfig(provider)
+		if err != nil {
+			service.logger.Error(err)
+			return output.ErrInternal
+		}
+
+		cfg := providerTypes[provider.Type].newConfig()
+		err = json.Unmarshal(payload.Config, cfg)
+		if err != nil {
+			service.logger.Debug(err)
+			return output.ErrValidationFailed
+		}
+		restoreRedacted(cfg, oldCfg)
+
+		encryptedCfg, err := service.encryptProviderConfig(cfg)
+		if err != nil {
+			service.logger.Error(err)
+			return output.ErrInternal
+		}
+		payload.EncryptedConfig = &encryptedCfg
+	}
+	// Enabled does not need validation
+	// end validation
+
+	// apply update to provider
+	if payload.Enabled != nil {
+		provider.Enabled = *payload.Enabled
+	}
+	if payload.EncryptedConfig != nil {
+		provider.EncryptedConfig = *payload.EncryptedConfig
+	}
+
+	// if enabled, build the provider to confirm the config works
+	var newProvider providerService
+	if provider.Enabled {
+		newProvider, err = service.buildProvider(provider)
+		if err != nil {
+			service.logger.Debugf("failed to configure challenge provider %s (%s)", provider.methodValue(), err)
+			return output.ErrBadProviderConfig
+		}
+	}
+
+	// add additional details to the payload before saving
+	payload.UpdatedAt = int(time.Now().Unix())
+
+	// save updated provider to storage
+	err = service.storage.PutProviderUpdate(payload)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// swap live provider (removes it if disabled)
+	service.installProvider(provider.methodValue(), newProvider, nil)
+
+	// return response to client
+	response := output.JsonResponse{
+		Status:  http.StatusOK,
+		Message: "updated",
+		ID:      payload.ID,
+	}
+
+	_, err = service.output.WriteJSON(w, response.Status, response, "response")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
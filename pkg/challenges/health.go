@@ -0,0 +1,141 @@
+package challenges
+
+import (
+	"sync"
+	"time"
+)
+
+// healthChecker is implemented by provider services that can check their own health
+// (e.g. verify credentials or reachability)
+type healthChecker interface {
+	HealthCheck() (err error)
+}
+
+// ProviderHealth is the result of a provider's periodic health checks
+type ProviderHealth struct {
+	Healthy     bool   `json:"healthy"`
+	LastCheck   int    `json:"last_check"`
+	LastSuccess int    `json:"last_success,omitempty"`
+	LastError   string `json:"last_error,omitempty"`
+	LastErrorAt int    `json:"last_error_at,omitempty"`
+}
+
+// update records the result of a health check
+func (health ProviderHealth) update(err error) ProviderHealth {
+	health.LastCheck = int(time.Now().Unix())
+	health.Healthy = err == nil
+
+	if err == nil {
+		health.LastSuccess = health.LastCheck
+	} else {
+		health.LastError = err.Error()
+		health.LastErrorAt = health.LastCheck
+	}
+
+	return health
+}
+
+// HealthStatus is the health of the enabled providers and of the dns checker
+type HealthStatus struct {
+	Providers  []MethodWithStatus `json:"providers"`
+	DnsChecker *ProviderHealth    `json:"dns_checker,omitempty"`
+}
+
+// HealthStatus returns the current health of the enabled providers and of the dns
+// checker (if it is running)
+func (service *Service) HealthStatus() HealthStatus {
+	service.mu.RLock()
+	defer service.mu.RUnlock()
+
+	status := HealthStatus{
+		Providers: []MethodWithStatus{},
+	}
+
+	for i := range service.methodsWithStatus {
+		if service.methodsWithStatus[i].Enabled {
+			status.Providers = append(status.Providers, service.methodsWithStatus[i])
+		}
+	}
+
+	if service.dnsChecker != nil {
+		dnsCheckerHealth := service.dnsCheckerHealth
+		status.DnsChecker = &dnsCheckerHealth
+	}
+
+	return status
+}
+
+// startHealthChecks runs all health checks now and then again every interval until
+// shutdown
+func (service *Service) startHealthChecks(interval time.Duration, wg *sync.WaitGroup) {
+	service.logger.Infof("challenge provider health checks running every %s", interval)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			service.runHealthChecks()
+
+			select {
+			case <-service.shutdownContext.Done():
+				return
+			case <-ticker.C:
+				// run again
+			}
+		}
+	}()
+}
+
+// runHealthChecks checks the health of each live provider that supports health
+// checks and of the dns checker
+func (service *Service) runHealthChecks() {
+	// copy live services so checks don't hold the lock
+	service.mu.RLock()
+	checked := make(map[MethodValue]providerService)
+	for methodValue, provider := range service.providers {
+		if _, ok := provider.(healthChecker); ok {
+			checked[methodValue] = provider
+		}
+	}
+	dnsChecker := service.dnsChecker
+	service.mu.RUnlock()
+
+	// run checks
+	results := make(map[MethodValue]error, len(checked))
+	for methodValue, provider := range checked {
+		err := provider.(healthChecker).HealthCheck()
+		if err != nil {
+			service.logger.Warnf("challenge provider %s health check failed (%s)", methodValue, err)
+		}
+		results[methodValue] = err
+	}
+
+	var dnsCheckerErr error
+	if dnsChecker != nil {
+		dnsCheckerErr = dnsChecker.HealthCheck()
+		if dnsCheckerErr != nil {
+			service.logger.Warnf("dns checker health check failed (%s)", dnsCheckerErr)
+		}
+	}
+
+	// save results (unless the provider was changed while checking)
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	for methodValue, err := range results {
+		if service.providers[methodValue] != checked[methodValue] {
+			continue
+		}
+		service.health[methodValue] = service.health[methodValue].update(err)
+	}
+
+	if dnsChecker != nil {
+		service.dnsCheckerHealth = service.dnsCheckerHealth.update(dnsCheckerErr)
+	}
+
+	service.updateMethodsWithStatus()
+}
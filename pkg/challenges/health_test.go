@@ -0,0 +1,32 @@
+package challenges
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHealth_ProviderHealthUpdate(t *testing.T) {
+	health := ProviderHealth{}
+
+	// success
+	health = health.update(nil)
+	if !health.Healthy || health.LastSuccess == 0 || health.LastSuccess != health.LastCheck {
+		t.Errorf("health after success is %+v", health)
+	}
+	lastSuccess := health.LastSuccess
+
+	// failure keeps last success
+	health = health.update(errors.New("bad token"))
+	if health.Healthy || health.LastError != "bad token" || health.LastErrorAt != health.LastCheck {
+		t.Errorf("health after failure is %+v", health)
+	}
+	if health.LastSuccess != lastSuccess {
+		t.Errorf("last success changed after failure (%d, expected %d)", health.LastSuccess, lastSuccess)
+	}
+
+	// success again keeps last error
+	health = health.update(nil)
+	if !health.Healthy || health.LastError != "bad token" {
+		t.Errorf("health after recovery is %+v", health)
+	}
+}
@@ -1,7 +1,9 @@
 package challenges
 
 import (
+	"fmt"
 	"legocerthub-backend/pkg/acme"
+	"strings"
 )
 
 // Define challenge methods (which are more than just a challenge
@@ -35,6 +37,9 @@ func (method Method) AddStatus(enabled bool) MethodWithStatus {
 // service AddStatus transforms a Method into a MethodWithStatus based on how
 // the challenges service is currently configured (adds enabled/disabled)
 func (service *Service) AddStatus(method Method) MethodWithStatus {
+	service.mu.RLock()
+	defer service.mu.RUnlock()
+
 	for i := range service.methodsWithStatus {
 		if service.methodsWithStatus[i].Value == method.Value {
 			return service.methodsWithStatus[i]
@@ -54,6 +59,8 @@ const (
 	methodValueDns01AcmeDns    MethodValue = "dns-01-acme-dns"
 	methodValueDns01AcmeSh     MethodValue = "dns-01-acme-sh"
 	methodValueDns01Cloudflare MethodValue = "dns-01-cloudflare"
+	methodValueDns01Internal   MethodValue = "dns-01-internal"
+	methodValueDnsPersist01    MethodValue = "dns-persist-01"
 )
 
 // UnknownMethod is used when a Method does not match any known Method.
@@ -94,29 +101,112 @@ var allMethods = []Method{
 		Name:          "DNS Cloudflare",
 		ChallengeType: acme.ChallengeTypeDns01,
 	},
+	{
+		// serve the dns record from an internal dns server (record names
+		// are delegated to it)
+		Value:         methodValueDns01Internal,
+		Name:          "DNS on API Server",
+		ChallengeType: acme.ChallengeTypeDns01,
+	},
+	{
+		// use the account's persistent dns record (created once, outside of LeGo)
+		Value:         methodValueDnsPersist01,
+		Name:          "DNS Persistent Record",
+		ChallengeType: acme.ChallengeTypeDnsPersist01,
+	},
+}
+
+// instanceSeparator separates the provider type and the instance name in
+// the Value of a named provider instance's Method (e.g. dns-01-cloudflare:org-a)
+const instanceSeparator = ":"
+
+// instanceMethodValue returns the Method Value for the named instance of a
+// provider type. An instance with no name uses the type's Value.
+func instanceMethodValue(provType MethodValue, name string) MethodValue {
+	if name == "" {
+		return provType
+	}
+
+	return provType + instanceSeparator + MethodValue(name)
 }
 
 // MethodByStorageValue returns a challenge method based on its Value.
 // If a method isn't found, UnknownMethod is returned.
 func MethodByStorageValue(value MethodValue) Method {
+	// exec plugin
+	challType, pluginName, isPlugin := strings.Cut(string(value), pluginSeparator)
+	if isPlugin {
+		if pluginName == "" || (acme.ChallengeType(challType) != acme.ChallengeTypeHttp01 &&
+			acme.ChallengeType(challType) != acme.ChallengeTypeDns01) {
+			return UnknownMethod
+		}
+
+		return Method{
+			Value:         value,
+			Name:          fmt.Sprintf("Plugin %s (%s)", challType, pluginName),
+			ChallengeType: acme.ChallengeType(challType),
+		}
+	}
+
+	// named instance of a provider type
+	provType, name, isInstance := strings.Cut(string(value), instanceSeparator)
+
 	for i := range allMethods {
-		if value == allMethods[i].Value {
-			return allMethods[i]
+		if MethodValue(provType) == allMethods[i].Value {
+			if !isInstance {
+				return allMethods[i]
+			}
+
+			// only storage configured provider types can have named instances
+			if _, ok := providerTypes[allMethods[i].Value]; !ok || name == "" {
+				return UnknownMethod
+			}
+
+			return Method{
+				Value:         value,
+				Name:          fmt.Sprintf("%s (%s)", allMethods[i].Name, name),
+				ChallengeType: allMethods[i].ChallengeType,
+			}
 		}
 	}
 
 	return UnknownMethod
 }
 
+// pluginSeparator separates the challenge type and the plugin name in the
+// Value of an exec plugin's Method (e.g. dns-01-plugin-my-dns)
+const pluginSeparator = "-plugin-"
+
+// pluginMethodValue returns the Method Value for an exec plugin
+func pluginMethodValue(challengeType acme.ChallengeType, pluginName string) MethodValue {
+	return MethodValue(string(challengeType) + pluginSeparator + pluginName)
+}
+
+// isBaseMethod returns true if the Value is one of the predefined Methods (i.e. it is
+// not a named instance of a provider type)
+func isBaseMethod(value MethodValue) bool {
+	for i := range allMethods {
+		if value == allMethods[i].Value {
+			return true
+		}
+	}
+
+	return false
+}
+
 // MethodWithStatus is a struct to return service status with a Method
 type MethodWithStatus struct {
 	Method
-	Enabled bool `json:"enabled"`
+	Enabled bool            `json:"enabled"`
+	Health  *ProviderHealth `json:"health,omitempty"`
 }
 
 // ListOfMethodsWithStatus returns a slice of all possible challenge methods
 // with an additional field indicating if each is currently enabled in the challenges
 // service.
 func (service *Service) ListOfMethodsWithStatus() []MethodWithStatus {
+	service.mu.RLock()
+	defer service.mu.RUnlock()
+
 	return service.methodsWithStatus
 }
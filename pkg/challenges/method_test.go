@@ -0,0 +1,33 @@
+package challenges
+
+import (
+	"legocerthub-backend/pkg/acme"
+	"testing"
+)
+
+func TestMethod_MethodByStorageValue(t *testing.T) {
+	tests := []struct {
+		value    MethodValue
+		expected Method
+	}{
+		{"dns-01-cloudflare", Method{"dns-01-cloudflare", "DNS Cloudflare", acme.ChallengeTypeDns01}},
+		{"dns-01-cloudflare:org-a", Method{"dns-01-cloudflare:org-a", "DNS Cloudflare (org-a)", acme.ChallengeTypeDns01}},
+		{"dns-01-acme-sh:dns_cf", Method{"dns-01-acme-sh:dns_cf", "DNS acme.sh Script (dns_cf)", acme.ChallengeTypeDns01}},
+		// http-01-internal is not configured in storage and can't have instances
+		{"http-01-internal:other", UnknownMethod},
+		{"dns-01-cloudflare:", UnknownMethod},
+		{"not-a-method:org-a", UnknownMethod},
+		{"dns-01-plugin-my-dns", Method{"dns-01-plugin-my-dns", "Plugin dns-01 (my-dns)", acme.ChallengeTypeDns01}},
+		{"http-01-plugin-web", Method{"http-01-plugin-web", "Plugin http-01 (web)", acme.ChallengeTypeHttp01}},
+		{"dns-01-plugin-", UnknownMethod},
+		{"tls-alpn-01-plugin-other", UnknownMethod},
+		{"", UnknownMethod},
+	}
+
+	for _, test := range tests {
+		method := MethodByStorageValue(test.value)
+		if method != test.expected {
+			t.Errorf("method for '%s' is %v (expected %v)", test.value, method, test.expected)
+		}
+	}
+}
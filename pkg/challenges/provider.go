@@ -0,0 +1,156 @@
+package challenges
+
+import (
+	"encoding/json"
+	"errors"
+	"legocerthub-backend/pkg/output"
+	"legocerthub-backend/pkg/storage"
+	"legocerthub-backend/pkg/validation"
+	"strings"
+)
+
+var (
+	errProviderTypeBad = errors.New("challenge provider type is not valid")
+	errProviderExists  = errors.New("challenge provider of this type and name already exists")
+	errProviderNameBad = errors.New("challenge provider name is not valid")
+)
+
+// Provider is the struct for a challenge provider's stored configuration.
+// The provider's config is stored encrypted as it contains credentials.
+// Multiple instances of the same Type may exist, each with a unique Name.
+type Provider struct {
+	ID              int
+	Type            MethodValue
+	Name            string
+	Enabled         bool
+	EncryptedConfig string
+	CreatedAt       int
+	UpdatedAt       int
+}
+
+// methodValue returns the Value of the Method that uses this Provider
+func (provider Provider) methodValue() MethodValue {
+	return instanceMethodValue(provider.Type, provider.Name)
+}
+
+// providerResponse is the api response for a challenge provider. Any credentials
+// in the config are redacted.
+type providerResponse struct {
+	ID        int              `json:"id"`
+	Type      MethodValue      `json:"type"`
+	Name      string           `json:"name"`
+	Method    MethodWithStatus `json:"method"`
+	Enabled   bool             `json:"enabled"`
+	Config    interface{}      `json:"config"`
+	Error     string           `json:"error,omitempty"`
+	CreatedAt int              `json:"created_at"`
+	UpdatedAt int              `json:"updated_at"`
+}
+
+// response creates the api response for the Provider
+func (provider Provider) response(service *Service) (providerResponse, error) {
+	// decrypt config
+	cfg, err := service.decryptProviderConfig(provider)
+	if err != nil {
+		return providerResponse{}, err
+	}
+
+	// last build error (if any)
+	errString := ""
+	service.mu.RLock()
+	if provErr := service.providerErrs[provider.methodValue()]; provErr != nil {
+		errString = provErr.Error()
+	}
+	service.mu.RUnlock()
+
+	return providerResponse{
+		ID:        provider.ID,
+		Type:      provider.Type,
+		Name:      provider.Name,
+		Method:    service.AddStatus(MethodByStorageValue(provider.methodValue())),
+		Enabled:   provider.Enabled,
+		Config:    redactConfig(cfg),
+		Error:     errString,
+		CreatedAt: provider.CreatedAt,
+		UpdatedAt: provider.UpdatedAt,
+	}, nil
+}
+
+// decryptProviderConfig decrypts the stored config of a Provider and decodes it into
+// the Provider type's Config struct
+func (service *Service) decryptProviderConfig(provider Provider) (interface{}, error) {
+	provType, ok := providerTypes[provider.Type]
+	if !ok {
+		return nil, errProviderTypeBad
+	}
+
+	cfgJson, err := service.cipher.Decrypt(provider.EncryptedConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := provType.newConfig()
+	err = json.Unmarshal(cfgJson, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// encryptProviderConfig encodes a provider's Config struct and encrypts it for storage
+func (service *Service) encryptProviderConfig(cfg interface{}) (string, error) {
+	cfgJson, err := json.Marshal(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	return service.cipher.Encrypt(cfgJson)
+}
+
+// getProvider returns the Provider from storage for the specified id. If the id
+// does not exist, output.ErrNotFound is returned.
+func (service *Service) getProvider(id int) (Provider, error) {
+	provider, err := service.storage.GetOneProvider(id)
+	if err != nil {
+		// special error case for no record found
+		if errors.Is(err, storage.ErrNoRecord) {
+			service.logger.Debug(err)
+			return Provider{}, output.ErrNotFound
+		} else {
+			service.logger.Error(err)
+			return Provider{}, output.ErrStorageGeneric
+		}
+	}
+
+	return provider, nil
+}
+
+// providerExists returns true if a provider of the specified type and name is
+// already in storage
+func (service *Service) providerExists(provType MethodValue, name string) bool {
+	providers, err := service.storage.GetAllProviders()
+	if err != nil {
+		service.logger.Error(err)
+		// if can't confirm, assume it exists
+		return true
+	}
+
+	for i := range providers {
+		if providers[i].Type == provType && providers[i].Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// providerNameValid returns true if the name is acceptable for a provider instance.
+// Blank is allowed (the instance's Method Value is then the type's Value).
+func providerNameValid(name string) bool {
+	if name == "" {
+		return true
+	}
+
+	return validation.NameValid(name) && !strings.Contains(name, instanceSeparator)
+}
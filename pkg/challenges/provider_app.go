@@ -0,0 +1,23 @@
+package challenges
+
+import (
+	"context"
+	"legocerthub-backend/pkg/httpclient"
+
+	"go.uber.org/zap"
+)
+
+// functions so that challenges.Service satisfies the App interface
+// contained within each provider pkg. This allows challenges to start
+// up new providers when they're configured using the api
+func (service *Service) GetLogger() *zap.SugaredLogger {
+	return service.logger
+}
+
+func (service *Service) GetHttpClient() *httpclient.Client {
+	return service.httpClient
+}
+
+func (service *Service) GetShutdownContext() context.Context {
+	return service.shutdownContext
+}
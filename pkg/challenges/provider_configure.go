@@ -0,0 +1,247 @@
+package challenges
+
+import (
+	"legocerthub-backend/pkg/challenges/dns_checker"
+	"sort"
+	"time"
+)
+
+// configProviderImport is a dns provider from the config file that may be imported
+// into storage
+type configProviderImport struct {
+	methodValue MethodValue
+	enable      *bool
+	cfg         interface{}
+}
+
+// anyDnsEnabled returns true if any of the storage configured providers are enabled
+// in the config file
+func (cfgProviders *ConfigProviders) anyDnsEnabled() bool {
+	for _, imp := range cfgProviders.imports() {
+		if imp.enable != nil && *imp.enable {
+			return true
+		}
+	}
+
+	return false
+}
+
+// imports returns the storage configured providers from the config file
+func (cfgProviders *ConfigProviders) imports() []configProviderImport {
+	return []configProviderImport{
+		{methodValueDns01Manual, cfgProviders.Dns01ManualConfig.Enable, &cfgProviders.Dns01ManualConfig},
+		{methodValueDns01AcmeDns, cfgProviders.Dns01AcmeDnsConfig.Enable, &cfgProviders.Dns01AcmeDnsConfig},
+		{methodValueDns01AcmeSh, cfgProviders.Dns01AcmeShConfig.Enable, &cfgProviders.Dns01AcmeShConfig},
+		{methodValueDns01Cloudflare, cfgProviders.Dns01CloudflareConfig.Enable, &cfgProviders.Dns01CloudflareConfig},
+	}
+}
+
+// importConfigProviders saves each enabled dns provider from the config file to
+// storage and returns the new Providers
+func (service *Service) importConfigProviders(cfgProviders *ConfigProviders) ([]Provider, error) {
+	providers := []Provider{}
+
+	for _, imp := range cfgProviders.imports() {
+		if imp.enable == nil || !*imp.enable {
+			continue
+		}
+
+		encryptedCfg, err := service.encryptProviderConfig(imp.cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		payload := NewProviderPayload{
+			Type:            &imp.methodValue,
+			Name:            new(string),
+			Enabled:         imp.enable,
+			EncryptedConfig: encryptedCfg,
+			CreatedAt:       int(time.Now().Unix()),
+		}
+		payload.UpdatedAt = payload.CreatedAt
+
+		id, err := service.storage.PostNewProvider(payload)
+		if err != nil {
+			return nil, err
+		}
+
+		service.logger.Infof("imported challenge provider %s from config file into storage (id: %d)", imp.methodValue, id)
+
+		providers = append(providers, Provider{
+			ID:              id,
+			Type:            imp.methodValue,
+			Enabled:         true,
+			EncryptedConfig: encryptedCfg,
+			CreatedAt:       payload.CreatedAt,
+			UpdatedAt:       payload.UpdatedAt,
+		})
+	}
+
+	return providers, nil
+}
+
+// setProvider builds the live provider service for the stored Provider and swaps it in
+// place of the current one. If the Provider is disabled, the live service is removed.
+func (service *Service) setProvider(provider Provider) error {
+	// disabled, remove live service
+	if !provider.Enabled {
+		service.installProvider(provider.methodValue(), nil, nil)
+		return nil
+	}
+
+	// build new provider service
+	newProvider, err := service.buildProvider(provider)
+	service.installProvider(provider.methodValue(), newProvider, err)
+
+	return err
+}
+
+// installProvider swaps newProvider in as the live provider service for the Method Value.
+// If newProvider is nil, the live service is removed. If buildErr is not nil, the live
+// service is removed and the error is recorded so it can be reported.
+// Challenges that are already being solved continue to use the provider service they
+// started with, so in-flight orders are not disrupted.
+func (service *Service) installProvider(methodValue MethodValue, newProvider providerService, buildErr error) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	// named instances are added to the list of Methods
+	if !isBaseMethod(methodValue) {
+		service.instanceMethods[methodValue] = MethodByStorageValue(methodValue)
+	}
+
+	// health of the old provider service no longer applies
+	delete(service.health, methodValue)
+
+	if buildErr != nil {
+		delete(service.providers, methodValue)
+		service.providerErrs[methodValue] = buildErr
+	} else if newProvider == nil {
+		delete(service.providers, methodValue)
+		delete(service.providerErrs, methodValue)
+	} else {
+		service.providers[methodValue] = newProvider
+		delete(service.providerErrs, methodValue)
+	}
+
+	service.updateMethodsWithStatus()
+}
+
+// buildProvider creates a new provider service from the stored Provider
+func (service *Service) buildProvider(provider Provider) (providerService, error) {
+	provType, ok := providerTypes[provider.Type]
+	if !ok {
+		return nil, errProviderTypeBad
+	}
+
+	cfg, err := service.decryptProviderConfig(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	newProvider, err := provType.newService(service, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// dns providers need the dns checker
+	err = service.startDnsChecker(provider.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	return newProvider, nil
+}
+
+// removeProvider removes the live provider service for the Method Value and, if it
+// is a named instance, removes the Method from the list of Methods
+func (service *Service) removeProvider(methodValue MethodValue) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	delete(service.providers, methodValue)
+	delete(service.providerErrs, methodValue)
+	delete(service.health, methodValue)
+	delete(service.instanceMethods, methodValue)
+	service.updateMethodsWithStatus()
+}
+
+// startDnsChecker starts the dns checker service, if the Method Value is a dns Method
+// and the checker isn't already running
+// Fixes https://github.com/gregtwallace/legocerthub/issues/6
+func (service *Service) startDnsChecker(methodValue MethodValue) (err error) {
+	if !isDnsChallengeType(MethodByStorageValue(methodValue).ChallengeType) {
+		return nil
+	}
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	if service.dnsChecker != nil {
+		return nil
+	}
+
+	service.dnsChecker, err = dns_checker.NewService(service, service.dnsCheckerConfig)
+	if err != nil {
+		service.logger.Errorf("failed to configure dns checker (%s)", err)
+		return err
+	}
+
+	return nil
+}
+
+// updateMethodsWithStatus updates the slice of all Methods (including exec plugins and
+// named provider instances) and whether each is currently enabled. The caller must hold the write lock.
+func (service *Service) updateMethodsWithStatus() {
+	methodsWithStatus := []MethodWithStatus{}
+	for i := range allMethods {
+		methodsWithStatus = append(methodsWithStatus, service.methodWithStatus(allMethods[i]))
+	}
+
+	// exec plugins and named instances, sorted by Value
+	extraMethods := make(map[MethodValue]Method, len(service.pluginMethods)+len(service.instanceMethods))
+	for value, method := range service.pluginMethods {
+		extraMethods[value] = method
+	}
+	for value, method := range service.instanceMethods {
+		extraMethods[value] = method
+	}
+
+	instanceValues := []string{}
+	for value := range extraMethods {
+		instanceValues = append(instanceValues, string(value))
+	}
+	sort.Strings(instanceValues)
+
+	for _, value := range instanceValues {
+		methodsWithStatus = append(methodsWithStatus, service.methodWithStatus(extraMethods[MethodValue(value)]))
+	}
+
+	service.methodsWithStatus = methodsWithStatus
+}
+
+// methodWithStatus adds the Method's enabled status and its last health check result
+// (if there is one). The caller must hold the lock.
+func (service *Service) methodWithStatus(method Method) MethodWithStatus {
+	_, enabled := service.providers[method.Value]
+	methodWithStatus := method.AddStatus(enabled)
+
+	if health, ok := service.health[method.Value]; ok && enabled {
+		methodWithStatus.Health = &health
+	}
+
+	return methodWithStatus
+}
+
+// provider returns the live provider service for the specified Method Value
+func (service *Service) provider(methodValue MethodValue) (providerService, error) {
+	service.mu.RLock()
+	defer service.mu.RUnlock()
+
+	provider, ok := service.providers[methodValue]
+	if !ok || provider == nil {
+		return nil, errUnsupportedMethod
+	}
+
+	return provider, nil
+}
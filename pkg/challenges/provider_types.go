@@ -0,0 +1,65 @@
+package challenges
+
+import (
+	"legocerthub-backend/pkg/challenges/providers/dns01acmedns"
+	"legocerthub-backend/pkg/challenges/providers/dns01acmesh"
+	"legocerthub-backend/pkg/challenges/providers/dns01cloudflare"
+	"legocerthub-backend/pkg/challenges/providers/dns01manual"
+)
+
+// providerType defines how to make the config and service for each type of
+// provider that is configured in storage
+type providerType struct {
+	// newConfig returns a pointer to a new Config (with default values) for the type
+	newConfig func() interface{}
+	// newService creates the provider service from a pointer to a Config
+	newService func(service *Service, cfg interface{}) (providerService, error)
+}
+
+// providerTypes maps each storage configured provider to its Method Value
+var providerTypes = map[MethodValue]providerType{
+	methodValueDns01Manual: {
+		newConfig: func() interface{} {
+			cfg := dns01manual.DefaultConfig()
+			return &cfg
+		},
+		newService: func(service *Service, cfg interface{}) (providerService, error) {
+			providerCfg := cfg.(*dns01manual.Config)
+			*providerCfg.Enable = true
+			return dns01manual.NewService(service, providerCfg)
+		},
+	},
+	methodValueDns01AcmeDns: {
+		newConfig: func() interface{} {
+			cfg := dns01acmedns.DefaultConfig()
+			return &cfg
+		},
+		newService: func(service *Service, cfg interface{}) (providerService, error) {
+			providerCfg := cfg.(*dns01acmedns.Config)
+			*providerCfg.Enable = true
+			return dns01acmedns.NewService(service, providerCfg)
+		},
+	},
+	methodValueDns01AcmeSh: {
+		newConfig: func() interface{} {
+			cfg := dns01acmesh.DefaultConfig()
+			return &cfg
+		},
+		newService: func(service *Service, cfg interface{}) (providerService, error) {
+			providerCfg := cfg.(*dns01acmesh.Config)
+			*providerCfg.Enable = true
+			return dns01acmesh.NewService(service, providerCfg)
+		},
+	},
+	methodValueDns01Cloudflare: {
+		newConfig: func() interface{} {
+			cfg := dns01cloudflare.DefaultConfig()
+			return &cfg
+		},
+		newService: func(service *Service, cfg interface{}) (providerService, error) {
+			providerCfg := cfg.(*dns01cloudflare.Config)
+			*providerCfg.Enable = true
+			return dns01cloudflare.NewService(service, providerCfg)
+		},
+	},
+}
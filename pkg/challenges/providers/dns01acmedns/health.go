@@ -0,0 +1,26 @@
+package dns01acmedns
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// acme-dns health endpoint
+const acmeDnsHealthEndpoint = "/health"
+
+// HealthCheck confirms the acme-dns server is reachable
+func (service *Service) HealthCheck() error {
+	resp, err := service.httpClient.Get(service.acmeDnsAddress + acmeDnsHealthEndpoint)
+	if err != nil {
+		return fmt.Errorf("acme-dns %s unreachable (%s)", service.acmeDnsAddress, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("acme-dns %s health returned status %d", service.acmeDnsAddress, resp.StatusCode)
+	}
+
+	return nil
+}
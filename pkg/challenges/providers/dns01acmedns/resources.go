@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"legocerthub-backend/pkg/diagnostics"
 	"net/http"
 	"strings"
 )
@@ -12,16 +13,17 @@ import (
 var (
 	ErrDomainNotConfigured = errors.New("dns01acmedns domain name not configured (restart lego if config was updated)")
 	ErrUpdateFailed        = errors.New("dns01acmedns failed to update domain ")
+	ErrTooManyValues       = errors.New("dns01acmedns can't serve more than two values for the same domain at once")
 )
 
 // acmeDnsResource contains the needed configuration to update
 // and acme-dns record and also the corresponding 'real' domain
 // that will have a certificate issued for it.
 type acmeDnsResource struct {
-	RealDomain string `yaml:"real_domain"`
-	FullDomain string `yaml:"full_domain"`
-	Username   string `yaml:"username"`
-	Password   string `yaml:"password"`
+	RealDomain string `yaml:"real_domain" json:"real_domain"`
+	FullDomain string `yaml:"full_domain" json:"full_domain"`
+	Username   string `yaml:"username" json:"username"`
+	Password   string `yaml:"password" json:"password" redact:"true"`
 }
 
 // subdomain returns just the first piece of the subdomain of FullDomain
@@ -66,26 +68,29 @@ func (service *Service) updateRequest(adr *acmeDnsResource, resourceContent stri
 	return req, nil
 }
 
-// Provision updates the acme-dns resource record with the correct content
-func (service *Service) Provision(resourceName string, resourceContent string) error {
-	// check if resource exists
-	adr := acmeDnsResource{}
-	found := false
+// acme-dns only serves the two most recently updated values for each record
+const acmeDnsMaxValues = 2
+
+// voidValue is the dummy value sent when a record is no longer in use
+const voidValue = "VOID_____VOID______VOID_______VOID_____VOID"
+
+// findResource returns the configured acme-dns resource for the resourceName
+func (service *Service) findResource(resourceName string) (acmeDnsResource, error) {
 	for _, r := range service.acmeDnsResources {
 		if "_acme-challenge."+r.RealDomain == resourceName {
-			adr = r
-			found = true
-			break
+			return r, nil
 		}
 	}
+
 	// this package does not support registering new records, so fail if
 	// record was not found
-	if !found {
-		return ErrDomainNotConfigured
-	}
+	return acmeDnsResource{}, ErrDomainNotConfigured
+}
 
+// sendUpdate sends an update to acme-dns for the adr with the specified content
+func (service *Service) sendUpdate(adr *acmeDnsResource, resourceContent string) error {
 	// make request
-	req, err := service.updateRequest(&adr, resourceContent)
+	req, err := service.updateRequest(adr, resourceContent)
 	if err != nil {
 		return err
 	}
@@ -107,45 +112,69 @@ func (service *Service) Provision(resourceName string, resourceContent string) e
 	return nil
 }
 
-// Derovision updates the acme-dns resource record with blank content. This probably
-// isn't really needed and this could be an empty stub. Clearing the data doesn't
-// hurt though.
-func (service *Service) Deprovision(resourceName string, resourceContent string) error {
+// Provision updates the acme-dns resource record with the correct content. Since
+// acme-dns keeps the two most recent values, up to two values can be provisioned
+// for the same name at once (e.g. for a wildcard and its apex).
+func (service *Service) Provision(resourceName string, resourceContent string, diag *diagnostics.Log) error {
 	// check if resource exists
-	adr := acmeDnsResource{}
-	found := false
-	for _, r := range service.acmeDnsResources {
-		if "_acme-challenge."+r.RealDomain == resourceName {
-			adr = r
-			found = true
-			break
-		}
+	adr, err := service.findResource(resourceName)
+	if err != nil {
+		return err
 	}
-	// this package does not support registering new records, so fail if
-	// record was not found
-	if !found {
-		return ErrDomainNotConfigured
+
+	// updates are serialized so the values acme-dns holds always match the
+	// tracked values
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	// track value, acme-dns can't hold more than two
+	exists, count := service.dnsRecords.Add(resourceName, resourceContent)
+	if count > acmeDnsMaxValues {
+		if !exists {
+			_, _ = service.dnsRecords.Remove(resourceName, resourceContent)
+		}
+		return ErrTooManyValues
 	}
 
-	// make request (dummy text value when not in use)
-	req, err := service.updateRequest(&adr, "VOID_____VOID______VOID_______VOID_____VOID")
+	err = service.sendUpdate(&adr, resourceContent)
 	if err != nil {
+		if !exists {
+			_, _ = service.dnsRecords.Remove(resourceName, resourceContent)
+		}
 		return err
 	}
 
-	// send request
-	resp, err := service.httpClient.Do(req)
+	return nil
+}
+
+// Deprovision stops tracking the value and updates acme-dns. If another value is
+// still in use for the same name, that value is sent again so it remains one of
+// the two values acme-dns serves. Otherwise, the record is updated with dummy
+// content. Clearing the data probably isn't needed, but doesn't hurt.
+func (service *Service) Deprovision(resourceName string, resourceContent string, diag *diagnostics.Log) error {
+	// check if resource exists
+	adr, err := service.findResource(resourceName)
 	if err != nil {
 		return err
 	}
-	// ready body & close
-	defer resp.Body.Close()
-	_, _ = io.Copy(io.Discard, resp.Body)
 
-	// if not status 200
-	if resp.StatusCode != http.StatusOK {
-		return ErrUpdateFailed
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	remaining, err := service.dnsRecords.Remove(resourceName, resourceContent)
+	if err != nil {
+		// value was never provisioned (e.g. Provision failed), updating acme-dns
+		// could push out a value that is still in use
+		service.logger.Debugf("dns-01 (acme-dns) resource (%s) value not provisioned, "+
+			"nothing to deprovision", resourceName)
+		return nil
 	}
 
-	return nil
+	// if nothing remains, use dummy text value (not in use)
+	newContent := voidValue
+	if len(remaining) > 0 {
+		newContent = remaining[0]
+	}
+
+	return service.sendUpdate(&adr, newContent)
 }
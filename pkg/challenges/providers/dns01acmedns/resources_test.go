@@ -0,0 +1,163 @@
+package dns01acmedns
+
+import (
+	"encoding/json"
+	"legocerthub-backend/pkg/httpclient"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// testApp satisfies App for testing
+type testApp struct{}
+
+func (testApp) GetLogger() *zap.SugaredLogger     { return zap.NewNop().Sugar() }
+func (testApp) GetHttpClient() *httpclient.Client { return httpclient.New("lego-test", false) }
+
+// fakeAcmeDns mimics acme-dns, which serves the two most recent values for
+// each subdomain
+type fakeAcmeDns struct {
+	values map[string][]string
+	mu     sync.Mutex
+}
+
+func (fake *fakeAcmeDns) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var update acmeDnsUpdate
+	err := json.NewDecoder(r.Body).Decode(&update)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	values := append(fake.values[update.SubDomain], update.Txt)
+	if len(values) > 2 {
+		values = values[len(values)-2:]
+	}
+	fake.values[update.SubDomain] = values
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// serving returns true if acme-dns is currently serving value for subdomain
+func (fake *fakeAcmeDns) serving(subdomain string, value string) bool {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	for _, v := range fake.values[subdomain] {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// test acme-dns registration and the values provisioned through it
+const (
+	testRealDomain   = "example.com"
+	testSubdomain    = "ee29dc47-aaaa-aaaa-aaaa-aaaaaaaaaaaa"
+	testResourceName = "_acme-challenge." + testRealDomain
+	testApexValue    = "apex_XKrxpRBosdIKFzxW_CT3KLZNf6q0HG9i01zxXp5CPBs"
+	testWildValue    = "wild_Gf2Nd4Hc8KsPq0Yz3aBvL7mWxT1uRjE9oIe5dC6hQkA"
+	testOtherValue   = "othr_9pQ2sLm4Vn6Bx8Zc1Ad3Fg5Hj7Kl0Qw2Er4Ty6Ui8Op"
+)
+
+func newTestService(t *testing.T) (*Service, *fakeAcmeDns) {
+	fake := &fakeAcmeDns{values: make(map[string][]string)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	enable := true
+	address := server.URL
+	service, err := NewService(testApp{}, &Config{
+		Enable:      &enable,
+		HostAddress: &address,
+		Resources: []acmeDnsResource{
+			{
+				RealDomain: testRealDomain,
+				FullDomain: testSubdomain + ".acme-dns.example.net",
+				Username:   "user",
+				Password:   "pass",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create service (%s)", err)
+	}
+
+	return service, fake
+}
+
+func TestAcmeDns_WildcardAndApex(t *testing.T) {
+	service, fake := newTestService(t)
+
+	// provision both concurrently (as FulfillAuths would)
+	var wg sync.WaitGroup
+	for _, value := range []string{testApexValue, testWildValue} {
+		wg.Add(1)
+		go func(value string) {
+			defer wg.Done()
+			err := service.Provision(testResourceName, value, nil)
+			if err != nil {
+				t.Errorf("failed to provision '%s' (%s)", value, err)
+			}
+		}(value)
+	}
+	wg.Wait()
+
+	if !fake.serving(testSubdomain, testApexValue) || !fake.serving(testSubdomain, testWildValue) {
+		t.Fatalf("both values not served after provisioning (serving: %v)", fake.values[testSubdomain])
+	}
+
+	// deprovision the most recently provisioned value first, the other must
+	// still be served
+	last := fake.values[testSubdomain][1]
+	first := fake.values[testSubdomain][0]
+	err := service.Deprovision(testResourceName, last, nil)
+	if err != nil {
+		t.Errorf("failed to deprovision '%s' (%s)", last, err)
+	}
+	if !fake.serving(testSubdomain, first) {
+		t.Errorf("deprovisioning '%s' removed '%s'", last, first)
+	}
+
+	// deprovision the other
+	err = service.Deprovision(testResourceName, first, nil)
+	if err != nil {
+		t.Errorf("failed to deprovision '%s' (%s)", first, err)
+	}
+	if len(service.dnsRecords.Values(testResourceName)) != 0 {
+		t.Errorf("values still tracked after deprovisioning both")
+	}
+}
+
+func TestAcmeDns_TooManyValues(t *testing.T) {
+	service, fake := newTestService(t)
+
+	for _, value := range []string{testApexValue, testWildValue} {
+		err := service.Provision(testResourceName, value, nil)
+		if err != nil {
+			t.Fatalf("failed to provision '%s' (%s)", value, err)
+		}
+	}
+
+	// a third value can't be served at the same time
+	err := service.Provision(testResourceName, testOtherValue, nil)
+	if err != ErrTooManyValues {
+		t.Errorf("expected too many values error, got: %v", err)
+	}
+
+	// deprovisioning the rejected value must not disturb the others
+	err = service.Deprovision(testResourceName, testOtherValue, nil)
+	if err != nil {
+		t.Errorf("deprovision of rejected value errored (%s)", err)
+	}
+	if !fake.serving(testSubdomain, testApexValue) || !fake.serving(testSubdomain, testWildValue) {
+		t.Errorf("existing values disturbed (serving: %v)", fake.values[testSubdomain])
+	}
+}
@@ -2,7 +2,9 @@ package dns01acmedns
 
 import (
 	"errors"
+	"legocerthub-backend/pkg/datatypes"
 	"legocerthub-backend/pkg/httpclient"
+	"sync"
 
 	"go.uber.org/zap"
 )
@@ -23,13 +25,27 @@ type Service struct {
 	httpClient       *httpclient.Client
 	acmeDnsAddress   string
 	acmeDnsResources []acmeDnsResource
+	dnsRecords       *datatypes.SafeValueSets
+	mu               sync.Mutex
 }
 
 // Configuration options
 type Config struct {
-	Enable      *bool             `yaml:"enable"`
-	HostAddress *string           `yaml:"acme_dns_address"`
-	Resources   []acmeDnsResource `yaml:"resources"`
+	Enable      *bool             `yaml:"enable" json:"-"`
+	HostAddress *string           `yaml:"acme_dns_address" json:"acme_dns_address,omitempty"`
+	Resources   []acmeDnsResource `yaml:"resources" json:"resources"`
+}
+
+// DefaultConfig returns a Config with the default values set
+func DefaultConfig() Config {
+	cfg := Config{
+		Enable:      new(bool),
+		HostAddress: new(string),
+	}
+
+	*cfg.Enable = false
+
+	return cfg
 }
 
 // NewService creates a new service
@@ -59,5 +75,8 @@ func NewService(app App, cfg *Config) (*Service, error) {
 	// acme-dns resources that will be updated
 	service.acmeDnsResources = cfg.Resources
 
+	// values currently in use for each record
+	service.dnsRecords = datatypes.NewSafeValueSets()
+
 	return service, nil
 }
@@ -1,22 +1,21 @@
 package dns01acmesh
 
 import (
-	"os"
-	"os/exec"
+	"legocerthub-backend/pkg/challenges/providers/script_runner"
 )
 
 // makeCreateCommand creates the command to make a dns record
-func (service *Service) makeCreateCommand(resourceName string, resourceContent string) *exec.Cmd {
+func (service *Service) makeCreateCommand(resourceName string, resourceContent string) script_runner.Command {
 	return service.makeCommand(resourceName, resourceContent, false)
 }
 
 // makeDeleteCommand creates the command to delete a dns record
-func (service *Service) makeDeleteCommand(resourceName string, resourceContent string) *exec.Cmd {
+func (service *Service) makeDeleteCommand(resourceName string, resourceContent string) script_runner.Command {
 	return service.makeCommand(resourceName, resourceContent, true)
 }
 
 // makeCommand makes a command to create or delete a dns record
-func (service *Service) makeCommand(resourceName string, resourceContent string, delete bool) *exec.Cmd {
+func (service *Service) makeCommand(resourceName string, resourceContent string, delete bool) script_runner.Command {
 	// func name
 	funcName := service.dnsHook + "_add"
 	if delete {
@@ -33,11 +32,9 @@ func (service *Service) makeCommand(resourceName string, resourceContent string,
 	args = append(args, "source "+service.shellScriptPath+" ; "+funcName+" "+resourceName+" "+resourceContent)
 
 	// make command
-	cmd := exec.Command(service.shellPath, args...)
-
-	// set command environment
-	cmd.Env = os.Environ()
-	cmd.Env = append(cmd.Env, service.environmentVars...)
-
-	return cmd
+	return script_runner.Command{
+		Name:        service.shellPath,
+		Args:        args,
+		Environment: service.environmentVars,
+	}
 }
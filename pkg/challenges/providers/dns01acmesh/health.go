@@ -0,0 +1,11 @@
+package dns01acmesh
+
+import (
+	"legocerthub-backend/pkg/challenges/providers/script_runner"
+)
+
+// HealthCheck confirms bash is executable and the acme.sh script (with the dns
+// hook) still exists
+func (service *Service) HealthCheck() error {
+	return script_runner.CheckFiles(service.shellPath, service.shellScriptPath)
+}
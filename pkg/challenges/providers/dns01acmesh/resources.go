@@ -2,45 +2,43 @@ package dns01acmesh
 
 import (
 	"fmt"
-	"os/exec"
+	"legocerthub-backend/pkg/challenges/providers/script_runner"
+	"legocerthub-backend/pkg/diagnostics"
 )
 
+// diagnostics source name
+const diagSource = "dns-01-acme-sh"
+
 // Provision adds the resource to the internal tracking map and provisions
 // the corresponding DNS record.
-func (service *Service) Provision(resourceName string, resourceContent string) error {
-	// add to internal map
-	exists, existingContent := service.dnsRecords.Add(resourceName, resourceContent)
-	// if already exists, but content is different, error
-	if exists && existingContent != resourceContent {
-		return fmt.Errorf("dns-01 (acme.sh) can't add resource (%s), already exists "+
-			"and content does not match", resourceName)
-	}
+func (service *Service) Provision(resourceName string, resourceContent string, diag *diagnostics.Log) error {
+	// add to internal map (multiple values may exist for the same name, e.g.
+	// when a wildcard and its apex are in the same order)
+	_, _ = service.dnsRecords.Add(resourceName, resourceContent)
 
 	// run create script
 	// script command
 	cmd := service.makeCreateCommand(resourceName, resourceContent)
 
 	// run script command
-	result, err := cmd.Output()
+	result, err := script_runner.Run(service.shutdownContext, service.timeout, cmd)
+	result.AddToDiagnostics(diag, diagSource, "create script")
 	if err != nil {
-		// try to get detailed err
-		exitErr, ok := err.(*exec.ExitError)
-		if ok {
-			service.logger.Errorf("acme.sh dns create script std err: %s", exitErr.Stderr)
-		}
+		service.logger.Errorf("acme.sh dns create script std err: %s", result.Stderr)
 		service.logger.Errorf("acme.sh dns create script error: %s", err)
-		return err
+		return fmt.Errorf("dns-01 (acme.sh) create script failed (%s)", err)
 	}
-	service.logger.Debugf("acme.sh dns create script output: %s", string(result))
+	service.logger.Debugf("acme.sh dns create script output: %s", result.Stdout)
 
 	return nil
 }
 
 // Deprovision removes the resource from the internal tracking map and deletes
 // the corresponding DNS record.
-func (service *Service) Deprovision(resourceName string, resourceContent string) error {
-	// remove from internal map
-	err := service.dnsRecords.Delete(resourceName)
+func (service *Service) Deprovision(resourceName string, resourceContent string, diag *diagnostics.Log) error {
+	// remove from internal map (only this value, other values for the same
+	// name are left alone)
+	_, err := service.dnsRecords.Remove(resourceName, resourceContent)
 	if err != nil {
 		service.logger.Errorf("dns-01 (acme.sh) could not remove resource (%s) from "+
 			"internal map", resourceName)
@@ -52,17 +50,14 @@ func (service *Service) Deprovision(resourceName string, resourceContent string)
 	cmd := service.makeDeleteCommand(resourceName, resourceContent)
 
 	// run script command
-	result, err := cmd.Output()
+	result, err := script_runner.Run(service.shutdownContext, service.timeout, cmd)
+	result.AddToDiagnostics(diag, diagSource, "delete script")
 	if err != nil {
-		// try to get detailed err
-		exitErr, ok := err.(*exec.ExitError)
-		if ok {
-			service.logger.Errorf("acme.sh dns delete script std err: %s", exitErr.Stderr)
-		}
+		service.logger.Errorf("acme.sh dns delete script std err: %s", result.Stderr)
 		service.logger.Errorf("acme.sh dns delete script error: %s", err)
-		return err
+		return fmt.Errorf("dns-01 (acme.sh) delete script failed (%s)", err)
 	}
-	service.logger.Debugf("acme.sh dns delete script output: %s", string(result))
+	service.logger.Debugf("acme.sh dns delete script output: %s", result.Stdout)
 
 	return nil
 }
@@ -2,10 +2,12 @@ package dns01acmesh
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"legocerthub-backend/pkg/datatypes"
 	"os"
 	"os/exec"
+	"time"
 
 	"go.uber.org/zap"
 )
@@ -20,29 +22,49 @@ var (
 	errServiceComponent = errors.New("necessary dns-01 acme.sh component is missing")
 	errNoAcmeShPath     = errors.New("acme.sh path not specified in config")
 	errBashMissing      = errors.New("unable to find bash")
+	errBadTimeout       = errors.New("dns-01 acme.sh timeout must be greater than 0")
 )
 
 // App interface is for connecting to the main app
 type App interface {
 	GetLogger() *zap.SugaredLogger
+	GetShutdownContext() context.Context
 }
 
 // Accounts service struct
 type Service struct {
 	logger          *zap.SugaredLogger
+	shutdownContext context.Context
+	timeout         time.Duration
 	shellPath       string
 	shellScriptPath string
 	dnsHook         string
 	environmentVars []string
-	dnsRecords      *datatypes.SafeMap
+	dnsRecords      *datatypes.SafeValueSets
 }
 
 // Configuration options
 type Config struct {
-	Enable      *bool    `yaml:"enable"`
-	AcmeShPath  *string  `yaml:"acme_sh_path"`
-	Environment []string `yaml:"environment"`
-	DnsHook     string   `yaml:"dns_hook"`
+	Enable         *bool    `yaml:"enable" json:"-"`
+	AcmeShPath     *string  `yaml:"acme_sh_path" json:"acme_sh_path,omitempty"`
+	Environment    []string `yaml:"environment" json:"environment" redact:"env"`
+	DnsHook        string   `yaml:"dns_hook" json:"dns_hook"`
+	TimeoutSeconds *int     `yaml:"timeout_seconds" json:"timeout_seconds,omitempty"`
+}
+
+// DefaultConfig returns a Config with the default values set
+func DefaultConfig() Config {
+	cfg := Config{
+		Enable:         new(bool),
+		AcmeShPath:     new(string),
+		TimeoutSeconds: new(int),
+	}
+
+	*cfg.Enable = false
+	*cfg.AcmeShPath = "./scripts/acme.sh"
+	*cfg.TimeoutSeconds = 60
+
+	return cfg
 }
 
 // NewService creates a new service
@@ -67,6 +89,18 @@ func NewService(app App, config *Config) (*Service, error) {
 		return nil, errServiceComponent
 	}
 
+	// shutdown context
+	service.shutdownContext = app.GetShutdownContext()
+	if service.shutdownContext == nil {
+		return nil, errServiceComponent
+	}
+
+	// script timeout
+	if *config.TimeoutSeconds <= 0 {
+		return nil, errBadTimeout
+	}
+	service.timeout = time.Duration(*config.TimeoutSeconds) * time.Second
+
 	// bash is required
 	service.shellPath, err = exec.LookPath("bash")
 	if err != nil {
@@ -118,7 +152,7 @@ func NewService(app App, config *Config) (*Service, error) {
 	service.environmentVars = config.Environment
 
 	// map to hold current dnsRecords
-	service.dnsRecords = datatypes.NewSafeMap()
+	service.dnsRecords = datatypes.NewSafeValueSets()
 
 	return service, nil
 }
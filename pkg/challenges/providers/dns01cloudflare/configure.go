@@ -10,16 +10,27 @@ import (
 
 // Configuration options
 type Config struct {
-	Enable   *bool `yaml:"enable"`
+	Enable   *bool `yaml:"enable" json:"-"`
 	Accounts []struct {
-		Email        string `yaml:"email"`
-		GlobalApiKey string `yaml:"global_api_key"`
-	} `yaml:"accounts"`
+		Email        string `yaml:"email" json:"email"`
+		GlobalApiKey string `yaml:"global_api_key" json:"global_api_key" redact:"true"`
+	} `yaml:"accounts" json:"accounts"`
 	// TODO: Simplify this to not be nested, however, config version number will need
 	// to change as this will break configs.
 	ApiTokens []struct {
-		APIToken string `yaml:"api_token"`
-	} `yaml:"api_tokens"`
+		APIToken string `yaml:"api_token" json:"api_token" redact:"true"`
+	} `yaml:"api_tokens" json:"api_tokens"`
+}
+
+// DefaultConfig returns a Config with the default values set
+func DefaultConfig() Config {
+	cfg := Config{
+		Enable: new(bool),
+	}
+
+	*cfg.Enable = false
+
+	return cfg
 }
 
 // configureCloudflareAPI configures the service to use the API Tokens
@@ -41,6 +52,7 @@ func (service *Service) configureCloudflareAPI(config *Config) error {
 		}
 
 		// add zones from api
+		service.apis = append(service.apis, apiInstance)
 		err = service.addZonesFromApiInstance(apiInstance)
 		if err != nil {
 			return err
@@ -54,12 +66,13 @@ func (service *Service) configureCloudflareAPI(config *Config) error {
 		// make api for the token
 		apiInstance, err := cloudflare.NewWithAPIToken(config.ApiTokens[i].APIToken)
 		if err != nil {
-			err = fmt.Errorf("failed to create api instance %s (%s)", redactIdentifier(config.Accounts[i].GlobalApiKey), err)
+			err = fmt.Errorf("failed to create api instance %s (%s)", redactIdentifier(config.ApiTokens[i].APIToken), err)
 			service.logger.Error(err)
 			return err
 		}
 
 		// add zones from api
+		service.apis = append(service.apis, apiInstance)
 		err = service.addZonesFromApiInstance(apiInstance)
 		if err != nil {
 			return err
@@ -0,0 +1,53 @@
+package dns01cloudflare
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudflare/cloudflare-go"
+)
+
+// healthCheckTimeout is the max time to wait for each api instance's health check
+const healthCheckTimeout = 30 * time.Second
+
+// HealthCheck verifies each api token and confirms each api instance can still
+// list its zones
+func (service *Service) HealthCheck() error {
+	for _, cfApi := range service.apis {
+		err := healthCheckApi(cfApi)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// healthCheckApi checks a single api instance
+func healthCheckApi(cfApi *cloudflare.API) error {
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+
+	// verify token (global api keys can't be verified this way)
+	if cfApi.APIToken != "" {
+		verify, err := cfApi.VerifyAPIToken(ctx)
+		if err != nil {
+			return fmt.Errorf("api instance %s failed to verify token (%s)", redactedApiIdentifier(cfApi), err)
+		}
+		if verify.Status != "active" {
+			return fmt.Errorf("api instance %s token status is %s", redactedApiIdentifier(cfApi), verify.Status)
+		}
+	}
+
+	// list zones
+	zoneList, err := cfApi.ListZones(ctx)
+	if err != nil {
+		return fmt.Errorf("api instance %s failed to list zones (%s)", redactedApiIdentifier(cfApi), err)
+	}
+	if len(zoneList) == 0 {
+		return fmt.Errorf("api instance %s has no zones", redactedApiIdentifier(cfApi))
+	}
+
+	return nil
+}
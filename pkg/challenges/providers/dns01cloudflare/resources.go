@@ -3,7 +3,7 @@ package dns01cloudflare
 import (
 	"context"
 	"errors"
-	"fmt"
+	"legocerthub-backend/pkg/diagnostics"
 	"strings"
 
 	"github.com/cloudflare/cloudflare-go"
@@ -25,14 +25,10 @@ func newAcmeRecord(resourceName, resourceContent string) cloudflare.DNSRecord {
 
 // Provision adds the resource to the internal tracking map and provisions
 // the corresponding DNS record on Cloudflare.
-func (service *Service) Provision(resourceName string, resourceContent string) error {
-	// add to internal map
-	exists, existingContent := service.dnsRecords.Add(resourceName, resourceContent)
-	// if already exists, but content is different, error
-	if exists && existingContent != resourceContent {
-		return fmt.Errorf("dns-01 (cloudflare) can't add resource (%s), already exists "+
-			"and content does not match", resourceName)
-	}
+func (service *Service) Provision(resourceName string, resourceContent string, diag *diagnostics.Log) error {
+	// add to internal map (multiple values may exist for the same name, e.g.
+	// when a wildcard and its apex are in the same order)
+	_, _ = service.dnsRecords.Add(resourceName, resourceContent)
 
 	// get the relevant zone from known list
 	zone, err := service.getResourceZone(resourceName)
@@ -54,9 +50,10 @@ func (service *Service) Provision(resourceName string, resourceContent string) e
 
 // Deprovision removes the resource from the internal tracking map and deletes
 // the corresponding DNS record on Cloudflare.
-func (service *Service) Deprovision(resourceName string, resourceContent string) error {
-	// remove from internal map
-	err := service.dnsRecords.Delete(resourceName)
+func (service *Service) Deprovision(resourceName string, resourceContent string, diag *diagnostics.Log) error {
+	// remove from internal map (only this value, other values for the same
+	// name are left alone)
+	_, err := service.dnsRecords.Remove(resourceName, resourceContent)
 	if err != nil {
 		service.logger.Errorf("dns-01 (cloudflare) could not remove resource (%s) from "+
 			"internal map", resourceName)
@@ -91,7 +88,7 @@ func (service *Service) Deprovision(resourceName string, resourceContent string)
 	}
 
 	if deleteErr != nil {
-		return err
+		return deleteErr
 	}
 	// remove old DNS record - END
 
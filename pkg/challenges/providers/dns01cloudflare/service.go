@@ -4,6 +4,7 @@ import (
 	"errors"
 	"legocerthub-backend/pkg/datatypes"
 
+	"github.com/cloudflare/cloudflare-go"
 	"go.uber.org/zap"
 )
 
@@ -20,8 +21,9 @@ type App interface {
 // Accounts service struct
 type Service struct {
 	logger           *zap.SugaredLogger
+	apis             []*cloudflare.API
 	knownDomainZones *datatypes.SafeMap
-	dnsRecords       *datatypes.SafeMap
+	dnsRecords       *datatypes.SafeValueSets
 }
 
 // NewService creates a new service
@@ -54,7 +56,7 @@ func NewService(app App, config *Config) (*Service, error) {
 	service.logger.Infof("dns01cloudflare configured domains: %s", service.knownDomainZones.ListKeys())
 
 	// map to hold current dnsRecords
-	service.dnsRecords = datatypes.NewSafeMap()
+	service.dnsRecords = datatypes.NewSafeValueSets()
 
 	return service, nil
 }
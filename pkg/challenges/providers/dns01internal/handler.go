@@ -0,0 +1,110 @@
+package dns01internal
+
+import (
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// acmeChallengeLabel is the label that prefixes all dns-01 record names
+const acmeChallengeLabel = "_acme-challenge"
+
+// ServeDNS answers a dns query. The server is authoritative for its zone and
+// for any _acme-challenge name that is delegated to it. All other names are
+// refused.
+func (service *Service) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
+	m := new(dns.Msg)
+	m.SetReply(r)
+	m.Authoritative = true
+
+	defer func() {
+		err := w.WriteMsg(m)
+		if err != nil {
+			service.logger.Debugf("dns-01 internal failed to write response (%s)", err)
+		}
+	}()
+
+	if len(r.Question) != 1 {
+		m.SetRcode(r, dns.RcodeFormatError)
+		return
+	}
+	q := r.Question[0]
+	name := normalizeName(q.Name)
+
+	// find the apex of the zone the name is in
+	apex := ""
+	if _, inZone := service.zonePrefix(name); inZone {
+		apex = service.zone
+	} else if strings.HasPrefix(name, acmeChallengeLabel+".") {
+		// delegated record name is its own zone
+		apex = name
+	} else {
+		m.SetRcode(r, dns.RcodeRefused)
+		m.Authoritative = false
+		return
+	}
+
+	switch q.Qtype {
+	case dns.TypeTXT:
+		for _, value := range service.txtValues(name) {
+			m.Answer = append(m.Answer, &dns.TXT{
+				Hdr: service.rrHeader(q.Name, dns.TypeTXT),
+				Txt: []string{value},
+			})
+		}
+
+	case dns.TypeSOA:
+		if name == apex {
+			m.Answer = append(m.Answer, service.soa(apex))
+		}
+
+	case dns.TypeNS:
+		if name == apex {
+			m.Answer = append(m.Answer, &dns.NS{
+				Hdr: service.rrHeader(q.Name, dns.TypeNS),
+				Ns:  dns.Fqdn(service.nameServer),
+			})
+		}
+
+	case dns.TypeA, dns.TypeAAAA:
+		if name == service.nameServer {
+			for _, ip := range service.nsAddrs {
+				if ip.To4() != nil && q.Qtype == dns.TypeA {
+					m.Answer = append(m.Answer, &dns.A{Hdr: service.rrHeader(q.Name, dns.TypeA), A: ip})
+				} else if ip.To4() == nil && q.Qtype == dns.TypeAAAA {
+					m.Answer = append(m.Answer, &dns.AAAA{Hdr: service.rrHeader(q.Name, dns.TypeAAAA), AAAA: ip})
+				}
+			}
+		}
+	}
+
+	// no data, include SOA so the empty answer is cached briefly
+	if len(m.Answer) == 0 {
+		m.Ns = append(m.Ns, service.soa(apex))
+	}
+}
+
+// rrHeader returns a resource record header for the name and type
+func (service *Service) rrHeader(name string, rrType uint16) dns.RR_Header {
+	return dns.RR_Header{
+		Name:   dns.Fqdn(name),
+		Rrtype: rrType,
+		Class:  dns.ClassINET,
+		Ttl:    service.ttl,
+	}
+}
+
+// soa returns the SOA record for the specified apex
+func (service *Service) soa(apex string) *dns.SOA {
+	return &dns.SOA{
+		Hdr:     service.rrHeader(apex, dns.TypeSOA),
+		Ns:      dns.Fqdn(service.nameServer),
+		Mbox:    dns.Fqdn("hostmaster." + service.zone),
+		Serial:  uint32(time.Now().Unix()),
+		Refresh: 3600,
+		Retry:   600,
+		Expire:  86400,
+		Minttl:  service.ttl,
+	}
+}
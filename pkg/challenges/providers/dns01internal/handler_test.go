@@ -0,0 +1,110 @@
+package dns01internal
+
+import (
+	"legocerthub-backend/pkg/datatypes"
+	"net"
+	"sort"
+	"testing"
+
+	"github.com/miekg/dns"
+	"go.uber.org/zap"
+)
+
+// startTestServer starts a udp server on a random local port for the service
+func startTestServer(t *testing.T, service *Service) string {
+	packetConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := &dns.Server{PacketConn: packetConn, Handler: service}
+	go func() { _ = srv.ActivateAndServe() }()
+	t.Cleanup(func() { _ = srv.Shutdown() })
+
+	return packetConn.LocalAddr().String()
+}
+
+func query(t *testing.T, addr string, name string, qType uint16) *dns.Msg {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(name), qType)
+
+	resp, _, err := new(dns.Client).Exchange(m, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return resp
+}
+
+func txtAnswers(resp *dns.Msg) []string {
+	values := []string{}
+	for _, rr := range resp.Answer {
+		if txt, ok := rr.(*dns.TXT); ok {
+			values = append(values, txt.Txt...)
+		}
+	}
+	sort.Strings(values)
+
+	return values
+}
+
+func TestDns01Internal_ServeDNS(t *testing.T) {
+	service := &Service{
+		logger:     zap.NewNop().Sugar(),
+		zone:       "acme.example.org",
+		nameServer: "ns.acme.example.org",
+		nsAddrs:    []net.IP{net.ParseIP("192.0.2.1")},
+		ttl:        60,
+		dnsRecords: datatypes.NewSafeValueSets(),
+	}
+	addr := startTestServer(t, service)
+
+	// wildcard and apex in the same order
+	_ = service.Provision("_acme-challenge.www.example.com", "value1", nil)
+	_ = service.Provision("_acme-challenge.www.example.com", "value2", nil)
+
+	// NS delegated name
+	values := txtAnswers(query(t, addr, "_acme-challenge.WWW.example.com", dns.TypeTXT))
+	if len(values) != 2 || values[0] != "value1" || values[1] != "value2" {
+		t.Errorf("delegated name returned %v", values)
+	}
+
+	// CNAME target in zone
+	values = txtAnswers(query(t, addr, "www.example.com.acme.example.org", dns.TypeTXT))
+	if len(values) != 2 {
+		t.Errorf("zone name returned %v", values)
+	}
+
+	// deprovision one value
+	_ = service.Deprovision("_acme-challenge.www.example.com", "value1", nil)
+	values = txtAnswers(query(t, addr, "_acme-challenge.www.example.com", dns.TypeTXT))
+	if len(values) != 1 || values[0] != "value2" {
+		t.Errorf("after deprovision returned %v", values)
+	}
+
+	// soa and ns at zone apex
+	resp := query(t, addr, "acme.example.org", dns.TypeSOA)
+	if len(resp.Answer) != 1 || !resp.Authoritative {
+		t.Errorf("soa query returned %v", resp.Answer)
+	}
+	resp = query(t, addr, "acme.example.org", dns.TypeNS)
+	if len(resp.Answer) != 1 || resp.Answer[0].(*dns.NS).Ns != "ns.acme.example.org." {
+		t.Errorf("ns query returned %v", resp.Answer)
+	}
+	resp = query(t, addr, "ns.acme.example.org", dns.TypeA)
+	if len(resp.Answer) != 1 || !resp.Answer[0].(*dns.A).A.Equal(net.ParseIP("192.0.2.1")) {
+		t.Errorf("ns address query returned %v", resp.Answer)
+	}
+
+	// no data in zone
+	resp = query(t, addr, "other.acme.example.org", dns.TypeTXT)
+	if resp.Rcode != dns.RcodeSuccess || len(resp.Answer) != 0 || len(resp.Ns) != 1 {
+		t.Errorf("no data query returned rcode %d, %v", resp.Rcode, resp.Answer)
+	}
+
+	// outside of zone
+	resp = query(t, addr, "www.example.com", dns.TypeTXT)
+	if resp.Rcode != dns.RcodeRefused {
+		t.Errorf("outside zone query returned rcode %d", resp.Rcode)
+	}
+}
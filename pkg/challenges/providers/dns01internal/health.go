@@ -0,0 +1,37 @@
+package dns01internal
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// HealthCheck queries the dns server for the SOA of its zone to confirm it is
+// answering
+func (service *Service) HealthCheck() error {
+	// an unspecified bind address listens on all addresses, query loopback
+	host, port, err := net.SplitHostPort(service.servAddr)
+	if err != nil {
+		return err
+	}
+	if host == "" || net.ParseIP(host).IsUnspecified() {
+		host = "127.0.0.1"
+	}
+
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(service.zone), dns.TypeSOA)
+
+	client := &dns.Client{Timeout: 10 * time.Second}
+	resp, _, err := client.Exchange(msg, net.JoinHostPort(host, port))
+	if err != nil {
+		return fmt.Errorf("dns-01 challenge server self query failed (%s)", err)
+	}
+
+	if resp.Rcode != dns.RcodeSuccess || len(resp.Answer) == 0 {
+		return fmt.Errorf("dns-01 challenge server self query returned %s", dns.RcodeToString[resp.Rcode])
+	}
+
+	return nil
+}
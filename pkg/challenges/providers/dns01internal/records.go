@@ -0,0 +1,62 @@
+package dns01internal
+
+import (
+	"legocerthub-backend/pkg/diagnostics"
+	"strings"
+)
+
+// Provision adds the TXT record value to the records served by the dns server
+func (service *Service) Provision(resourceName string, resourceContent string, diag *diagnostics.Log) error {
+	// add to internal map (multiple values may exist for the same name, e.g.
+	// when a wildcard and its apex are in the same order)
+	_, _ = service.dnsRecords.Add(normalizeName(resourceName), resourceContent)
+
+	return nil
+}
+
+// Deprovision removes the TXT record value from the records served by the dns
+// server
+func (service *Service) Deprovision(resourceName string, resourceContent string, diag *diagnostics.Log) error {
+	_, err := service.dnsRecords.Remove(normalizeName(resourceName), resourceContent)
+	if err != nil {
+		service.logger.Errorf("dns-01 (internal) could not remove resource (%s) from "+
+			"internal map", resourceName)
+		// do not return
+	}
+
+	return nil
+}
+
+// txtValues returns the TXT values for a queried name. A name is either the
+// record name itself (when the record name is delegated to this server using
+// NS records) or the record name without the _acme-challenge label, inside of
+// the server's zone (when the record name is a CNAME to the name in the zone).
+// For example, _acme-challenge.www.example.com can be CNAME'd to
+// www.example.com.<zone>.
+func (service *Service) txtValues(queryName string) []string {
+	values := service.dnsRecords.Values(queryName)
+	if len(values) > 0 {
+		return values
+	}
+
+	// name in zone that maps to a record name
+	if prefix, inZone := service.zonePrefix(queryName); inZone && prefix != "" {
+		return service.dnsRecords.Values("_acme-challenge." + prefix)
+	}
+
+	return values
+}
+
+// zonePrefix returns the portion of the name before the zone and if the name is
+// in the zone at all
+func (service *Service) zonePrefix(name string) (prefix string, inZone bool) {
+	if name == service.zone {
+		return "", true
+	}
+
+	if strings.HasSuffix(name, "."+service.zone) {
+		return strings.TrimSuffix(name, "."+service.zone), true
+	}
+
+	return "", false
+}
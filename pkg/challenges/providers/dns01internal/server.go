@@ -0,0 +1,70 @@
+package dns01internal
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// startServer starts the udp and tcp dns servers. The listeners are opened before
+// returning so any error (e.g. port in use) is returned.
+func (service *Service) startServer(bindAddress string, port int, ctx context.Context, wg *sync.WaitGroup) (err error) {
+	servAddr := net.JoinHostPort(bindAddress, strconv.Itoa(port))
+	service.servAddr = servAddr
+
+	packetConn, err := net.ListenPacket("udp", servAddr)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", servAddr)
+	if err != nil {
+		_ = packetConn.Close()
+		return err
+	}
+
+	servers := []*dns.Server{
+		{PacketConn: packetConn, Handler: service},
+		{Listener: listener, Handler: service},
+	}
+
+	// launch dns servers
+	service.logger.Infof("starting dns-01 challenge server on %s for zone %s.", servAddr, service.zone)
+	if port != 53 {
+		service.logger.Warnf("dns-01 challenge server is not running on port 53; internet "+
+			"facing port 53 (udp and tcp) must be proxied to port %d to function.", port)
+	}
+
+	for i := range servers {
+		srv := servers[i]
+		wg.Add(1)
+
+		go func() {
+			err := srv.ActivateAndServe()
+			if err != nil {
+				service.logger.Errorf("dns-01 challenge server error (%s)", err)
+			}
+			wg.Done()
+		}()
+
+		// monitor shutdown context
+		go func() {
+			<-ctx.Done()
+
+			maxShutdownTime := 30 * time.Second
+			ctx, cancel := context.WithTimeout(context.Background(), maxShutdownTime)
+			defer cancel()
+
+			err := srv.ShutdownContext(ctx)
+			if err != nil {
+				service.logger.Errorf("error shutting down dns-01 challenge server")
+			}
+		}()
+	}
+
+	return nil
+}
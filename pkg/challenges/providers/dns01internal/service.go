@@ -0,0 +1,107 @@
+package dns01internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"legocerthub-backend/pkg/datatypes"
+	"net"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+var (
+	errServiceComponent = errors.New("necessary dns-01 internal challenge service component is missing")
+	errConfigComponent  = errors.New("necessary dns-01 internal config option missing")
+	errZoneBad          = errors.New("dns-01 internal zone is not valid")
+)
+
+// App interface is for connecting to the main app
+type App interface {
+	GetLogger() *zap.SugaredLogger
+	GetShutdownContext() context.Context
+	GetShutdownWaitGroup() *sync.WaitGroup
+}
+
+// Accounts service struct
+type Service struct {
+	logger     *zap.SugaredLogger
+	servAddr   string
+	zone       string
+	nameServer string
+	nsAddrs    []net.IP
+	ttl        uint32
+	dnsRecords *datatypes.SafeValueSets
+}
+
+// Configuration options
+type Config struct {
+	Enable              *bool    `yaml:"enable"`
+	BindAddress         *string  `yaml:"bind_address"`
+	Port                *int     `yaml:"port"`
+	Zone                *string  `yaml:"zone"`
+	NameServer          *string  `yaml:"name_server"`
+	NameServerAddresses []string `yaml:"name_server_addresses"`
+	Ttl                 *int     `yaml:"ttl"`
+}
+
+// NewService creates a new service
+func NewService(app App, config *Config) (*Service, error) {
+	// if disabled, return nil and no error
+	if !*config.Enable {
+		return nil, nil
+	}
+
+	service := new(Service)
+
+	// logger
+	service.logger = app.GetLogger()
+	if service.logger == nil {
+		return nil, errServiceComponent
+	}
+
+	// zone this server is authoritative for
+	if config.Zone == nil || config.BindAddress == nil || config.Port == nil || config.Ttl == nil {
+		return nil, errConfigComponent
+	}
+	service.zone = normalizeName(*config.Zone)
+	if service.zone == "" || !strings.Contains(service.zone, ".") {
+		return nil, errZoneBad
+	}
+
+	// name server (defaults to ns.zone)
+	service.nameServer = "ns." + service.zone
+	if config.NameServer != nil && *config.NameServer != "" {
+		service.nameServer = normalizeName(*config.NameServer)
+	}
+
+	// addresses of the name server (only used if the name server is in the zone)
+	for _, addr := range config.NameServerAddresses {
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			return nil, fmt.Errorf("dns-01 internal name server address %s is not valid", addr)
+		}
+		service.nsAddrs = append(service.nsAddrs, ip)
+	}
+
+	// ttl of records served
+	service.ttl = uint32(*config.Ttl)
+
+	// values currently in use for each record
+	service.dnsRecords = datatypes.NewSafeValueSets()
+
+	// start dns server
+	err := service.startServer(*config.BindAddress, *config.Port, app.GetShutdownContext(), app.GetShutdownWaitGroup())
+	if err != nil {
+		return nil, err
+	}
+
+	return service, nil
+}
+
+// normalizeName returns the dns name in lowercase without a trailing dot
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))
+}
@@ -1,50 +1,103 @@
 package dns01manual
 
 import (
-	"os"
-	"os/exec"
+	"encoding/json"
+	"legocerthub-backend/pkg/challenges/providers/script_runner"
 	"strings"
 )
 
-// makeCreateCommand creates the command to make a dns record
-func (service *Service) makeCreateCommand(resourceName string, resourceContent string) *exec.Cmd {
-	return service.makeCommand(resourceName, resourceContent, false)
+// script actions
+const (
+	actionCreate = "create"
+	actionDelete = "delete"
+)
+
+// scriptRequest is the json document sent to v2 scripts on stdin
+type scriptRequest struct {
+	Version    int    `json:"version"`
+	Action     string `json:"action"`
+	Zone       string `json:"zone"`
+	RecordName string `json:"record_name"`
+	RecordType string `json:"record_type"`
+	Value      string `json:"value"`
+	Ttl        int    `json:"ttl"`
+	RecordId   string `json:"record_id,omitempty"`
 }
 
-// makeDeleteCommand creates the command to delete a dns record
-func (service *Service) makeDeleteCommand(resourceName string, resourceContent string) *exec.Cmd {
-	return service.makeCommand(resourceName, resourceContent, true)
+// scriptResponse is the (optional) json document v2 scripts may write to stdout
+type scriptResponse struct {
+	RecordId string `json:"record_id"`
 }
 
-// makeCommand makes a command to create or delete a dns record
-func (service *Service) makeCommand(resourceName string, resourceContent string, delete bool) *exec.Cmd {
+// makeCommand makes a command to create or delete a dns record. recordId is only
+// used by the v2 protocol when deleting a record.
+func (service *Service) makeCommand(resourceName string, resourceContent string, recordId string, action string) (script_runner.Command, error) {
 	// create or delete?
 	scriptPath := service.createScriptPath
-	if delete {
+	if action == actionDelete {
 		scriptPath = service.deleteScriptPath
 	}
 
-	// make args for command
-	// 0 - script name (e.g. /path/to/script.sh)
-	args := []string{scriptPath}
+	zone := service.findZone(resourceName)
+
+	cmd := script_runner.Command{
+		Name:        service.shellPath,
+		Environment: service.environmentVars,
+	}
 
-	// 1 - Domain (2nd Level + TLD)
-	domainParts := strings.Split(resourceName, ".")
-	secondAndTLD := domainParts[len(domainParts)-2] + "." + domainParts[len(domainParts)-1]
-	args = append(args, secondAndTLD)
+	switch service.protocolVersion {
+	case protocolV2:
+		// args are only the script, all info is sent on stdin
+		cmd.Args = []string{scriptPath}
 
-	// 2 - RecordName (e.g. _acme-challenge.www.example.com)
-	args = append(args, resourceName)
+		stdin, err := json.Marshal(scriptRequest{
+			Version:    protocolV2,
+			Action:     action,
+			Zone:       zone,
+			RecordName: resourceName,
+			RecordType: "TXT",
+			Value:      resourceContent,
+			Ttl:        service.ttl,
+			RecordId:   recordId,
+		})
+		if err != nil {
+			return script_runner.Command{}, err
+		}
+		cmd.Stdin = stdin
 
-	// 3 - RecordValue (e.g. XKrxpRBosdIKFzxW_CT3KLZNf6q0HG9i01zxXp5CPBs)
-	args = append(args, resourceContent)
+	default:
+		// make args for command
+		// 0 - script name (e.g. /path/to/script.sh)
+		// 1 - Zone (e.g. example.com)
+		// 2 - RecordName (e.g. _acme-challenge.www.example.com)
+		// 3 - RecordValue (e.g. XKrxpRBosdIKFzxW_CT3KLZNf6q0HG9i01zxXp5CPBs)
+		cmd.Args = []string{scriptPath, zone, resourceName, resourceContent}
+	}
 
-	// make command
-	cmd := exec.Command(service.shellPath, args...)
+	return cmd, nil
+}
 
-	// set command environment
-	cmd.Env = os.Environ()
-	cmd.Env = append(cmd.Env, service.environmentVars...)
+// parseRecordId returns the record_id from a v2 script's stdout. stdout may
+// be entirely the json response, or the json response may be the last line
+// of output. If no record_id is found, an empty string is returned.
+func parseRecordId(stdout string) string {
+	if stdout == "" {
+		return ""
+	}
+
+	candidates := []string{stdout}
+	lines := strings.Split(stdout, "\n")
+	if len(lines) > 1 {
+		candidates = append(candidates, strings.TrimSpace(lines[len(lines)-1]))
+	}
+
+	for _, candidate := range candidates {
+		var response scriptResponse
+		err := json.Unmarshal([]byte(candidate), &response)
+		if err == nil {
+			return response.RecordId
+		}
+	}
 
-	return cmd
+	return ""
 }
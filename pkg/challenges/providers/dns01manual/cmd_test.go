@@ -0,0 +1,104 @@
+package dns01manual
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func testService(protocolVersion int) *Service {
+	return &Service{
+		shellPath:        "/bin/sh",
+		environmentVars:  []string{"TEST_VAR=1"},
+		createScriptPath: "/scripts/create.sh",
+		deleteScriptPath: "/scripts/delete.sh",
+		protocolVersion:  protocolVersion,
+		ttl:              120,
+		zones:            []string{"example.com", "sub.example.com"},
+	}
+}
+
+func TestMakeCommand_V1(t *testing.T) {
+	service := testService(protocolV1)
+
+	cmd, err := service.makeCommand("_acme-challenge.www.sub.example.com", "token-value", "some-id", actionDelete)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedArgs := []string{"/scripts/delete.sh", "sub.example.com", "_acme-challenge.www.sub.example.com", "token-value"}
+	if !reflect.DeepEqual(cmd.Args, expectedArgs) {
+		t.Errorf("args %v, expected %v", cmd.Args, expectedArgs)
+	}
+	if cmd.Stdin != nil {
+		t.Errorf("v1 command should not have stdin, got '%s'", cmd.Stdin)
+	}
+	if cmd.Name != service.shellPath || !reflect.DeepEqual(cmd.Environment, service.environmentVars) {
+		t.Errorf("command name or environment not set from service")
+	}
+}
+
+func TestMakeCommand_V2(t *testing.T) {
+	service := testService(protocolV2)
+
+	tests := []struct {
+		action     string
+		recordId   string
+		scriptPath string
+	}{
+		{actionCreate, "", "/scripts/create.sh"},
+		{actionDelete, "some-id", "/scripts/delete.sh"},
+	}
+
+	for _, test := range tests {
+		cmd, err := service.makeCommand("_acme-challenge.example.com", "token-value", test.recordId, test.action)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(cmd.Args, []string{test.scriptPath}) {
+			t.Errorf("%s: args %v, expected only the script", test.action, cmd.Args)
+		}
+
+		var request scriptRequest
+		err = json.Unmarshal(cmd.Stdin, &request)
+		if err != nil {
+			t.Fatalf("%s: stdin is not valid json: %s", test.action, err)
+		}
+
+		expected := scriptRequest{
+			Version:    protocolV2,
+			Action:     test.action,
+			Zone:       "example.com",
+			RecordName: "_acme-challenge.example.com",
+			RecordType: "TXT",
+			Value:      "token-value",
+			Ttl:        120,
+			RecordId:   test.recordId,
+		}
+		if request != expected {
+			t.Errorf("%s: stdin %+v, expected %+v", test.action, request, expected)
+		}
+	}
+}
+
+func TestParseRecordId(t *testing.T) {
+	tests := []struct {
+		stdout   string
+		recordId string
+	}{
+		{"", ""},
+		{`{"record_id":"abc123"}`, "abc123"},
+		{"creating record\ndone\n" + `{"record_id":"abc123"}`, "abc123"},
+		{"creating record\n" + `{"record_id":"abc123"}` + "\ndone", ""},
+		{"not json", ""},
+		{`{"other":"value"}`, ""},
+	}
+
+	for _, test := range tests {
+		recordId := parseRecordId(test.stdout)
+		if recordId != test.recordId {
+			t.Errorf("stdout '%s': record id '%s', expected '%s'", test.stdout, recordId, test.recordId)
+		}
+	}
+}
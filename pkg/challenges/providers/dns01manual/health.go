@@ -0,0 +1,11 @@
+package dns01manual
+
+import (
+	"legocerthub-backend/pkg/challenges/providers/script_runner"
+)
+
+// HealthCheck confirms the shell is executable and the create and delete scripts
+// still exist
+func (service *Service) HealthCheck() error {
+	return script_runner.CheckFiles(service.shellPath, service.createScriptPath, service.deleteScriptPath)
+}
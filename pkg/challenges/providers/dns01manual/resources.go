@@ -2,67 +2,90 @@ package dns01manual
 
 import (
 	"fmt"
-	"os/exec"
+	"legocerthub-backend/pkg/challenges/providers/script_runner"
+	"legocerthub-backend/pkg/diagnostics"
 )
 
+// diagnostics source name
+const diagSource = "dns-01-manual"
+
+// recordKey is the key used to track a specific record value's id
+func recordKey(resourceName string, resourceContent string) string {
+	return resourceName + " " + resourceContent
+}
+
 // Provision adds the resource to the internal tracking map and provisions
-// the corresponding DNS record on Cloudflare.
-func (service *Service) Provision(resourceName string, resourceContent string) error {
-	// add to internal map
-	exists, existingContent := service.dnsRecords.Add(resourceName, resourceContent)
-	// if already exists, but content is different, error
-	if exists && existingContent != resourceContent {
-		return fmt.Errorf("dns-01 (manual script) can't add resource (%s), already exists "+
-			"and content does not match", resourceName)
-	}
+// the corresponding DNS record using the create script.
+func (service *Service) Provision(resourceName string, resourceContent string, diag *diagnostics.Log) error {
+	// add to internal map (multiple values may exist for the same name, e.g.
+	// when a wildcard and its apex are in the same order)
+	_, _ = service.dnsRecords.Add(resourceName, resourceContent)
 
 	// run create script
 	// script command
-	cmd := service.makeCreateCommand(resourceName, resourceContent)
+	cmd, err := service.makeCommand(resourceName, resourceContent, "", actionCreate)
+	if err != nil {
+		return err
+	}
 
 	// run script command
-	result, err := cmd.Output()
+	result, err := script_runner.Run(service.shutdownContext, service.timeout, cmd)
+	result.AddToDiagnostics(diag, diagSource, "create script")
 	if err != nil {
-		// try to get detailed err
-		exitErr, ok := err.(*exec.ExitError)
-		if ok {
-			service.logger.Errorf("dns create script std err: %s", exitErr.Stderr)
-		}
+		service.logger.Errorf("dns create script std err: %s", result.Stderr)
 		service.logger.Errorf("dns create script error: %s", err)
-		return err
+		return fmt.Errorf("dns-01 (manual script) create script failed (%s)", err)
+	}
+	service.logger.Debugf("dns create script output: %s", result.Stdout)
+
+	// save record id, if the script returned one
+	if service.protocolVersion == protocolV2 {
+		recordId := parseRecordId(result.Stdout)
+		if recordId != "" {
+			_, _ = service.recordIds.Add(recordKey(resourceName, resourceContent), recordId)
+		}
 	}
-	service.logger.Debugf("dns create script output: %s", string(result))
 
 	return nil
 }
 
 // Deprovision removes the resource from the internal tracking map and deletes
-// the corresponding DNS record on Cloudflare.
-func (service *Service) Deprovision(resourceName string, resourceContent string) error {
-	// remove from internal map
-	err := service.dnsRecords.Delete(resourceName)
+// the corresponding DNS record using the delete script.
+func (service *Service) Deprovision(resourceName string, resourceContent string, diag *diagnostics.Log) error {
+	// remove from internal map (only this value, other values for the same
+	// name are left alone)
+	_, err := service.dnsRecords.Remove(resourceName, resourceContent)
 	if err != nil {
 		service.logger.Errorf("dns-01 (manual script) could not remove resource (%s) from "+
 			"internal map", resourceName)
 		// do not return
 	}
 
+	// get record id (if there is one)
+	recordId := ""
+	key := recordKey(resourceName, resourceContent)
+	id, err := service.recordIds.Read(key)
+	if err == nil {
+		recordId, _ = id.(string)
+		_ = service.recordIds.Delete(key)
+	}
+
 	// run delete script
 	// script command
-	cmd := service.makeDeleteCommand(resourceName, resourceContent)
+	cmd, err := service.makeCommand(resourceName, resourceContent, recordId, actionDelete)
+	if err != nil {
+		return err
+	}
 
 	// run script command
-	result, err := cmd.Output()
+	result, err := script_runner.Run(service.shutdownContext, service.timeout, cmd)
+	result.AddToDiagnostics(diag, diagSource, "delete script")
 	if err != nil {
-		// try to get detailed err
-		exitErr, ok := err.(*exec.ExitError)
-		if ok {
-			service.logger.Errorf("dns delete script std err: %s", exitErr.Stderr)
-		}
+		service.logger.Errorf("dns delete script std err: %s", result.Stderr)
 		service.logger.Errorf("dns delete script error: %s", err)
-		return err
+		return fmt.Errorf("dns-01 (manual script) delete script failed (%s)", err)
 	}
-	service.logger.Debugf("dns delete script output: %s", string(result))
+	service.logger.Debugf("dns delete script output: %s", result.Stdout)
 
 	return nil
 }
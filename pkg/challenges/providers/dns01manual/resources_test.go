@@ -0,0 +1,129 @@
+package dns01manual
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// testApp satisfies App for testing
+type testApp struct{}
+
+func (testApp) GetLogger() *zap.SugaredLogger       { return zap.NewNop().Sugar() }
+func (testApp) GetShutdownContext() context.Context { return context.Background() }
+
+// scripts that maintain a file of records, one "name value" per line
+const (
+	testCreateScript = `echo "$2 $3" >> "$RECORDS_FILE"`
+	testDeleteScript = `grep -v -x -F "$2 $3" "$RECORDS_FILE" > "$RECORDS_FILE.tmp"; mv "$RECORDS_FILE.tmp" "$RECORDS_FILE"`
+)
+
+// test record name and the two values provisioned to it
+const (
+	testResourceName = "_acme-challenge.example.com"
+	testApexValue    = "apex_XKrxpRBosdIKFzxW_CT3KLZNf6q0HG9i01zxXp5CPBs"
+	testWildValue    = "wild_Gf2Nd4Hc8KsPq0Yz3aBvL7mWxT1uRjE9oIe5dC6hQkA"
+)
+
+// readRecords returns the sorted lines of the records file
+func readRecords(t *testing.T, path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read records file (%s)", err)
+	}
+
+	records := []string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			records = append(records, line)
+		}
+	}
+	sort.Strings(records)
+
+	return records
+}
+
+func TestManual_WildcardAndApex(t *testing.T) {
+	// test scripts are bash
+	if _, err := exec.LookPath("powershell.exe"); err == nil {
+		t.Skip("powershell would be used for scripts")
+	}
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not available")
+	}
+
+	dir := t.TempDir()
+	recordsFile := filepath.Join(dir, "records")
+	createScript := filepath.Join(dir, "create.sh")
+	deleteScript := filepath.Join(dir, "delete.sh")
+
+	err := os.WriteFile(recordsFile, []byte{}, 0600)
+	if err == nil {
+		err = os.WriteFile(createScript, []byte(testCreateScript), 0700)
+	}
+	if err == nil {
+		err = os.WriteFile(deleteScript, []byte(testDeleteScript), 0700)
+	}
+	if err != nil {
+		t.Fatalf("failed to write test files (%s)", err)
+	}
+
+	enable := true
+	protocol := protocolV1
+	timeout := 10
+	ttl := 60
+	service, err := NewService(testApp{}, &Config{
+		Enable:          &enable,
+		Environment:     []string{"RECORDS_FILE=" + recordsFile},
+		CreateScript:    createScript,
+		DeleteScript:    deleteScript,
+		ProtocolVersion: &protocol,
+		TimeoutSeconds:  &timeout,
+		Ttl:             &ttl,
+		Zones:           []string{"example.com"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create service (%s)", err)
+	}
+
+	// provision both concurrently (as FulfillAuths would)
+	var wg sync.WaitGroup
+	for _, value := range []string{testApexValue, testWildValue} {
+		wg.Add(1)
+		go func(value string) {
+			defer wg.Done()
+			err := service.Provision(testResourceName, value, nil)
+			if err != nil {
+				t.Errorf("failed to provision '%s' (%s)", value, err)
+			}
+		}(value)
+	}
+	wg.Wait()
+
+	records := readRecords(t, recordsFile)
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records after provisioning, got: %v", records)
+	}
+
+	// deprovision apex, wildcard must remain
+	err = service.Deprovision(testResourceName, testApexValue, nil)
+	if err != nil {
+		t.Errorf("failed to deprovision apex value (%s)", err)
+	}
+
+	records = readRecords(t, recordsFile)
+	if len(records) != 1 || records[0] != testResourceName+" "+testWildValue {
+		t.Errorf("deprovisioning apex value did not leave only wildcard value (records: %v)", records)
+	}
+
+	if values := service.dnsRecords.Values(testResourceName); len(values) != 1 || values[0] != testWildValue {
+		t.Errorf("tracked values incorrect after deprovisioning apex (values: %v)", values)
+	}
+}
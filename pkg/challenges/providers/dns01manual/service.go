@@ -1,40 +1,83 @@
 package dns01manual
 
 import (
+	"context"
 	"errors"
 	"legocerthub-backend/pkg/datatypes"
 	"os"
 	"os/exec"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
 )
 
 var (
-	errServiceComponent = errors.New("necessary dns-01 manual script component is missing")
-	errScriptIsDir      = errors.New("dns-01 manual script is a path not a file")
+	errServiceComponent   = errors.New("necessary dns-01 manual script component is missing")
+	errScriptIsDir        = errors.New("dns-01 manual script is a path not a file")
+	errBadProtocolVersion = errors.New("dns-01 manual script protocol version must be 1 or 2")
+	errBadTimeout         = errors.New("dns-01 manual script timeout must be greater than 0")
+)
+
+// script protocol versions
+const (
+	// protocolV1 passes the zone, record name, and record value as positional args
+	protocolV1 = 1
+	// protocolV2 passes a json document on stdin and optionally reads a json
+	// document from stdout
+	protocolV2 = 2
 )
 
 // App interface is for connecting to the main app
 type App interface {
 	GetLogger() *zap.SugaredLogger
+	GetShutdownContext() context.Context
 }
 
 // Accounts service struct
 type Service struct {
 	logger           *zap.SugaredLogger
+	shutdownContext  context.Context
 	shellPath        string
 	environmentVars  []string
 	createScriptPath string
 	deleteScriptPath string
-	dnsRecords       *datatypes.SafeMap
+	protocolVersion  int
+	timeout          time.Duration
+	ttl              int
+	zones            []string
+	dnsRecords       *datatypes.SafeValueSets
+	recordIds        *datatypes.SafeMap
 }
 
 // Configuration options
 type Config struct {
-	Enable       *bool    `yaml:"enable"`
-	Environment  []string `yaml:"environment"`
-	CreateScript string   `yaml:"create_script"`
-	DeleteScript string   `yaml:"delete_script"`
+	Enable          *bool    `yaml:"enable" json:"-"`
+	Environment     []string `yaml:"environment" json:"environment" redact:"env"`
+	CreateScript    string   `yaml:"create_script" json:"create_script"`
+	DeleteScript    string   `yaml:"delete_script" json:"delete_script"`
+	ProtocolVersion *int     `yaml:"protocol_version" json:"protocol_version,omitempty"`
+	TimeoutSeconds  *int     `yaml:"timeout_seconds" json:"timeout_seconds,omitempty"`
+	Ttl             *int     `yaml:"ttl" json:"ttl,omitempty"`
+	Zones           []string `yaml:"zones" json:"zones"`
+}
+
+// DefaultConfig returns a Config with the default values set
+func DefaultConfig() Config {
+	cfg := Config{
+		Enable:          new(bool),
+		ProtocolVersion: new(int),
+		TimeoutSeconds:  new(int),
+		Ttl:             new(int),
+		// script paths don't have a default
+	}
+
+	*cfg.Enable = false
+	*cfg.ProtocolVersion = protocolV1
+	*cfg.TimeoutSeconds = 60
+	*cfg.Ttl = 60
+
+	return cfg
 }
 
 // NewService creates a new service
@@ -54,6 +97,32 @@ func NewService(app App, config *Config) (*Service, error) {
 		return nil, errServiceComponent
 	}
 
+	// shutdown context
+	service.shutdownContext = app.GetShutdownContext()
+	if service.shutdownContext == nil {
+		return nil, errServiceComponent
+	}
+
+	// script protocol
+	service.protocolVersion = *config.ProtocolVersion
+	if service.protocolVersion != protocolV1 && service.protocolVersion != protocolV2 {
+		return nil, errBadProtocolVersion
+	}
+
+	// script timeout
+	if *config.TimeoutSeconds <= 0 {
+		return nil, errBadTimeout
+	}
+	service.timeout = time.Duration(*config.TimeoutSeconds) * time.Second
+
+	// record ttl (only sent with v2 protocol)
+	service.ttl = *config.Ttl
+
+	// zones (optional, otherwise zone is found using dns)
+	for _, zone := range config.Zones {
+		service.zones = append(service.zones, strings.ToLower(strings.Trim(zone, ".")))
+	}
+
 	// determine shell (os dependent)
 	// powershell
 	service.shellPath, err = exec.LookPath("powershell.exe")
@@ -103,7 +172,10 @@ func NewService(app App, config *Config) (*Service, error) {
 	service.deleteScriptPath = config.DeleteScript
 
 	// map to hold current dnsRecords
-	service.dnsRecords = datatypes.NewSafeMap()
+	service.dnsRecords = datatypes.NewSafeValueSets()
+
+	// map to hold record ids returned by v2 create scripts
+	service.recordIds = datatypes.NewSafeMap()
 
 	return service, nil
 }
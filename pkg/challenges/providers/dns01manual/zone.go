@@ -0,0 +1,66 @@
+package dns01manual
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+)
+
+// zoneLookupTimeout is the max time to spend looking up a resource's zone
+const zoneLookupTimeout = 10 * time.Second
+
+// findZone returns the dns zone that contains resourceName. If zones were
+// specified in the config, the longest configured zone that contains the
+// resource is used. Otherwise, dns is queried for NS records starting at the
+// resource name and walking toward the root. The first name that has NS
+// records is the apex of the zone.
+func (service *Service) findZone(resourceName string) string {
+	name := strings.ToLower(strings.Trim(resourceName, "."))
+
+	// check configured zones
+	if len(service.zones) > 0 {
+		bestZone := ""
+		for _, zone := range service.zones {
+			if (name == zone || strings.HasSuffix(name, "."+zone)) && len(zone) > len(bestZone) {
+				bestZone = zone
+			}
+		}
+
+		if bestZone != "" {
+			return bestZone
+		}
+
+		service.logger.Warnf("dns-01 (manual script) resource %s is not in any configured zone, "+
+			"trying dns lookup", resourceName)
+	}
+
+	// query dns
+	ctx, cancel := context.WithTimeout(service.shutdownContext, zoneLookupTimeout)
+	defer cancel()
+
+	labels := strings.Split(name, ".")
+	for i := 0; i < len(labels)-1; i++ {
+		candidate := strings.Join(labels[i:], ".")
+
+		nameServers, err := net.DefaultResolver.LookupNS(ctx, candidate)
+		if err == nil && len(nameServers) > 0 {
+			return candidate
+		}
+
+		// stop if timed out or shutting down
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	// fallback to 2nd level + TLD
+	fallback := name
+	if len(labels) >= 2 {
+		fallback = strings.Join(labels[len(labels)-2:], ".")
+	}
+	service.logger.Warnf("dns-01 (manual script) could not determine zone for %s, using %s",
+		resourceName, fallback)
+
+	return fallback
+}
@@ -0,0 +1,63 @@
+package dnspersist01
+
+import (
+	"errors"
+	"legocerthub-backend/pkg/diagnostics"
+
+	"go.uber.org/zap"
+)
+
+// diagnostics source name
+const diagSource = "dns-persist-01"
+
+var errServiceComponent = errors.New("necessary dns-persist-01 component is missing")
+
+// App interface is for connecting to the main app
+type App interface {
+	GetLogger() *zap.SugaredLogger
+}
+
+// Service struct
+// dns-persist-01 doesn't create or delete any records. The account's persistent
+// record is created once (outside of LeGo) and reused by every order, so no dns
+// credentials are needed to renew certificates.
+type Service struct {
+	logger *zap.SugaredLogger
+}
+
+// Configuration options
+type Config struct {
+	Enable *bool `yaml:"enable"`
+}
+
+// NewService creates a new service
+func NewService(app App, config *Config) (*Service, error) {
+	// if disabled, return nil and no error
+	if !*config.Enable {
+		return nil, nil
+	}
+
+	service := new(Service)
+
+	// logger
+	service.logger = app.GetLogger()
+	if service.logger == nil {
+		return nil, errServiceComponent
+	}
+
+	return service, nil
+}
+
+// Provision does not create a record, the persistent record must already exist. The
+// required record is logged (and added to diag) so it can be created if it is missing.
+func (service *Service) Provision(resourceName string, resourceContent string, diag *diagnostics.Log) error {
+	service.logger.Debugf("dns-persist-01 using persistent record %s TXT \"%s\"", resourceName, resourceContent)
+	diag.Addf(diagSource, "requires persistent record %s TXT \"%s\"", resourceName, resourceContent)
+
+	return nil
+}
+
+// Deprovision does nothing, the persistent record is reused by future orders.
+func (service *Service) Deprovision(resourceName string, resourceContent string, diag *diagnostics.Log) error {
+	return nil
+}
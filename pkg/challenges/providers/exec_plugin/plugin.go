@@ -0,0 +1,100 @@
+package exec_plugin
+
+import (
+	"fmt"
+	"legocerthub-backend/pkg/diagnostics"
+	"regexp"
+)
+
+// nameRegex is the form a plugin's name must be in
+var nameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// challenge types plugins may use
+const (
+	challengeTypeHttp01 = "http-01"
+	challengeTypeDns01  = "dns-01"
+)
+
+// Plugin is a challenge provider that is an external executable
+type Plugin struct {
+	client       *rpcClient
+	capabilities Capabilities
+}
+
+// Capabilities returns the capabilities the plugin reported when it was loaded
+func (plugin *Plugin) Capabilities() Capabilities {
+	return plugin.capabilities
+}
+
+// loadCapabilities calls the plugin's capabilities method and validates the result
+func (plugin *Plugin) loadCapabilities() error {
+	err := plugin.client.call(methodCapabilities, capabilitiesParams{ProtocolVersion: ProtocolVersion}, &plugin.capabilities)
+	if err != nil {
+		return err
+	}
+
+	caps := plugin.capabilities
+	if caps.ProtocolVersion != ProtocolVersion {
+		return fmt.Errorf("plugin protocol version %d is not supported", caps.ProtocolVersion)
+	}
+	if !nameRegex.MatchString(caps.Name) {
+		return fmt.Errorf("plugin name '%s' is not valid", caps.Name)
+	}
+	if caps.ChallengeType != challengeTypeHttp01 && caps.ChallengeType != challengeTypeDns01 {
+		return fmt.Errorf("plugin challenge type '%s' is not supported", caps.ChallengeType)
+	}
+	if caps.DisplayName == "" {
+		plugin.capabilities.DisplayName = caps.Name
+	}
+
+	return nil
+}
+
+// Provision calls the plugin's provision method
+func (plugin *Plugin) Provision(resourceName string, resourceContent string, diag *diagnostics.Log) error {
+	return plugin.resourceCall(methodProvision, resourceName, resourceContent, diag)
+}
+
+// Deprovision calls the plugin's deprovision method
+func (plugin *Plugin) Deprovision(resourceName string, resourceContent string, diag *diagnostics.Log) error {
+	return plugin.resourceCall(methodDeprovision, resourceName, resourceContent, diag)
+}
+
+// resourceCall calls a provision or deprovision method and adds any diagnostics
+// from the plugin to diag
+func (plugin *Plugin) resourceCall(method string, resourceName string, resourceContent string, diag *diagnostics.Log) error {
+	diagSource := "plugin-" + plugin.capabilities.Name
+
+	var result resourceResult
+	err := plugin.client.call(method, resourceParams{
+		ResourceName:    resourceName,
+		ResourceContent: resourceContent,
+	}, &result)
+
+	for _, message := range result.Diagnostics {
+		diag.Add(diagSource, message)
+	}
+
+	if err != nil {
+		diag.Addf(diagSource, "%s of %s failed (%s)", method, resourceName, err)
+		return err
+	}
+
+	return nil
+}
+
+// HealthCheck calls the plugin's health method. An error is returned if the plugin
+// can't be reached or it reports it is not healthy.
+func (plugin *Plugin) HealthCheck() error {
+	var result healthResult
+	err := plugin.client.call(methodHealth, struct{}{}, &result)
+	if err != nil {
+		return err
+	}
+
+	if !result.Healthy {
+		return fmt.Errorf("plugin %s is not healthy (%s)", plugin.capabilities.Name, result.Message)
+	}
+
+	return nil
+}
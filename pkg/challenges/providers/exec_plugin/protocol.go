@@ -0,0 +1,95 @@
+package exec_plugin
+
+import "encoding/json"
+
+// Plugin protocol (version 1)
+//
+// A plugin is an executable in the plugins directory. LeGo starts each plugin
+// once and keeps it running. Requests are sent to the plugin's stdin and
+// responses are read from its stdout as JSON-RPC 2.0 messages, one message
+// per line. Each request has a unique id and the plugin must include the same
+// id in its response. Responses may be sent in any order. Anything the plugin
+// writes to stderr is logged by LeGo at the debug level. When LeGo closes the
+// plugin's stdin, the plugin should exit. If the plugin exits on its own, it
+// is started again when it is next needed.
+//
+// Methods:
+//   capabilities - params: {"protocol_version": 1}
+//                  result: {"protocol_version": 1, "name": "my-dns",
+//                           "display_name": "DNS My System", "challenge_type": "dns-01"}
+//   provision    - params: {"resource_name": "...", "resource_content": "..."}
+//                  result: {"diagnostics": ["optional", "messages"]} (or null)
+//   deprovision  - same as provision
+//   health       - params: {}
+//                  result: {"healthy": true, "message": "optional detail"}
+//
+// For dns-01, resource_name is the full record name (e.g. _acme-challenge.example.com)
+// and resource_content is the TXT value. For http-01, resource_name is the token
+// and resource_content is the key authorization to serve at
+// /.well-known/acme-challenge/<token>.
+//
+// A failure is returned as a JSON-RPC error response, e.g.
+//   {"jsonrpc": "2.0", "id": 3, "error": {"code": 1, "message": "zone not found"}}
+
+// ProtocolVersion is the plugin protocol version LeGo speaks
+const ProtocolVersion = 1
+
+// rpc method names
+const (
+	methodCapabilities = "capabilities"
+	methodProvision    = "provision"
+	methodDeprovision  = "deprovision"
+	methodHealth       = "health"
+)
+
+// rpcRequest is a JSON-RPC 2.0 request
+type rpcRequest struct {
+	JsonRpc string      `json:"jsonrpc"`
+	ID      int         `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+// rpcError is a JSON-RPC 2.0 error
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// rpcResponse is a JSON-RPC 2.0 response
+type rpcResponse struct {
+	JsonRpc string          `json:"jsonrpc"`
+	ID      int             `json:"id"`
+	Result  json.RawMessage `json:"result"`
+	Error   *rpcError       `json:"error"`
+}
+
+// capabilitiesParams are the params of the capabilities method
+type capabilitiesParams struct {
+	ProtocolVersion int `json:"protocol_version"`
+}
+
+// Capabilities is the result of the capabilities method
+type Capabilities struct {
+	ProtocolVersion int    `json:"protocol_version"`
+	Name            string `json:"name"`
+	DisplayName     string `json:"display_name"`
+	ChallengeType   string `json:"challenge_type"`
+}
+
+// resourceParams are the params of the provision and deprovision methods
+type resourceParams struct {
+	ResourceName    string `json:"resource_name"`
+	ResourceContent string `json:"resource_content"`
+}
+
+// resourceResult is the (optional) result of the provision and deprovision methods
+type resourceResult struct {
+	Diagnostics []string `json:"diagnostics"`
+}
+
+// healthResult is the result of the health method
+type healthResult struct {
+	Healthy bool   `json:"healthy"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,211 @@
+package exec_plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	errShutdown     = errors.New("plugin call canceled due to shutdown")
+	errPluginExited = errors.New("plugin exited before responding")
+)
+
+// maxResponseBytes is the max length of one response line from a plugin
+const maxResponseBytes = 1024 * 1024
+
+// rpcClient runs a plugin executable and makes JSON-RPC calls to it. If the
+// plugin exits, it is restarted on the next call.
+type rpcClient struct {
+	path            string
+	logger          *zap.SugaredLogger
+	shutdownContext context.Context
+	timeout         time.Duration
+
+	mu      sync.Mutex
+	stdin   io.WriteCloser
+	exited  chan struct{}
+	nextId  int
+	pending map[int]chan rpcResponse
+}
+
+// newRpcClient creates a client for the plugin executable at path. The plugin
+// is not started until the first call.
+func newRpcClient(path string, logger *zap.SugaredLogger, shutdownCtx context.Context, timeout time.Duration) *rpcClient {
+	return &rpcClient{
+		path:            path,
+		logger:          logger,
+		shutdownContext: shutdownCtx,
+		timeout:         timeout,
+		pending:         make(map[int]chan rpcResponse),
+	}
+}
+
+// start starts the plugin process. The caller must hold the lock.
+func (client *rpcClient) start() error {
+	cmd := exec.CommandContext(client.shutdownContext, client.path)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		return err
+	}
+
+	exited := make(chan struct{})
+	client.stdin = stdin
+	client.exited = exited
+
+	// log stderr
+	var stderrDone sync.WaitGroup
+	stderrDone.Add(1)
+	go func() {
+		defer stderrDone.Done()
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			client.logger.Debugf("plugin %s: %s", client.path, scanner.Text())
+		}
+	}()
+
+	// read responses and send each to its caller
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxResponseBytes)
+		for scanner.Scan() {
+			var response rpcResponse
+			err := json.Unmarshal(scanner.Bytes(), &response)
+			if err != nil {
+				client.logger.Errorf("plugin %s sent invalid response (%s)", client.path, err)
+				continue
+			}
+
+			client.mu.Lock()
+			ch, ok := client.pending[response.ID]
+			delete(client.pending, response.ID)
+			client.mu.Unlock()
+
+			if ok {
+				ch <- response
+			}
+		}
+
+		// Wait closes the pipes, so it must not be called until stderr has
+		// been read to the end
+		stderrDone.Wait()
+		err := cmd.Wait()
+		client.logger.Warnf("plugin %s exited (%v)", client.path, err)
+
+		client.mu.Lock()
+		if client.exited == exited {
+			client.stdin = nil
+		}
+		client.mu.Unlock()
+		close(exited)
+	}()
+
+	return nil
+}
+
+// stop closes the plugin's stdin, which signals the plugin to exit
+func (client *rpcClient) stop() {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	if client.stdin != nil {
+		_ = client.stdin.Close()
+		client.stdin = nil
+	}
+}
+
+// call calls method on the plugin and decodes the result into result (if result
+// is not nil)
+func (client *rpcClient) call(method string, params interface{}, result interface{}) error {
+	client.mu.Lock()
+
+	// start (or restart) plugin, if needed
+	if client.stdin == nil {
+		err := client.start()
+		if err != nil {
+			client.mu.Unlock()
+			return fmt.Errorf("failed to start plugin %s (%s)", client.path, err)
+		}
+	}
+
+	client.nextId++
+	id := client.nextId
+	ch := make(chan rpcResponse, 1)
+	client.pending[id] = ch
+	exited := client.exited
+
+	request, err := json.Marshal(rpcRequest{
+		JsonRpc: "2.0",
+		ID:      id,
+		Method:  method,
+		Params:  params,
+	})
+	if err == nil {
+		_, err = client.stdin.Write(append(request, '\n'))
+	}
+	if err != nil {
+		delete(client.pending, id)
+		client.mu.Unlock()
+		return err
+	}
+	client.mu.Unlock()
+
+	// no longer pending once done waiting, regardless of why
+	defer func() {
+		client.mu.Lock()
+		delete(client.pending, id)
+		client.mu.Unlock()
+	}()
+
+	// wait for response
+	var response rpcResponse
+	select {
+	case response = <-ch:
+	case <-exited:
+		// a response may have been received just before exiting
+		select {
+		case response = <-ch:
+		default:
+			return errPluginExited
+		}
+	case <-client.shutdownContext.Done():
+		return errShutdown
+	case <-time.After(client.timeout):
+		return fmt.Errorf("plugin %s timed out after %s (method %s)", client.path, client.timeout, method)
+	}
+
+	if response.Error != nil {
+		return fmt.Errorf("plugin %s error %d (%s)", client.path, response.Error.Code, response.Error.Message)
+	}
+
+	if result != nil && len(response.Result) > 0 {
+		err = json.Unmarshal(response.Result, result)
+		if err != nil {
+			return fmt.Errorf("plugin %s result is invalid (%s)", client.path, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package exec_plugin
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	errServiceComponent = errors.New("necessary exec plugin component is missing")
+	errBadTimeout       = errors.New("plugin timeout_seconds must be greater than 0")
+)
+
+// App interface is for connecting to the main app
+type App interface {
+	GetLogger() *zap.SugaredLogger
+	GetShutdownContext() context.Context
+}
+
+// Configuration options
+type Config struct {
+	Enable         *bool   `yaml:"enable"`
+	Directory      *string `yaml:"directory"`
+	TimeoutSeconds *int    `yaml:"timeout_seconds"`
+}
+
+// LoadPlugins starts each executable in the plugins directory and loads its
+// capabilities. A plugin that fails to load is logged and skipped.
+func LoadPlugins(app App, cfg *Config) ([]*Plugin, error) {
+	// if disabled, return nil and no error
+	if !*cfg.Enable {
+		return nil, nil
+	}
+
+	logger := app.GetLogger()
+	if logger == nil {
+		return nil, errServiceComponent
+	}
+
+	if *cfg.TimeoutSeconds <= 0 {
+		return nil, errBadTimeout
+	}
+	timeout := time.Duration(*cfg.TimeoutSeconds) * time.Second
+
+	entries, err := os.ReadDir(*cfg.Directory)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			logger.Warnf("challenge plugins directory %s does not exist", *cfg.Directory)
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	plugins := []*Plugin{}
+	names := make(map[string]struct{})
+	for _, entry := range entries {
+		// only executable regular files
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+			continue
+		}
+
+		path := filepath.Join(*cfg.Directory, entry.Name())
+		plugin := &Plugin{
+			client: newRpcClient(path, logger, app.GetShutdownContext(), timeout),
+		}
+
+		err = plugin.loadCapabilities()
+		if err != nil {
+			logger.Errorf("failed to load challenge plugin %s (%s)", path, err)
+			plugin.client.stop()
+			continue
+		}
+
+		// names must be unique
+		if _, exists := names[plugin.capabilities.Name]; exists {
+			logger.Errorf("failed to load challenge plugin %s (name %s is already in use)", path, plugin.capabilities.Name)
+			plugin.client.stop()
+			continue
+		}
+		names[plugin.capabilities.Name] = struct{}{}
+
+		logger.Infof("loaded challenge plugin %s (%s, %s)", plugin.capabilities.Name, plugin.capabilities.ChallengeType, path)
+		plugins = append(plugins, plugin)
+	}
+
+	return plugins, nil
+}
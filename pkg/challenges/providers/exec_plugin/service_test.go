@@ -0,0 +1,107 @@
+package exec_plugin
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// testApp satisfies App for testing
+type testApp struct{}
+
+func (testApp) GetLogger() *zap.SugaredLogger       { return zap.NewNop().Sugar() }
+func (testApp) GetShutdownContext() context.Context { return context.Background() }
+
+// testPlugin is a bash plugin that records provision calls in $0.log and fails to
+// deprovision names containing "fail"
+const testPlugin = `#!/usr/bin/env bash
+while IFS= read -r line; do
+  id=$(echo "$line" | sed -E 's/.*"id":([0-9]+).*/\1/')
+  method=$(echo "$line" | sed -E 's/.*"method":"([a-z]+)".*/\1/')
+  case "$method" in
+    capabilities)
+      echo "{\"jsonrpc\":\"2.0\",\"id\":$id,\"result\":{\"protocol_version\":1,\"name\":\"test-dns\",\"display_name\":\"DNS Test\",\"challenge_type\":\"dns-01\"}}" ;;
+    provision)
+      echo "$line" >> "$0.log"
+      echo "{\"jsonrpc\":\"2.0\",\"id\":$id,\"result\":{\"diagnostics\":[\"added\"]}}" ;;
+    deprovision)
+      if [[ "$line" == *fail* ]]; then
+        echo "{\"jsonrpc\":\"2.0\",\"id\":$id,\"error\":{\"code\":1,\"message\":\"no such record\"}}"
+      else
+        echo "{\"jsonrpc\":\"2.0\",\"id\":$id,\"result\":null}"
+      fi ;;
+    health)
+      echo "{\"jsonrpc\":\"2.0\",\"id\":$id,\"result\":{\"healthy\":true}}" ;;
+  esac
+done
+`
+
+func TestExecPlugin_LoadAndCall(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not available")
+	}
+
+	dir := t.TempDir()
+	pluginPath := filepath.Join(dir, "test-plugin")
+	err := os.WriteFile(pluginPath, []byte(testPlugin), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// not executable, should be ignored
+	err = os.WriteFile(filepath.Join(dir, "README"), []byte("not a plugin"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enable, timeout := true, 10
+	plugins, err := LoadPlugins(testApp{}, &Config{Enable: &enable, Directory: &dir, TimeoutSeconds: &timeout})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plugins) != 1 {
+		t.Fatalf("loaded %d plugins (expected 1)", len(plugins))
+	}
+	plugin := plugins[0]
+	defer plugin.client.stop()
+
+	caps := plugin.Capabilities()
+	if caps.Name != "test-dns" || caps.ChallengeType != "dns-01" || caps.DisplayName != "DNS Test" {
+		t.Errorf("unexpected capabilities %+v", caps)
+	}
+
+	err = plugin.Provision("_acme-challenge.example.com", "value1", nil)
+	if err != nil {
+		t.Errorf("provision failed (%s)", err)
+	}
+	log, _ := os.ReadFile(pluginPath + ".log")
+	if !strings.Contains(string(log), `"resource_content":"value1"`) {
+		t.Errorf("plugin did not receive provision params (%s)", log)
+	}
+
+	err = plugin.Deprovision("_acme-challenge.example.com", "value1", nil)
+	if err != nil {
+		t.Errorf("deprovision failed (%s)", err)
+	}
+
+	err = plugin.Deprovision("_acme-challenge.fail.example.com", "value1", nil)
+	if err == nil || !strings.Contains(err.Error(), "no such record") {
+		t.Errorf("expected plugin error, got %v", err)
+	}
+
+	err = plugin.HealthCheck()
+	if err != nil {
+		t.Errorf("health failed (%s)", err)
+	}
+
+	// plugin is restarted after exiting
+	plugin.client.stop()
+	err = plugin.HealthCheck()
+	if err != nil {
+		t.Errorf("health after restart failed (%s)", err)
+	}
+}
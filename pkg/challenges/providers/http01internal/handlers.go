@@ -8,7 +8,8 @@ import (
 
 // challengeHandler responds to the ACME http-01 challenge path. If the requested
 // token exists in this service's tokens, the expected token content is sent back to
-// the client. If the token is not in the service's tokens, a 404 reply is sent.
+// the client. If the token is not in the service's tokens, the request is sent to the
+// proxy upstream (if configured) or a 404 reply is sent.
 func (service *Service) challengeHandler(w http.ResponseWriter, r *http.Request) {
 	var keyAuth string
 	var exists bool
@@ -17,14 +18,20 @@ func (service *Service) challengeHandler(w http.ResponseWriter, r *http.Request)
 	// token from the client request
 	token := httprouter.ParamsFromContext(r.Context()).ByName("token")
 
-	// lock tokens for reading
+	// read token
 	service.mu.RLock()
-	defer service.mu.RUnlock()
+	keyAuth, exists = service.tokens[token]
+	service.mu.RUnlock()
 
-	// check token existence, if no token, write error 404 and return
-	if keyAuth, exists = service.tokens[token]; !exists {
+	// check token existence, if no token, proxy or write error 404 and return
+	if !exists {
 		service.logger.Debugf("http-01 challenge token not found: %s", token)
 
+		if service.proxy != nil {
+			service.proxy.ServeHTTP(w, r)
+			return
+		}
+
 		w.WriteHeader(http.StatusNotFound)
 		_, err = w.Write([]byte("404 page not found"))
 		if err != nil {
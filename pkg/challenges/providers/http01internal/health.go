@@ -0,0 +1,44 @@
+package http01internal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HealthCheck provisions a random token and then fetches it from the challenge
+// server to confirm the server is serving tokens
+func (service *Service) HealthCheck() error {
+	tokenBytes := make([]byte, 16)
+	_, err := rand.Read(tokenBytes)
+	if err != nil {
+		return err
+	}
+	token := "health-" + hex.EncodeToString(tokenBytes)
+	keyAuth := token + ".health"
+
+	_ = service.Provision(token, keyAuth, nil)
+	defer func() { _ = service.Deprovision(token, keyAuth, nil) }()
+
+	// fetch token
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(fmt.Sprintf("http://%s/.well-known/acme-challenge/%s", service.healthAddr, token))
+	if err != nil {
+		return fmt.Errorf("http-01 challenge server self fetch failed (%s)", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("http-01 challenge server self fetch failed (%s)", err)
+	}
+
+	if resp.StatusCode != http.StatusOK || string(body) != keyAuth {
+		return fmt.Errorf("http-01 challenge server self fetch returned unexpected response (status %d)", resp.StatusCode)
+	}
+
+	return nil
+}
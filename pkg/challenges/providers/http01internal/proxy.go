@@ -0,0 +1,47 @@
+package http01internal
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+)
+
+var errProxyUpstreamBad = errors.New("http-01 internal proxy upstream must be an absolute http or https url")
+
+// makeProxy creates a reverse proxy to the upstream url. Requests keep their
+// original Host header and have X-Forwarded-* headers added.
+func (service *Service) makeProxy(upstream string) (*httputil.ReverseProxy, error) {
+	upstreamUrl, err := url.Parse(upstream)
+	if err != nil {
+		return nil, err
+	}
+	if (upstreamUrl.Scheme != "http" && upstreamUrl.Scheme != "https") || upstreamUrl.Host == "" {
+		return nil, errProxyUpstreamBad
+	}
+
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(upstreamUrl)
+			r.SetXForwarded()
+			r.Out.Host = r.In.Host
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			service.logger.Errorf("http-01 challenge server proxy to %s failed (%s)", upstreamUrl.Host, err)
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	}
+
+	return proxy, nil
+}
+
+// proxyHandler sends the request to the upstream, if there is one. Otherwise a 404
+// reply is sent.
+func (service *Service) proxyHandler(w http.ResponseWriter, r *http.Request) {
+	if service.proxy == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	service.proxy.ServeHTTP(w, r)
+}
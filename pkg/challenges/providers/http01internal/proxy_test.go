@@ -0,0 +1,59 @@
+package http01internal
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestProxy_Fallthrough(t *testing.T) {
+	// upstream app
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("upstream " + r.Method + " " + r.Host + r.URL.Path))
+	}))
+	defer upstream.Close()
+
+	service := &Service{
+		logger: zap.NewNop().Sugar(),
+		tokens: map[string]string{"abc": "abc.keyauth"},
+	}
+	var err error
+	service.proxy, err = service.makeProxy(upstream.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(service.routes())
+	defer srv.Close()
+
+	tests := []struct {
+		method   string
+		path     string
+		expected string
+	}{
+		{http.MethodGet, "/.well-known/acme-challenge/abc", "abc.keyauth"},
+		{http.MethodGet, "/.well-known/acme-challenge/other", "upstream GET example.com/.well-known/acme-challenge/other"},
+		{http.MethodGet, "/", "upstream GET example.com/"},
+		{http.MethodPost, "/.well-known/acme-challenge/abc", "upstream POST example.com/.well-known/acme-challenge/abc"},
+		{http.MethodGet, "/app/page/", "upstream GET example.com/app/page/"},
+	}
+
+	for _, test := range tests {
+		req, _ := http.NewRequest(test.method, srv.URL+test.path, nil)
+		req.Host = "example.com"
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if string(body) != test.expected {
+			t.Errorf("%s %s returned '%s' (expected '%s')", test.method, test.path, body, test.expected)
+		}
+	}
+}
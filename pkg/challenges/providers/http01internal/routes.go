@@ -14,5 +14,11 @@ func (service *Service) routes() http.Handler {
 	// acme challenge route, per rfc8555 8.3
 	router.HandlerFunc(http.MethodGet, "/.well-known/acme-challenge/:token", service.challengeHandler)
 
+	// everything else goes to the upstream (if configured)
+	router.NotFound = http.HandlerFunc(service.proxyHandler)
+	router.HandleMethodNotAllowed = false
+	router.RedirectTrailingSlash = false
+	router.RedirectFixedPath = false
+
 	return router
 }
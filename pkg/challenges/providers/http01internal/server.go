@@ -3,52 +3,67 @@ package http01internal
 import (
 	"context"
 	"errors"
-	"fmt"
+	"net"
 	"net/http"
 	"sync"
 	"time"
 )
 
-func (service *Service) startServer(port int, ctx context.Context, wg *sync.WaitGroup) (err error) {
+// startServer starts the http server on each of the listen addresses. The listeners
+// are opened before returning so any error (e.g. port in use) is returned.
+func (service *Service) startServer(listenAddrs []string, ctx context.Context, wg *sync.WaitGroup) (err error) {
 	// configure webserver
 	readTimeout := 5 * time.Second
 	writeTimeout := 10 * time.Second
-	// allow longer timeouts when in development
-	if service.devMode {
-		readTimeout = 15 * time.Second
-		writeTimeout = 30 * time.Second
+	// allow longer timeouts when in development or when proxying to an upstream app
+	if service.devMode || service.proxy != nil {
+		readTimeout = 30 * time.Second
+		writeTimeout = 60 * time.Second
 	}
 
-	// TODO: modify to allow specifying specific interface addresses
-	hostName := ""
-
-	servAddr := fmt.Sprintf("%s:%d", hostName, port)
 	srv := &http.Server{
-		Addr:         servAddr,
 		Handler:      service.routes(),
 		ReadTimeout:  readTimeout,
 		WriteTimeout: writeTimeout,
 	}
 
-	// no need to keep these connections alive
-	srv.SetKeepAlivesEnabled(false)
+	// no need to keep these connections alive (unless proxying)
+	srv.SetKeepAlivesEnabled(service.proxy != nil)
 
-	// launch webserver
-	service.logger.Infof("starting http-01 challenge server on %s.", servAddr)
-	if port != 80 {
-		service.logger.Warnf("http-01 challenge server is not running on port 80; internet "+
-			"facing port 80 must be proxied to port %d to function.", port)
+	// open all listeners
+	listeners := []net.Listener{}
+	for _, addr := range listenAddrs {
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			for i := range listeners {
+				_ = listeners[i].Close()
+			}
+			return err
+		}
+		listeners = append(listeners, listener)
 	}
-	wg.Add(1)
 
-	go func() {
-		err := srv.ListenAndServe()
-		if err != nil && !errors.Is(err, http.ErrServerClosed) {
-			service.logger.Panic(err)
+	// launch webserver
+	for i := range listeners {
+		listener := listeners[i]
+
+		service.logger.Infof("starting http-01 challenge server on %s.", listener.Addr())
+		_, port, _ := net.SplitHostPort(listener.Addr().String())
+		if port != "80" {
+			service.logger.Warnf("http-01 challenge server is not running on port 80; internet "+
+				"facing port 80 must be proxied to port %s to function.", port)
 		}
-		service.logger.Info("http-01 challenge server shutdown complete")
-		wg.Done()
-	}()
+
+		wg.Add(1)
+		go func() {
+			err := srv.Serve(listener)
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				service.logger.Errorf("http-01 challenge server error (%s)", err)
+			}
+			service.logger.Infof("http-01 challenge server on %s shutdown complete", listener.Addr())
+			wg.Done()
+		}()
+	}
 
 	// monitor shutdown context
 	go func() {
@@ -58,7 +73,7 @@ func (service *Service) startServer(port int, ctx context.Context, wg *sync.Wait
 		ctx, cancel := context.WithTimeout(context.Background(), maxShutdownTime)
 		defer cancel()
 
-		err = srv.Shutdown(ctx)
+		err := srv.Shutdown(ctx)
 		if err != nil {
 			service.logger.Errorf("error shutting down http-01 challenge server")
 		}
@@ -3,6 +3,9 @@ package http01internal
 import (
 	"context"
 	"errors"
+	"net"
+	"net/http/httputil"
+	"strconv"
 	"sync"
 
 	"go.uber.org/zap"
@@ -23,16 +26,23 @@ type App interface {
 
 // Accounts service struct
 type Service struct {
-	devMode bool
-	logger  *zap.SugaredLogger
-	tokens  map[string]string
-	mu      sync.RWMutex // added mutex due to unsafe if add and remove token both run
+	devMode    bool
+	logger     *zap.SugaredLogger
+	healthAddr string
+	proxy      *httputil.ReverseProxy
+	tokens     map[string]string
+	mu         sync.RWMutex // added mutex due to unsafe if add and remove token both run
 }
 
 // Configuration options
 type Config struct {
-	Enable *bool `yaml:"enable"`
-	Port   *int  `yaml:"port"`
+	Enable      *bool   `yaml:"enable"`
+	BindAddress *string `yaml:"bind_address"`
+	Port        *int    `yaml:"port"`
+	// additional host:port addresses to also listen on
+	AdditionalListenAddresses []string `yaml:"additional_listen_addresses"`
+	// if set, all non challenge requests are reverse proxied to this url
+	ProxyUpstream *string `yaml:"proxy_upstream"`
 }
 
 // NewService creates a new service
@@ -56,11 +66,34 @@ func NewService(app App, config *Config) (*Service, error) {
 	// allocate token map
 	service.tokens = make(map[string]string, 50)
 
+	// reverse proxy for non challenge requests
+	if config.ProxyUpstream != nil && *config.ProxyUpstream != "" {
+		var err error
+		service.proxy, err = service.makeProxy(*config.ProxyUpstream)
+		if err != nil {
+			return nil, err
+		}
+		service.logger.Infof("http-01 challenge server will proxy other requests to %s", *config.ProxyUpstream)
+	}
+
 	// start web server for http01 challenges
 	if config.Port == nil {
 		return nil, errConfigComponent
 	}
-	err := service.startServer(*config.Port, app.GetShutdownContext(), app.GetShutdownWaitGroup())
+	bindAddress := ""
+	if config.BindAddress != nil {
+		bindAddress = *config.BindAddress
+	}
+	listenAddrs := append([]string{net.JoinHostPort(bindAddress, strconv.Itoa(*config.Port))}, config.AdditionalListenAddresses...)
+
+	// health check uses the first listener (loopback if listening on all addresses)
+	healthHost := bindAddress
+	if healthHost == "" || net.ParseIP(healthHost).IsUnspecified() {
+		healthHost = "127.0.0.1"
+	}
+	service.healthAddr = net.JoinHostPort(healthHost, strconv.Itoa(*config.Port))
+
+	err := service.startServer(listenAddrs, app.GetShutdownContext(), app.GetShutdownWaitGroup())
 	if err != nil {
 		return nil, err
 	}
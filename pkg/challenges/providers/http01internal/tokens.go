@@ -1,7 +1,9 @@
 package http01internal
 
+import "legocerthub-backend/pkg/diagnostics"
+
 // AddToken adds a token to the slice of hosted tokens
-func (service *Service) Provision(token string, keyAuth string) (err error) {
+func (service *Service) Provision(token string, keyAuth string, diag *diagnostics.Log) (err error) {
 	// add new entry
 	service.mu.Lock()
 	defer service.mu.Unlock()
@@ -13,7 +15,7 @@ func (service *Service) Provision(token string, keyAuth string) (err error) {
 
 // RemoveToken removes the specified token from the slice of
 // hosted tokens
-func (service *Service) Deprovision(token string, keyAuth string) (err error) {
+func (service *Service) Deprovision(token string, keyAuth string, diag *diagnostics.Log) (err error) {
 	// keyAuth is unused in this function
 
 	service.mu.Lock()
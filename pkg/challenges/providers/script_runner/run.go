@@ -0,0 +1,116 @@
+package script_runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"legocerthub-backend/pkg/diagnostics"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+var errShutdown = errors.New("script canceled due to shutdown")
+
+// Command is a script command to run
+type Command struct {
+	// Name is the executable (e.g. the path to bash)
+	Name string
+	// Args are the arguments to pass to Name
+	Args []string
+	// Environment is appended to the current process' environment
+	Environment []string
+	// Stdin, if not nil, is written to the command's stdin
+	Stdin []byte
+}
+
+// Result contains the output that was captured from running a Command
+type Result struct {
+	Stdout string
+	Stderr string
+}
+
+// Run runs the Command and captures its output. The command is killed if it
+// runs longer than timeout or if shutdownCtx is canceled. Output is captured
+// and returned even if an error is returned.
+func Run(shutdownCtx context.Context, timeout time.Duration, command Command) (Result, error) {
+	ctx, cancel := context.WithTimeout(shutdownCtx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, command.Name, command.Args...)
+
+	// set command environment
+	cmd.Env = os.Environ()
+	cmd.Env = append(cmd.Env, command.Environment...)
+
+	// stdin
+	if command.Stdin != nil {
+		cmd.Stdin = bytes.NewReader(command.Stdin)
+	}
+
+	// capture output
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	// don't block forever on children of the script that are holding the
+	// output pipes after the script itself is killed
+	cmd.WaitDelay = 5 * time.Second
+
+	err := cmd.Run()
+
+	result := Result{
+		Stdout: strings.TrimSpace(stdout.String()),
+		Stderr: strings.TrimSpace(stderr.String()),
+	}
+
+	if err != nil {
+		// check for cancelation reason
+		if shutdownCtx.Err() != nil {
+			return result, errShutdown
+		}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return result, fmt.Errorf("script timed out after %s", timeout)
+		}
+
+		return result, err
+	}
+
+	return result, nil
+}
+
+// AddToDiagnostics records any output in the Result to the diagnostics Log
+// using the specified source and a description of what was run
+func (result Result) AddToDiagnostics(diag *diagnostics.Log, source string, description string) {
+	if result.Stdout != "" {
+		diag.Addf(source, "%s stdout: %s", description, result.Stdout)
+	}
+	if result.Stderr != "" {
+		diag.Addf(source, "%s stderr: %s", description, result.Stderr)
+	}
+}
+
+// CheckFiles returns an error if the shell is not an executable file or if any of
+// the scripts is not a readable file. This is used to health check script based
+// providers.
+func CheckFiles(shellPath string, scriptPaths ...string) error {
+	info, err := os.Stat(shellPath)
+	if err != nil {
+		return fmt.Errorf("shell %s not found (%s)", shellPath, err)
+	}
+	if info.IsDir() || info.Mode().Perm()&0111 == 0 {
+		return fmt.Errorf("shell %s is not executable", shellPath)
+	}
+
+	for _, scriptPath := range scriptPaths {
+		f, err := os.Open(scriptPath)
+		if err != nil {
+			return fmt.Errorf("script %s not readable (%s)", scriptPath, err)
+		}
+		f.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package script_runner
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testShell = "/bin/sh"
+
+func TestRun_Stdin(t *testing.T) {
+	stdin := []byte(`{"version":2,"action":"create"}`)
+
+	result, err := Run(context.Background(), 5*time.Second, Command{
+		Name:        testShell,
+		Args:        []string{"-c", `cat; echo "$TEST_VAR" >&2`},
+		Environment: []string{"TEST_VAR=some value"},
+		Stdin:       stdin,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Stdout != string(stdin) {
+		t.Errorf("stdout '%s', expected '%s'", result.Stdout, stdin)
+	}
+	if result.Stderr != "some value" {
+		t.Errorf("stderr '%s', expected 'some value'", result.Stderr)
+	}
+}
+
+func TestRun_Timeout(t *testing.T) {
+	start := time.Now()
+
+	result, err := Run(context.Background(), 200*time.Millisecond, Command{
+		Name: testShell,
+		Args: []string{"-c", "echo started; sleep 10"},
+	})
+	elapsed := time.Since(start)
+
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("error '%v', expected timeout", err)
+	}
+	if result.Stdout != "started" {
+		t.Errorf("stdout '%s', expected output from before the timeout", result.Stdout)
+	}
+
+	// sh is killed but its sleep child still holds the output pipes, so Run
+	// must give up on them after WaitDelay instead of waiting for the sleep
+	if elapsed > 8*time.Second {
+		t.Errorf("run took %s after a timeout", elapsed)
+	}
+}
+
+func TestRun_Shutdown(t *testing.T) {
+	shutdownCtx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	_, err := Run(shutdownCtx, 10*time.Second, Command{
+		Name: testShell,
+		Args: []string{"-c", "exec sleep 10"},
+	})
+	if !errors.Is(err, errShutdown) {
+		t.Errorf("error '%v', expected '%v'", err, errShutdown)
+	}
+}
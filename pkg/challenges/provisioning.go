@@ -4,65 +4,83 @@ import (
 	"errors"
 	"legocerthub-backend/pkg/acme"
 	"legocerthub-backend/pkg/challenges/dns_checker"
-	"reflect"
+	"legocerthub-backend/pkg/diagnostics"
+	"legocerthub-backend/pkg/events"
 )
 
 var errUnsupportedMethod = errors.New("unsupported or disabled challenge method")
 
-// Provision generates the needed ACME challenge resource (to validate
-// the challenge) and then provisions that resource using the Method's
-// provider.
-func (service *Service) Provision(identifier acme.Identifier, method Method, key acme.AccountKey, token string) (err error) {
-	// calculate the needed resource
-	resourceName, resourceContent, err := method.validationResource(identifier, key, token)
-	if err != nil {
-		return err
-	}
+var errNoIssuerDomainName = errors.New("dns-persist-01 challenge does not contain an issuer domain name")
+
+// isDnsChallengeType returns true if the challenge type is validated using a dns
+// record (and therefore the dns checker is used)
+func isDnsChallengeType(challType acme.ChallengeType) bool {
+	return challType == acme.ChallengeTypeDns01 || challType == acme.ChallengeTypeDnsPersist01
+}
 
-	// confirm provider is available
-	if provider, ok := service.providers[method.Value]; !ok || reflect.ValueOf(provider).IsNil() {
-		return errUnsupportedMethod
+// validationResource creates the resource name and content that are required to
+// successfully validate the ACME Challenge using the Method.
+func (service *Service) validationResource(auth acme.Authorization, challenge acme.Challenge, method Method, key acme.AccountKey) (name string, content string, err error) {
+	// dns-persist-01 resource is the account's persistent record (which does not
+	// depend on the challenge token)
+	if method.ChallengeType == acme.ChallengeTypeDnsPersist01 {
+		if len(challenge.IssuerDomainNames) == 0 {
+			return "", "", errNoIssuerDomainName
+		}
+
+		return acme.DnsPersistRecord(auth.Identifier.Value, challenge.IssuerDomainNames[0], key.Kid, auth.Wildcard)
 	}
 
+	return method.validationResource(auth.Identifier, key, challenge.Token)
+}
+
+// provision provisions the ACME challenge resource (to validate the challenge)
+// using the Method's provider. Any diagnostic output from the provider is added
+// to diag and progress events are sent with emitter.
+func (service *Service) provision(provider providerService, method Method, resourceName string, resourceContent string, diag *diagnostics.Log, emitter *events.Emitter) (err error) {
 	// Provision with the appropriate provider
-	err = service.providers[method.Value].Provision(resourceName, resourceContent)
+	err = provider.Provision(resourceName, resourceContent, diag)
 	if err != nil {
 		return err
 	}
+	emitter.Emitf(events.TypeChallengeProvisioned, "%s resource %s provisioned using %s", method.ChallengeType, resourceName, method.Value)
+
+	// if using a dns method, utilize dnsChecker
+	if isDnsChallengeType(method.ChallengeType) {
+		// check for propagation (persistent records should already exist, so
+		// don't wait as long for them)
+		service.mu.RLock()
+		dnsChecker := service.dnsChecker
+		service.mu.RUnlock()
 
-	// if using dns-01 method, utilize dnsChecker
-	if method.ChallengeType == acme.ChallengeTypeDns01 {
-		// check for propagation
-		propagated, err := service.dnsChecker.CheckTXTWithRetry(resourceName, resourceContent, 10)
+		maxTries := 10
+		if method.ChallengeType == acme.ChallengeTypeDnsPersist01 {
+			maxTries = 2
+		}
+
+		propagated, err := dnsChecker.CheckTXTWithRetry(resourceName, resourceContent, maxTries)
 		if err != nil {
 			service.logger.Error(err)
+			diag.Addf("dns-checker", "error checking propagation of %s (%s)", resourceName, err)
 			return err
 		}
 
 		// if failed to propagate
 		if !propagated {
+			diag.Addf("dns-checker", "record %s did not propagate", resourceName)
 			return dns_checker.ErrDnsRecordNotFound
 		}
+
+		emitter.Emitf(events.TypeDnsPropagated, "record %s propagated", resourceName)
 	}
 
 	return nil
 }
 
-// Deprovision removes the ACME challenge resource from the Method's provider.
-func (service *Service) Deprovision(identifier acme.Identifier, method Method, key acme.AccountKey, token string) (err error) {
-	// calculate the needed resource
-	resourceName, resourceContent, err := method.validationResource(identifier, key, token)
-	if err != nil {
-		return err
-	}
-
-	// confirm provider is available
-	if provider, ok := service.providers[method.Value]; !ok || reflect.ValueOf(provider).IsNil() {
-		return errUnsupportedMethod
-	}
-
+// deprovision removes the ACME challenge resource from the Method's provider.
+func (service *Service) deprovision(provider providerService, resourceName string, resourceContent string, diag *diagnostics.Log) (err error) {
 	// Deprovision with the appropriate provider
-	err = service.providers[method.Value].Deprovision(resourceName, resourceContent)
+	err = provider.Deprovision(resourceName, resourceContent, diag)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,42 @@
+package challenges
+
+import (
+	"legocerthub-backend/pkg/acme"
+	"testing"
+)
+
+func TestService_ValidationResourceDnsPersist(t *testing.T) {
+	service := new(Service)
+	method := Method{Value: "dns-persist-01-test", ChallengeType: acme.ChallengeTypeDnsPersist01}
+	key := acme.AccountKey{Kid: "https://acme.example.com/acme/acct/12345"}
+
+	auth := acme.Authorization{
+		Identifier: acme.Identifier{Type: "dns", Value: "example.com"},
+		Wildcard:   true,
+	}
+	challenge := acme.Challenge{
+		Type:              acme.ChallengeTypeDnsPersist01,
+		Token:             "unused-token",
+		IssuerDomainNames: []string{"letsencrypt.org", "other.example.net"},
+	}
+
+	// record uses the first issuer domain name and the account's kid
+	name, content, err := service.validationResource(auth, challenge, method, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "_validation-persist.example.com" {
+		t.Errorf("name '%s', expected '_validation-persist.example.com'", name)
+	}
+	expectedContent := "letsencrypt.org; accounturi=" + key.Kid + "; policy=wildcard"
+	if content != expectedContent {
+		t.Errorf("content '%s', expected '%s'", content, expectedContent)
+	}
+
+	// no issuer domain name
+	challenge.IssuerDomainNames = nil
+	_, _, err = service.validationResource(auth, challenge, method, key)
+	if err != errNoIssuerDomainName {
+		t.Errorf("error '%v', expected '%v'", err, errNoIssuerDomainName)
+	}
+}
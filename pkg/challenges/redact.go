@@ -0,0 +1,127 @@
+package challenges
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// redactedValue replaces credentials when a provider's config is sent to a client
+const redactedValue = "[redacted]"
+
+// Provider Config fields are redacted based on their struct tag:
+//   `redact:"true"` redacts the entire string value
+//   `redact:"env"` redacts the value portion of each KEY=VALUE string in a slice
+
+// redactConfig returns a copy of cfg (a pointer to a provider Config) with all
+// of its credentials redacted
+func redactConfig(cfg interface{}) interface{} {
+	// deep copy cfg so the original isn't modified
+	cfgJson, err := json.Marshal(cfg)
+	if err != nil {
+		return nil
+	}
+	cfgCopy := reflect.New(reflect.TypeOf(cfg).Elem())
+	err = json.Unmarshal(cfgJson, cfgCopy.Interface())
+	if err != nil {
+		return nil
+	}
+
+	redactValue(cfgCopy, "")
+
+	return cfgCopy.Interface()
+}
+
+// redactValue recursively redacts v based on the redact tag of the field that
+// contains it
+func redactValue(v reflect.Value, tag string) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			redactValue(v.Elem(), tag)
+		}
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			redactValue(v.Field(i), v.Type().Field(i).Tag.Get("redact"))
+		}
+
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			redactValue(v.Index(i), tag)
+		}
+
+	case reflect.String:
+		if !v.CanSet() || v.String() == "" {
+			return
+		}
+
+		switch tag {
+		case "true":
+			v.SetString(redactedValue)
+		case "env":
+			key, _, found := strings.Cut(v.String(), "=")
+			if found {
+				v.SetString(key + "=" + redactedValue)
+			}
+		}
+	}
+}
+
+// restoreRedacted replaces any redacted values in newCfg with the matching value
+// from oldCfg (both are pointers to the same type of provider Config). This allows
+// a client to send back a config it received without re-entering credentials.
+// Slice elements are matched by index, except env values which are matched by KEY.
+func restoreRedacted(newCfg interface{}, oldCfg interface{}) {
+	restoreValue(reflect.ValueOf(newCfg), reflect.ValueOf(oldCfg), "")
+}
+
+// restoreValue recursively restores redacted values in newV from oldV
+func restoreValue(newV reflect.Value, oldV reflect.Value, tag string) {
+	switch newV.Kind() {
+	case reflect.Pointer:
+		if !newV.IsNil() && !oldV.IsNil() {
+			restoreValue(newV.Elem(), oldV.Elem(), tag)
+		}
+
+	case reflect.Struct:
+		for i := 0; i < newV.NumField(); i++ {
+			restoreValue(newV.Field(i), oldV.Field(i), newV.Type().Field(i).Tag.Get("redact"))
+		}
+
+	case reflect.Slice:
+		// env is matched by key
+		if tag == "env" {
+			for i := 0; i < newV.Len(); i++ {
+				restoreEnv(newV.Index(i), oldV)
+			}
+			return
+		}
+
+		for i := 0; i < newV.Len() && i < oldV.Len(); i++ {
+			restoreValue(newV.Index(i), oldV.Index(i), tag)
+		}
+
+	case reflect.String:
+		if tag == "true" && newV.CanSet() && newV.String() == redactedValue {
+			newV.SetString(oldV.String())
+		}
+	}
+}
+
+// restoreEnv restores a redacted KEY=VALUE string in newV using the value for the
+// same KEY in the oldEnv slice
+func restoreEnv(newV reflect.Value, oldEnv reflect.Value) {
+	key, value, found := strings.Cut(newV.String(), "=")
+	if !found || value != redactedValue || !newV.CanSet() {
+		return
+	}
+
+	for i := 0; i < oldEnv.Len(); i++ {
+		oldKey, _, _ := strings.Cut(oldEnv.Index(i).String(), "=")
+		if oldKey == key {
+			newV.SetString(oldEnv.Index(i).String())
+			return
+		}
+	}
+}
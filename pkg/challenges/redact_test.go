@@ -0,0 +1,67 @@
+package challenges
+
+import (
+	"encoding/json"
+	"legocerthub-backend/pkg/challenges/providers/dns01acmedns"
+	"legocerthub-backend/pkg/challenges/providers/dns01manual"
+	"testing"
+)
+
+func TestRedact_EnvRoundTrip(t *testing.T) {
+	stored := dns01manual.DefaultConfig()
+	stored.Environment = []string{"API_KEY=secret", "API_USER=lego"}
+	stored.CreateScript = "./create.sh"
+
+	redacted := redactConfig(&stored).(*dns01manual.Config)
+	if redacted.Environment[0] != "API_KEY="+redactedValue || redacted.Environment[1] != "API_USER="+redactedValue {
+		t.Errorf("env not redacted: %v", redacted.Environment)
+	}
+	if redacted.CreateScript != "./create.sh" {
+		t.Errorf("non-credential field modified: %s", redacted.CreateScript)
+	}
+	if stored.Environment[0] != "API_KEY=secret" {
+		t.Errorf("original config modified: %v", stored.Environment)
+	}
+
+	// client sends back the redacted config with one key reordered, one new key
+	// and one changed value
+	redacted.Environment = []string{"API_USER=other", "API_KEY=" + redactedValue, "NEW=" + redactedValue}
+	restoreRedacted(redacted, &stored)
+
+	expected := []string{"API_USER=other", "API_KEY=secret", "NEW=" + redactedValue}
+	for i := range expected {
+		if redacted.Environment[i] != expected[i] {
+			t.Errorf("env %d restored to '%s' (expected '%s')", i, redacted.Environment[i], expected[i])
+		}
+	}
+}
+
+func TestRedact_NestedSliceRoundTrip(t *testing.T) {
+	stored := dns01acmedns.DefaultConfig()
+	err := jsonToConfig(`{"resources":[{"real_domain":"a.example.com","password":"pw1"},{"real_domain":"b.example.com","password":"pw2"}]}`, &stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	redacted := redactConfig(&stored).(*dns01acmedns.Config)
+	err = jsonToConfig(`{"resources":[{"real_domain":"a.example.com","password":"[redacted]"},{"real_domain":"b.example.com","password":"new"}]}`, redacted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restoreRedacted(redacted, &stored)
+
+	out, _ := jsonFromConfig(redacted)
+	expected := `{"acme_dns_address":"","resources":[{"real_domain":"a.example.com","full_domain":"","username":"","password":"pw1"},{"real_domain":"b.example.com","full_domain":"","username":"","password":"new"}]}`
+	if out != expected {
+		t.Errorf("restored config '%s' (expected '%s')", out, expected)
+	}
+}
+
+func jsonToConfig(s string, cfg interface{}) error {
+	return json.Unmarshal([]byte(s), cfg)
+}
+
+func jsonFromConfig(cfg interface{}) (string, error) {
+	b, err := json.Marshal(cfg)
+	return string(b), err
+}
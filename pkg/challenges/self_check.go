@@ -0,0 +1,130 @@
+package challenges
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"legocerthub-backend/pkg/acme"
+	"legocerthub-backend/pkg/diagnostics"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// diagnostics source name
+const selfCheckDiagSource = "http-01-self-check"
+
+// ACME servers follow a limited number of redirects and only to the standard ports
+const selfCheckMaxRedirects = 10
+
+var (
+	errSelfCheckRedirectLoop   = errors.New("redirect loop")
+	errSelfCheckRedirects      = fmt.Errorf("more than %d redirects", selfCheckMaxRedirects)
+	errSelfCheckRedirectScheme = errors.New("redirect to a scheme other than http or https")
+	errSelfCheckRedirectPort   = errors.New("redirect to a port other than 80 or 443")
+)
+
+// http01SelfCheck fetches the http-01 resource for the identifier the same way the
+// ACME server will (including following redirects) and confirms the response is the
+// key authorization. This allows failing fast, with a clear reason, instead of
+// using one of the ACME server's validation attempts.
+func (service *Service) http01SelfCheck(identifier acme.Identifier, token string, keyAuth string, diag *diagnostics.Log) error {
+	url := "http://" + identifier.Value + "/.well-known/acme-challenge/" + token
+
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			// ACME servers don't validate the certificate of an https redirect target
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+			DisableKeepAlives: true,
+		},
+		CheckRedirect: selfCheckRedirect,
+	}
+
+	err := selfCheckFetch(client, url, keyAuth)
+	if err != nil {
+		err = fmt.Errorf("http-01 self check of %s failed (%s)", url, err)
+		service.logger.Error(err)
+		diag.Add(selfCheckDiagSource, err.Error())
+		return err
+	}
+
+	diag.Addf(selfCheckDiagSource, "%s served the expected key authorization", url)
+	return nil
+}
+
+// selfCheckRedirect applies the same redirect rules an ACME server uses
+func selfCheckRedirect(req *http.Request, via []*http.Request) error {
+	for i := range via {
+		if via[i].URL.String() == req.URL.String() {
+			return errSelfCheckRedirectLoop
+		}
+	}
+
+	if len(via) > selfCheckMaxRedirects {
+		return errSelfCheckRedirects
+	}
+
+	switch req.URL.Scheme {
+	case "http", "https":
+		// okay
+	default:
+		return errSelfCheckRedirectScheme
+	}
+
+	port := req.URL.Port()
+	if port != "" && port != "80" && port != "443" {
+		return errSelfCheckRedirectPort
+	}
+
+	return nil
+}
+
+// selfCheckFetch fetches url and compares the response to keyAuth. The returned error
+// describes the likely cause of any failure.
+func selfCheckFetch(client *http.Client, url string, keyAuth string) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		var dnsErr *net.DNSError
+		var opErr *net.OpError
+
+		switch {
+		case errors.Is(err, errSelfCheckRedirectLoop),
+			errors.Is(err, errSelfCheckRedirects),
+			errors.Is(err, errSelfCheckRedirectScheme),
+			errors.Is(err, errSelfCheckRedirectPort):
+			return err
+
+		case errors.As(err, &dnsErr):
+			return fmt.Errorf("name does not resolve (%s)", dnsErr)
+
+		case errors.As(err, &opErr):
+			return fmt.Errorf("could not connect, check the dns record points to the correct ip and that port 80 "+
+				"is reachable (%s)", opErr)
+
+		default:
+			return err
+		}
+	}
+	defer resp.Body.Close()
+
+	// key auth is short, don't read an unbounded body
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d from %s, check that a proxy is forwarding challenge requests to LeGo",
+			resp.StatusCode, resp.Request.URL)
+	}
+
+	if strings.TrimSpace(string(body)) != keyAuth {
+		return fmt.Errorf("response from %s is not the key authorization, check that the dns record points to "+
+			"LeGo (or a proxy forwarding to it)", resp.Request.URL)
+	}
+
+	return nil
+}
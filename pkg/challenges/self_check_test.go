@@ -0,0 +1,60 @@
+package challenges
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSelfCheck_Fetch(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/acme-challenge/good", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("good.keyauth"))
+	})
+	mux.HandleFunc("/.well-known/acme-challenge/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/.well-known/acme-challenge/good", http.StatusFound)
+	})
+	mux.HandleFunc("/.well-known/acme-challenge/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/.well-known/acme-challenge/loop", http.StatusFound)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	// test server isn't on port 80, so allow its port
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			err := selfCheckRedirect(req, via)
+			if errors.Is(err, errSelfCheckRedirectPort) {
+				return nil
+			}
+			return err
+		},
+	}
+
+	// good, directly and after a redirect
+	for _, token := range []string{"good", "redirect"} {
+		err := selfCheckFetch(client, srv.URL+"/.well-known/acme-challenge/"+token, "good.keyauth")
+		if err != nil {
+			t.Errorf("self check of %s failed (%s)", token, err)
+		}
+	}
+
+	// wrong content
+	err := selfCheckFetch(client, srv.URL+"/.well-known/acme-challenge/good", "other.keyauth")
+	if err == nil {
+		t.Error("self check with wrong key auth succeeded")
+	}
+
+	// not found
+	err = selfCheckFetch(client, srv.URL+"/.well-known/acme-challenge/missing", "good.keyauth")
+	if err == nil {
+		t.Error("self check of missing token succeeded")
+	}
+
+	// redirect loop
+	err = selfCheckFetch(client, srv.URL+"/.well-known/acme-challenge/loop", "good.keyauth")
+	if !errors.Is(err, errSelfCheckRedirectLoop) {
+		t.Errorf("self check of redirect loop returned %v (expected %s)", err, errSelfCheckRedirectLoop)
+	}
+}
@@ -8,59 +8,98 @@ import (
 	"legocerthub-backend/pkg/challenges/providers/dns01acmedns"
 	"legocerthub-backend/pkg/challenges/providers/dns01acmesh"
 	"legocerthub-backend/pkg/challenges/providers/dns01cloudflare"
+	"legocerthub-backend/pkg/challenges/providers/dns01internal"
 	"legocerthub-backend/pkg/challenges/providers/dns01manual"
+	"legocerthub-backend/pkg/challenges/providers/dnspersist01"
+	"legocerthub-backend/pkg/challenges/providers/exec_plugin"
 	"legocerthub-backend/pkg/challenges/providers/http01internal"
+	"legocerthub-backend/pkg/diagnostics"
 	"legocerthub-backend/pkg/domain/acme_servers"
+	"legocerthub-backend/pkg/encryption"
 	"legocerthub-backend/pkg/httpclient"
+	"legocerthub-backend/pkg/output"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
 )
 
 var (
 	errServiceComponent = errors.New("necessary challenges service component is missing")
-	errNoProviders      = errors.New("no challenge providers are properly configured (at least one must be enabled)")
+	errNoProviders      = errors.New("no challenge providers are properly configured (at least one must be enabled to order certificates)")
 )
 
 // App interface is for connecting to the main app
 type App interface {
 	GetLogger() *zap.SugaredLogger
+	GetOutputter() *output.Service
 	GetHttpClient() *httpclient.Client
+	GetCipher() *encryption.Cipher
+	GetChallengeProviderStorage() Storage
 	GetAcmeServerService() *acme_servers.Service
 	GetDevMode() bool
 	GetShutdownContext() context.Context
 	GetShutdownWaitGroup() *sync.WaitGroup
 }
 
+// Storage interface for storage functions
+type Storage interface {
+	GetAllProviders() (providers []Provider, err error)
+	GetOneProvider(id int) (provider Provider, err error)
+
+	PostNewProvider(payload NewProviderPayload) (id int, err error)
+	PutProviderUpdate(payload UpdateProviderPayload) (err error)
+	DeleteProvider(id int) (err error)
+
+	ChallengeMethodInUse(methodValue MethodValue) (inUse bool)
+}
+
 // interface for any provider service
 type providerService interface {
-	Provision(resourceName string, resourceContent string) (err error)
-	Deprovision(resourceName string, resourceContent string) (err error)
+	Provision(resourceName string, resourceContent string, diag *diagnostics.Log) (err error)
+	Deprovision(resourceName string, resourceContent string, diag *diagnostics.Log) (err error)
 }
 
-// ConfigProviders holds the challenge provider configs
+// ConfigProviders holds the challenge provider configs. The dns-01 providers
+// are configured in storage. Their config file values are only used to import
+// them into storage the first time LeGo starts with empty provider storage.
 type ConfigProviders struct {
 	Http01InternalConfig  http01internal.Config  `yaml:"http_01_internal"`
+	Dns01InternalConfig   dns01internal.Config   `yaml:"dns_01_internal"`
 	Dns01ManualConfig     dns01manual.Config     `yaml:"dns_01_manual"`
 	Dns01AcmeDnsConfig    dns01acmedns.Config    `yaml:"dns_01_acme_dns"`
 	Dns01AcmeShConfig     dns01acmesh.Config     `yaml:"dns_01_acme_sh"`
 	Dns01CloudflareConfig dns01cloudflare.Config `yaml:"dns_01_cloudflare"`
+	DnsPersist01Config    dnspersist01.Config    `yaml:"dns_persist_01"`
+	ExecPluginsConfig     exec_plugin.Config     `yaml:"exec_plugins"`
 }
 
 // Config holds all of the challenge config
 type Config struct {
-	DnsCheckerConfig dns_checker.Config `yaml:"dns_checker"`
-	ProviderConfigs  ConfigProviders    `yaml:"providers"`
+	DnsCheckerConfig           dns_checker.Config `yaml:"dns_checker"`
+	HealthCheckIntervalMinutes *int               `yaml:"health_check_interval_minutes"`
+	ProviderConfigs            ConfigProviders    `yaml:"providers"`
 }
 
 // service struct
 type Service struct {
 	shutdownContext   context.Context
 	logger            *zap.SugaredLogger
+	output            *output.Service
+	httpClient        *httpclient.Client
+	cipher            *encryption.Cipher
+	storage           Storage
 	acmeServerService *acme_servers.Service
+	dnsCheckerConfig  dns_checker.Config
 	dnsChecker        *dns_checker.Service
 	providers         map[MethodValue]providerService
+	providerErrs      map[MethodValue]error
+	health            map[MethodValue]ProviderHealth
+	dnsCheckerHealth  ProviderHealth
+	instanceMethods   map[MethodValue]Method
+	pluginMethods     map[MethodValue]Method
 	methodsWithStatus []MethodWithStatus
+	mu                sync.RWMutex
 }
 
 // NewService creates a new service
@@ -76,14 +115,45 @@ func NewService(app App, cfg *Config) (service *Service, err error) {
 	// shutdown context
 	service.shutdownContext = app.GetShutdownContext()
 
+	// output service
+	service.output = app.GetOutputter()
+	if service.output == nil {
+		return nil, errServiceComponent
+	}
+
+	// http client
+	service.httpClient = app.GetHttpClient()
+	if service.httpClient == nil {
+		return nil, errServiceComponent
+	}
+
+	// cipher
+	service.cipher = app.GetCipher()
+	if service.cipher == nil {
+		return nil, errServiceComponent
+	}
+
+	// storage
+	service.storage = app.GetChallengeProviderStorage()
+	if service.storage == nil {
+		return nil, errServiceComponent
+	}
+
 	// acme services
 	service.acmeServerService = app.GetAcmeServerService()
 	if service.acmeServerService == nil {
 		return nil, errServiceComponent
 	}
 
+	// dns checker config (checker is created once a dns provider is enabled)
+	service.dnsCheckerConfig = cfg.DnsCheckerConfig
+
 	// challenge providers
 	service.providers = make(map[MethodValue]providerService)
+	service.providerErrs = make(map[MethodValue]error)
+	service.health = make(map[MethodValue]ProviderHealth)
+	service.instanceMethods = make(map[MethodValue]Method)
+	service.pluginMethods = make(map[MethodValue]Method)
 
 	// http-01 internal challenge server
 	http01Internal, err := http01internal.NewService(app, &cfg.ProviderConfigs.Http01InternalConfig)
@@ -95,79 +165,97 @@ func NewService(app App, cfg *Config) (service *Service, err error) {
 		service.providers[methodValueHttp01Internal] = http01Internal
 	}
 
-	// dns-01 manual external scripts
-	dns01Manual, err := dns01manual.NewService(app, &cfg.ProviderConfigs.Dns01ManualConfig)
+	// dns-01 internal challenge server
+	dns01Internal, err := dns01internal.NewService(app, &cfg.ProviderConfigs.Dns01InternalConfig)
 	if err != nil {
-		service.logger.Errorf("failed to configure dns 01 manual (%s)", err)
+		service.logger.Errorf("failed to configure dns 01 internal (%s)", err)
 		return nil, err
 	}
-	if dns01Manual != nil {
-		service.providers[methodValueDns01Manual] = dns01Manual
+	if dns01Internal != nil {
+		service.providers[methodValueDns01Internal] = dns01Internal
+
+		err = service.startDnsChecker(methodValueDns01Internal)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// dns-01 acme-dns challenge service
-	dns01AcmeDns, err := dns01acmedns.NewService(app, &cfg.ProviderConfigs.Dns01AcmeDnsConfig)
+	// dns-persist-01 (uses existing persistent records)
+	dnsPersist01, err := dnspersist01.NewService(app, &cfg.ProviderConfigs.DnsPersist01Config)
 	if err != nil {
-		service.logger.Errorf("failed to configure dns 01 acme-dns (%s)", err)
+		service.logger.Errorf("failed to configure dns persist 01 (%s)", err)
 		return nil, err
 	}
-	if dns01AcmeDns != nil {
-		service.providers[methodValueDns01AcmeDns] = dns01AcmeDns
+	if dnsPersist01 != nil {
+		service.providers[methodValueDnsPersist01] = dnsPersist01
+
+		err = service.startDnsChecker(methodValueDnsPersist01)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// dns-01 acme.sh script service
-	dns01AcmeSh, err := dns01acmesh.NewService(app, &cfg.ProviderConfigs.Dns01AcmeShConfig)
+	// exec plugins
+	plugins, err := exec_plugin.LoadPlugins(service, &cfg.ProviderConfigs.ExecPluginsConfig)
 	if err != nil {
-		service.logger.Errorf("failed to configure dns 01 acme.sh (%s)", err)
+		service.logger.Errorf("failed to load challenge plugins (%s)", err)
 		return nil, err
 	}
-	if dns01AcmeSh != nil {
-		service.providers[methodValueDns01AcmeSh] = dns01AcmeSh
+	for _, plugin := range plugins {
+		caps := plugin.Capabilities()
+		method := Method{
+			Value:         pluginMethodValue(acme.ChallengeType(caps.ChallengeType), caps.Name),
+			Name:          caps.DisplayName,
+			ChallengeType: acme.ChallengeType(caps.ChallengeType),
+		}
+
+		service.mu.Lock()
+		service.pluginMethods[method.Value] = method
+		service.providers[method.Value] = plugin
+		service.mu.Unlock()
+
+		err = service.startDnsChecker(method.Value)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// dns-01 cloudflare challenge service
-	dns01Cloudflare, err := dns01cloudflare.NewService(app, &cfg.ProviderConfigs.Dns01CloudflareConfig)
+	// providers from storage (import from config file if storage is empty)
+	providers, err := service.storage.GetAllProviders()
 	if err != nil {
-		service.logger.Errorf("failed to configure dns 01 cloudflare (%s)", err)
 		return nil, err
 	}
-	if dns01Cloudflare != nil {
-		service.providers[methodValueDns01Cloudflare] = dns01Cloudflare
+
+	if len(providers) == 0 {
+		providers, err = service.importConfigProviders(&cfg.ProviderConfigs)
+		if err != nil {
+			service.logger.Errorf("failed to import challenge providers from config file (%s)", err)
+			return nil, err
+		}
+	} else if cfg.ProviderConfigs.anyDnsEnabled() {
+		service.logger.Warn("dns challenge providers in the config file are ignored, providers are now " +
+			"configured using the api")
 	}
 
+	// configure each provider, a failed provider is logged (and reported by the api) but does
+	// not stop the app, so the provider's config can be fixed
+	for i := range providers {
+		err = service.setProvider(providers[i])
+		if err != nil {
+			service.logger.Errorf("failed to configure challenge provider %s (%s)", providers[i].methodValue(), err)
+		}
+	}
 	// end challenge providers
 
 	// make array containing service methods and if they're enabled or disabled
-	// fail out if none enabled
-	atLeastOneEnabled := false
-	for i := range allMethods {
-		if _, ok := service.providers[allMethods[i].Value]; ok {
-			// enabled
-			service.methodsWithStatus = append(service.methodsWithStatus, allMethods[i].AddStatus(true))
-			atLeastOneEnabled = true
-		} else {
-			// disabled
-			service.methodsWithStatus = append(service.methodsWithStatus, allMethods[i].AddStatus(false))
-		}
-	}
-	if !atLeastOneEnabled {
-		return nil, errNoProviders
+	service.updateMethodsWithStatus()
+	if len(service.providers) == 0 {
+		service.logger.Warn(errNoProviders)
 	}
 
-	// configure dns checker service (if any enabled Method is a DNS method)
-	// Fixes https://github.com/gregtwallace/legocerthub/issues/6
-	for i := range service.methodsWithStatus {
-		if service.methodsWithStatus[i].Enabled && service.methodsWithStatus[i].ChallengeType == acme.ChallengeTypeDns01 {
-			// enable checker
-			service.dnsChecker, err = dns_checker.NewService(app, cfg.DnsCheckerConfig)
-			if err != nil {
-				service.logger.Errorf("failed to configure dns checker (%s)", err)
-				return nil, err
-			}
-
-			// no need to continue loop once DNS challenge service is confirmed required
-			break
-		}
+	// periodic provider health checks (0 disables)
+	if cfg.HealthCheckIntervalMinutes != nil && *cfg.HealthCheckIntervalMinutes > 0 {
+		service.startHealthChecks(time.Duration(*cfg.HealthCheckIntervalMinutes)*time.Minute, app.GetShutdownWaitGroup())
 	}
 
 	return service, nil
@@ -3,6 +3,8 @@ package challenges
 import (
 	"errors"
 	"legocerthub-backend/pkg/acme"
+	"legocerthub-backend/pkg/diagnostics"
+	"legocerthub-backend/pkg/events"
 	"time"
 )
 
@@ -11,31 +13,47 @@ var (
 	errChallengeTypeNotFound     = errors.New("intended challenge type not found")
 )
 
-// Solve accepts a slice of challenges from an authorization and solves the specific challenge
+// Solve accepts an authorization and solves the specific challenge of the authorization
 // specified by the method. Valid or invalid status is returned.  An error is returned if can't resolve
-// a valid or invalid state.
-func (service *Service) Solve(identifier acme.Identifier, challenges []acme.Challenge, method Method, key acme.AccountKey, acmeServerId int) (status string, err error) {
+// a valid or invalid state. If http01SelfCheck is true, an http-01 resource is fetched (the same way
+// the ACME server will) before validation is requested. Diagnostic output from provisioning is added to diag
+// and progress events are sent with emitter.
+func (service *Service) Solve(auth acme.Authorization, method Method, http01SelfCheck bool, key acme.AccountKey, acmeServerId int, diag *diagnostics.Log, emitter *events.Emitter) (status string, err error) {
 	var challenge acme.Challenge
 	found := false
+	identifier := auth.Identifier
 
 	// range to the correct challenge to solve based on Type
-	for i := range challenges {
-		if challenges[i].Type == method.ChallengeType {
+	for i := range auth.Challenges {
+		if auth.Challenges[i].Type == method.ChallengeType {
 			found = true
-			challenge = challenges[i]
+			challenge = auth.Challenges[i]
 		}
 	}
 	if !found {
 		return "", errChallengeTypeNotFound
 	}
 
+	// get the method's provider, the same provider is used to deprovision even if
+	// the provider is reconfigured while solving
+	provider, err := service.provider(method.Value)
+	if err != nil {
+		return "", err
+	}
+
+	// calculate the needed resource
+	resourceName, resourceContent, err := service.validationResource(auth, challenge, method, key)
+	if err != nil {
+		return "", err
+	}
+
 	// provision the needed resource for validation and defer deprovisioning
-	err = service.Provision(identifier, method, key, challenge.Token)
+	err = service.provision(provider, method, resourceName, resourceContent, diag, emitter)
 	// do error check after Deprovision to ensure any records that were created
 	// get cleaned up, even if Provisioning errored.
 
 	defer func() {
-		err := service.Deprovision(identifier, method, key, challenge.Token)
+		err := service.deprovision(provider, resourceName, resourceContent, diag)
 		if err != nil {
 			service.logger.Error(err)
 		}
@@ -46,6 +64,15 @@ func (service *Service) Solve(identifier acme.Identifier, challenges []acme.Chal
 		return "", err
 	}
 
+	// optionally confirm the http-01 resource is reachable before using one of the
+	// ACME server's validation attempts
+	if http01SelfCheck && method.ChallengeType == acme.ChallengeTypeHttp01 {
+		err = service.http01SelfCheck(identifier, challenge.Token, resourceContent, diag)
+		if err != nil {
+			return "", err
+		}
+	}
+
 	// Below this point is to inform ACME the challenge is ready to be validated
 	// by the server and to subsequently monitor the challenge to be moved to the
 	// valid or invalid state.
@@ -86,6 +113,9 @@ func (service *Service) Solve(identifier acme.Identifier, challenges []acme.Chal
 			return challenge.Status, nil
 		} else if challenge.Status == "invalid" {
 			service.logger.Debug(challenge.Error)
+			if challenge.Error != nil {
+				diag.Addf("acme", "%s challenge for %s invalid (%s)", challenge.Type, identifier.Value, challenge.Error)
+			}
 			return challenge.Status, nil
 		}
 		// else repeat loop
@@ -0,0 +1,15 @@
+package cmdoutput
+
+// MaxLength is the max length of command output that is saved (e.g. for deploy
+// hooks and deploy target post commands)
+const MaxLength = 16 * 1024
+
+// Tail returns the end of the output, truncated to MaxLength bytes (the end of
+// the output is kept since that is where errors usually are)
+func Tail(output string) string {
+	if len(output) <= MaxLength {
+		return output
+	}
+
+	return output[len(output)-MaxLength:]
+}
@@ -0,0 +1,90 @@
+package datatypes
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// errors
+var errValueDoesntExist = errors.New("specified value does not exist")
+
+// SafeValueSets is a map of names to sets of string values, with a mutex.
+// It is used when multiple values can exist for the same name at the same
+// time (e.g. two TXT records at one dns name for a wildcard and its apex).
+type SafeValueSets struct {
+	sets map[string]map[string]struct{}
+	sync.RWMutex
+}
+
+// NewSafeValueSets creates a new SafeValueSets
+func NewSafeValueSets() *SafeValueSets {
+	return &SafeValueSets{
+		sets: make(map[string]map[string]struct{}),
+	}
+}
+
+// Add adds value to the set for name. If the value was already in the set,
+// exists is true. The number of values for name after the add is returned.
+func (valueSets *SafeValueSets) Add(name string, value string) (exists bool, count int) {
+	valueSets.Lock()
+	defer valueSets.Unlock()
+
+	set, ok := valueSets.sets[name]
+	if !ok {
+		set = make(map[string]struct{})
+		valueSets.sets[name] = set
+	}
+
+	_, exists = set[value]
+	set[value] = struct{}{}
+
+	return exists, len(set)
+}
+
+// Remove removes value from the set for name and returns the values that
+// remain for name. If the value was not in the set, an error is returned.
+func (valueSets *SafeValueSets) Remove(name string, value string) (remaining []string, err error) {
+	valueSets.Lock()
+	defer valueSets.Unlock()
+
+	set, ok := valueSets.sets[name]
+	if !ok {
+		return nil, errValueDoesntExist
+	}
+
+	_, exists := set[value]
+	if exists {
+		delete(set, value)
+	}
+
+	// clean up empty set
+	if len(set) == 0 {
+		delete(valueSets.sets, name)
+	}
+
+	if !exists {
+		return sortedKeys(set), errValueDoesntExist
+	}
+
+	return sortedKeys(set), nil
+}
+
+// Values returns all of the values currently in the set for name, sorted
+func (valueSets *SafeValueSets) Values(name string) []string {
+	valueSets.RLock()
+	defer valueSets.RUnlock()
+
+	return sortedKeys(valueSets.sets[name])
+}
+
+// sortedKeys returns the keys of set as a sorted slice
+func sortedKeys(set map[string]struct{}) []string {
+	keys := []string{}
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,78 @@
+package datatypes
+
+import (
+	"sync"
+	"testing"
+)
+
+// a wildcard and its apex share the same challenge record name
+const wildcardApexName = "_acme-challenge.example.com"
+
+var wildcardApexValues = []string{
+	"apex_XKrxpRBosdIKFzxW_CT3KLZNf6q0HG9i01zxXp5CPBs",
+	"wild_Gf2Nd4Hc8KsPq0Yz3aBvL7mWxT1uRjE9oIe5dC6hQkA",
+}
+
+func TestSafeValueSets_WildcardAndApex(t *testing.T) {
+	valueSets := NewSafeValueSets()
+
+	// add both values concurrently (as FulfillAuths would)
+	var wg sync.WaitGroup
+	for _, value := range wildcardApexValues {
+		wg.Add(1)
+		go func(value string) {
+			defer wg.Done()
+			exists, _ := valueSets.Add(wildcardApexName, value)
+			if exists {
+				t.Errorf("value '%s' reported as already existing", value)
+			}
+		}(value)
+	}
+	wg.Wait()
+
+	values := valueSets.Values(wildcardApexName)
+	if len(values) != 2 {
+		t.Fatalf("expected 2 values, got %d", len(values))
+	}
+
+	// remove the first, second must remain
+	remaining, err := valueSets.Remove(wildcardApexName, wildcardApexValues[0])
+	if err != nil {
+		t.Errorf("failed to remove value '%s' (%s)", wildcardApexValues[0], err)
+	}
+	if len(remaining) != 1 || remaining[0] != wildcardApexValues[1] {
+		t.Errorf("removing one value did not leave the other (remaining: %v)", remaining)
+	}
+
+	// removing the same value again is an error and doesn't affect the other
+	remaining, err = valueSets.Remove(wildcardApexName, wildcardApexValues[0])
+	if err == nil {
+		t.Errorf("removing value '%s' twice did not error", wildcardApexValues[0])
+	}
+	if len(remaining) != 1 {
+		t.Errorf("repeat remove changed remaining values (remaining: %v)", remaining)
+	}
+
+	// remove the second, nothing remains
+	remaining, err = valueSets.Remove(wildcardApexName, wildcardApexValues[1])
+	if err != nil {
+		t.Errorf("failed to remove value '%s' (%s)", wildcardApexValues[1], err)
+	}
+	if len(remaining) != 0 {
+		t.Errorf("expected no remaining values, got %v", remaining)
+	}
+}
+
+func TestSafeValueSets_AddExisting(t *testing.T) {
+	valueSets := NewSafeValueSets()
+
+	exists, count := valueSets.Add(wildcardApexName, wildcardApexValues[0])
+	if exists || count != 1 {
+		t.Errorf("first add returned exists: %t, count: %d", exists, count)
+	}
+
+	exists, count = valueSets.Add(wildcardApexName, wildcardApexValues[0])
+	if !exists || count != 1 {
+		t.Errorf("repeat add returned exists: %t, count: %d", exists, count)
+	}
+}
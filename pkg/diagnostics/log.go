@@ -0,0 +1,82 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Entry is a single diagnostic message that was recorded while working
+// an order (e.g. the output of a challenge provider script)
+type Entry struct {
+	Time    int    `json:"time"`
+	Source  string `json:"source"`
+	Message string `json:"message"`
+}
+
+// Log is a concurrency safe collection of diagnostic Entries. A nil Log
+// is valid and discards anything added to it.
+type Log struct {
+	entries []Entry
+	mu      sync.Mutex
+}
+
+// NewLog creates a new empty Log
+func NewLog() *Log {
+	return &Log{
+		entries: []Entry{},
+	}
+}
+
+// Add appends a message from the specified source to the Log
+func (log *Log) Add(source string, message string) {
+	if log == nil {
+		return
+	}
+
+	log.mu.Lock()
+	defer log.mu.Unlock()
+
+	log.entries = append(log.entries, Entry{
+		Time:    int(time.Now().Unix()),
+		Source:  source,
+		Message: message,
+	})
+}
+
+// Addf formats a message and then appends it to the Log
+func (log *Log) Addf(source string, format string, args ...interface{}) {
+	log.Add(source, fmt.Sprintf(format, args...))
+}
+
+// Entries returns a copy of all of the Entries currently in the Log
+func (log *Log) Entries() []Entry {
+	if log == nil {
+		return nil
+	}
+
+	log.mu.Lock()
+	defer log.mu.Unlock()
+
+	entries := make([]Entry, len(log.entries))
+	copy(entries, log.entries)
+
+	return entries
+}
+
+// EntriesFromJson converts a json array of Entry objects into a slice of
+// Entry. If the json is nil or invalid, nil is returned.
+func EntriesFromJson(entriesJson *string) []Entry {
+	if entriesJson == nil {
+		return nil
+	}
+
+	var entries []Entry
+	err := json.Unmarshal([]byte(*entriesJson), &entries)
+	if err != nil {
+		return nil
+	}
+
+	return entries
+}
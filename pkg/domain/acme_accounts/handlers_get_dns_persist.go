@@ -0,0 +1,112 @@
+package acme_accounts
+
+import (
+	"errors"
+	"legocerthub-backend/pkg/acme"
+	"legocerthub-backend/pkg/output"
+	"legocerthub-backend/pkg/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+var (
+	errNoKid              = errors.New("account is not registered with the acme server")
+	errDomainBad          = errors.New("domain is not valid")
+	errNoIssuerDomainName = errors.New("acme server does not specify an issuer domain name (caa identity), specify issuer_domain_name")
+)
+
+// dnsPersistRecordResponse is the dns-persist-01 record an account needs for a domain
+type dnsPersistRecordResponse struct {
+	RecordName       string `json:"record_name"`
+	RecordType       string `json:"record_type"`
+	RecordValue      string `json:"record_value"`
+	IssuerDomainName string `json:"issuer_domain_name"`
+	Present          bool   `json:"present"`
+	CheckError       string `json:"check_error,omitempty"`
+}
+
+// GetDnsPersistRecord is an http handler that returns the persistent dns record the
+// account needs to validate a domain using dns-persist-01, and whether that record
+// currently exists. The domain is specified with the domain query param. Optionally,
+// wildcard=true and issuer_domain_name may also be specified.
+func (service *Service) GetDnsPersistRecord(w http.ResponseWriter, r *http.Request) (err error) {
+	// get id from param
+	idParam := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// get from storage
+	account, err := service.getAccount(id)
+	if err != nil {
+		return err
+	}
+
+	// account must be registered with the acme server (the record contains the account uri)
+	if account.Kid == "" {
+		service.logger.Debug(errNoKid)
+		return output.ErrValidationFailed
+	}
+
+	// query params
+	query := r.URL.Query()
+
+	domain := query.Get("domain")
+	if !validation.DomainValid(domain, false) {
+		service.logger.Debug(errDomainBad)
+		return output.ErrValidationFailed
+	}
+
+	wildcard, _ := strconv.ParseBool(query.Get("wildcard"))
+
+	// issuer domain name (default to the server's first caa identity)
+	issuerDomainName := query.Get("issuer_domain_name")
+	if issuerDomainName == "" {
+		acmeService, err := service.acmeServerService.AcmeService(account.AcmeServer.ID)
+		if err != nil {
+			service.logger.Error(err)
+			return output.ErrInternal
+		}
+
+		caaIdentities := acmeService.CaaIdentities()
+		if len(caaIdentities) == 0 {
+			service.logger.Debug(errNoIssuerDomainName)
+			return output.ErrValidationFailed
+		}
+		issuerDomainName = caaIdentities[0]
+	}
+
+	// make record
+	name, value, err := acme.DnsPersistRecord(domain, issuerDomainName, account.Kid, wildcard)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	response := dnsPersistRecordResponse{
+		RecordName:       name,
+		RecordType:       "TXT",
+		RecordValue:      value,
+		IssuerDomainName: issuerDomainName,
+	}
+
+	// check if the record exists
+	response.Present, err = service.challenges.CheckDnsPersistRecord(name, value)
+	if err != nil {
+		service.logger.Debug(err)
+		response.CheckError = err.Error()
+	}
+
+	// return response to client
+	_, err = service.output.WriteJSON(w, http.StatusOK, response, "dns_persist_record")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package acme_accounts
 
 import (
 	"errors"
+	"legocerthub-backend/pkg/challenges"
 	"legocerthub-backend/pkg/domain/acme_servers"
 	"legocerthub-backend/pkg/domain/private_keys"
 	"legocerthub-backend/pkg/output"
@@ -19,6 +20,7 @@ type App interface {
 	GetAccountStorage() Storage
 	GetKeysService() *private_keys.Service
 	GetAcmeServerService() *acme_servers.Service
+	GetChallengesService() *challenges.Service
 }
 
 // Storage interface for storage functions
@@ -47,6 +49,7 @@ type Service struct {
 	storage           Storage
 	keys              *private_keys.Service
 	acmeServerService *acme_servers.Service
+	challenges        *challenges.Service
 }
 
 // NewService creates a new acme_accounts service
@@ -83,5 +86,11 @@ func NewService(app App) (*Service, error) {
 		return nil, errServiceComponent
 	}
 
+	// challenges service
+	service.challenges = app.GetChallengesService()
+	if service.challenges == nil {
+		return nil, errServiceComponent
+	}
+
 	return service, nil
 }
@@ -13,6 +13,7 @@ type Server struct {
 	Description  string
 	DirectoryURL string
 	IsStaging    bool
+	Limits       acme.Limits
 	CreatedAt    int
 	UpdatedAt    int
 }
@@ -64,8 +65,11 @@ func (serv Server) summaryResponse(service *Service) (ServerSummaryResponse, err
 // serverDetailedResponse contains full details about an ACME server
 type serverDetailedResponse struct {
 	ServerSummaryResponse
-	CreatedAt int `json:"created_at"`
-	UpdatedAt int `json:"updated_at"`
+	MaxConcurrentOrders int     `json:"max_concurrent_orders"`
+	RequestsPerSecond   float64 `json:"requests_per_second"`
+	RequestBurst        int     `json:"request_burst"`
+	CreatedAt           int     `json:"created_at"`
+	UpdatedAt           int     `json:"updated_at"`
 }
 
 func (serv Server) detailedResponse(service *Service) (serverDetailedResponse, error) {
@@ -76,6 +80,9 @@ func (serv Server) detailedResponse(service *Service) (serverDetailedResponse, e
 
 	return serverDetailedResponse{
 		ServerSummaryResponse: summaryResp,
+		MaxConcurrentOrders:   serv.Limits.MaxConcurrentOrders,
+		RequestsPerSecond:     serv.Limits.RequestsPerSecond,
+		RequestBurst:          serv.Limits.RequestBurst,
 		CreatedAt:             serv.CreatedAt,
 		UpdatedAt:             serv.UpdatedAt,
 	}, nil
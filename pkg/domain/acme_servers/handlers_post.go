@@ -10,12 +10,15 @@ import (
 
 // NewPayload is used to post a new Server to LeGo
 type NewPayload struct {
-	Name         *string `json:"name"`
-	Description  *string `json:"description"`
-	DirectoryURL *string `json:"directory_url"`
-	IsStaging    *bool   `json:"is_staging"`
-	CreatedAt    int     `json:"-"`
-	UpdatedAt    int     `json:"-"`
+	Name                *string  `json:"name"`
+	Description         *string  `json:"description"`
+	DirectoryURL        *string  `json:"directory_url"`
+	IsStaging           *bool    `json:"is_staging"`
+	MaxConcurrentOrders *int     `json:"max_concurrent_orders"`
+	RequestsPerSecond   *float64 `json:"requests_per_second"`
+	RequestBurst        *int     `json:"request_burst"`
+	CreatedAt           int      `json:"-"`
+	UpdatedAt           int      `json:"-"`
 }
 
 // PostNewServer creates a new server, saves it to storage, and starts an *acme.Service
@@ -52,6 +55,20 @@ func (service *Service) PostNewServer(w http.ResponseWriter, r *http.Request) (e
 		service.logger.Debug("cant post: is_staging is missing")
 		return output.ErrValidationFailed
 	}
+	// limits (optional, default to unlimited)
+	if !limitsValid(payload.MaxConcurrentOrders, payload.RequestsPerSecond, payload.RequestBurst) {
+		service.logger.Debug("cant post: limits can't be negative")
+		return output.ErrValidationFailed
+	}
+	if payload.MaxConcurrentOrders == nil {
+		payload.MaxConcurrentOrders = new(int)
+	}
+	if payload.RequestsPerSecond == nil {
+		payload.RequestsPerSecond = new(float64)
+	}
+	if payload.RequestBurst == nil {
+		payload.RequestBurst = new(int)
+	}
 	// end validation
 
 	// add additional details to the payload before saving
@@ -74,6 +91,11 @@ func (service *Service) PostNewServer(w http.ResponseWriter, r *http.Request) (e
 		service.logger.Error(err)
 		return output.ErrInternal
 	}
+	service.acmeServers[serverId].SetLimits(acme.Limits{
+		MaxConcurrentOrders: *payload.MaxConcurrentOrders,
+		RequestsPerSecond:   *payload.RequestsPerSecond,
+		RequestBurst:        *payload.RequestBurst,
+	})
 
 	// return response to client
 	response := output.JsonResponse{
@@ -14,12 +14,15 @@ import (
 // UpdatePayload is the struct for editing an existing Server's
 // information (only certain fields are editable)
 type UpdatePayload struct {
-	ID           int     `json:"-"`
-	Name         *string `json:"name"`
-	Description  *string `json:"description"`
-	DirectoryURL *string `json:"directory_url"`
-	IsStaging    *bool   `json:"is_staging"`
-	UpdatedAt    int     `json:"-"`
+	ID                  int      `json:"-"`
+	Name                *string  `json:"name"`
+	Description         *string  `json:"description"`
+	DirectoryURL        *string  `json:"directory_url"`
+	IsStaging           *bool    `json:"is_staging"`
+	MaxConcurrentOrders *int     `json:"max_concurrent_orders"`
+	RequestsPerSecond   *float64 `json:"requests_per_second"`
+	RequestBurst        *int     `json:"request_burst"`
+	UpdatedAt           int      `json:"-"`
 }
 
 // PutServerUpdate updates a Server that already exists in storage.
@@ -56,6 +59,11 @@ func (service *Service) PutServerUpdate(w http.ResponseWriter, r *http.Request)
 	if payload.DirectoryURL != nil && !service.directoryUrlValid(*payload.DirectoryURL) {
 		return output.ErrBadDirectoryURL
 	}
+	// limits (optional)
+	if !limitsValid(payload.MaxConcurrentOrders, payload.RequestsPerSecond, payload.RequestBurst) {
+		service.logger.Debug("cant put: limits can't be negative")
+		return output.ErrValidationFailed
+	}
 	// Description, and IsStaging do not need validation
 	// end validation
 
@@ -69,11 +77,17 @@ func (service *Service) PutServerUpdate(w http.ResponseWriter, r *http.Request)
 		return output.ErrStorageGeneric
 	}
 
+	// get the updated server (for limits)
+	server, err := service.getServer(payload.ID)
+	if err != nil {
+		return err
+	}
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
 	// if directory url changed, create new acme.Service
 	if payload.DirectoryURL != nil {
-		service.mu.Lock()
-		defer service.mu.Unlock()
-
 		service.acmeServers[payload.ID], err = acme.NewService(service, *payload.DirectoryURL)
 		if err != nil {
 			service.logger.Error(err)
@@ -81,6 +95,9 @@ func (service *Service) PutServerUpdate(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
+	// apply (possibly updated) limits
+	service.acmeServers[payload.ID].SetLimits(server.Limits)
+
 	// return response to client
 	response := output.JsonResponse{
 		Status:  http.StatusOK,
@@ -115,6 +115,9 @@ func NewService(app App) (*Service, error) {
 			// make service
 			acmeService, err := acme.NewService(app, serv.DirectoryURL)
 			wgErrors <- err
+			if err == nil {
+				acmeService.SetLimits(serv.Limits)
+			}
 
 			// don't directly assign to map so dir fetching can occur simultaneously
 			service.mu.Lock()
@@ -101,3 +101,19 @@ func (service *Service) directoryUrlValid(dirUrl string) bool {
 
 	return true
 }
+
+// limitsValid returns true if the specified limits are not negative (0 is
+// unlimited)
+func limitsValid(maxConcurrentOrders *int, requestsPerSecond *float64, requestBurst *int) bool {
+	if maxConcurrentOrders != nil && *maxConcurrentOrders < 0 {
+		return false
+	}
+	if requestsPerSecond != nil && *requestsPerSecond < 0 {
+		return false
+	}
+	if requestBurst != nil && *requestBurst < 0 {
+		return false
+	}
+
+	return true
+}
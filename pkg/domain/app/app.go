@@ -10,10 +10,18 @@ import (
 	"legocerthub-backend/pkg/domain/app/updater"
 	"legocerthub-backend/pkg/domain/authorizations"
 	"legocerthub-backend/pkg/domain/certificates"
+	"legocerthub-backend/pkg/domain/deploy_endpoints"
+	"legocerthub-backend/pkg/domain/deploy_targets"
 	"legocerthub-backend/pkg/domain/download"
 	"legocerthub-backend/pkg/domain/orders"
 	"legocerthub-backend/pkg/domain/private_keys"
+	"legocerthub-backend/pkg/domain/unmanaged_certificates"
+	"legocerthub-backend/pkg/domain/webhooks"
+	"legocerthub-backend/pkg/encryption"
+	"legocerthub-backend/pkg/events"
+	"legocerthub-backend/pkg/export"
 	"legocerthub-backend/pkg/httpclient"
+	"legocerthub-backend/pkg/notifications"
 	"legocerthub-backend/pkg/output"
 	"legocerthub-backend/pkg/storage/sqlite"
 	"sync"
@@ -32,6 +40,9 @@ const configVersion = 0
 // data storage root
 const dataStoragePath = "./data"
 
+// encryption key file (within data storage root)
+const encryptionKeyFilename = "/encryption.key"
+
 // Application is the main app struct
 type Application struct {
 	config            *config
@@ -40,7 +51,12 @@ type Application struct {
 	shutdownWaitgroup *sync.WaitGroup
 	httpsCert         *datatypes.SafeCert
 	httpClient        *httpclient.Client
+	cipher            *encryption.Cipher
 	output            *output.Service
+	events            *events.Service
+	notifications     *notifications.Service
+	webhooks          *webhooks.Service
+	export            *export.Service
 	router            *httprouter.Router
 	storage           *sqlite.Storage
 	acmeServers       *acme_servers.Service
@@ -52,6 +68,9 @@ type Application struct {
 	authorizations    *authorizations.Service
 	orders            *orders.Service
 	certificates      *certificates.Service
+	unmanagedCerts    *unmanaged_certificates.Service
+	deployTargets     *deploy_targets.Service
+	deployEndpoints   *deploy_endpoints.Service
 	download          *download.Service
 }
 
@@ -98,10 +117,34 @@ func (app *Application) GetHttpClient() *httpclient.Client {
 	return app.httpClient
 }
 
+func (app *Application) GetCipher() *encryption.Cipher {
+	return app.cipher
+}
+
 func (app *Application) GetOutputter() *output.Service {
 	return app.output
 }
 
+func (app *Application) GetEventsService() *events.Service {
+	return app.events
+}
+
+func (app *Application) GetNotificationsService() *notifications.Service {
+	return app.notifications
+}
+
+func (app *Application) GetWebhooksService() *webhooks.Service {
+	return app.webhooks
+}
+
+func (app *Application) GetExportService() *export.Service {
+	return app.export
+}
+
+func (app *Application) GetDeployTargetsService() *deploy_targets.Service {
+	return app.deployTargets
+}
+
 func (app *Application) GetChallengesService() *challenges.Service {
 	return app.challenges
 }
@@ -113,6 +156,21 @@ func (app *Application) GetAuthStorage() auth.Storage {
 func (app *Application) GetKeyStorage() private_keys.Storage {
 	return app.storage
 }
+func (app *Application) GetNotificationsStorage() notifications.Storage {
+	return app.storage
+}
+func (app *Application) GetWebhooksStorage() webhooks.Storage {
+	return app.storage
+}
+func (app *Application) GetExportStorage() export.Storage {
+	return app.storage
+}
+func (app *Application) GetDeployTargetsStorage() deploy_targets.Storage {
+	return app.storage
+}
+func (app *Application) GetDeployEndpointsStorage() deploy_endpoints.Storage {
+	return app.storage
+}
 func (app *Application) GetAcmeServerStorage() acme_servers.Storage {
 	return app.storage
 }
@@ -122,12 +180,18 @@ func (app *Application) GetAccountStorage() acme_accounts.Storage {
 func (app *Application) GetCertificatesStorage() certificates.Storage {
 	return app.storage
 }
+func (app *Application) GetUnmanagedCertificatesStorage() unmanaged_certificates.Storage {
+	return app.storage
+}
 func (app *Application) GetOrderStorage() orders.Storage {
 	return app.storage
 }
 func (app *Application) GetDownloadStorage() download.Storage {
 	return app.storage
 }
+func (app *Application) GetChallengeProviderStorage() challenges.Storage {
+	return app.storage
+}
 
 //
 
@@ -151,6 +215,10 @@ func (app *Application) GetCertificatesService() *certificates.Service {
 	return app.certificates
 }
 
+func (app *Application) GetUnmanagedCertificatesService() *unmanaged_certificates.Service {
+	return app.unmanagedCerts
+}
+
 // shutdown related
 func (app *Application) GetShutdownContext() context.Context {
 	return app.shutdownContext
@@ -7,10 +7,18 @@ import (
 	"legocerthub-backend/pkg/challenges/providers/dns01acmedns"
 	"legocerthub-backend/pkg/challenges/providers/dns01acmesh"
 	"legocerthub-backend/pkg/challenges/providers/dns01cloudflare"
+	"legocerthub-backend/pkg/challenges/providers/dns01internal"
 	"legocerthub-backend/pkg/challenges/providers/dns01manual"
+	"legocerthub-backend/pkg/challenges/providers/dnspersist01"
+	"legocerthub-backend/pkg/challenges/providers/exec_plugin"
 	"legocerthub-backend/pkg/challenges/providers/http01internal"
 	"legocerthub-backend/pkg/domain/app/updater"
+	"legocerthub-backend/pkg/domain/deploy_endpoints"
+	"legocerthub-backend/pkg/domain/deploy_targets"
 	"legocerthub-backend/pkg/domain/orders"
+	"legocerthub-backend/pkg/domain/webhooks"
+	"legocerthub-backend/pkg/export"
+	"legocerthub-backend/pkg/notifications"
 	"os"
 
 	"gopkg.in/yaml.v3"
@@ -21,20 +29,25 @@ const configFile = dataStoragePath + "/config.yaml"
 
 // config is the configuration structure for app (and subsequently services)
 type config struct {
-	ConfigVersion        int               `yaml:"config_version"`
-	BindAddress          *string           `yaml:"bind_address"`
-	HttpsPort            *int              `yaml:"https_port"`
-	HttpPort             *int              `yaml:"http_port"`
-	EnableHttpRedirect   *bool             `yaml:"enable_http_redirect"`
-	LogLevel             *string           `yaml:"log_level"`
-	ServeFrontend        *bool             `yaml:"serve_frontend"`
-	CORSPermittedOrigins []string          `yaml:"cors_permitted_origins"`
-	PrivateKeyName       *string           `yaml:"private_key_name"`
-	CertificateName      *string           `yaml:"certificate_name"`
-	DevMode              *bool             `yaml:"dev_mode"`
-	Updater              updater.Config    `yaml:"updater"`
-	Orders               orders.Config     `yaml:"orders"`
-	Challenges           challenges.Config `yaml:"challenges"`
+	ConfigVersion        int                     `yaml:"config_version"`
+	BindAddress          *string                 `yaml:"bind_address"`
+	HttpsPort            *int                    `yaml:"https_port"`
+	HttpPort             *int                    `yaml:"http_port"`
+	EnableHttpRedirect   *bool                   `yaml:"enable_http_redirect"`
+	LogLevel             *string                 `yaml:"log_level"`
+	ServeFrontend        *bool                   `yaml:"serve_frontend"`
+	CORSPermittedOrigins []string                `yaml:"cors_permitted_origins"`
+	PrivateKeyName       *string                 `yaml:"private_key_name"`
+	CertificateName      *string                 `yaml:"certificate_name"`
+	DevMode              *bool                   `yaml:"dev_mode"`
+	Updater              updater.Config          `yaml:"updater"`
+	Orders               orders.Config           `yaml:"orders"`
+	Notifications        notifications.Config    `yaml:"notifications"`
+	Webhooks             webhooks.Config         `yaml:"webhooks"`
+	Export               export.Config           `yaml:"export"`
+	DeployTargets        deploy_targets.Config   `yaml:"deploy_targets"`
+	DeployEndpoints      deploy_endpoints.Config `yaml:"deploy_endpoints"`
+	Challenges           challenges.Config       `yaml:"challenges"`
 }
 
 // httpAddress() returns formatted http server address string
@@ -97,34 +110,93 @@ func defaultConfig() (cfg *config) {
 		Orders: orders.Config{
 			AutomaticOrderingEnable:     new(bool),
 			ValidRemainingDaysThreshold: new(int),
-			RefreshTimeHour:             new(int),
-			RefreshTimeMinute:           new(int),
+			RenewalJitterMinutes:        new(int),
+			NewOrderDelaySeconds:        new(int),
+			WorkerCount:                 new(int),
+			// renewal windows are a slice, no need to call new()
+			Retention: orders.RetentionConfig{
+				Enable:            new(bool),
+				KeepValidOrders:   new(int),
+				InvalidMaxAgeDays: new(int),
+				IntervalHours:     new(int),
+			},
+			DeployHooks: orders.DeployHooksConfig{
+				Enable:                new(bool),
+				DefaultTimeoutSeconds: new(int),
+			},
+			KeyRotation: orders.KeyRotationConfig{
+				GracePeriodDays: new(int),
+			},
+		},
+		Notifications: notifications.Config{
+			Enable: new(bool),
+			Smtp: notifications.SmtpConfig{
+				Host:     new(string),
+				Port:     new(int),
+				Security: new(string),
+				Username: new(string),
+				Password: new(string),
+				From:     new(string),
+			},
+			// recipients are a slice, no need to call new()
+			ExpiryWarningDays:  new(int),
+			WeeklyDigestEnable: new(bool),
+			WeeklyDigestDay:    new(string),
+			WeeklyDigestHour:   new(int),
+			TemplatesDirectory: new(string),
+		},
+		Webhooks: webhooks.Config{
+			ExpiringDays: new(int),
+		},
+		Export: export.Config{
+			Enable:    new(bool),
+			Directory: new(string),
+			Owner:     new(string),
+			Group:     new(string),
+			FileMode:  new(string),
+		},
+		DeployTargets: deploy_targets.Config{
+			TimeoutSeconds: new(int),
+		},
+		DeployEndpoints: deploy_endpoints.Config{
+			CheckIntervalMinutes: new(int),
+			TimeoutSeconds:       new(int),
 		},
 		Challenges: challenges.Config{
 			DnsCheckerConfig: dns_checker.Config{
 				// skip_check_wait_seconds defaults to nil
 				// servers are a slice, no need to call new()
 			},
+			HealthCheckIntervalMinutes: new(int),
 			ProviderConfigs: challenges.ConfigProviders{
 				Http01InternalConfig: http01internal.Config{
-					Enable: new(bool),
-					Port:   new(int),
+					Enable:        new(bool),
+					BindAddress:   new(string),
+					Port:          new(int),
+					ProxyUpstream: new(string),
 				},
-				Dns01ManualConfig: dns01manual.Config{
-					Enable: new(bool),
-					// script paths don't have a default
-				},
-				Dns01AcmeDnsConfig: dns01acmedns.Config{
+				Dns01InternalConfig: dns01internal.Config{
 					Enable:      new(bool),
-					HostAddress: new(string),
-				},
-				Dns01AcmeShConfig: dns01acmesh.Config{
-					Enable:     new(bool),
-					AcmeShPath: new(string),
+					BindAddress: new(string),
+					Port:        new(int),
+					Zone:        new(string),
+					NameServer:  new(string),
+					Ttl:         new(int),
 				},
-				Dns01CloudflareConfig: dns01cloudflare.Config{
+				DnsPersist01Config: dnspersist01.Config{
 					Enable: new(bool),
 				},
+				ExecPluginsConfig: exec_plugin.Config{
+					Enable:         new(bool),
+					Directory:      new(string),
+					TimeoutSeconds: new(int),
+				},
+				// dns providers are only read from the config file to import them
+				// into storage (see challenges.NewService)
+				Dns01ManualConfig:     dns01manual.DefaultConfig(),
+				Dns01AcmeDnsConfig:    dns01acmedns.DefaultConfig(),
+				Dns01AcmeShConfig:     dns01acmesh.DefaultConfig(),
+				Dns01CloudflareConfig: dns01cloudflare.DefaultConfig(),
 			},
 		},
 	}
@@ -158,8 +230,49 @@ func defaultConfig() (cfg *config) {
 	// orders
 	*cfg.Orders.AutomaticOrderingEnable = true
 	*cfg.Orders.ValidRemainingDaysThreshold = 40
-	*cfg.Orders.RefreshTimeHour = 3
-	*cfg.Orders.RefreshTimeMinute = 12
+	*cfg.Orders.RenewalJitterMinutes = 30
+	cfg.Orders.RenewalWindows = []orders.RenewalWindow{}
+	*cfg.Orders.NewOrderDelaySeconds = 15
+	*cfg.Orders.WorkerCount = 3
+	*cfg.Orders.Retention.Enable = false
+	*cfg.Orders.Retention.KeepValidOrders = 5
+	*cfg.Orders.Retention.InvalidMaxAgeDays = 90
+	*cfg.Orders.Retention.IntervalHours = 24
+	*cfg.Orders.DeployHooks.Enable = false
+	*cfg.Orders.DeployHooks.DefaultTimeoutSeconds = 60
+	*cfg.Orders.KeyRotation.GracePeriodDays = 7
+
+	// notifications
+	*cfg.Notifications.Enable = false
+	*cfg.Notifications.Smtp.Host = "localhost"
+	*cfg.Notifications.Smtp.Port = 587
+	*cfg.Notifications.Smtp.Security = "starttls"
+	*cfg.Notifications.Smtp.Username = ""
+	*cfg.Notifications.Smtp.Password = ""
+	*cfg.Notifications.Smtp.From = ""
+	cfg.Notifications.Recipients = []string{}
+	*cfg.Notifications.ExpiryWarningDays = 14
+	*cfg.Notifications.WeeklyDigestEnable = true
+	*cfg.Notifications.WeeklyDigestDay = "monday"
+	*cfg.Notifications.WeeklyDigestHour = 8
+	*cfg.Notifications.TemplatesDirectory = ""
+
+	// webhooks
+	*cfg.Webhooks.ExpiringDays = 14
+
+	// certificate export
+	*cfg.Export.Enable = false
+	*cfg.Export.Directory = dataStoragePath + "/export"
+	*cfg.Export.Owner = ""
+	*cfg.Export.Group = ""
+	*cfg.Export.FileMode = "0600"
+
+	// deploy targets
+	*cfg.DeployTargets.TimeoutSeconds = 120
+
+	// deploy endpoints
+	*cfg.DeployEndpoints.CheckIntervalMinutes = 60
+	*cfg.DeployEndpoints.TimeoutSeconds = 10
 
 	// challenge dns checker services
 	cfg.Challenges.DnsCheckerConfig.DnsServices = []dns_checker.DnsServiceIPPair{
@@ -180,23 +293,33 @@ func defaultConfig() (cfg *config) {
 		},
 	}
 
+	// challenge provider health checks
+	*cfg.Challenges.HealthCheckIntervalMinutes = 60
+
 	// challenge providers
 	// http-01-internal
 	*cfg.Challenges.ProviderConfigs.Http01InternalConfig.Enable = true
+	*cfg.Challenges.ProviderConfigs.Http01InternalConfig.BindAddress = ""
 	*cfg.Challenges.ProviderConfigs.Http01InternalConfig.Port = 4060
+	*cfg.Challenges.ProviderConfigs.Http01InternalConfig.ProxyUpstream = ""
 
-	// dns-01-manual
-	*cfg.Challenges.ProviderConfigs.Dns01ManualConfig.Enable = false
+	// dns-01-internal
+	*cfg.Challenges.ProviderConfigs.Dns01InternalConfig.Enable = false
+	*cfg.Challenges.ProviderConfigs.Dns01InternalConfig.BindAddress = ""
+	*cfg.Challenges.ProviderConfigs.Dns01InternalConfig.Port = 53
+	*cfg.Challenges.ProviderConfigs.Dns01InternalConfig.Zone = ""
+	*cfg.Challenges.ProviderConfigs.Dns01InternalConfig.NameServer = ""
+	*cfg.Challenges.ProviderConfigs.Dns01InternalConfig.Ttl = 60
 
-	// dns-01-acme-dns
-	*cfg.Challenges.ProviderConfigs.Dns01AcmeDnsConfig.Enable = false
+	// dns-persist-01
+	*cfg.Challenges.ProviderConfigs.DnsPersist01Config.Enable = false
 
-	// dns-01-acme-sh
-	*cfg.Challenges.ProviderConfigs.Dns01AcmeShConfig.Enable = false
-	*cfg.Challenges.ProviderConfigs.Dns01AcmeShConfig.AcmeShPath = "./scripts/acme.sh"
+	// exec plugins
+	*cfg.Challenges.ProviderConfigs.ExecPluginsConfig.Enable = false
+	*cfg.Challenges.ProviderConfigs.ExecPluginsConfig.Directory = "./data/plugins"
+	*cfg.Challenges.ProviderConfigs.ExecPluginsConfig.TimeoutSeconds = 60
 
-	// dns-01-cloudflare
-	*cfg.Challenges.ProviderConfigs.Dns01CloudflareConfig.Enable = false
+	// dns-01 providers use their package defaults
 
 	// end challenge providers
 
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"io"
+	"legocerthub-backend/pkg/challenges"
 	"legocerthub-backend/pkg/output"
 	"legocerthub-backend/pkg/storage/sqlite"
 	"net/http"
@@ -14,11 +15,12 @@ import (
 )
 
 type appStatus struct {
-	Status             string `json:"status"`
-	DevMode            bool   `json:"development_mode,omitempty"`
-	Version            string `json:"version"`
-	DbUserVersion      int    `json:"database_version"`
-	ConfigVersionMatch bool   `json:"config_version_match"`
+	Status             string                  `json:"status"`
+	DevMode            bool                    `json:"development_mode,omitempty"`
+	Version            string                  `json:"version"`
+	DbUserVersion      int                     `json:"database_version"`
+	ConfigVersionMatch bool                    `json:"config_version_match"`
+	ChallengeProviders challenges.HealthStatus `json:"challenge_providers"`
 }
 
 // statusHandler writes some basic info about the status of the Application
@@ -30,6 +32,7 @@ func (app *Application) statusHandler(w http.ResponseWriter, r *http.Request) (e
 		Version:            appVersion,
 		DbUserVersion:      sqlite.DbCurrentUserVersion,
 		ConfigVersionMatch: app.config.ConfigVersion == configVersion,
+		ChallengeProviders: app.challenges.HealthStatus(),
 	}
 
 	_, err = app.output.WriteJSON(w, http.StatusOK, currentStatus, "server")
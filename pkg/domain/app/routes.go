@@ -39,6 +39,18 @@ func (app *Application) routes() http.Handler {
 	app.makeSecureHandle(http.MethodGet, apiUrlPath+"/v1/app/new-version", app.updater.GetNewVersionInfo)
 	app.makeSecureHandle(http.MethodPost, apiUrlPath+"/v1/app/new-version", app.updater.CheckForNewVersion)
 
+	// notifications
+	app.makeSecureHandle(http.MethodPost, apiUrlPath+"/v1/notifications/test", app.notifications.SendTestNotification)
+
+	// webhooks
+	app.makeSecureHandle(http.MethodGet, apiUrlPath+"/v1/webhooks", app.webhooks.GetAllWebhooks)
+	app.makeSecureHandle(http.MethodGet, apiUrlPath+"/v1/webhooks/:id", app.webhooks.GetOneWebhook)
+	app.makeSecureHandle(http.MethodGet, apiUrlPath+"/v1/webhooks/:id/deliveries", app.webhooks.GetWebhookDeliveries)
+
+	app.makeSecureHandle(http.MethodPost, apiUrlPath+"/v1/webhooks", app.webhooks.PostNewWebhook)
+	app.makeSecureHandle(http.MethodPut, apiUrlPath+"/v1/webhooks/:id", app.webhooks.PutWebhookUpdate)
+	app.makeSecureHandle(http.MethodDelete, apiUrlPath+"/v1/webhooks/:id", app.webhooks.DeleteWebhook)
+
 	// acme_servers
 	app.makeSecureHandle(http.MethodGet, apiUrlPath+"/v1/acmeservers", app.acmeServers.GetAllServers)
 	app.makeSecureHandle(http.MethodGet, apiUrlPath+"/v1/acmeservers/:id", app.acmeServers.GetOneServer)
@@ -47,6 +59,14 @@ func (app *Application) routes() http.Handler {
 	app.makeSecureHandle(http.MethodPut, apiUrlPath+"/v1/acmeservers/:id", app.acmeServers.PutServerUpdate)
 	app.makeSecureHandle(http.MethodDelete, apiUrlPath+"/v1/acmeservers/:id", app.acmeServers.DeleteServer)
 
+	// challenge providers
+	app.makeSecureHandle(http.MethodGet, apiUrlPath+"/v1/challenges/providers", app.challenges.GetAllProviders)
+	app.makeSecureHandle(http.MethodGet, apiUrlPath+"/v1/challenges/providers/:id", app.challenges.GetOneProvider)
+
+	app.makeSecureHandle(http.MethodPost, apiUrlPath+"/v1/challenges/providers", app.challenges.PostNewProvider)
+	app.makeSecureHandle(http.MethodPut, apiUrlPath+"/v1/challenges/providers/:id", app.challenges.PutProviderUpdate)
+	app.makeSecureHandle(http.MethodDelete, apiUrlPath+"/v1/challenges/providers/:id", app.challenges.DeleteProvider)
+
 	// private_keys
 	app.makeSecureHandle(http.MethodGet, apiUrlPath+"/v1/privatekeys", app.keys.GetAllKeys)
 	app.makeSecureHandle(http.MethodGet, apiUrlPath+"/v1/privatekeys/:id", app.keys.GetOneKey)
@@ -63,6 +83,7 @@ func (app *Application) routes() http.Handler {
 	// acme_accounts
 	app.makeSecureHandle(http.MethodGet, apiUrlPath+"/v1/acmeaccounts", app.accounts.GetAllAccounts)
 	app.makeSecureHandle(http.MethodGet, apiUrlPath+"/v1/acmeaccounts/:id", app.accounts.GetOneAccount)
+	app.makeSecureHandle(http.MethodGet, apiUrlPath+"/v1/acmeaccounts/:id/dnspersist", app.accounts.GetDnsPersistRecord)
 
 	app.makeSecureHandle(http.MethodPost, apiUrlPath+"/v1/acmeaccounts", app.accounts.PostNewAccount)
 
@@ -85,11 +106,44 @@ func (app *Application) routes() http.Handler {
 	app.makeSecureHandle(http.MethodDelete, apiUrlPath+"/v1/certificates/:certid/apikey", app.certificates.RemoveOldApiKey)
 
 	app.makeSecureHandle(http.MethodPut, apiUrlPath+"/v1/certificates/:certid", app.certificates.PutDetailsCert)
+	app.makeSecureHandle(http.MethodPut, apiUrlPath+"/v1/certificates/:certid/csr", app.certificates.PutCertCsr)
 
 	app.makeSecureHandle(http.MethodDelete, apiUrlPath+"/v1/certificates/:certid", app.certificates.DeleteCert)
 
+	// unmanaged certificates
+	app.makeSecureHandle(http.MethodGet, apiUrlPath+"/v1/unmanaged_certificates", app.unmanagedCerts.GetAllCerts)
+	app.makeSecureHandle(http.MethodGet, apiUrlPath+"/v1/unmanaged_certificates/:id", app.unmanagedCerts.GetOneCert)
+
+	app.makeSecureHandle(http.MethodPost, apiUrlPath+"/v1/unmanaged_certificates", app.unmanagedCerts.PostNewCert)
+	app.makeSecureHandle(http.MethodPost, apiUrlPath+"/v1/unmanaged_certificates/:id/convert", app.certificates.ConvertUnmanagedCert)
+
+	app.makeSecureHandle(http.MethodPut, apiUrlPath+"/v1/unmanaged_certificates/:id", app.unmanagedCerts.PutCertUpdate)
+
+	app.makeSecureHandle(http.MethodDelete, apiUrlPath+"/v1/unmanaged_certificates/:id", app.unmanagedCerts.DeleteCert)
+
+	// deploy targets (for certificates)
+	app.makeSecureHandle(http.MethodGet, apiUrlPath+"/v1/certificates/:certid/deploy_targets", app.deployTargets.GetCertDeployTargets)
+	app.makeSecureHandle(http.MethodGet, apiUrlPath+"/v1/certificates/:certid/deploy_targets/:id", app.deployTargets.GetOneDeployTarget)
+
+	app.makeSecureHandle(http.MethodPost, apiUrlPath+"/v1/certificates/:certid/deploy_targets", app.deployTargets.PostNewDeployTarget)
+	app.makeSecureHandle(http.MethodPut, apiUrlPath+"/v1/certificates/:certid/deploy_targets/:id", app.deployTargets.PutDeployTargetUpdate)
+	app.makeSecureHandle(http.MethodDelete, apiUrlPath+"/v1/certificates/:certid/deploy_targets/:id", app.deployTargets.DeleteDeployTarget)
+
+	// deploy endpoints (for certificates)
+	app.makeSecureHandle(http.MethodGet, apiUrlPath+"/v1/certificates/:certid/deploy_endpoints", app.deployEndpoints.GetCertDeployEndpoints)
+	app.makeSecureHandle(http.MethodGet, apiUrlPath+"/v1/certificates/:certid/deploy_endpoints/:id", app.deployEndpoints.GetOneDeployEndpoint)
+	app.makeSecureHandle(http.MethodGet, apiUrlPath+"/v1/deploy_endpoints/flagged", app.deployEndpoints.GetFlaggedDeployEndpoints)
+
+	app.makeSecureHandle(http.MethodPost, apiUrlPath+"/v1/certificates/:certid/deploy_endpoints", app.deployEndpoints.PostNewDeployEndpoint)
+	app.makeSecureHandle(http.MethodPost, apiUrlPath+"/v1/certificates/:certid/deploy_endpoints/:id/check", app.deployEndpoints.CheckDeployEndpoint)
+	app.makeSecureHandle(http.MethodPut, apiUrlPath+"/v1/certificates/:certid/deploy_endpoints/:id", app.deployEndpoints.PutDeployEndpointUpdate)
+	app.makeSecureHandle(http.MethodDelete, apiUrlPath+"/v1/certificates/:certid/deploy_endpoints/:id", app.deployEndpoints.DeleteDeployEndpoint)
+
 	// orders (for certificates)
 	app.makeSecureHandle(http.MethodGet, apiUrlPath+"/v1/orders/currentvalid", app.orders.GetAllValidCurrentOrders)
+	app.makeSecureHandle(http.MethodGet, apiUrlPath+"/v1/orders/jobs", app.orders.GetOrderJobs)
+	app.makeSecureHandle(http.MethodGet, apiUrlPath+"/v1/orders/events", app.events.StreamOrderEvents)
+	app.makeSecureHandle(http.MethodGet, apiUrlPath+"/v1/orders/retention/preview", app.orders.GetRetentionPreview)
 	app.makeSecureHandle(http.MethodGet, apiUrlPath+"/v1/certificates/:certid/orders", app.orders.GetCertOrders)
 	app.makeSecureHandle(http.MethodPost, apiUrlPath+"/v1/certificates/:certid/orders", app.orders.NewOrder)
 
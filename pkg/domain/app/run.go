@@ -11,10 +11,18 @@ import (
 	"legocerthub-backend/pkg/domain/app/updater"
 	"legocerthub-backend/pkg/domain/authorizations"
 	"legocerthub-backend/pkg/domain/certificates"
+	"legocerthub-backend/pkg/domain/deploy_endpoints"
+	"legocerthub-backend/pkg/domain/deploy_targets"
 	"legocerthub-backend/pkg/domain/download"
 	"legocerthub-backend/pkg/domain/orders"
 	"legocerthub-backend/pkg/domain/private_keys"
+	"legocerthub-backend/pkg/domain/unmanaged_certificates"
+	"legocerthub-backend/pkg/domain/webhooks"
+	"legocerthub-backend/pkg/encryption"
+	"legocerthub-backend/pkg/events"
+	"legocerthub-backend/pkg/export"
 	"legocerthub-backend/pkg/httpclient"
+	"legocerthub-backend/pkg/notifications"
 	"legocerthub-backend/pkg/output"
 	"legocerthub-backend/pkg/storage/sqlite"
 	"net/http"
@@ -243,6 +251,13 @@ func create(ctx context.Context) (*Application, error) {
 	userAgent := fmt.Sprintf("LeGoCertHub/%s (%s; %s)", appVersion, runtime.GOOS, runtime.GOARCH)
 	app.httpClient = httpclient.New(userAgent, *app.config.DevMode)
 
+	// cipher to encrypt sensitive data in storage (key is kept outside of the db)
+	app.cipher, err = encryption.NewCipher(dataStoragePath + encryptionKeyFilename)
+	if err != nil {
+		app.logger.Errorf("failed to configure app encryption (%s)", err)
+		return app, err
+	}
+
 	// output service
 	app.output, err = output.NewService(app)
 	if err != nil {
@@ -250,6 +265,13 @@ func create(ctx context.Context) (*Application, error) {
 		return app, err
 	}
 
+	// order progress events service
+	app.events, err = events.NewService(app)
+	if err != nil {
+		app.logger.Errorf("failed to configure app events (%s)", err)
+		return app, err
+	}
+
 	// storage
 	app.storage, err = sqlite.OpenStorage(app, dataStoragePath)
 	if err != nil {
@@ -257,6 +279,27 @@ func create(ctx context.Context) (*Application, error) {
 		return app, err
 	}
 
+	// notifications
+	app.notifications, err = notifications.NewService(app, &app.config.Notifications)
+	if err != nil {
+		app.logger.Errorf("failed to configure app notifications (%s)", err)
+		return app, err
+	}
+
+	// webhooks
+	app.webhooks, err = webhooks.NewService(app, &app.config.Webhooks)
+	if err != nil {
+		app.logger.Errorf("failed to configure app webhooks (%s)", err)
+		return app, err
+	}
+
+	// certificate export
+	app.export, err = export.NewService(app, &app.config.Export)
+	if err != nil {
+		app.logger.Errorf("failed to configure app certificate export (%s)", err)
+		return app, err
+	}
+
 	// acmeServers
 	app.acmeServers, err = acme_servers.NewService(app)
 	if err != nil {
@@ -318,6 +361,13 @@ func create(ctx context.Context) (*Application, error) {
 		return app, err
 	}
 
+	// unmanaged certificates service
+	app.unmanagedCerts, err = unmanaged_certificates.NewService(app)
+	if err != nil {
+		app.logger.Errorf("failed to configure app unmanaged certificates (%s)", err)
+		return app, err
+	}
+
 	// certificates service
 	app.certificates, err = certificates.NewService(app)
 	if err != nil {
@@ -325,6 +375,20 @@ func create(ctx context.Context) (*Application, error) {
 		return app, err
 	}
 
+	// deploy targets service
+	app.deployTargets, err = deploy_targets.NewService(app, &app.config.DeployTargets)
+	if err != nil {
+		app.logger.Errorf("failed to configure app deploy targets (%s)", err)
+		return app, err
+	}
+
+	// deploy endpoints service
+	app.deployEndpoints, err = deploy_endpoints.NewService(app, &app.config.DeployEndpoints)
+	if err != nil {
+		app.logger.Errorf("failed to configure app deploy endpoints (%s)", err)
+		return app, err
+	}
+
 	// orders service
 	app.orders, err = orders.NewService(app, &app.config.Orders)
 	if err != nil {
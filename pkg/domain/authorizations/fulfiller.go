@@ -4,6 +4,8 @@ import (
 	"errors"
 	"legocerthub-backend/pkg/acme"
 	"legocerthub-backend/pkg/challenges"
+	"legocerthub-backend/pkg/diagnostics"
+	"legocerthub-backend/pkg/events"
 	"sync"
 )
 
@@ -12,7 +14,10 @@ var errAuthPending = errors.New("one or more auths are still in 'pending' status
 // FulfillAuths attempts to validate each of the auth URLs in the slice of auth URLs. It returns 'valid' Status if all auths were
 // determined to be 'valid'. It returns 'invalid' if any of the auths were determined to be in any state other than valid or pending.
 // It returns an error if any of the auth Statuses could not be determined or if any are still in pending.
-func (service *Service) FulfillAuths(authUrls []string, method challenges.Method, key acme.AccountKey, acmeServerId int) (status string, err error) {
+// If http01SelfCheck is true, http-01 challenge resources are fetched before ACME is asked to validate them.
+// Diagnostic output from solving challenges is added to diag and progress events are sent
+// with emitter.
+func (service *Service) FulfillAuths(authUrls []string, method challenges.Method, http01SelfCheck bool, key acme.AccountKey, acmeServerId int, diag *diagnostics.Log, emitter *events.Emitter) (status string, err error) {
 	// aysnc checking the authz for validity
 	var wg sync.WaitGroup
 	wgSize := len(authUrls)
@@ -26,7 +31,7 @@ func (service *Service) FulfillAuths(authUrls []string, method challenges.Method
 	for i := range authUrls {
 		go func(authUrl string, method challenges.Method, key acme.AccountKey, acmeServerId int) {
 			defer wg.Done()
-			status, err = service.fulfillAuth(authUrl, method, key, acmeServerId)
+			status, err := service.fulfillAuth(authUrl, method, http01SelfCheck, key, acmeServerId, diag, emitter)
 			wgStatuses <- status
 			wgErrors <- err
 		}(authUrls[i], method, key, acmeServerId)
@@ -61,7 +66,7 @@ func (service *Service) FulfillAuths(authUrls []string, method challenges.Method
 
 // fulfillAuth attempts to validate an auth URL using the specified method. It will either respond from cache
 // or call an authWorker.  An error is returned if the auth status could not be determined.
-func (service *Service) fulfillAuth(authUrl string, method challenges.Method, key acme.AccountKey, acmeServerId int) (status string, err error) {
+func (service *Service) fulfillAuth(authUrl string, method challenges.Method, http01SelfCheck bool, key acme.AccountKey, acmeServerId int, diag *diagnostics.Log, emitter *events.Emitter) (status string, err error) {
 	// add authUrl to working and call a worker, if the authUrl is already being worked,
 	// block and return the cached result. If the cached result is an error, try to work
 	// the auth again.
@@ -95,7 +100,7 @@ func (service *Service) fulfillAuth(authUrl string, method challenges.Method, ke
 	}(authUrl, service)
 
 	// work the auth
-	status, err = service.authWorker(authUrl, method, key, acmeServerId)
+	status, err = service.authWorker(authUrl, method, http01SelfCheck, key, acmeServerId, diag, emitter)
 
 	// cache result &
 	// error check
@@ -110,7 +115,7 @@ func (service *Service) fulfillAuth(authUrl string, method challenges.Method, ke
 
 // authWorker returns the Status of an authorization URL. If the authorization Status is currently 'pending', authWorker attempts to
 // move the authorization to the 'valid' Status.  An error is returned if the Status can't be determined.
-func (service *Service) authWorker(authUrl string, method challenges.Method, key acme.AccountKey, acmeServerId int) (status string, err error) {
+func (service *Service) authWorker(authUrl string, method challenges.Method, http01SelfCheck bool, key acme.AccountKey, acmeServerId int, diag *diagnostics.Log, emitter *events.Emitter) (status string, err error) {
 	// PaG the authorization
 	acmeService, err := service.acmeServerService.AcmeService(acmeServerId)
 	if err != nil {
@@ -126,7 +131,9 @@ func (service *Service) authWorker(authUrl string, method challenges.Method, key
 	switch auth.Status {
 	// try to solve a challenge if auth is pending
 	case "pending":
-		auth.Status, err = service.challenges.Solve(auth.Identifier, auth.Challenges, method, key, acmeServerId)
+		emitter.Emitf(events.TypeAuthzPending, "authorization for %s is pending, solving %s challenge", auth.Identifier.Value, method.ChallengeType)
+
+		auth.Status, err = service.challenges.Solve(auth, method, http01SelfCheck, key, acmeServerId, diag, emitter)
 		// return error if couldn't solve
 		if err != nil {
 			return "", err
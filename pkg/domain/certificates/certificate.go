@@ -28,6 +28,12 @@ type Certificate struct {
 	ApiKey             string
 	ApiKeyNew          string
 	ApiKeyViaUrl       bool
+	Http01SelfCheck    bool
+	RenewalPolicy      RenewalPolicy
+	KeyRotation        KeyRotation
+	NotificationEmails []string
+	DeployHooks        []DeployHook
+	CsrPem             string
 }
 
 // certificateSummaryResponse is a JSON response containing only
@@ -36,12 +42,13 @@ type certificateSummaryResponse struct {
 	ID                 int                               `json:"id"`
 	Name               string                            `json:"name"`
 	Description        string                            `json:"description"`
-	CertificateKey     certificateKeySummaryResponse     `json:"private_key"`
+	CertificateKey     *certificateKeySummaryResponse    `json:"private_key"`
 	CertificateAccount certificateAccountSummaryResponse `json:"acme_account"`
 	Subject            string                            `json:"subject"`
 	SubjectAltNames    []string                          `json:"subject_alts"`
 	ChallengeMethod    challenges.MethodWithStatus       `json:"challenge_method"`
 	ApiKeyViaUrl       bool                              `json:"api_key_via_url"`
+	Http01SelfCheck    bool                              `json:"http01_self_check"`
 }
 
 type certificateKeySummaryResponse struct {
@@ -62,14 +69,20 @@ type certificateAccountServerSummaryResponse struct {
 }
 
 func (cert Certificate) summaryResponse(service *Service) certificateSummaryResponse {
-	return certificateSummaryResponse{
-		ID:          cert.ID,
-		Name:        cert.Name,
-		Description: cert.Description,
-		CertificateKey: certificateKeySummaryResponse{
+	// certs defined by a csr have no key
+	var certKey *certificateKeySummaryResponse
+	if !cert.HasCsr() {
+		certKey = &certificateKeySummaryResponse{
 			ID:   cert.CertificateKey.ID,
 			Name: cert.CertificateKey.Name,
-		},
+		}
+	}
+
+	return certificateSummaryResponse{
+		ID:             cert.ID,
+		Name:           cert.Name,
+		Description:    cert.Description,
+		CertificateKey: certKey,
 		CertificateAccount: certificateAccountSummaryResponse{
 			ID:   cert.CertificateAccount.ID,
 			Name: cert.CertificateAccount.Name,
@@ -83,6 +96,7 @@ func (cert Certificate) summaryResponse(service *Service) certificateSummaryResp
 		SubjectAltNames: cert.SubjectAltNames,
 		ChallengeMethod: service.challenges.AddStatus(cert.ChallengeMethod),
 		ApiKeyViaUrl:    cert.ApiKeyViaUrl,
+		Http01SelfCheck: cert.Http01SelfCheck,
 	}
 }
 
@@ -90,15 +104,20 @@ func (cert Certificate) summaryResponse(service *Service) certificateSummaryResp
 // fields that can be returned as JSON
 type certificateDetailedResponse struct {
 	certificateSummaryResponse
-	Organization       string `json:"organization"`
-	OrganizationalUnit string `json:"organizational_unit"`
-	Country            string `json:"country"`
-	State              string `json:"state"`
-	City               string `json:"city"`
-	CreatedAt          int    `json:"created_at"`
-	UpdatedAt          int    `json:"updated_at"`
-	ApiKey             string `json:"api_key"`
-	ApiKeyNew          string `json:"api_key_new,omitempty"`
+	Organization       string        `json:"organization"`
+	OrganizationalUnit string        `json:"organizational_unit"`
+	Country            string        `json:"country"`
+	State              string        `json:"state"`
+	City               string        `json:"city"`
+	CreatedAt          int           `json:"created_at"`
+	UpdatedAt          int           `json:"updated_at"`
+	ApiKey             string        `json:"api_key"`
+	ApiKeyNew          string        `json:"api_key_new,omitempty"`
+	RenewalPolicy      RenewalPolicy `json:"renewal_policy"`
+	KeyRotation        KeyRotation   `json:"key_rotation"`
+	NotificationEmails []string      `json:"notification_emails"`
+	DeployHooks        []DeployHook  `json:"deploy_hooks"`
+	Csr                *string       `json:"csr"`
 }
 
 func (cert Certificate) detailedResponse(service *Service, withSensitive bool) certificateDetailedResponse {
@@ -113,6 +132,11 @@ func (cert Certificate) detailedResponse(service *Service, withSensitive bool) c
 		}
 	}
 
+	var csr *string
+	if cert.HasCsr() {
+		csr = &cert.CsrPem
+	}
+
 	return certificateDetailedResponse{
 		certificateSummaryResponse: cert.summaryResponse(service),
 		Organization:               cert.Organization,
@@ -124,6 +148,11 @@ func (cert Certificate) detailedResponse(service *Service, withSensitive bool) c
 		UpdatedAt:                  cert.UpdatedAt,
 		ApiKey:                     apiKey,
 		ApiKeyNew:                  apiKeyNew,
+		RenewalPolicy:              cert.RenewalPolicy,
+		KeyRotation:                cert.KeyRotation,
+		NotificationEmails:         cert.NotificationEmails,
+		DeployHooks:                cert.DeployHooks,
+		Csr:                        csr,
 	}
 }
 
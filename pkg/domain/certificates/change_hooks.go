@@ -0,0 +1,15 @@
+package certificates
+
+// OnChange registers fn to be called each time a certificate is created, updated,
+// or deleted. Hooks should be registered when services are created (before the
+// api is serving) and fn should not block.
+func (service *Service) OnChange(fn func()) {
+	service.changeHooks = append(service.changeHooks, fn)
+}
+
+// notifyChange calls all of the registered change hooks
+func (service *Service) notifyChange() {
+	for _, fn := range service.changeHooks {
+		fn()
+	}
+}
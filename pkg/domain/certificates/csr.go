@@ -4,11 +4,37 @@ import (
 	"crypto/rand"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
 	"legocerthub-backend/pkg/domain/private_keys/key_crypto"
+	"strings"
 )
 
-// MakeCsrDer generates the CSR bytes for ACME to POST To a Finalize URL
+var (
+	ErrCsrBad        = errors.New("csr is not valid (must be a single pem encoded certificate request with a valid signature and only dns names)")
+	ErrCsrAndKey     = errors.New("csr and private key (or key algorithm) both specified")
+	ErrCsrFieldsBad  = errors.New("private key, subject alts, and csr fields can't be changed for a certificate defined by a csr")
+	ErrCsrNotDefined = errors.New("certificate is not defined by a csr")
+	ErrCsrRotation   = errors.New("key rotation can't be enabled for a certificate defined by a csr")
+)
+
+// HasCsr returns true if the certificate is defined by an uploaded CSR instead of
+// a private key
+func (cert *Certificate) HasCsr() bool {
+	return cert.CsrPem != ""
+}
+
+// MakeCsrDer generates the CSR bytes for ACME to POST To a Finalize URL. If the
+// certificate is defined by an uploaded CSR, that CSR is used as is.
 func (cert *Certificate) MakeCsrDer() (csr []byte, err error) {
+	if cert.HasCsr() {
+		uploaded, err := parseCsrPem(cert.CsrPem)
+		if err != nil {
+			return nil, err
+		}
+		return uploaded.Raw, nil
+	}
+
 	// create Subject
 	subj := pkix.Name{
 		CommonName:         cert.Subject,
@@ -44,3 +70,88 @@ func (cert *Certificate) MakeCsrDer() (csr []byte, err error) {
 
 	return csr, nil
 }
+
+// parseCsrPem decodes and validates an uploaded pem CSR. The signature must be
+// valid and only dns names may be requested.
+func parseCsrPem(csrPem string) (*x509.CertificateRequest, error) {
+	block, rest := pem.Decode([]byte(csrPem))
+	if block == nil || block.Type != "CERTIFICATE REQUEST" || strings.TrimSpace(string(rest)) != "" {
+		return nil, ErrCsrBad
+	}
+
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, ErrCsrBad
+	}
+
+	err = csr.CheckSignature()
+	if err != nil {
+		return nil, ErrCsrBad
+	}
+
+	// acme only issues for dns identifiers
+	if len(csr.EmailAddresses) > 0 || len(csr.IPAddresses) > 0 || len(csr.URIs) > 0 {
+		return nil, ErrCsrBad
+	}
+	if csr.Subject.CommonName == "" && len(csr.DNSNames) == 0 {
+		return nil, ErrCsrBad
+	}
+
+	return csr, nil
+}
+
+// csrDetails is the information a certificate derives from its uploaded CSR
+type csrDetails struct {
+	pem                string
+	subject            string
+	subjectAltNames    []string
+	organization       string
+	organizationalUnit string
+	country            string
+	state              string
+	city               string
+}
+
+// parseCsrDetails validates the uploaded pem CSR and returns the details it
+// defines. The subject is the CSR's common name (or its first dns name if there is
+// no common name) and the subject alts are the rest of the dns names.
+func parseCsrDetails(csrPem string) (csrDetails, error) {
+	csr, err := parseCsrPem(csrPem)
+	if err != nil {
+		return csrDetails{}, err
+	}
+
+	subject := strings.ToLower(csr.Subject.CommonName)
+	if subject == "" {
+		subject = strings.ToLower(csr.DNSNames[0])
+	}
+
+	alts := []string{}
+	seen := map[string]bool{subject: true}
+	for _, name := range csr.DNSNames {
+		name = strings.ToLower(name)
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		alts = append(alts, name)
+	}
+
+	first := func(values []string) string {
+		if len(values) == 0 {
+			return ""
+		}
+		return values[0]
+	}
+
+	return csrDetails{
+		pem:                string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr.Raw})),
+		subject:            subject,
+		subjectAltNames:    alts,
+		organization:       first(csr.Subject.Organization),
+		organizationalUnit: first(csr.Subject.OrganizationalUnit),
+		country:            first(csr.Subject.Country),
+		state:              first(csr.Subject.Province),
+		city:               first(csr.Subject.Locality),
+	}, nil
+}
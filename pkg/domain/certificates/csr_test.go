@@ -0,0 +1,92 @@
+package certificates
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"net"
+	"reflect"
+	"testing"
+)
+
+// testCsrPem returns a pem CSR for the template signed by a new key
+func testCsrPem(t *testing.T, template x509.CertificateRequest) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, &template, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+}
+
+func TestParseCsrDetails(t *testing.T) {
+	csrPem := testCsrPem(t, x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: "Example.com", Organization: []string{"Org"}, Country: []string{"US"}},
+		DNSNames: []string{"example.com", "www.example.com", "api.example.com", "WWW.example.com"},
+	})
+
+	details, err := parseCsrDetails(csrPem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if details.subject != "example.com" ||
+		!reflect.DeepEqual(details.subjectAltNames, []string{"www.example.com", "api.example.com"}) {
+		t.Errorf("names are %s %v", details.subject, details.subjectAltNames)
+	}
+	if details.organization != "Org" || details.country != "US" || details.city != "" {
+		t.Errorf("csr fields are %+v", details)
+	}
+	if details.pem != csrPem {
+		t.Errorf("pem was not standardized (%q)", details.pem)
+	}
+
+	// no common name, first dns name is the subject
+	details, err = parseCsrDetails(testCsrPem(t, x509.CertificateRequest{
+		DNSNames: []string{"a.example.com", "b.example.com"},
+	}))
+	if err != nil || details.subject != "a.example.com" ||
+		!reflect.DeepEqual(details.subjectAltNames, []string{"b.example.com"}) {
+		t.Errorf("names are %s %v (err %v)", details.subject, details.subjectAltNames, err)
+	}
+}
+
+func TestParseCsrDetails_Invalid(t *testing.T) {
+	valid := testCsrPem(t, x509.CertificateRequest{DNSNames: []string{"example.com"}})
+
+	// corrupt the signature
+	block, _ := pem.Decode([]byte(valid))
+	block.Bytes[len(block.Bytes)-1] ^= 0xff
+	badSignature := string(pem.EncodeToMemory(block))
+
+	for name, csrPem := range map[string]string{
+		"empty":         "",
+		"not a csr":     "-----BEGIN CERTIFICATE-----\nAAAA\n-----END CERTIFICATE-----\n",
+		"bad signature": badSignature,
+		"two csrs":      valid + valid,
+		"ip address":    testCsrPem(t, x509.CertificateRequest{DNSNames: []string{"example.com"}, IPAddresses: []net.IP{net.ParseIP("10.0.0.1")}}),
+		"no names":      testCsrPem(t, x509.CertificateRequest{Subject: pkix.Name{Organization: []string{"Org"}}}),
+	} {
+		_, err := parseCsrDetails(csrPem)
+		if err != ErrCsrBad {
+			t.Errorf("%s: err is %v (expected ErrCsrBad)", name, err)
+		}
+	}
+}
+
+func TestMakeCsrDer_Uploaded(t *testing.T) {
+	csrPem := testCsrPem(t, x509.CertificateRequest{DNSNames: []string{"example.com"}})
+	block, _ := pem.Decode([]byte(csrPem))
+
+	cert := Certificate{Subject: "example.com", CsrPem: csrPem}
+	der, err := cert.MakeCsrDer()
+	if err != nil || !reflect.DeepEqual(der, block.Bytes) {
+		t.Errorf("uploaded csr was not used (err %v)", err)
+	}
+}
@@ -0,0 +1,57 @@
+package certificates
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+)
+
+// deploy hook limits
+const (
+	maxDeployHooks              = 10
+	maxDeployHookTimeoutSeconds = 3600
+)
+
+var ErrDeployHooksBad = errors.New("deploy hooks are not valid (each command must be an absolute path, timeout_seconds must be 0 to 3600, max 10 hooks)")
+
+// DeployHook is a command that is run after a new certificate is issued. The
+// command is run directly (not in a shell) with Args. Its environment includes
+// paths to temporary files containing the key, cert, chain, and fullchain. If
+// TimeoutSeconds is 0, the configured default timeout is used.
+type DeployHook struct {
+	Command        string   `json:"command"`
+	Args           []string `json:"args"`
+	TimeoutSeconds int      `json:"timeout_seconds"`
+}
+
+// deployHooksValid returns true if there aren't too many hooks and each hook's
+// command and timeout are valid
+func deployHooksValid(hooks []DeployHook) bool {
+	if len(hooks) > maxDeployHooks {
+		return false
+	}
+
+	for _, hook := range hooks {
+		if hook.Command == "" || !filepath.IsAbs(hook.Command) {
+			return false
+		}
+
+		if hook.TimeoutSeconds < 0 || hook.TimeoutSeconds > maxDeployHookTimeoutSeconds {
+			return false
+		}
+	}
+
+	return true
+}
+
+// DeployHooksFromJson converts a json array of DeployHook objects into a slice
+// of DeployHook. If the json is invalid, an empty slice is returned.
+func DeployHooksFromJson(hooksJson string) []DeployHook {
+	hooks := []DeployHook{}
+	err := json.Unmarshal([]byte(hooksJson), &hooks)
+	if err != nil || hooks == nil {
+		return []DeployHook{}
+	}
+
+	return hooks
+}
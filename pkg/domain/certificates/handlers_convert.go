@@ -0,0 +1,183 @@
+package certificates
+
+import (
+	"encoding/json"
+	"legocerthub-backend/pkg/challenges"
+	"legocerthub-backend/pkg/domain/private_keys"
+	"legocerthub-backend/pkg/domain/private_keys/key_crypto"
+	"legocerthub-backend/pkg/output"
+	"legocerthub-backend/pkg/randomness"
+	"legocerthub-backend/pkg/storage"
+	"legocerthub-backend/pkg/validation"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// ConvertPayload is the struct for converting an unmanaged cert into a managed
+// cert. The algorithm is only used (and required) if the unmanaged cert doesn't
+// have a key.
+type ConvertPayload struct {
+	AcmeAccountID        *int                    `json:"acme_account_id"`
+	ChallengeMethodValue *challenges.MethodValue `json:"challenge_method_value"`
+	NewKeyAlgorithmValue *string                 `json:"algorithm_value"`
+}
+
+// ConvertUnmanagedCert replaces an unmanaged cert with a managed cert of the same
+// name, description, names, key, notification emails, and api key. No order is
+// placed; the imported pem stops being served once the unmanaged cert is removed,
+// so an order should be placed right away.
+func (service *Service) ConvertUnmanagedCert(w http.ResponseWriter, r *http.Request) (err error) {
+	var payload ConvertPayload
+
+	// decode body into payload
+	err = json.NewDecoder(r.Body).Decode(&payload)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// get id from param
+	idParam := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	unmanagedId, err := strconv.Atoi(idParam)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// validation
+	// unmanaged cert
+	if !validation.IsIdExistingValidRange(unmanagedId) {
+		service.logger.Debug(ErrIdBad)
+		return output.ErrValidationFailed
+	}
+	unmanagedCert, err := service.storage.GetOneUnmanagedCertById(unmanagedId)
+	if err != nil {
+		if err == storage.ErrNoRecord {
+			service.logger.Debug(err)
+			return output.ErrNotFound
+		}
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+	// acme account
+	if payload.AcmeAccountID == nil || !service.accounts.AccountUsable(*payload.AcmeAccountID) {
+		service.logger.Debug("acme account is not usable")
+		return output.ErrValidationFailed
+	}
+	// challenge method
+	if payload.ChallengeMethodValue == nil {
+		service.logger.Debug("unknown challenge method")
+		return output.ErrValidationFailed
+	}
+	challMethod := challenges.MethodByStorageValue(*payload.ChallengeMethodValue)
+	if challMethod == challenges.UnknownMethod {
+		service.logger.Debug("unknown challenge method")
+		return output.ErrValidationFailed
+	}
+	// subject and subject alts (must be names acme can issue for)
+	if !subjectValid(unmanagedCert.Subject, challMethod) || !subjectAltsValid(unmanagedCert.SubjectAltNames, challMethod) {
+		service.logger.Debug(ErrDomainBad)
+		return output.ErrValidationFailed
+	}
+	// key (keep the existing key, or generate a new one)
+	generatedKeyPem := ""
+	if unmanagedCert.PrivateKey != nil {
+		if payload.NewKeyAlgorithmValue != nil && *payload.NewKeyAlgorithmValue != "" {
+			service.logger.Debug(ErrKeyIdAndAlgorithm)
+			return output.ErrValidationFailed
+		}
+	} else {
+		if payload.NewKeyAlgorithmValue == nil || *payload.NewKeyAlgorithmValue == "" {
+			service.logger.Debug(ErrKeyAlgorithmNone)
+			return output.ErrValidationFailed
+		}
+		if !service.keys.NameValid(unmanagedCert.Name, nil) {
+			service.logger.Debug(ErrKeyNameBad)
+			return output.ErrValidationFailed
+		}
+		generatedKeyPem, err = key_crypto.AlgorithmByStorageValue(*payload.NewKeyAlgorithmValue).GeneratePrivateKeyPem()
+		if err != nil {
+			service.logger.Debug(err)
+			return output.ErrValidationFailed
+		}
+	}
+	// end validation
+
+	now := int(time.Now().Unix())
+
+	newPayload := NewPayload{
+		Name:                 &unmanagedCert.Name,
+		Description:          &unmanagedCert.Description,
+		PrivateKeyID:         new(int),
+		AcmeAccountID:        payload.AcmeAccountID,
+		ChallengeMethodValue: payload.ChallengeMethodValue,
+		Subject:              &unmanagedCert.Subject,
+		SubjectAltNames:      unmanagedCert.SubjectAltNames,
+		Organization:         new(string),
+		OrganizationalUnit:   new(string),
+		Country:              new(string),
+		State:                new(string),
+		City:                 new(string),
+		RenewalPolicy:        new(RenewalPolicy),
+		KeyRotation:          new(KeyRotation),
+		NotificationEmails:   unmanagedCert.NotificationEmails,
+		ApiKey:               unmanagedCert.ApiKey,
+		ApiKeyViaUrl:         unmanagedCert.ApiKeyViaUrl,
+		CreatedAt:            now,
+		UpdatedAt:            now,
+	}
+
+	// if new key was generated, it is saved to storage as part of the conversion
+	var newKeyPayload *private_keys.NewPayload
+	if generatedKeyPem != "" {
+		newKeyPayload = &private_keys.NewPayload{
+			Name:           &unmanagedCert.Name,
+			Description:    &unmanagedCert.Description,
+			AlgorithmValue: payload.NewKeyAlgorithmValue,
+			PemContent:     &generatedKeyPem,
+			ApiKeyDisabled: new(bool),
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		newKeyPayload.ApiKey, err = randomness.GenerateApiKey()
+		if err != nil {
+			service.logger.Error(err)
+			return output.ErrInternal
+		}
+	} else {
+		*newPayload.PrivateKeyID = unmanagedCert.PrivateKey.ID
+	}
+
+	// replace the unmanaged cert (and save the new key, if any)
+	id, err := service.storage.PostConvertedUnmanagedCert(newPayload, newKeyPayload, unmanagedId)
+	if err != nil {
+		// unmanaged cert was deleted after validation
+		if err == storage.ErrNoRecord {
+			service.logger.Debug(err)
+			return output.ErrNotFound
+		}
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// renewal timing may have changed
+	service.notifyChange()
+
+	// return response to client
+	response := output.JsonResponse{
+		Status:  http.StatusCreated,
+		Message: "converted",
+		ID:      id,
+	}
+
+	_, err = service.output.WriteJSON(w, response.Status, response, "response")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
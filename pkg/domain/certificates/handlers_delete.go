@@ -2,6 +2,7 @@ package certificates
 
 import (
 	"errors"
+	"legocerthub-backend/pkg/domain/webhooks"
 	"legocerthub-backend/pkg/output"
 	"net/http"
 	"strconv"
@@ -33,6 +34,9 @@ func (service *Service) DeleteCert(w http.ResponseWriter, r *http.Request) (err
 		return output.ErrStorageGeneric
 	}
 
+	// renewal timing may have changed
+	service.notifyChange()
+
 	// return response to client
 	response := output.JsonResponse{
 		Status:  http.StatusOK,
@@ -88,6 +92,8 @@ func (service *Service) RemoveOldApiKey(w http.ResponseWriter, r *http.Request)
 		return output.ErrStorageGeneric
 	}
 
+	service.webhooks.ApiKeyRotated(webhooks.ResourceCertificate, certId, cert.Name)
+
 	// return response to client
 	response := output.JsonResponse{
 		Status:  http.StatusOK,
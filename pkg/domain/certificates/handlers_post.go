@@ -31,6 +31,12 @@ type NewPayload struct {
 	Country              *string                 `json:"country"`
 	State                *string                 `json:"state"`
 	City                 *string                 `json:"city"`
+	Http01SelfCheck      bool                    `json:"http01_self_check"`
+	RenewalPolicy        *RenewalPolicy          `json:"renewal_policy"`
+	KeyRotation          *KeyRotation            `json:"key_rotation"`
+	NotificationEmails   []string                `json:"notification_emails"`
+	DeployHooks          []DeployHook            `json:"deploy_hooks"`
+	Csr                  *string                 `json:"csr"`
 	ApiKey               string                  `json:"-"`
 	ApiKeyViaUrl         bool                    `json:"-"`
 	CreatedAt            int                     `json:"-"`
@@ -60,16 +66,36 @@ func (service *Service) PostNewCert(w http.ResponseWriter, r *http.Request) (err
 	if payload.Description == nil {
 		payload.Description = new(string)
 	}
-	// private key
-	// if key id not specified
-	if payload.PrivateKeyID == nil {
-		service.logger.Debug(ErrKeyIdBad)
-		return output.ErrValidationFailed
-	}
 	// keep track if new key will be generated and saved
 	generatedKeyPem := ""
-	// if new key id specified
-	if validation.IsIdNew(*payload.PrivateKeyID) {
+	// csr (optional, replaces the private key and defines the subject, subject
+	// alts, and csr fields)
+	if payload.Csr != nil {
+		// error if a key or key algorithm was also specified
+		if payload.PrivateKeyID != nil || (payload.NewKeyAlgorithmValue != nil && *payload.NewKeyAlgorithmValue != "") {
+			service.logger.Debug(ErrCsrAndKey)
+			return output.ErrValidationFailed
+		}
+		details, err := parseCsrDetails(*payload.Csr)
+		if err != nil {
+			service.logger.Debug(err)
+			return output.ErrValidationFailed
+		}
+		payload.Csr = &details.pem
+		payload.Subject = &details.subject
+		payload.SubjectAltNames = details.subjectAltNames
+		payload.Organization = &details.organization
+		payload.OrganizationalUnit = &details.organizationalUnit
+		payload.Country = &details.country
+		payload.State = &details.state
+		payload.City = &details.city
+	} else if payload.PrivateKeyID == nil {
+		// private key
+		// if key id not specified
+		service.logger.Debug(ErrKeyIdBad)
+		return output.ErrValidationFailed
+	} else if validation.IsIdNew(*payload.PrivateKeyID) {
+		// if new key id specified
 		// confirm algorithm is specified
 		if payload.NewKeyAlgorithmValue == nil || *payload.NewKeyAlgorithmValue == "" {
 			service.logger.Debug(ErrKeyAlgorithmNone)
@@ -139,6 +165,34 @@ func (service *Service) PostNewCert(w http.ResponseWriter, r *http.Request) (err
 	if payload.City == nil {
 		payload.City = new(string)
 	}
+	// renewal policy (optional, default is the global threshold)
+	if payload.RenewalPolicy == nil {
+		payload.RenewalPolicy = new(RenewalPolicy)
+	} else if !payload.RenewalPolicy.valid() {
+		service.logger.Debug(ErrRenewalPolicyBad)
+		return output.ErrValidationFailed
+	}
+	// key rotation (optional, default is disabled)
+	if payload.KeyRotation == nil {
+		payload.KeyRotation = new(KeyRotation)
+	} else if !payload.KeyRotation.valid() {
+		service.logger.Debug(ErrKeyRotationBad)
+		return output.ErrValidationFailed
+	}
+	if payload.Csr != nil && payload.KeyRotation.Enable {
+		service.logger.Debug(ErrCsrRotation)
+		return output.ErrValidationFailed
+	}
+	// notification emails (optional)
+	if !notificationEmailsValid(payload.NotificationEmails) {
+		service.logger.Debug(ErrNotificationEmailsBad)
+		return output.ErrValidationFailed
+	}
+	// deploy hooks (optional)
+	if !deployHooksValid(payload.DeployHooks) {
+		service.logger.Debug(ErrDeployHooksBad)
+		return output.ErrValidationFailed
+	}
 	// end validation
 
 	// if new key was generated, save it to storage
@@ -188,6 +242,9 @@ func (service *Service) PostNewCert(w http.ResponseWriter, r *http.Request) (err
 		return output.ErrStorageGeneric
 	}
 
+	// renewal timing may have changed
+	service.notifyChange()
+
 	// return response to client
 	response := output.JsonResponse{
 		Status:  http.StatusCreated,
@@ -28,6 +28,11 @@ type DetailsUpdatePayload struct {
 	ApiKey               *string                 `json:"api_key"`
 	ApiKeyNew            *string                 `json:"api_key_new"`
 	ApiKeyViaUrl         *bool                   `json:"api_key_via_url"`
+	Http01SelfCheck      *bool                   `json:"http01_self_check"`
+	RenewalPolicy        *RenewalPolicy          `json:"renewal_policy"`
+	KeyRotation          *KeyRotation            `json:"key_rotation"`
+	NotificationEmails   []string                `json:"notification_emails"`
+	DeployHooks          []DeployHook            `json:"deploy_hooks"`
 	UpdatedAt            int                     `json:"-"`
 }
 
@@ -63,6 +68,14 @@ func (service *Service) PutDetailsCert(w http.ResponseWriter, r *http.Request) (
 		return output.ErrValidationFailed
 	}
 	// description - no validation
+	// certs defined by a csr get their key, subject alts, and csr fields from the
+	// csr (use PutCertCsr to change them)
+	if cert.HasCsr() && (payload.PrivateKeyId != nil || payload.SubjectAltNames != nil ||
+		payload.Organization != nil || payload.OrganizationalUnit != nil || payload.Country != nil ||
+		payload.State != nil || payload.City != nil) {
+		service.logger.Debug(ErrCsrFieldsBad)
+		return output.ErrValidationFailed
+	}
 	// private key (optional)
 	if payload.PrivateKeyId != nil && !service.privateKeyIdValid(*payload.PrivateKeyId, &payload.ID) {
 		service.logger.Debug(err)
@@ -118,6 +131,30 @@ func (service *Service) PutDetailsCert(w http.ResponseWriter, r *http.Request) (
 		service.logger.Debug(ErrApiKeyNewBad)
 		return output.ErrValidationFailed
 	}
+	// renewal policy (optional, an empty policy reverts to the global threshold)
+	if payload.RenewalPolicy != nil && !payload.RenewalPolicy.valid() {
+		service.logger.Debug(ErrRenewalPolicyBad)
+		return output.ErrValidationFailed
+	}
+	// key rotation (optional)
+	if payload.KeyRotation != nil && !payload.KeyRotation.valid() {
+		service.logger.Debug(ErrKeyRotationBad)
+		return output.ErrValidationFailed
+	}
+	if cert.HasCsr() && payload.KeyRotation != nil && payload.KeyRotation.Enable {
+		service.logger.Debug(ErrCsrRotation)
+		return output.ErrValidationFailed
+	}
+	// notification emails (optional, empty clears them)
+	if !notificationEmailsValid(payload.NotificationEmails) {
+		service.logger.Debug(ErrNotificationEmailsBad)
+		return output.ErrValidationFailed
+	}
+	// deploy hooks (optional, empty clears them)
+	if !deployHooksValid(payload.DeployHooks) {
+		service.logger.Debug(ErrDeployHooksBad)
+		return output.ErrValidationFailed
+	}
 	// TODO: Do any validation of CSR components?
 	// end validation
 
@@ -132,6 +169,117 @@ func (service *Service) PutDetailsCert(w http.ResponseWriter, r *http.Request) (
 		return output.ErrStorageGeneric
 	}
 
+	// renewal timing may have changed
+	service.notifyChange()
+
+	// exported files change if the key or name did
+	if (payload.PrivateKeyId != nil && *payload.PrivateKeyId != cert.CertificateKey.ID) ||
+		(payload.Name != nil && *payload.Name != cert.Name) {
+		service.export.CertificateChanged(payload.ID)
+	}
+
+	// return response to client
+	response := output.JsonResponse{
+		Status:  http.StatusOK,
+		Message: "updated",
+		ID:      payload.ID,
+	}
+
+	_, err = service.output.WriteJSON(w, response.Status, response, "response")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
+
+// CsrUpdatePayload is the struct for replacing the CSR of a cert that is defined
+// by a CSR. The subject, subject alts, and csr fields are derived from the CSR.
+type CsrUpdatePayload struct {
+	ID                 int      `json:"-"`
+	Csr                *string  `json:"csr"`
+	Subject            string   `json:"-"`
+	SubjectAltNames    []string `json:"-"`
+	Organization       string   `json:"-"`
+	OrganizationalUnit string   `json:"-"`
+	Country            string   `json:"-"`
+	State              string   `json:"-"`
+	City               string   `json:"-"`
+	UpdatedAt          int      `json:"-"`
+}
+
+// PutCertCsr is a handler that replaces the CSR of a cert that is defined by a CSR.
+// The new CSR is used the next time the cert is ordered.
+func (service *Service) PutCertCsr(w http.ResponseWriter, r *http.Request) (err error) {
+	// payload decoding
+	var payload CsrUpdatePayload
+	err = json.NewDecoder(r.Body).Decode(&payload)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// get id from param
+	idParam := httprouter.ParamsFromContext(r.Context()).ByName("certid")
+	payload.ID, err = strconv.Atoi(idParam)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// validation
+	// id
+	cert, err := service.GetCertificate(payload.ID)
+	if err != nil {
+		return err
+	}
+	// only certs that are already defined by a csr
+	if !cert.HasCsr() {
+		service.logger.Debug(ErrCsrNotDefined)
+		return output.ErrValidationFailed
+	}
+	// csr
+	if payload.Csr == nil {
+		service.logger.Debug(ErrCsrBad)
+		return output.ErrValidationFailed
+	}
+	details, err := parseCsrDetails(*payload.Csr)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+	// names must be valid for the challenge method
+	if !subjectValid(details.subject, cert.ChallengeMethod) ||
+		!subjectAltsValid(details.subjectAltNames, cert.ChallengeMethod) {
+		service.logger.Debug(ErrDomainBad)
+		return output.ErrValidationFailed
+	}
+	// end validation
+
+	// add details from the csr to the payload before saving
+	payload.Csr = &details.pem
+	payload.Subject = details.subject
+	payload.SubjectAltNames = details.subjectAltNames
+	payload.Organization = details.organization
+	payload.OrganizationalUnit = details.organizationalUnit
+	payload.Country = details.country
+	payload.State = details.state
+	payload.City = details.city
+	payload.UpdatedAt = int(time.Now().Unix())
+
+	err = service.storage.PutCertCsr(payload)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// renewal timing may have changed (names changed)
+	service.notifyChange()
+
+	// re-export so exported files match the cert as it is now defined
+	service.export.CertificateChanged(payload.ID)
+
 	// return response to client
 	response := output.JsonResponse{
 		Status:  http.StatusOK,
@@ -0,0 +1,34 @@
+package certificates
+
+import (
+	"errors"
+	"legocerthub-backend/pkg/domain/private_keys/key_crypto"
+)
+
+var ErrKeyRotationBad = errors.New("key rotation is not valid (algorithm_value must be blank or a supported key algorithm)")
+
+// KeyRotation configures generating a new private key each time the certificate
+// is renewed. The new key replaces the certificate's key just before the order is
+// finalized and the old key is retired (deleted after the configured grace period,
+// once it is no longer in use). If AlgorithmValue is blank, the new key uses the
+// same algorithm as the current key.
+type KeyRotation struct {
+	Enable         bool   `json:"enable"`
+	AlgorithmValue string `json:"algorithm_value"`
+}
+
+// valid returns true if the algorithm is blank or a known algorithm
+func (rotation KeyRotation) valid() bool {
+	return rotation.AlgorithmValue == "" ||
+		key_crypto.AlgorithmByStorageValue(rotation.AlgorithmValue) != key_crypto.UnknownAlgorithm
+}
+
+// Algorithm returns the algorithm to generate the next key with, given the
+// algorithm of the current key
+func (rotation KeyRotation) Algorithm(current key_crypto.Algorithm) key_crypto.Algorithm {
+	if rotation.AlgorithmValue == "" {
+		return current
+	}
+
+	return key_crypto.AlgorithmByStorageValue(rotation.AlgorithmValue)
+}
@@ -0,0 +1,35 @@
+package certificates
+
+import (
+	"errors"
+)
+
+var ErrRenewalPolicyBad = errors.New("renewal policy is not valid (specify only one of days_remaining (> 0) or lifetime_fraction (> 0 and < 1))")
+
+// RenewalPolicy overrides when a certificate is automatically renewed. A cert is
+// renewed once its newest valid order has less than DaysRemaining of validity
+// left, or less than LifetimeFraction of its total lifetime (valid_from to
+// valid_to) left. If neither is set, the orders valid_remaining_days_threshold
+// is used.
+type RenewalPolicy struct {
+	DaysRemaining    *int     `json:"days_remaining"`
+	LifetimeFraction *float64 `json:"lifetime_fraction"`
+}
+
+// valid returns true if no more than one of the policy options is set and the
+// set option is in range
+func (policy RenewalPolicy) valid() bool {
+	if policy.DaysRemaining != nil && policy.LifetimeFraction != nil {
+		return false
+	}
+
+	if policy.DaysRemaining != nil && *policy.DaysRemaining < 1 {
+		return false
+	}
+
+	if policy.LifetimeFraction != nil && (*policy.LifetimeFraction <= 0 || *policy.LifetimeFraction >= 1) {
+		return false
+	}
+
+	return true
+}
@@ -5,6 +5,9 @@ import (
 	"legocerthub-backend/pkg/challenges"
 	"legocerthub-backend/pkg/domain/acme_accounts"
 	"legocerthub-backend/pkg/domain/private_keys"
+	"legocerthub-backend/pkg/domain/unmanaged_certificates"
+	"legocerthub-backend/pkg/domain/webhooks"
+	"legocerthub-backend/pkg/export"
 	"legocerthub-backend/pkg/output"
 	"legocerthub-backend/pkg/pagination_sort"
 
@@ -23,6 +26,8 @@ type App interface {
 	GetCertificatesStorage() Storage
 	GetKeysService() *private_keys.Service
 	GetAcctsService() *acme_accounts.Service
+	GetWebhooksService() *webhooks.Service
+	GetExportService() *export.Service
 }
 
 // Storage interface for storage functions
@@ -33,13 +38,18 @@ type Storage interface {
 	GetCertPemById(certId int) (name string, pem string, err error)
 
 	PostNewCert(payload NewPayload) (id int, err error)
+	PostConvertedUnmanagedCert(payload NewPayload, newKey *private_keys.NewPayload, unmanagedId int) (id int, err error)
 
 	PutDetailsCert(payload DetailsUpdatePayload) (err error)
 	PutCertApiKey(certId int, apiKey string, updateTimeUnix int) (err error)
 	PutCertNewApiKey(certId int, newApiKey string, updateTimeUnix int) (err error)
+	PutCertCsr(payload CsrUpdatePayload) (err error)
 
 	DeleteCert(id int) (err error)
 
+	GetOneUnmanagedCertById(id int) (cert unmanaged_certificates.Certificate, err error)
+	UnmanagedCertNameInUse(name string) (inUse bool, err error)
+
 	PostNewKey(private_keys.NewPayload) (keyId int, err error)
 }
 
@@ -53,6 +63,10 @@ type Service struct {
 	storage    Storage
 	keys       *private_keys.Service
 	accounts   *acme_accounts.Service
+	webhooks   *webhooks.Service
+	export     *export.Service
+
+	changeHooks []func()
 }
 
 // NewService creates a new service
@@ -101,5 +115,17 @@ func NewService(app App) (*Service, error) {
 		return nil, errServiceComponent
 	}
 
+	// webhooks
+	service.webhooks = app.GetWebhooksService()
+	if service.webhooks == nil {
+		return nil, errServiceComponent
+	}
+
+	// certificate export
+	service.export = app.GetExportService()
+	if service.export == nil {
+		return nil, errServiceComponent
+	}
+
 	return service, nil
 }
@@ -28,6 +28,9 @@ var (
 
 	// domain
 	ErrDomainBad = errors.New("domain or subject name not valid")
+
+	// notifications
+	ErrNotificationEmailsBad = errors.New("one or more notification emails are not valid")
 )
 
 // GetCertificate returns the Certificate for the specified id.
@@ -55,13 +58,20 @@ func (service *Service) GetCertificate(id int) (Certificate, error) {
 }
 
 // nameValid returns if a name is valid (meets char requirements
-// and is not in use in storage OR is in use by the specified certId)
+// and is not in use in storage OR is in use by the specified certId). Names
+// of unmanaged certs are also in use since the download api looks up both.
 func (service *Service) nameValid(certName string, certId *int) bool {
 	// basic check
 	if !validation.NameValid(certName) {
 		return false
 	}
 
+	// unmanaged certs
+	inUse, err := service.storage.UnmanagedCertNameInUse(certName)
+	if err != nil || inUse {
+		return false
+	}
+
 	// make sure the name isn't already in use in storage
 	cert, err := service.storage.GetOneCertByName(certName)
 	if err == storage.ErrNoRecord {
@@ -108,10 +118,11 @@ func (service *Service) privateKeyIdValid(keyId int, certId *int) bool {
 }
 
 // subjectValid validates domain name and if it is a wildcard
-// domain name it also verifies the method is dns-01
+// domain name it also verifies the method is dns-01 or dns-persist-01
 func subjectValid(domain string, challMethod challenges.Method) bool {
 	// wild is only valid for Dns challenges
-	wildOk := challMethod.ChallengeType == acme.ChallengeTypeDns01
+	wildOk := challMethod.ChallengeType == acme.ChallengeTypeDns01 ||
+		challMethod.ChallengeType == acme.ChallengeTypeDnsPersist01
 
 	// check domain is valid
 	return validation.DomainValid(domain, wildOk)
@@ -128,3 +139,14 @@ func subjectAltsValid(alts []string, challMethod challenges.Method) bool {
 
 	return true
 }
+
+// notificationEmailsValid validates each of the notification recipient emails
+func notificationEmailsValid(emails []string) bool {
+	for _, email := range emails {
+		if !validation.EmailValid(email) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,189 @@
+package deploy_endpoints
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"legocerthub-backend/pkg/certinfo"
+	"legocerthub-backend/pkg/storage"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	errNoValidOrder = errors.New("certificate has no valid order")
+	errNoPeerCert   = errors.New("endpoint did not present a certificate")
+)
+
+// startChecks checks all enabled endpoints now and then again every interval
+// until shutdown
+func (service *Service) startChecks(interval time.Duration, wg *sync.WaitGroup) {
+	service.logger.Infof("deploy endpoint checks running every %s", interval)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			service.checkAll()
+
+			select {
+			case <-service.shutdownContext.Done():
+				service.logger.Info("deploy endpoint checks shutdown complete")
+				return
+			case <-ticker.C:
+				// run again
+			}
+		}
+	}()
+}
+
+// checkAll checks each enabled endpoint
+func (service *Service) checkAll() {
+	endpoints, err := service.storage.GetEnabledDeployEndpoints()
+	if err != nil {
+		service.logger.Errorf("failed to fetch deploy endpoints (%s)", err)
+		return
+	}
+
+	for _, endpoint := range endpoints {
+		// stop early if shutting down
+		if service.shutdownContext.Err() != nil {
+			return
+		}
+
+		service.check(endpoint)
+	}
+}
+
+// check checks the endpoint, saves the result, and sends a notification if the
+// endpoint is flagged on two checks in a row (a single stale result may just be
+// a deploy that hasn't finished yet)
+func (service *Service) check(endpoint Endpoint) Check {
+	check := service.checkEndpoint(endpoint)
+
+	// count consecutive checks with the same status
+	check.Consecutive = 1
+	if endpoint.LastCheck != nil && endpoint.LastCheck.Status == check.Status {
+		check.Consecutive = endpoint.LastCheck.Consecutive + 1
+	}
+
+	if check.Status != StatusOk {
+		service.logger.Warnf("deploy endpoint %s (cert %d) check: %s %s", endpoint.address(), endpoint.CertificateID, check.Status, check.Error)
+	}
+
+	err := service.storage.PutDeployEndpointCheck(endpoint.ID, check)
+	if err != nil {
+		service.logger.Errorf("failed to save deploy endpoint %d check (%s)", endpoint.ID, err)
+	}
+
+	if check.Status.flagged() && check.Consecutive == 2 {
+		service.notifications.DeployEndpointFlagged(endpoint.CertificateID, endpoint.address(), endpoint.sni(), string(check.Status),
+			check.ServedSerial, check.ExpectedSerial)
+	}
+
+	return check
+}
+
+// checkEndpoint does a tls handshake with the endpoint and compares the served
+// leaf with the leaf of the certificate's newest valid order
+func (service *Service) checkEndpoint(endpoint Endpoint) Check {
+	check := Check{
+		Time: int(time.Now().Unix()),
+	}
+
+	// expected leaf
+	certPem, _, err := service.storage.GetCertPemAndKeyNameById(endpoint.CertificateID)
+	if err != nil {
+		if err == storage.ErrNoRecord {
+			err = errNoValidOrder
+		}
+		check.Status = StatusError
+		check.Error = err.Error()
+		return check
+	}
+	expected, err := certinfo.LeafFromPem(certPem)
+	if err != nil {
+		check.Status = StatusError
+		check.Error = err.Error()
+		return check
+	}
+	check.ExpectedSerial = certinfo.SerialString(expected)
+	check.ExpectedValidTo = int(expected.NotAfter.Unix())
+
+	// served leaf
+	served, err := service.servedLeaf(endpoint)
+	if err != nil {
+		check.Status = StatusError
+		check.Error = err.Error()
+		return check
+	}
+	check.ServedSerial = certinfo.SerialString(served)
+	check.ServedValidTo = int(served.NotAfter.Unix())
+
+	check.Status = compareLeaf(served, expected)
+	return check
+}
+
+// servedLeaf returns the leaf certificate the endpoint serves. The chain is not
+// verified, the point is to see what is served (even if it is expired).
+func (service *Service) servedLeaf(endpoint Endpoint) (*x509.Certificate, error) {
+	dialer := &net.Dialer{Timeout: service.timeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", endpoint.address(), &tls.Config{
+		ServerName:         endpoint.sni(),
+		InsecureSkipVerify: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	peerCerts := conn.ConnectionState().PeerCertificates
+	if len(peerCerts) == 0 {
+		return nil, errNoPeerCert
+	}
+
+	return peerCerts[0], nil
+}
+
+// compareLeaf returns the status of serving the served leaf when the expected
+// leaf is the newest
+func compareLeaf(served *x509.Certificate, expected *x509.Certificate) Status {
+	if served.SerialNumber.Cmp(expected.SerialNumber) == 0 && served.Issuer.String() == expected.Issuer.String() {
+		return StatusOk
+	}
+
+	// an older cert for the same subject is stale
+	if served.NotAfter.Before(expected.NotAfter) && coversName(served, subjectName(expected)) {
+		return StatusStale
+	}
+
+	return StatusMismatch
+}
+
+// subjectName returns the cert's common name (or first dns name if there is no
+// common name)
+func subjectName(cert *x509.Certificate) string {
+	if cert.Subject.CommonName != "" || len(cert.DNSNames) == 0 {
+		return cert.Subject.CommonName
+	}
+
+	return cert.DNSNames[0]
+}
+
+// coversName returns true if the cert is valid for the name (wildcard names
+// must be in the cert exactly)
+func coversName(cert *x509.Certificate, name string) bool {
+	for _, certName := range append([]string{cert.Subject.CommonName}, cert.DNSNames...) {
+		if strings.EqualFold(certName, name) {
+			return true
+		}
+	}
+
+	return !strings.HasPrefix(name, "*.") && cert.VerifyHostname(name) == nil
+}
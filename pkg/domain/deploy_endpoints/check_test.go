@@ -0,0 +1,91 @@
+package deploy_endpoints
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"legocerthub-backend/pkg/certinfo"
+	"legocerthub-backend/pkg/internal/certtest"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCompareLeaf(t *testing.T) {
+	newest := certtest.IssueForNames(t, 90*24*time.Hour, "example.com", "www.example.com").Leaf
+	older := certtest.IssueForNames(t, 30*24*time.Hour, "example.com", "www.example.com").Leaf
+	otherName := certtest.IssueForNames(t, 30*24*time.Hour, "other.example.com").Leaf
+	wildcard := certtest.IssueForNames(t, 30*24*time.Hour, "*.example.com").Leaf
+	newestWildcard := certtest.IssueForNames(t, 90*24*time.Hour, "*.example.com").Leaf
+
+	tests := []struct {
+		name     string
+		served   *x509.Certificate
+		expected *x509.Certificate
+		status   Status
+	}{
+		{"same cert", newest, newest, StatusOk},
+		{"older cert for the name", older, newest, StatusStale},
+		{"older wildcard cert", wildcard, newestWildcard, StatusStale},
+		{"different name", otherName, newest, StatusMismatch},
+		{"wildcard does not cover the apex", wildcard, newest, StatusMismatch},
+		{"newer cert than expected", newest, older, StatusMismatch},
+	}
+
+	for _, test := range tests {
+		status := compareLeaf(test.served, test.expected)
+		if status != test.status {
+			t.Errorf("%s: got status %s, expected %s", test.name, status, test.status)
+		}
+	}
+}
+
+func TestServedLeaf(t *testing.T) {
+	servedCert := certtest.IssueForNames(t, 24*time.Hour, "example.com").TLSCertificate()
+	otherCert := certtest.IssueForNames(t, 24*time.Hour, "other.example.com").TLSCertificate()
+
+	// serve the cert matching the requested sni
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			if hello.ServerName == "other.example.com" {
+				return &otherCert, nil
+			}
+			return &servedCert, nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_ = conn.(*tls.Conn).Handshake()
+				conn.Close()
+			}()
+		}
+	}()
+
+	host, portStr, _ := net.SplitHostPort(listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+
+	service := &Service{timeout: 5 * time.Second}
+
+	for serverName, expected := range map[string]*x509.Certificate{
+		"":                  servedCert.Leaf,
+		"other.example.com": otherCert.Leaf,
+	} {
+		leaf, err := service.servedLeaf(Endpoint{Host: host, Port: port, ServerName: serverName})
+		if err != nil {
+			t.Fatalf("server name '%s': %s", serverName, err)
+		}
+		if compareLeaf(leaf, expected) != StatusOk {
+			t.Errorf("server name '%s': served serial %s, expected %s", serverName, certinfo.SerialString(leaf), certinfo.SerialString(expected))
+		}
+	}
+}
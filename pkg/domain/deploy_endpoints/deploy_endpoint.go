@@ -0,0 +1,118 @@
+package deploy_endpoints
+
+import (
+	"encoding/json"
+	"net"
+	"strconv"
+)
+
+// Endpoint is a tls endpoint that serves a certificate (e.g. a web server the
+// certificate is deployed to). It is periodically checked to confirm it serves
+// the certificate's newest valid order. ServerName is the SNI sent in the
+// handshake; if blank, Host is sent (unless Host is an ip).
+type Endpoint struct {
+	ID            int
+	CertificateID int
+	Host          string
+	Port          int
+	ServerName    string
+	Enabled       bool
+	LastCheck     *Check
+	CreatedAt     int
+	UpdatedAt     int
+}
+
+// address returns the host:port to dial
+func (endpoint Endpoint) address() string {
+	return net.JoinHostPort(endpoint.Host, strconv.Itoa(endpoint.Port))
+}
+
+// sni returns the server name to send in the handshake (blank for none)
+func (endpoint Endpoint) sni() string {
+	if endpoint.ServerName != "" {
+		return endpoint.ServerName
+	}
+
+	if net.ParseIP(endpoint.Host) != nil {
+		return ""
+	}
+
+	return endpoint.Host
+}
+
+// endpointResponse is the api response for an endpoint
+type endpointResponse struct {
+	ID            int    `json:"id"`
+	CertificateID int    `json:"certificate_id"`
+	Host          string `json:"host"`
+	Port          int    `json:"port"`
+	ServerName    string `json:"server_name"`
+	Enabled       bool   `json:"enabled"`
+	LastCheck     *Check `json:"last_check"`
+	CreatedAt     int    `json:"created_at"`
+	UpdatedAt     int    `json:"updated_at"`
+}
+
+func (endpoint Endpoint) response() endpointResponse {
+	return endpointResponse{
+		ID:            endpoint.ID,
+		CertificateID: endpoint.CertificateID,
+		Host:          endpoint.Host,
+		Port:          endpoint.Port,
+		ServerName:    endpoint.ServerName,
+		Enabled:       endpoint.Enabled,
+		LastCheck:     endpoint.LastCheck,
+		CreatedAt:     endpoint.CreatedAt,
+		UpdatedAt:     endpoint.UpdatedAt,
+	}
+}
+
+// Status is the outcome of checking an endpoint
+type Status string
+
+const (
+	// serving the certificate of the newest valid order
+	StatusOk Status = "ok"
+	// serving an older certificate (e.g. renewed but the server was never
+	// reloaded)
+	StatusStale Status = "stale"
+	// serving a certificate that isn't an older or the current certificate
+	StatusMismatch Status = "mismatch"
+	// the check couldn't be completed (e.g. connection failed or the
+	// certificate has no valid order)
+	StatusError Status = "error"
+)
+
+// flagged returns true if the status indicates a bad deployment
+func (status Status) flagged() bool {
+	return status == StatusStale || status == StatusMismatch
+}
+
+// Check is the result of checking an endpoint. Consecutive is how many checks
+// in a row (including this one) had the same status.
+type Check struct {
+	Status          Status `json:"status"`
+	Time            int    `json:"time"`
+	Consecutive     int    `json:"consecutive"`
+	ServedSerial    string `json:"served_serial,omitempty"`
+	ServedValidTo   int    `json:"served_valid_to,omitempty"`
+	ExpectedSerial  string `json:"expected_serial,omitempty"`
+	ExpectedValidTo int    `json:"expected_valid_to,omitempty"`
+	Error           string `json:"error,omitempty"`
+}
+
+// CheckFromJson converts a json Check object into a Check. If the json is nil or
+// invalid, nil is returned.
+func CheckFromJson(checkJson *string) *Check {
+	if checkJson == nil {
+		return nil
+	}
+
+	check := new(Check)
+	err := json.Unmarshal([]byte(*checkJson), check)
+	if err != nil {
+		return nil
+	}
+
+	return check
+}
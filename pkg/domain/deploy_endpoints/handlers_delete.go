@@ -0,0 +1,53 @@
+package deploy_endpoints
+
+import (
+	"legocerthub-backend/pkg/output"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// DeleteDeployEndpoint deletes a deploy endpoint from storage
+func (service *Service) DeleteDeployEndpoint(w http.ResponseWriter, r *http.Request) (err error) {
+	// get params
+	params := httprouter.ParamsFromContext(r.Context())
+	certId, err := strconv.Atoi(params.ByName("certid"))
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+	id, err := strconv.Atoi(params.ByName("id"))
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// validate endpoint exists
+	_, err = service.getEndpoint(certId, id)
+	if err != nil {
+		return err
+	}
+
+	// delete from storage
+	err = service.storage.DeleteDeployEndpoint(id)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// return response to client
+	response := output.JsonResponse{
+		Status:  http.StatusOK,
+		Message: "deleted",
+		ID:      id,
+	}
+
+	_, err = service.output.WriteJSON(w, response.Status, response, "response")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package deploy_endpoints
+
+import (
+	"legocerthub-backend/pkg/output"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// GetCertDeployEndpoints returns all of a certificate's deploy endpoints as JSON
+func (service *Service) GetCertDeployEndpoints(w http.ResponseWriter, r *http.Request) (err error) {
+	// convert id param to an integer
+	certIdParam := httprouter.ParamsFromContext(r.Context()).ByName("certid")
+	certId, err := strconv.Atoi(certIdParam)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// validate certificate ID
+	_, err = service.certificates.GetCertificate(certId)
+	if err != nil {
+		return err
+	}
+
+	// get endpoints from storage
+	endpoints, err := service.storage.GetDeployEndpointsByCert(certId)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// assemble response
+	response := []endpointResponse{}
+	for i := range endpoints {
+		response = append(response, endpoints[i].response())
+	}
+
+	// return response to client
+	_, err = service.output.WriteJSON(w, http.StatusOK, response, "deploy_endpoints")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
+
+// GetFlaggedDeployEndpoints returns the enabled deploy endpoints (of all
+// certificates) whose last check was stale or mismatched as JSON
+func (service *Service) GetFlaggedDeployEndpoints(w http.ResponseWriter, r *http.Request) (err error) {
+	// get endpoints from storage
+	endpoints, err := service.storage.GetFlaggedDeployEndpoints()
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// assemble response
+	response := []endpointResponse{}
+	for i := range endpoints {
+		response = append(response, endpoints[i].response())
+	}
+
+	// return response to client
+	_, err = service.output.WriteJSON(w, http.StatusOK, response, "deploy_endpoints")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
+
+// GetOneDeployEndpoint returns a single deploy endpoint as JSON
+func (service *Service) GetOneDeployEndpoint(w http.ResponseWriter, r *http.Request) (err error) {
+	// params
+	params := httprouter.ParamsFromContext(r.Context())
+	certId, err := strconv.Atoi(params.ByName("certid"))
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+	id, err := strconv.Atoi(params.ByName("id"))
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// get the endpoint from storage (and validate ids)
+	endpoint, err := service.getEndpoint(certId, id)
+	if err != nil {
+		return err
+	}
+
+	// return response to client
+	_, err = service.output.WriteJSON(w, http.StatusOK, endpoint.response(), "deploy_endpoint")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
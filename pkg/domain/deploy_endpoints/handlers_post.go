@@ -0,0 +1,147 @@
+package deploy_endpoints
+
+import (
+	"encoding/json"
+	"legocerthub-backend/pkg/output"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// defaultPort is the port used if none is specified
+const defaultPort = 443
+
+// NewPayload is a struct for posting a new deploy endpoint
+type NewPayload struct {
+	CertificateID int     `json:"-"`
+	Host          *string `json:"host"`
+	Port          *int    `json:"port"`
+	ServerName    *string `json:"server_name"`
+	Enabled       *bool   `json:"enabled"`
+	CreatedAt     int     `json:"-"`
+	UpdatedAt     int     `json:"-"`
+}
+
+// PostNewDeployEndpoint creates a new deploy endpoint for a certificate and saves
+// it to storage
+func (service *Service) PostNewDeployEndpoint(w http.ResponseWriter, r *http.Request) (err error) {
+	var payload NewPayload
+
+	// decode body into payload
+	err = json.NewDecoder(r.Body).Decode(&payload)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// get cert id from param
+	certIdParam := httprouter.ParamsFromContext(r.Context()).ByName("certid")
+	payload.CertificateID, err = strconv.Atoi(certIdParam)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// do validation
+	// certificate
+	_, err = service.certificates.GetCertificate(payload.CertificateID)
+	if err != nil {
+		return err
+	}
+	// host
+	if payload.Host == nil || !hostValid(*payload.Host) {
+		service.logger.Debug(ErrHostBad)
+		return output.ErrValidationFailed
+	}
+	// port (default 443)
+	if payload.Port == nil {
+		payload.Port = new(int)
+		*payload.Port = defaultPort
+	}
+	if !portValid(*payload.Port) {
+		service.logger.Debug(ErrPortBad)
+		return output.ErrValidationFailed
+	}
+	// server name (if none, set to blank)
+	if payload.ServerName == nil {
+		payload.ServerName = new(string)
+	}
+	if !serverNameValid(*payload.ServerName) {
+		service.logger.Debug(ErrServerNameBad)
+		return output.ErrValidationFailed
+	}
+	// not a duplicate
+	if !service.endpointUnique(payload.CertificateID, *payload.Host, *payload.Port, *payload.ServerName, nil) {
+		service.logger.Debug(ErrEndpointExists)
+		return output.ErrValidationFailed
+	}
+	// enabled (default true)
+	if payload.Enabled == nil {
+		payload.Enabled = new(bool)
+		*payload.Enabled = true
+	}
+	// end validation
+
+	// add additional details to the payload before saving
+	payload.CreatedAt = int(time.Now().Unix())
+	payload.UpdatedAt = payload.CreatedAt
+
+	// save new endpoint to storage, which also returns the new id
+	id, err := service.storage.PostNewDeployEndpoint(payload)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// return response to client
+	response := output.JsonResponse{
+		Status:  http.StatusCreated,
+		Message: "created",
+		ID:      id,
+	}
+
+	_, err = service.output.WriteJSON(w, response.Status, response, "response")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
+
+// CheckDeployEndpoint checks a deploy endpoint now (even if it is disabled) and
+// returns the result as JSON
+func (service *Service) CheckDeployEndpoint(w http.ResponseWriter, r *http.Request) (err error) {
+	// params
+	params := httprouter.ParamsFromContext(r.Context())
+	certId, err := strconv.Atoi(params.ByName("certid"))
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+	id, err := strconv.Atoi(params.ByName("id"))
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// get the endpoint from storage (and validate ids)
+	endpoint, err := service.getEndpoint(certId, id)
+	if err != nil {
+		return err
+	}
+
+	// check
+	check := service.check(endpoint)
+
+	// return response to client
+	_, err = service.output.WriteJSON(w, http.StatusOK, check, "deploy_endpoint_check")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
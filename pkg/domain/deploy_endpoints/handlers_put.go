@@ -0,0 +1,109 @@
+package deploy_endpoints
+
+import (
+	"encoding/json"
+	"legocerthub-backend/pkg/output"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// UpdatePayload is the struct for editing an existing deploy endpoint. Only
+// fields received in the payload (non-nil) are updated.
+type UpdatePayload struct {
+	ID         int     `json:"-"`
+	Host       *string `json:"host"`
+	Port       *int    `json:"port"`
+	ServerName *string `json:"server_name"`
+	Enabled    *bool   `json:"enabled"`
+	UpdatedAt  int     `json:"-"`
+}
+
+// PutDeployEndpointUpdate updates a deploy endpoint that already exists in storage
+func (service *Service) PutDeployEndpointUpdate(w http.ResponseWriter, r *http.Request) (err error) {
+	// parse payload
+	var payload UpdatePayload
+	err = json.NewDecoder(r.Body).Decode(&payload)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// get params
+	params := httprouter.ParamsFromContext(r.Context())
+	certId, err := strconv.Atoi(params.ByName("certid"))
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+	payload.ID, err = strconv.Atoi(params.ByName("id"))
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// validation
+	// id
+	endpoint, err := service.getEndpoint(certId, payload.ID)
+	if err != nil {
+		return err
+	}
+	// host (optional)
+	if payload.Host != nil {
+		if !hostValid(*payload.Host) {
+			service.logger.Debug(ErrHostBad)
+			return output.ErrValidationFailed
+		}
+		endpoint.Host = *payload.Host
+	}
+	// port (optional)
+	if payload.Port != nil {
+		if !portValid(*payload.Port) {
+			service.logger.Debug(ErrPortBad)
+			return output.ErrValidationFailed
+		}
+		endpoint.Port = *payload.Port
+	}
+	// server name (optional, blank sends the host)
+	if payload.ServerName != nil {
+		if !serverNameValid(*payload.ServerName) {
+			service.logger.Debug(ErrServerNameBad)
+			return output.ErrValidationFailed
+		}
+		endpoint.ServerName = *payload.ServerName
+	}
+	// not a duplicate (after changes)
+	if !service.endpointUnique(certId, endpoint.Host, endpoint.Port, endpoint.ServerName, &payload.ID) {
+		service.logger.Debug(ErrEndpointExists)
+		return output.ErrValidationFailed
+	}
+	// Enabled does not need validation
+	// end validation
+
+	// add additional details to the payload before saving
+	payload.UpdatedAt = int(time.Now().Unix())
+
+	// save updated endpoint to storage
+	err = service.storage.PutDeployEndpointUpdate(payload)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// return response to client
+	response := output.JsonResponse{
+		Status:  http.StatusOK,
+		Message: "updated",
+		ID:      payload.ID,
+	}
+
+	_, err = service.output.WriteJSON(w, response.Status, response, "response")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package deploy_endpoints
+
+import (
+	"context"
+	"errors"
+	"legocerthub-backend/pkg/domain/certificates"
+	"legocerthub-backend/pkg/notifications"
+	"legocerthub-backend/pkg/output"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var errServiceComponent = errors.New("necessary deploy endpoints service component is missing")
+
+// App interface is for connecting to the main app
+type App interface {
+	GetLogger() *zap.SugaredLogger
+	GetOutputter() *output.Service
+	GetDeployEndpointsStorage() Storage
+	GetCertificatesService() *certificates.Service
+	GetNotificationsService() *notifications.Service
+	GetShutdownContext() context.Context
+	GetShutdownWaitGroup() *sync.WaitGroup
+}
+
+// Storage interface for storage functions
+type Storage interface {
+	GetDeployEndpointsByCert(certId int) (endpoints []Endpoint, err error)
+	GetEnabledDeployEndpoints() (endpoints []Endpoint, err error)
+	GetFlaggedDeployEndpoints() (endpoints []Endpoint, err error)
+	GetOneDeployEndpointById(id int) (endpoint Endpoint, err error)
+
+	PostNewDeployEndpoint(payload NewPayload) (id int, err error)
+	PutDeployEndpointUpdate(payload UpdatePayload) (err error)
+	PutDeployEndpointCheck(id int, check Check) (err error)
+	DeleteDeployEndpoint(id int) (err error)
+
+	GetCertPemAndKeyNameById(certId int) (pem string, keyName string, err error)
+}
+
+// Configuration options
+type Config struct {
+	CheckIntervalMinutes *int `yaml:"check_interval_minutes"`
+	TimeoutSeconds       *int `yaml:"timeout_seconds"`
+}
+
+// Deploy endpoints service struct
+type Service struct {
+	shutdownContext context.Context
+	logger          *zap.SugaredLogger
+	output          *output.Service
+	storage         Storage
+	certificates    *certificates.Service
+	notifications   *notifications.Service
+	timeout         time.Duration
+}
+
+// NewService creates a new deploy endpoints service
+func NewService(app App, cfg *Config) (*Service, error) {
+	service := new(Service)
+
+	// logger
+	service.logger = app.GetLogger()
+	if service.logger == nil {
+		return nil, errServiceComponent
+	}
+
+	// output service
+	service.output = app.GetOutputter()
+	if service.output == nil {
+		return nil, errServiceComponent
+	}
+
+	// storage
+	service.storage = app.GetDeployEndpointsStorage()
+	if service.storage == nil {
+		return nil, errServiceComponent
+	}
+
+	// certificates
+	service.certificates = app.GetCertificatesService()
+	if service.certificates == nil {
+		return nil, errServiceComponent
+	}
+
+	// notifications
+	service.notifications = app.GetNotificationsService()
+	if service.notifications == nil {
+		return nil, errServiceComponent
+	}
+
+	// shutdown context
+	service.shutdownContext = app.GetShutdownContext()
+
+	// timeout for each endpoint's handshake
+	timeoutSeconds := *cfg.TimeoutSeconds
+	if timeoutSeconds < 1 {
+		service.logger.Warnf("deploy_endpoints timeout_seconds (%d) is invalid, using 10", timeoutSeconds)
+		timeoutSeconds = 10
+	}
+	service.timeout = time.Duration(timeoutSeconds) * time.Second
+
+	// periodic checks (0 disables)
+	if *cfg.CheckIntervalMinutes > 0 {
+		service.startChecks(time.Duration(*cfg.CheckIntervalMinutes)*time.Minute, app.GetShutdownWaitGroup())
+	}
+
+	return service, nil
+}
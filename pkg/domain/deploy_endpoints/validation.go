@@ -0,0 +1,87 @@
+package deploy_endpoints
+
+import (
+	"errors"
+	"legocerthub-backend/pkg/output"
+	"legocerthub-backend/pkg/storage"
+	"legocerthub-backend/pkg/validation"
+	"net"
+	"strings"
+)
+
+var (
+	ErrIdBad             = errors.New("deploy endpoint id is invalid")
+	ErrHostBad           = errors.New("deploy endpoint host is not valid (must be a hostname or ip)")
+	ErrPortBad           = errors.New("deploy endpoint port is not valid (must be 1 - 65535)")
+	ErrServerNameBad     = errors.New("deploy endpoint server name is not valid (must be blank or a hostname)")
+	ErrEndpointExists    = errors.New("deploy endpoint already exists for the certificate")
+	errEndpointCertWrong = errors.New("deploy endpoint does not belong to the certificate")
+)
+
+// getEndpoint returns the Endpoint for the specified id (which must belong to the
+// specified cert) or an error
+func (service *Service) getEndpoint(certId int, id int) (Endpoint, error) {
+	// basic check
+	if !validation.IsIdExistingValidRange(id) {
+		service.logger.Debug(ErrIdBad)
+		return Endpoint{}, output.ErrValidationFailed
+	}
+
+	// get the endpoint from storage
+	endpoint, err := service.storage.GetOneDeployEndpointById(id)
+	if err != nil {
+		// special error case for no record found
+		if err == storage.ErrNoRecord {
+			service.logger.Debug(err)
+			return Endpoint{}, output.ErrNotFound
+		} else {
+			service.logger.Error(err)
+			return Endpoint{}, output.ErrStorageGeneric
+		}
+	}
+
+	// must be the cert's endpoint
+	if endpoint.CertificateID != certId {
+		service.logger.Debug(errEndpointCertWrong)
+		return Endpoint{}, output.ErrNotFound
+	}
+
+	return endpoint, nil
+}
+
+// endpointUnique returns true if no other endpoint of the cert has the same host,
+// port, and server name. If an id is specified, that endpoint is ignored.
+func (service *Service) endpointUnique(certId int, host string, port int, serverName string, id *int) bool {
+	endpoints, err := service.storage.GetDeployEndpointsByCert(certId)
+	if err != nil {
+		return false
+	}
+
+	for _, endpoint := range endpoints {
+		if id != nil && endpoint.ID == *id {
+			continue
+		}
+
+		if strings.EqualFold(endpoint.Host, host) && endpoint.Port == port && strings.EqualFold(endpoint.ServerName, serverName) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hostValid returns true if host is an ip or a (non-wildcard) hostname
+func hostValid(host string) bool {
+	return net.ParseIP(host) != nil || validation.DomainValid(host, false)
+}
+
+// portValid returns true if port is a valid tcp port
+func portValid(port int) bool {
+	return port >= 1 && port <= 65535
+}
+
+// serverNameValid returns true if the server name is blank or a (non-wildcard)
+// hostname
+func serverNameValid(serverName string) bool {
+	return serverName == "" || validation.DomainValid(serverName, false)
+}
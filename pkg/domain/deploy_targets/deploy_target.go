@@ -0,0 +1,113 @@
+package deploy_targets
+
+import (
+	"encoding/json"
+)
+
+// Target is a remote host a certificate's key and fullchain are pushed to (over
+// SFTP) each time the certificate is issued. After uploading, PostCommand (if
+// any) is run on the host (e.g. to reload a service).
+type Target struct {
+	ID            int
+	CertificateID int
+	Name          string
+	Description   string
+	Host          string
+	Port          int
+	User          string
+	AuthKey       AuthKey
+	HostKey       string
+	KeyPath       string
+	FullchainPath string
+	PostCommand   string
+	Enabled       bool
+	LastResult    *Result
+	CreatedAt     int
+	UpdatedAt     int
+}
+
+// AuthKey is the private key used to authenticate to the host
+type AuthKey struct {
+	ID   int
+	Name string
+	Pem  string
+}
+
+// targetResponse is the api response for a target (the auth key pem is never
+// returned)
+type targetResponse struct {
+	ID            int             `json:"id"`
+	CertificateID int             `json:"certificate_id"`
+	Name          string          `json:"name"`
+	Description   string          `json:"description"`
+	Host          string          `json:"host"`
+	Port          int             `json:"port"`
+	User          string          `json:"user"`
+	PrivateKey    authKeyResponse `json:"private_key"`
+	HostKey       string          `json:"host_key"`
+	KeyPath       string          `json:"key_path"`
+	FullchainPath string          `json:"fullchain_path"`
+	PostCommand   string          `json:"post_command"`
+	Enabled       bool            `json:"enabled"`
+	LastResult    *Result         `json:"last_result"`
+	CreatedAt     int             `json:"created_at"`
+	UpdatedAt     int             `json:"updated_at"`
+}
+
+type authKeyResponse struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func (target Target) response() targetResponse {
+	return targetResponse{
+		ID:            target.ID,
+		CertificateID: target.CertificateID,
+		Name:          target.Name,
+		Description:   target.Description,
+		Host:          target.Host,
+		Port:          target.Port,
+		User:          target.User,
+		PrivateKey: authKeyResponse{
+			ID:   target.AuthKey.ID,
+			Name: target.AuthKey.Name,
+		},
+		HostKey:       target.HostKey,
+		KeyPath:       target.KeyPath,
+		FullchainPath: target.FullchainPath,
+		PostCommand:   target.PostCommand,
+		Enabled:       target.Enabled,
+		LastResult:    target.LastResult,
+		CreatedAt:     target.CreatedAt,
+		UpdatedAt:     target.UpdatedAt,
+	}
+}
+
+// Result is the result of deploying to a target
+type Result struct {
+	TargetID   int    `json:"target_id"`
+	Name       string `json:"name"`
+	Host       string `json:"host"`
+	Succeeded  bool   `json:"succeeded"`
+	Time       int    `json:"time"`
+	Error      string `json:"error,omitempty"`
+	ExitCode   *int   `json:"exit_code"`
+	Output     string `json:"output"`
+	DurationMs int    `json:"duration_ms"`
+}
+
+// ResultFromJson converts a json Result object into a Result. If the json is
+// nil or invalid, nil is returned.
+func ResultFromJson(resultJson *string) *Result {
+	if resultJson == nil {
+		return nil
+	}
+
+	result := new(Result)
+	err := json.Unmarshal([]byte(*resultJson), result)
+	if err != nil {
+		return nil
+	}
+
+	return result
+}
@@ -0,0 +1,53 @@
+package deploy_targets
+
+import (
+	"legocerthub-backend/pkg/output"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// DeleteDeployTarget deletes a deploy target from storage
+func (service *Service) DeleteDeployTarget(w http.ResponseWriter, r *http.Request) (err error) {
+	// get params
+	params := httprouter.ParamsFromContext(r.Context())
+	certId, err := strconv.Atoi(params.ByName("certid"))
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+	id, err := strconv.Atoi(params.ByName("id"))
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// validate target exists
+	_, err = service.getTarget(certId, id)
+	if err != nil {
+		return err
+	}
+
+	// delete from storage
+	err = service.storage.DeleteDeployTarget(id)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// return response to client
+	response := output.JsonResponse{
+		Status:  http.StatusOK,
+		Message: "deleted",
+		ID:      id,
+	}
+
+	_, err = service.output.WriteJSON(w, response.Status, response, "response")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package deploy_targets
+
+import (
+	"legocerthub-backend/pkg/output"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// GetCertDeployTargets returns all of a certificate's deploy targets as JSON
+func (service *Service) GetCertDeployTargets(w http.ResponseWriter, r *http.Request) (err error) {
+	// convert id param to an integer
+	certIdParam := httprouter.ParamsFromContext(r.Context()).ByName("certid")
+	certId, err := strconv.Atoi(certIdParam)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// validate certificate ID
+	_, err = service.certificates.GetCertificate(certId)
+	if err != nil {
+		return err
+	}
+
+	// get targets from storage
+	targets, err := service.storage.GetDeployTargetsByCert(certId)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// assemble response
+	response := []targetResponse{}
+	for i := range targets {
+		response = append(response, targets[i].response())
+	}
+
+	// return response to client
+	_, err = service.output.WriteJSON(w, http.StatusOK, response, "deploy_targets")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
+
+// GetOneDeployTarget returns a single deploy target as JSON
+func (service *Service) GetOneDeployTarget(w http.ResponseWriter, r *http.Request) (err error) {
+	// params
+	params := httprouter.ParamsFromContext(r.Context())
+	certId, err := strconv.Atoi(params.ByName("certid"))
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+	id, err := strconv.Atoi(params.ByName("id"))
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// get the target from storage (and validate ids)
+	target, err := service.getTarget(certId, id)
+	if err != nil {
+		return err
+	}
+
+	// return response to client
+	_, err = service.output.WriteJSON(w, http.StatusOK, target.response(), "deploy_target")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package deploy_targets
+
+import (
+	"encoding/json"
+	"legocerthub-backend/pkg/output"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// defaultPort is the port used if none is specified
+const defaultPort = 22
+
+// NewPayload is a struct for posting a new deploy target
+type NewPayload struct {
+	CertificateID int     `json:"-"`
+	Name          *string `json:"name"`
+	Description   *string `json:"description"`
+	Host          *string `json:"host"`
+	Port          *int    `json:"port"`
+	User          *string `json:"user"`
+	PrivateKeyID  *int    `json:"private_key_id"`
+	HostKey       *string `json:"host_key"`
+	KeyPath       *string `json:"key_path"`
+	FullchainPath *string `json:"fullchain_path"`
+	PostCommand   *string `json:"post_command"`
+	Enabled       *bool   `json:"enabled"`
+	CreatedAt     int     `json:"-"`
+	UpdatedAt     int     `json:"-"`
+}
+
+// PostNewDeployTarget creates a new deploy target for a certificate and saves it
+// to storage
+func (service *Service) PostNewDeployTarget(w http.ResponseWriter, r *http.Request) (err error) {
+	var payload NewPayload
+
+	// decode body into payload
+	err = json.NewDecoder(r.Body).Decode(&payload)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// get cert id from param
+	certIdParam := httprouter.ParamsFromContext(r.Context()).ByName("certid")
+	payload.CertificateID, err = strconv.Atoi(certIdParam)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// do validation
+	// certificate
+	_, err = service.certificates.GetCertificate(payload.CertificateID)
+	if err != nil {
+		return err
+	}
+	// name
+	if payload.Name == nil || !service.nameValid(*payload.Name, nil) {
+		service.logger.Debug(ErrNameBad)
+		return output.ErrValidationFailed
+	}
+	// description (if none, set to blank)
+	if payload.Description == nil {
+		payload.Description = new(string)
+	}
+	// host
+	if payload.Host == nil || !hostValid(*payload.Host) {
+		service.logger.Debug(ErrHostBad)
+		return output.ErrValidationFailed
+	}
+	// port (default 22)
+	if payload.Port == nil {
+		payload.Port = new(int)
+		*payload.Port = defaultPort
+	}
+	if !portValid(*payload.Port) {
+		service.logger.Debug(ErrPortBad)
+		return output.ErrValidationFailed
+	}
+	// user
+	if payload.User == nil || !userValid(*payload.User) {
+		service.logger.Debug(ErrUserBad)
+		return output.ErrValidationFailed
+	}
+	// private key
+	if payload.PrivateKeyID == nil || !service.privateKeyValid(*payload.PrivateKeyID) {
+		service.logger.Debug(ErrPrivateKeyBad)
+		return output.ErrValidationFailed
+	}
+	// host key
+	if payload.HostKey == nil || !hostKeyValid(*payload.HostKey) {
+		service.logger.Debug(ErrHostKeyBad)
+		return output.ErrValidationFailed
+	}
+	// remote paths
+	if payload.KeyPath == nil || payload.FullchainPath == nil || !remotePathsValid(*payload.KeyPath, *payload.FullchainPath) {
+		service.logger.Debug(ErrRemotePathsBad)
+		return output.ErrValidationFailed
+	}
+	// post command (if none, set to blank)
+	if payload.PostCommand == nil {
+		payload.PostCommand = new(string)
+	}
+	if !postCommandValid(*payload.PostCommand) {
+		service.logger.Debug(ErrPostCommandBad)
+		return output.ErrValidationFailed
+	}
+	// enabled (default true)
+	if payload.Enabled == nil {
+		payload.Enabled = new(bool)
+		*payload.Enabled = true
+	}
+	// end validation
+
+	// add additional details to the payload before saving
+	payload.CreatedAt = int(time.Now().Unix())
+	payload.UpdatedAt = payload.CreatedAt
+
+	// save new target to storage, which also returns the new id
+	id, err := service.storage.PostNewDeployTarget(payload)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// return response to client
+	response := output.JsonResponse{
+		Status:  http.StatusCreated,
+		Message: "created",
+		ID:      id,
+	}
+
+	_, err = service.output.WriteJSON(w, response.Status, response, "response")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package deploy_targets
+
+import (
+	"encoding/json"
+	"legocerthub-backend/pkg/output"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// UpdatePayload is the struct for editing an existing deploy target. Only fields
+// received in the payload (non-nil) are updated.
+type UpdatePayload struct {
+	ID            int     `json:"-"`
+	Name          *string `json:"name"`
+	Description   *string `json:"description"`
+	Host          *string `json:"host"`
+	Port          *int    `json:"port"`
+	User          *string `json:"user"`
+	PrivateKeyID  *int    `json:"private_key_id"`
+	HostKey       *string `json:"host_key"`
+	KeyPath       *string `json:"key_path"`
+	FullchainPath *string `json:"fullchain_path"`
+	PostCommand   *string `json:"post_command"`
+	Enabled       *bool   `json:"enabled"`
+	UpdatedAt     int     `json:"-"`
+}
+
+// PutDeployTargetUpdate updates a deploy target that already exists in storage
+func (service *Service) PutDeployTargetUpdate(w http.ResponseWriter, r *http.Request) (err error) {
+	// parse payload
+	var payload UpdatePayload
+	err = json.NewDecoder(r.Body).Decode(&payload)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// get params
+	params := httprouter.ParamsFromContext(r.Context())
+	certId, err := strconv.Atoi(params.ByName("certid"))
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+	payload.ID, err = strconv.Atoi(params.ByName("id"))
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// validation
+	// id
+	target, err := service.getTarget(certId, payload.ID)
+	if err != nil {
+		return err
+	}
+	// name (optional)
+	if payload.Name != nil && !service.nameValid(*payload.Name, &payload.ID) {
+		service.logger.Debug(ErrNameBad)
+		return output.ErrValidationFailed
+	}
+	// host (optional)
+	if payload.Host != nil && !hostValid(*payload.Host) {
+		service.logger.Debug(ErrHostBad)
+		return output.ErrValidationFailed
+	}
+	// port (optional)
+	if payload.Port != nil && !portValid(*payload.Port) {
+		service.logger.Debug(ErrPortBad)
+		return output.ErrValidationFailed
+	}
+	// user (optional)
+	if payload.User != nil && !userValid(*payload.User) {
+		service.logger.Debug(ErrUserBad)
+		return output.ErrValidationFailed
+	}
+	// private key (optional)
+	if payload.PrivateKeyID != nil && !service.privateKeyValid(*payload.PrivateKeyID) {
+		service.logger.Debug(ErrPrivateKeyBad)
+		return output.ErrValidationFailed
+	}
+	// host key (optional)
+	if payload.HostKey != nil && !hostKeyValid(*payload.HostKey) {
+		service.logger.Debug(ErrHostKeyBad)
+		return output.ErrValidationFailed
+	}
+	// remote paths (optional, check against the current path if only one changes)
+	if payload.KeyPath != nil || payload.FullchainPath != nil {
+		keyPath := target.KeyPath
+		if payload.KeyPath != nil {
+			keyPath = *payload.KeyPath
+		}
+		fullchainPath := target.FullchainPath
+		if payload.FullchainPath != nil {
+			fullchainPath = *payload.FullchainPath
+		}
+
+		if !remotePathsValid(keyPath, fullchainPath) {
+			service.logger.Debug(ErrRemotePathsBad)
+			return output.ErrValidationFailed
+		}
+	}
+	// post command (optional, blank disables it)
+	if payload.PostCommand != nil && !postCommandValid(*payload.PostCommand) {
+		service.logger.Debug(ErrPostCommandBad)
+		return output.ErrValidationFailed
+	}
+	// Description and Enabled do not need validation
+	// end validation
+
+	// add additional details to the payload before saving
+	payload.UpdatedAt = int(time.Now().Unix())
+
+	// save updated target to storage
+	err = service.storage.PutDeployTargetUpdate(payload)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// return response to client
+	response := output.JsonResponse{
+		Status:  http.StatusOK,
+		Message: "updated",
+		ID:      payload.ID,
+	}
+
+	_, err = service.output.WriteJSON(w, response.Status, response, "response")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
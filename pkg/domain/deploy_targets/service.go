@@ -0,0 +1,103 @@
+package deploy_targets
+
+import (
+	"context"
+	"errors"
+	"legocerthub-backend/pkg/domain/certificates"
+	"legocerthub-backend/pkg/domain/private_keys"
+	"legocerthub-backend/pkg/output"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var errServiceComponent = errors.New("necessary deploy targets service component is missing")
+
+// App interface is for connecting to the main app
+type App interface {
+	GetLogger() *zap.SugaredLogger
+	GetOutputter() *output.Service
+	GetDeployTargetsStorage() Storage
+	GetCertificatesService() *certificates.Service
+	GetKeysService() *private_keys.Service
+	GetShutdownContext() context.Context
+}
+
+// Storage interface for storage functions
+type Storage interface {
+	GetDeployTargetsByCert(certId int) (targets []Target, err error)
+	GetOneDeployTargetById(id int) (target Target, err error)
+	GetOneDeployTargetByName(name string) (target Target, err error)
+
+	PostNewDeployTarget(payload NewPayload) (id int, err error)
+	PutDeployTargetUpdate(payload UpdatePayload) (err error)
+	PutDeployTargetResult(id int, result Result) (err error)
+	DeleteDeployTarget(id int) (err error)
+
+	GetOneKeyById(id int) (private_keys.Key, error)
+	KeyInUseByDeployTarget(keyId int) (inUse bool, err error)
+}
+
+// Configuration options
+type Config struct {
+	TimeoutSeconds *int `yaml:"timeout_seconds"`
+}
+
+// Deploy targets service struct
+type Service struct {
+	shutdownContext context.Context
+	logger          *zap.SugaredLogger
+	output          *output.Service
+	storage         Storage
+	certificates    *certificates.Service
+	keys            *private_keys.Service
+	timeout         time.Duration
+}
+
+// NewService creates a new deploy targets service
+func NewService(app App, cfg *Config) (*Service, error) {
+	service := new(Service)
+
+	// logger
+	service.logger = app.GetLogger()
+	if service.logger == nil {
+		return nil, errServiceComponent
+	}
+
+	// output service
+	service.output = app.GetOutputter()
+	if service.output == nil {
+		return nil, errServiceComponent
+	}
+
+	// storage
+	service.storage = app.GetDeployTargetsStorage()
+	if service.storage == nil {
+		return nil, errServiceComponent
+	}
+
+	// certificates
+	service.certificates = app.GetCertificatesService()
+	if service.certificates == nil {
+		return nil, errServiceComponent
+	}
+
+	// keys
+	service.keys = app.GetKeysService()
+	if service.keys == nil {
+		return nil, errServiceComponent
+	}
+
+	// shutdown context
+	service.shutdownContext = app.GetShutdownContext()
+
+	// timeout for each target's deploy
+	timeoutSeconds := *cfg.TimeoutSeconds
+	if timeoutSeconds < 1 {
+		service.logger.Warnf("deploy_targets timeout_seconds (%d) is invalid, using 120", timeoutSeconds)
+		timeoutSeconds = 120
+	}
+	service.timeout = time.Duration(timeoutSeconds) * time.Second
+
+	return service, nil
+}
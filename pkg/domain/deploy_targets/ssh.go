@@ -0,0 +1,300 @@
+package deploy_targets
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"legocerthub-backend/pkg/cmdoutput"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// remote file modes
+const (
+	remoteKeyMode       = 0600
+	remoteFullchainMode = 0644
+)
+
+var (
+	errHostKeyMismatch = errors.New("host key does not match the pinned host key")
+	errDeployTimeout   = errors.New("deploy timed out")
+)
+
+// Deploy pushes the key and fullchain to each of the cert's enabled targets and
+// runs their post commands. The result of each target is saved to the target and
+// returned. Targets are deployed in order and a failed target does not stop the
+// remaining targets.
+func (service *Service) Deploy(certId int, keyPem string, fullchainPem string) []Result {
+	// no-op if no service
+	if service == nil {
+		return nil
+	}
+
+	targets, err := service.storage.GetDeployTargetsByCert(certId)
+	if err != nil {
+		service.logger.Errorf("failed to get deploy targets for certificate %d (%s)", certId, err)
+		return nil
+	}
+
+	results := []Result{}
+	for _, target := range targets {
+		if !target.Enabled {
+			continue
+		}
+
+		result := service.deployTarget(target, keyPem, fullchainPem)
+		if result.Succeeded {
+			service.logger.Infof("deployed certificate %d to target %s (%s)", certId, target.Name, target.Host)
+		} else {
+			service.logger.Errorf("failed to deploy certificate %d to target %s (%s) (%s)", certId, target.Name, target.Host, result.Error)
+		}
+
+		err = service.storage.PutDeployTargetResult(target.ID, result)
+		if err != nil {
+			service.logger.Error(err)
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// deployTarget pushes to a single target and returns the result
+func (service *Service) deployTarget(target Target, keyPem string, fullchainPem string) Result {
+	start := time.Now()
+
+	output, exitCode, err := service.push(target, []byte(keyPem), []byte(fullchainPem))
+
+	result := Result{
+		TargetID:   target.ID,
+		Name:       target.Name,
+		Host:       target.Host,
+		Succeeded:  err == nil,
+		Time:       int(start.Unix()),
+		ExitCode:   exitCode,
+		Output:     cmdoutput.Tail(output),
+		DurationMs: int(time.Since(start).Milliseconds()),
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// push connects to the target, uploads the key and fullchain, and runs the post
+// command (if any). The post command's combined output and exit code are returned.
+func (service *Service) push(target Target, keyPem []byte, fullchainPem []byte) (output string, exitCode *int, err error) {
+	ctx, cancel := context.WithTimeout(service.shutdownContext, service.timeout)
+	defer cancel()
+
+	// report a timeout instead of the error from the closed connection
+	defer func() {
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("%w (after %s)", errDeployTimeout, service.timeout)
+		}
+	}()
+
+	config, err := clientConfig(target, service.timeout)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// connect
+	addr := net.JoinHostPort(target.Host, strconv.Itoa(target.Port))
+	dialer := new(net.Dialer)
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return "", nil, err
+	}
+	// close the connection if the deploy times out (or LeGo shuts down)
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		return "", nil, err
+	}
+	client := ssh.NewClient(sshConn, chans, reqs)
+	defer client.Close()
+
+	// upload
+	sftpClient, err := sftp.NewClient(client)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to start sftp (%w)", err)
+	}
+	defer sftpClient.Close()
+
+	// certs defined by a csr have no key to upload
+	if len(keyPem) > 0 {
+		err = upload(sftpClient, target.KeyPath, keyPem, remoteKeyMode)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	err = upload(sftpClient, target.FullchainPath, fullchainPem, remoteFullchainMode)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// post command
+	if target.PostCommand == "" {
+		return "", nil, nil
+	}
+
+	session, err := client.NewSession()
+	if err != nil {
+		return "", nil, err
+	}
+	defer session.Close()
+
+	// stdout and stderr are copied concurrently, so the combined output must
+	// be safe for concurrent writes
+	combined := new(syncBuffer)
+	session.Stdout = combined
+	session.Stderr = combined
+	err = session.Run(target.PostCommand)
+
+	code := 0
+	var exitErr *ssh.ExitError
+	if errors.As(err, &exitErr) {
+		code = exitErr.ExitStatus()
+		exitCode = &code
+	} else if err == nil {
+		exitCode = &code
+	}
+
+	return combined.String(), exitCode, err
+}
+
+// clientConfig returns the ssh config to connect to the target, authenticating
+// with the target's key and only accepting the target's pinned host key
+func clientConfig(target Target, timeout time.Duration) (*ssh.ClientConfig, error) {
+	signer, err := ssh.ParsePrivateKey([]byte(target.AuthKey.Pem))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key (%w)", err)
+	}
+
+	pinnedKey, pinnedFingerprint, err := parseHostKeyPin(target.HostKey)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &ssh.ClientConfig{
+		User: target.User,
+		Auth: []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: func(_ string, _ net.Addr, key ssh.PublicKey) error {
+			if pinnedKey != nil && bytes.Equal(key.Marshal(), pinnedKey.Marshal()) {
+				return nil
+			}
+			if pinnedFingerprint != "" && ssh.FingerprintSHA256(key) == pinnedFingerprint {
+				return nil
+			}
+			return fmt.Errorf("%w (host presented %s %s)", errHostKeyMismatch, key.Type(), ssh.FingerprintSHA256(key))
+		},
+		Timeout: timeout,
+	}
+
+	// ask for the pinned key's type (the host may have several keys)
+	if pinnedKey != nil {
+		config.HostKeyAlgorithms = []string{pinnedKey.Type()}
+		if pinnedKey.Type() == ssh.KeyAlgoRSA {
+			config.HostKeyAlgorithms = []string{ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA}
+		}
+	}
+
+	return config, nil
+}
+
+// parseHostKeyPin parses a host key in authorized_keys format (e.g. the content
+// of the host's ssh_host_ed25519_key.pub) or a SHA256 fingerprint (as output
+// by ssh-keygen -l)
+func parseHostKeyPin(hostKey string) (key ssh.PublicKey, fingerprint string, err error) {
+	hostKey = strings.TrimSpace(hostKey)
+
+	if strings.HasPrefix(hostKey, "SHA256:") {
+		hash, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(hostKey, "SHA256:"))
+		if err != nil || len(hash) != 32 {
+			return nil, "", errors.New("host key fingerprint is not a valid SHA256 fingerprint")
+		}
+		return nil, hostKey, nil
+	}
+
+	key, _, _, _, err = ssh.ParseAuthorizedKey([]byte(hostKey))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse host key (%w)", err)
+	}
+
+	return key, "", nil
+}
+
+// upload writes content to a temporary file next to remotePath and then renames
+// it to remotePath, so the remote service never reads a partial file
+func upload(client *sftp.Client, remotePath string, content []byte, mode os.FileMode) (err error) {
+	tmpPath := remotePath + ".lego-tmp"
+	defer func() {
+		if err != nil {
+			_ = client.Remove(tmpPath)
+			err = fmt.Errorf("failed to upload %s (%w)", remotePath, err)
+		}
+	}()
+
+	f, err := client.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if err != nil {
+		return err
+	}
+
+	// set mode before writing so the key is never readable by others
+	err = f.Chmod(mode)
+	if err == nil {
+		_, err = f.Write(content)
+	}
+	closeErr := f.Close()
+	if err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+
+	// atomic replace if the server supports it, otherwise remove then rename
+	err = client.PosixRename(tmpPath, remotePath)
+	if err != nil {
+		_ = client.Remove(remotePath)
+		err = client.Rename(tmpPath, remotePath)
+	}
+
+	return err
+}
+
+// syncBuffer is a bytes.Buffer that is safe for concurrent use
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
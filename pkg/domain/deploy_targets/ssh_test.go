@@ -0,0 +1,284 @@
+package deploy_targets
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/sftp"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+)
+
+// testServer is an in-process ssh server that serves sftp and runs exec
+// requests with /bin/sh
+type testServer struct {
+	host    string
+	port    int
+	hostKey ssh.PublicKey
+}
+
+func newTestServer(t *testing.T, clientKey ssh.PublicKey) testServer {
+	_, hostPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostSigner, err := ssh.NewSignerFromKey(hostPriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) != string(clientKey.Marshal()) {
+				return nil, errors.New("unknown key")
+			}
+			return nil, nil
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestConn(conn, config)
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return testServer{host: addr.IP.String(), port: addr.Port, hostKey: hostSigner.PublicKey()}
+}
+
+func serveTestConn(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for newChannel := range chans {
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+
+		go func() {
+			defer channel.Close()
+			for req := range requests {
+				// payloads are a single ssh string (subsystem name or command)
+				arg := ""
+				if len(req.Payload) >= 4 {
+					arg = string(req.Payload[4:])
+				}
+
+				switch {
+				case req.Type == "subsystem" && arg == "sftp":
+					_ = req.Reply(true, nil)
+					server, err := sftp.NewServer(channel)
+					if err == nil {
+						_ = server.Serve()
+					}
+					return
+
+				case req.Type == "exec":
+					_ = req.Reply(true, nil)
+					cmd := exec.Command("/bin/sh", "-c", arg)
+					cmd.Stdout = channel
+					cmd.Stderr = channel.Stderr()
+					_ = cmd.Run()
+
+					status := make([]byte, 4)
+					binary.BigEndian.PutUint32(status, uint32(cmd.ProcessState.ExitCode()))
+					_, _ = channel.SendRequest("exit-status", false, status)
+					return
+
+				default:
+					_ = req.Reply(false, nil)
+				}
+			}
+		}()
+	}
+}
+
+// testTarget returns a target for the server and the service to deploy it
+func testTarget(t *testing.T) (*Service, Target, testServer) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientPub, err := ssh.NewPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := newTestServer(t, clientPub)
+	dir := t.TempDir()
+
+	target := Target{
+		ID:            1,
+		Name:          "web",
+		Host:          server.host,
+		Port:          server.port,
+		User:          "deploy",
+		AuthKey:       AuthKey{Pem: string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))},
+		HostKey:       string(ssh.MarshalAuthorizedKey(server.hostKey)),
+		KeyPath:       filepath.Join(dir, "key.pem"),
+		FullchainPath: filepath.Join(dir, "fullchain.pem"),
+		Enabled:       true,
+	}
+
+	service := &Service{
+		shutdownContext: context.Background(),
+		logger:          zap.NewNop().Sugar(),
+		timeout:         10 * time.Second,
+	}
+
+	return service, target, server
+}
+
+func TestDeployTarget_Push(t *testing.T) {
+	service, target, _ := testTarget(t)
+	target.PostCommand = "cat " + target.FullchainPath + "; echo reloaded >&2"
+
+	// existing files are replaced
+	err := os.WriteFile(target.KeyPath, []byte("old key"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := service.deployTarget(target, "key pem\n", "fullchain pem\n")
+	if !result.Succeeded || result.ExitCode == nil || *result.ExitCode != 0 {
+		t.Fatalf("deploy result is %+v (expected success)", result)
+	}
+	if !strings.Contains(result.Output, "fullchain pem\n") || !strings.Contains(result.Output, "reloaded\n") {
+		t.Errorf("post command output is %q", result.Output)
+	}
+
+	for _, file := range []struct {
+		path    string
+		content string
+		mode    os.FileMode
+	}{
+		{target.KeyPath, "key pem\n", remoteKeyMode},
+		{target.FullchainPath, "fullchain pem\n", remoteFullchainMode},
+	} {
+		content, err := os.ReadFile(file.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		info, err := os.Stat(file.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != file.content || info.Mode().Perm() != file.mode {
+			t.Errorf("%s is %q (mode %s), expected %q (mode %s)", file.path, content, info.Mode().Perm(), file.content, file.mode)
+		}
+	}
+
+	// no temp files left behind
+	_, err = os.Stat(target.KeyPath + ".lego-tmp")
+	if !os.IsNotExist(err) {
+		t.Errorf("temp file was left behind (%v)", err)
+	}
+}
+
+func TestDeployTarget_PostCommandFails(t *testing.T) {
+	service, target, _ := testTarget(t)
+	target.PostCommand = "echo broken; exit 3"
+
+	result := service.deployTarget(target, "key", "fullchain")
+	if result.Succeeded || result.ExitCode == nil || *result.ExitCode != 3 || result.Output != "broken\n" {
+		t.Errorf("deploy result is %+v (expected exit code 3 with output)", result)
+	}
+}
+
+func TestDeployTarget_HostKey(t *testing.T) {
+	service, target, server := testTarget(t)
+
+	// fingerprint pin
+	target.HostKey = ssh.FingerprintSHA256(server.hostKey)
+	result := service.deployTarget(target, "key", "fullchain")
+	if !result.Succeeded {
+		t.Errorf("deploy with fingerprint pin failed (%s)", result.Error)
+	}
+
+	// wrong key
+	_, otherPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherSigner, err := ssh.NewSignerFromKey(otherPriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target.HostKey = ssh.FingerprintSHA256(otherSigner.PublicKey())
+	result = service.deployTarget(target, "key", "fullchain")
+	if result.Succeeded || !strings.Contains(result.Error, errHostKeyMismatch.Error()) {
+		t.Errorf("deploy result is %+v (expected host key mismatch)", result)
+	}
+}
+
+func TestDeployTarget_Timeout(t *testing.T) {
+	service, target, _ := testTarget(t)
+	service.timeout = time.Second
+	target.PostCommand = "sleep 5"
+
+	result := service.deployTarget(target, "key", "fullchain")
+	if result.Succeeded || !strings.Contains(result.Error, errDeployTimeout.Error()) {
+		t.Errorf("deploy result is %+v (expected timeout)", result)
+	}
+}
+
+func TestParseHostKeyPin(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authorized := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey())))
+
+	for _, test := range []struct {
+		hostKey string
+		valid   bool
+	}{
+		{authorized, true},
+		{authorized + " root@host\n", true},
+		{ssh.FingerprintSHA256(signer.PublicKey()), true},
+		{"SHA256:abc", false},
+		{"ssh-ed25519 notbase64", false},
+		{"", false},
+	} {
+		if hostKeyValid(test.hostKey) != test.valid {
+			t.Errorf("hostKeyValid(%q) != %t", test.hostKey, test.valid)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package deploy_targets
+
+import (
+	"errors"
+	"legocerthub-backend/pkg/output"
+	"legocerthub-backend/pkg/storage"
+	"legocerthub-backend/pkg/validation"
+	"net"
+	"path"
+	"strings"
+	"unicode"
+
+	"golang.org/x/crypto/ssh"
+)
+
+var (
+	ErrIdBad           = errors.New("deploy target id is invalid")
+	ErrNameBad         = errors.New("deploy target name is not valid")
+	ErrHostBad         = errors.New("deploy target host is not valid (must be a hostname or ip)")
+	ErrPortBad         = errors.New("deploy target port is not valid (must be 1 - 65535)")
+	ErrUserBad         = errors.New("deploy target user is not valid")
+	ErrPrivateKeyBad   = errors.New("deploy target private key is not valid (must exist and be usable for ssh)")
+	ErrHostKeyBad      = errors.New("deploy target host key is not valid (must be a public key in authorized_keys format or a SHA256: fingerprint)")
+	ErrRemotePathsBad  = errors.New("deploy target key_path and fullchain_path must be different absolute paths")
+	ErrPostCommandBad  = errors.New("deploy target post command is not valid (must be a single line)")
+	errTargetCertWrong = errors.New("deploy target does not belong to the certificate")
+)
+
+// getTarget returns the Target for the specified id (which must belong to the
+// specified cert) or an error
+func (service *Service) getTarget(certId int, id int) (Target, error) {
+	// basic check
+	if !validation.IsIdExistingValidRange(id) {
+		service.logger.Debug(ErrIdBad)
+		return Target{}, output.ErrValidationFailed
+	}
+
+	// get the target from storage
+	target, err := service.storage.GetOneDeployTargetById(id)
+	if err != nil {
+		// special error case for no record found
+		if err == storage.ErrNoRecord {
+			service.logger.Debug(err)
+			return Target{}, output.ErrNotFound
+		} else {
+			service.logger.Error(err)
+			return Target{}, output.ErrStorageGeneric
+		}
+	}
+
+	// must be the cert's target
+	if target.CertificateID != certId {
+		service.logger.Debug(errTargetCertWrong)
+		return Target{}, output.ErrNotFound
+	}
+
+	return target, nil
+}
+
+// nameValid returns true if the specified target name is acceptable and not
+// already in use by another target. If an id is specified, the name is also
+// accepted if it is in use by that id.
+func (service *Service) nameValid(name string, id *int) bool {
+	// basic character/length check
+	if !validation.NameValid(name) {
+		return false
+	}
+
+	// make sure the name isn't already in use in storage
+	target, err := service.storage.GetOneDeployTargetByName(name)
+	if err == storage.ErrNoRecord {
+		return true
+	} else if err != nil {
+		return false
+	}
+
+	// if the returned target is the target being edited, name is ok
+	if id != nil && target.ID == *id {
+		return true
+	}
+
+	return false
+}
+
+// privateKeyValid returns true if the key can be used to authenticate with ssh
+// and is not in use by an account or a certificate (deploy targets may share
+// keys)
+func (service *Service) privateKeyValid(keyId int) bool {
+	if !validation.IsIdExistingValidRange(keyId) {
+		return false
+	}
+
+	if !service.keys.KeyAvailable(keyId) {
+		inUse, err := service.storage.KeyInUseByDeployTarget(keyId)
+		if err != nil || !inUse {
+			return false
+		}
+	}
+
+	key, err := service.storage.GetOneKeyById(keyId)
+	if err != nil {
+		return false
+	}
+
+	_, err = ssh.ParsePrivateKey([]byte(key.Pem))
+	return err == nil
+}
+
+// hostValid returns true if host is an ip or a (non-wildcard) hostname
+func hostValid(host string) bool {
+	return net.ParseIP(host) != nil || validation.DomainValid(host, false)
+}
+
+// portValid returns true if port is a valid tcp port
+func portValid(port int) bool {
+	return port >= 1 && port <= 65535
+}
+
+// userValid returns true if user is not blank and has no whitespace or control
+// characters
+func userValid(user string) bool {
+	if user == "" {
+		return false
+	}
+
+	return strings.IndexFunc(user, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsControl(r)
+	}) == -1
+}
+
+// hostKeyValid returns true if the host key can be parsed as a pin
+func hostKeyValid(hostKey string) bool {
+	_, _, err := parseHostKeyPin(hostKey)
+	return err == nil
+}
+
+// remotePathsValid returns true if the key and fullchain paths are different
+// absolute (remote, unix) paths
+func remotePathsValid(keyPath string, fullchainPath string) bool {
+	for _, p := range []string{keyPath, fullchainPath} {
+		if !path.IsAbs(p) || strings.HasSuffix(p, "/") {
+			return false
+		}
+	}
+
+	return path.Clean(keyPath) != path.Clean(fullchainPath)
+}
+
+// postCommandValid returns true if the command is a single line (blank is
+// valid, it disables the command)
+func postCommandValid(cmd string) bool {
+	return !strings.ContainsAny(cmd, "\r\n\x00")
+}
@@ -6,6 +6,7 @@ import (
 	"legocerthub-backend/pkg/output"
 	"legocerthub-backend/pkg/storage"
 	"net/http"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
 )
@@ -69,8 +70,9 @@ func (service *Service) DownloadCertViaUrl(w http.ResponseWriter, r *http.Reques
 // getCertPem returns the cert pem and private key name if the apiKey matches the
 // requested key. It also checks the apiKeyViaUrl property if the client is making
 // a request with the apiKey in the Url. The pem is from the most recent valid
-// order for the specified cert. The keyName is the name of the key that corresponds
-// to that order.
+// order for the specified cert. The keyName is the name of the key that finalized
+// that order (which is not the cert's current key if the key was changed or rotated
+// after the order).
 func (service *Service) getCertPem(certName string, apiKey string, fullChain bool, apiKeyViaUrl bool) (certPem string, keyName string, err error) {
 	// if not running https, error
 	if !service.https && !service.devMode {
@@ -86,10 +88,9 @@ func (service *Service) getCertPem(certName string, apiKey string, fullChain boo
 	// get the cert from storage
 	cert, err := service.storage.GetOneCertByName(certName)
 	if err != nil {
-		// special error case for no record found
+		// no managed cert, try the unmanaged certs
 		if err == storage.ErrNoRecord {
-			service.logger.Debug(err)
-			return "", "", output.ErrNotFound
+			return service.getUnmanagedCertPem(certName, apiKey, fullChain, apiKeyViaUrl)
 		} else {
 			service.logger.Error(err)
 			return "", "", output.ErrStorageGeneric
@@ -108,8 +109,8 @@ func (service *Service) getCertPem(certName string, apiKey string, fullChain boo
 		return "", "", output.ErrUnauthorized
 	}
 
-	// get pem of the most recent valid order for the cert
-	_, certPem, err = service.storage.GetCertPemById(cert.ID)
+	// get pem of the most recent valid order for the cert (and its key)
+	certPem, keyName, err = service.storage.GetCertPemAndKeyNameById(cert.ID)
 	if err != nil {
 		// special error case for no record found
 		// of note, this indicates the cert exists but there is no
@@ -138,5 +139,56 @@ func (service *Service) getCertPem(certName string, apiKey string, fullChain boo
 	}
 
 	// return pem content and key name
-	return certPem, cert.CertificateKey.Name, nil
+	return certPem, keyName, nil
+}
+
+// getUnmanagedCertPem is the same as getCertPem but for an unmanaged cert. The pem
+// is the imported chain (as long as it hasn't expired) and the keyName is the
+// name of the cert's key (blank if it doesn't have one).
+func (service *Service) getUnmanagedCertPem(certName string, apiKey string, fullChain bool, apiKeyViaUrl bool) (certPem string, keyName string, err error) {
+	// get the cert from storage
+	cert, err := service.storage.GetOneUnmanagedCertByName(certName)
+	if err != nil {
+		// special error case for no record found
+		if err == storage.ErrNoRecord {
+			service.logger.Debug(err)
+			return "", "", output.ErrNotFound
+		} else {
+			service.logger.Error(err)
+			return "", "", output.ErrStorageGeneric
+		}
+	}
+
+	// if apiKey came from URL, and cert does not support this, error
+	if apiKeyViaUrl && !cert.ApiKeyViaUrl {
+		service.logger.Debug(errApiKeyFromUrlDisallowed)
+		return "", "", output.ErrUnauthorized
+	}
+
+	// verify apikey matches cert apikey (new or old)
+	if (apiKey != cert.ApiKey) && (apiKey != cert.ApiKeyNew) {
+		service.logger.Debug(errWrongApiKey)
+		return "", "", output.ErrUnauthorized
+	}
+
+	// don't serve an expired cert (log warn, the cert needs to be replaced)
+	if int64(cert.ValidTo) <= time.Now().Unix() {
+		service.logger.Warnf("unmanaged certificate %s is expired", cert.Name)
+		return "", "", output.ErrNotFound
+	}
+
+	certPem = cert.Pem
+
+	// if not fullchain, discard rest of chain
+	if !fullChain {
+		certBlock, _ := pem.Decode([]byte(certPem))
+		certPem = string(pem.EncodeToMemory(certBlock))
+	}
+
+	if cert.PrivateKey != nil {
+		keyName = cert.PrivateKey.Name
+	}
+
+	// return pem content and key name
+	return certPem, keyName, nil
 }
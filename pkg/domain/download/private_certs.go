@@ -93,6 +93,13 @@ func (service *Service) getPrivateCertPem(certName string, apiKeysString string,
 		return "", err
 	}
 
+	// certs defined by a csr (and unmanaged certs without a key) only have public
+	// material
+	if keyName == "" {
+		service.logger.Debugf("certificate %s has no private key", certName)
+		return "", output.ErrNotFound
+	}
+
 	// fetch the matching private key
 	keyPem, err := service.getKeyPem(keyName, keyApiKey, apiKeyViaUrl)
 	if err != nil {
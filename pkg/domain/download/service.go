@@ -4,6 +4,7 @@ import (
 	"errors"
 	"legocerthub-backend/pkg/domain/certificates"
 	"legocerthub-backend/pkg/domain/private_keys"
+	"legocerthub-backend/pkg/domain/unmanaged_certificates"
 	"legocerthub-backend/pkg/output"
 
 	"go.uber.org/zap"
@@ -25,7 +26,9 @@ type Storage interface {
 	GetOneKeyByName(name string) (private_keys.Key, error)
 
 	GetOneCertByName(name string) (cert certificates.Certificate, err error)
-	GetCertPemById(certId int) (name string, pem string, err error)
+	GetCertPemAndKeyNameById(certId int) (pem string, keyName string, err error)
+
+	GetOneUnmanagedCertByName(name string) (cert unmanaged_certificates.Certificate, err error)
 }
 
 // Keys service struct
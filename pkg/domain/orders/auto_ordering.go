@@ -1,7 +1,6 @@
 package orders
 
 import (
-	"context"
 	"database/sql"
 	"errors"
 	"legocerthub-backend/pkg/randomness"
@@ -9,80 +8,202 @@ import (
 	"time"
 )
 
-// startAutoOrderService starts a go routine that completes existing orders that are
-// not yet in a 'valid' or 'invalid' state and also places new orders forexpiring certs
-// The service runs daily at the time specified in consts.
-func (service *Service) startAutoOrderService(cfg *Config, ctx context.Context, wg *sync.WaitGroup) {
+// renewalPlanInterval is the longest the scheduler sleeps before re-checking
+// storage, even if nothing signaled a change
+const renewalPlanInterval = 15 * time.Minute
+
+// renewalRetryDelay is how long the scheduler waits before ordering a cert
+// again if the cert is still due after it was ordered (e.g. the order failed)
+const renewalRetryDelay = 1 * time.Hour
+
+// incompleteOrdersInterval is how often incomplete orders are retried
+const incompleteOrdersInterval = 24 * time.Hour
+
+// CertRenewal is when a certificate is due to be renewed (unix time)
+type CertRenewal struct {
+	CertificateID int
+	RenewAt       int
+}
+
+// renewalScheduler holds the state of the automatic ordering service
+type renewalScheduler struct {
+	defaultRemaining time.Duration
+	newOrderDelay    time.Duration
+	maxJitterSeconds int
+	windows          []renewalWindow
+
+	// per cert jitter (so a cert's renewal time is stable between plans) and the
+	// last time each due cert was ordered
+	jitter      map[int]time.Duration
+	lastOrdered map[int]time.Time
+
+	nextIncompleteRetry time.Time
+}
+
+// replanRenewals signals the automatic ordering service to recalculate when
+// certificates are due (e.g. a certificate or order changed)
+func (service *Service) replanRenewals() {
+	select {
+	case service.renewalReplan <- struct{}{}:
+	default:
+		// replan already pending
+	}
+}
+
+// startAutoOrderService starts a go routine that calculates when each certificate
+// is due to be renewed and sleeps until the earliest one. Due certificates are
+// ordered (only during renewal windows, if any are configured) and existing orders
+// that are not yet in a 'valid' or 'invalid' state are also retried periodically.
+func (service *Service) startAutoOrderService(cfg *Config, windows []renewalWindow, wg *sync.WaitGroup) {
 	// dont run if not enabled
 	if !*cfg.AutomaticOrderingEnable {
 		return
 	}
 
-	// calculate timing based on config
-	remainingDaysThreshold := time.Duration(*cfg.ValidRemainingDaysThreshold) * (24 * time.Hour)
-	refreshHour := *cfg.RefreshTimeHour
-	refreshMinute := *cfg.RefreshTimeMinute
+	scheduler := &renewalScheduler{
+		defaultRemaining: time.Duration(*cfg.ValidRemainingDaysThreshold) * (24 * time.Hour),
+		newOrderDelay:    time.Duration(*cfg.NewOrderDelaySeconds) * time.Second,
+		maxJitterSeconds: *cfg.RenewalJitterMinutes * 60,
+		windows:          windows,
+		jitter:           make(map[int]time.Duration),
+		lastOrdered:      make(map[int]time.Time),
+	}
 
 	// log start and update wg
 	service.logger.Infof("starting automatic certificate ordering service; %d day expiration threshold; "+
-		"orders will be placed every day at %d:%d", *cfg.ValidRemainingDaysThreshold, refreshHour, refreshMinute)
+		"%d minute max jitter; %d renewal window(s)", *cfg.ValidRemainingDaysThreshold, *cfg.RenewalJitterMinutes, len(windows))
 	wg.Add(1)
 
 	// service routine
 	go func() {
 		defer wg.Done()
-		var nextRunTime time.Time
 
 		// indefinite service loop
 		for {
-			// run time for today
-			nextRunTime = time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(),
-				refreshHour, refreshMinute, 0, 0, time.Local)
+			now := time.Now()
+			due, next := service.planRenewals(scheduler, now)
+
+			// do work if any is due and allowed now
+			incompletesDue := !scheduler.nextIncompleteRetry.After(now)
+			if (len(due) > 0 || incompletesDue) && renewalAllowedAt(scheduler.windows, now).Equal(now) {
+				if incompletesDue {
+					// complete existing orders that are not 'valid' or 'invalid' (i.e. not completed)
+					err := service.retryIncompleteOrders()
+					if err != nil {
+						service.logger.Errorf("error retying incomplete orders: %s", err)
+					}
+					scheduler.nextIncompleteRetry = now.Add(incompleteOrdersInterval)
+				}
 
-			// if today's run already passed, run tomorrow
-			if !nextRunTime.After(time.Now()) {
-				nextRunTime = nextRunTime.Add(24 * time.Hour)
+				// order due certificates
+				err := service.orderExpiringCerts(scheduler, due)
+				if err != nil {
+					service.logger.Errorf("error ordering expiring certs: %s", err)
+				}
+
+				// replan immediately
+				continue
 			}
 
-			// add random second to runtime, as preferred by Let's Encrypt
-			// see: https://letsencrypt.org/docs/integration-guide/#when-to-renew
-			// added after timestamp calc to avoid accidental duplicate run on same day
-			// e.g. if runs at :12 and then next timestamp is :50, it is possible for the
-			// new stamp to not be after now and therefore would run a second time
-			refreshSecond, err := randomness.GenerateRandomInt(60)
-			if err != nil {
-				// if error, use 12
-				service.logger.Errorf("failed to generate auto order random second integer (%s)", err)
-				refreshSecond = 12
+			// wake for the next due work (once allowed) or to recheck storage
+			wake := scheduler.nextIncompleteRetry
+			if !next.IsZero() && next.Before(wake) {
+				wake = next
+			}
+			if len(due) > 0 || wake.Before(now) {
+				// due now, but not allowed yet
+				wake = now
+			}
+			wake = renewalAllowedAt(scheduler.windows, wake)
+			if wake.After(now.Add(renewalPlanInterval)) {
+				wake = now.Add(renewalPlanInterval)
 			}
-			nextRunTime = nextRunTime.Add(time.Duration(refreshSecond) * time.Second)
 
-			// sleep or wait for shutdown context to be done
+			if !next.IsZero() {
+				service.logger.Debugf("next certificate renewal due at %s", next.Format(time.RFC3339))
+			}
+
+			// sleep, wait for a replan signal, or wait for shutdown context to be done
 			select {
-			case <-ctx.Done():
+			case <-service.shutdownContext.Done():
 				// close routine
 				service.logger.Info("automatic certificate ordering service shutdown complete")
 				return
 
-			case <-time.After(time.Until(nextRunTime)):
-				// sleep until run time
-			}
+			case <-service.renewalReplan:
+				// certificates or orders changed
 
-			// complete existing orders that are not 'valid' or 'invalid' (i.e. not completed)
-			err = service.retryIncompleteOrders()
-			if err != nil {
-				service.logger.Errorf("error retying incomplete orders: %s", err)
-			}
-
-			// order expiring certificates
-			err = service.orderExpiringCerts(remainingDaysThreshold)
-			if err != nil {
-				service.logger.Errorf("error ordering expiring certs: %s", err)
+			case <-time.After(time.Until(wake)):
+				// sleep until wake time
 			}
 		}
 	}()
 }
 
+// planRenewals fetches the renewal time of each certificate and returns the ids of
+// the certs that are due now and the time the next cert (that isn't due) is due.
+// If no other certs will be due, next is zero.
+func (service *Service) planRenewals(scheduler *renewalScheduler, now time.Time) (due []int, next time.Time) {
+	renewals, err := service.storage.GetCertRenewalTimes(scheduler.defaultRemaining)
+	if err != nil {
+		service.logger.Errorf("failed to fetch certificate renewal times (%s)", err)
+		return nil, time.Time{}
+	}
+
+	current := make(map[int]struct{}, len(renewals))
+	for _, renewal := range renewals {
+		current[renewal.CertificateID] = struct{}{}
+
+		// renew a random amount of time early, as preferred by Let's Encrypt
+		// see: https://letsencrypt.org/docs/integration-guide/#when-to-renew
+		renewAt := time.Unix(int64(renewal.RenewAt), 0).Add(-service.renewalJitter(scheduler, renewal.CertificateID))
+
+		// not due, forget any previous order (cert was renewed)
+		if renewAt.After(now) {
+			delete(scheduler.lastOrdered, renewal.CertificateID)
+		} else if lastOrdered, ok := scheduler.lastOrdered[renewal.CertificateID]; ok {
+			// due, but was already ordered recently
+			renewAt = lastOrdered.Add(renewalRetryDelay)
+		}
+
+		if !renewAt.After(now) {
+			due = append(due, renewal.CertificateID)
+		} else if next.IsZero() || renewAt.Before(next) {
+			next = renewAt
+		}
+	}
+
+	// forget certs that no longer exist (or no longer have a valid order)
+	for certId := range scheduler.jitter {
+		if _, ok := current[certId]; !ok {
+			delete(scheduler.jitter, certId)
+			delete(scheduler.lastOrdered, certId)
+		}
+	}
+
+	return due, next
+}
+
+// renewalJitter returns the cert's jitter, generating it if the cert doesn't
+// have one yet
+func (service *Service) renewalJitter(scheduler *renewalScheduler, certId int) time.Duration {
+	jitter, ok := scheduler.jitter[certId]
+	if ok {
+		return jitter
+	}
+
+	if scheduler.maxJitterSeconds > 0 {
+		jitterSeconds, err := randomness.GenerateRandomInt(scheduler.maxJitterSeconds)
+		if err != nil {
+			service.logger.Errorf("failed to generate renewal jitter for cert %d (%s)", certId, err)
+		}
+		jitter = time.Duration(jitterSeconds) * time.Second
+	}
+
+	scheduler.jitter[certId] = jitter
+	return jitter
+}
+
 // retryIncompleteOrders retries all incomplete orders within storage. this should
 // move all orders to valid or invalid state.
 func (service *Service) retryIncompleteOrders() (err error) {
@@ -107,19 +228,15 @@ func (service *Service) retryIncompleteOrders() (err error) {
 	return nil
 }
 
-// orderExpiringCerts automatically orders any certficates that are valid but have a valid_to
-// timestamp within the specified threshold
-func (service *Service) orderExpiringCerts(remainingDaysThreshold time.Duration) (err error) {
-	service.logger.Info("adding expiring certificates to order queue")
-
-	// get slice of all expiring certificate ids
-	expiringCertIds, err := service.storage.GetExpiringCertIds(remainingDaysThreshold)
-	if err != nil {
-		return err
-	}
+// orderExpiringCerts automatically orders the specified certificates. The scheduler's
+// newOrderDelay is slept between each certificate.
+func (service *Service) orderExpiringCerts(scheduler *renewalScheduler, certIds []int) (err error) {
+	service.logger.Infof("adding %d expiring certificate(s) to order queue", len(certIds))
 
 	// address each expiring cert
-	for _, certId := range expiringCertIds {
+	for _, certId := range certIds {
+		scheduler.lastOrdered[certId] = time.Now()
+
 		// check for an existing incomplete order
 		orderId, err := service.storage.GetNewestIncompleteCertOrderId(certId)
 
@@ -153,7 +270,7 @@ func (service *Service) orderExpiringCerts(remainingDaysThreshold time.Duration)
 			// abort refreshing due to shutdown
 			return errors.New("expiring certificates refresh canceled due to shutdown")
 
-		case <-time.After(15 * time.Second):
+		case <-time.After(scheduler.newOrderDelay):
 			// sleep and continue
 		}
 	}
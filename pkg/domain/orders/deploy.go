@@ -0,0 +1,88 @@
+package orders
+
+import (
+	"encoding/json"
+	"legocerthub-backend/pkg/domain/deploy_targets"
+	"time"
+)
+
+// deploy statuses
+const (
+	deployStatusSucceeded = "succeeded"
+	deployStatusFailed    = "failed"
+)
+
+// DeployResult is the result of running a certificate's deploy hooks and pushing
+// to its deploy targets after an order's certificate was issued
+type DeployResult struct {
+	Status  string                  `json:"status"`
+	Time    int                     `json:"time"`
+	Hooks   []DeployHookResult      `json:"hooks"`
+	Targets []deploy_targets.Result `json:"targets"`
+}
+
+// DeployResultFromJson converts a json DeployResult object into a DeployResult.
+// If the json is nil or invalid, nil is returned.
+func DeployResultFromJson(resultJson *string) *DeployResult {
+	if resultJson == nil {
+		return nil
+	}
+
+	result := new(DeployResult)
+	err := json.Unmarshal([]byte(*resultJson), result)
+	if err != nil {
+		return nil
+	}
+
+	return result
+}
+
+// runDeploy runs the certificate's deploy hooks and pushes to its deploy targets,
+// then saves the combined result to the order. If there was nothing to deploy,
+// no result is saved.
+func (service *Service) runDeploy(order Order, pemChain string) {
+	cert := order.Certificate
+
+	result := DeployResult{
+		Status:  deployStatusSucceeded,
+		Time:    int(time.Now().Unix()),
+		Hooks:   []DeployHookResult{},
+		Targets: []deploy_targets.Result{},
+	}
+
+	// hooks
+	if len(cert.DeployHooks) > 0 {
+		if service.deployHooks.enable {
+			result.Hooks = service.runDeployHooks(order, pemChain)
+		} else {
+			service.logger.Warnf("certificate %s has deploy hooks but deploy hooks are disabled (orders deploy_hooks enable)", cert.Name)
+		}
+	}
+
+	// targets
+	targetResults := service.deployTargets.Deploy(cert.ID, cert.CertificateKey.Pem, pemChain)
+	if targetResults != nil {
+		result.Targets = targetResults
+	}
+
+	// nothing ran
+	if len(result.Hooks) == 0 && len(result.Targets) == 0 {
+		return
+	}
+
+	for _, hook := range result.Hooks {
+		if !hook.Succeeded {
+			result.Status = deployStatusFailed
+		}
+	}
+	for _, target := range result.Targets {
+		if !target.Succeeded {
+			result.Status = deployStatusFailed
+		}
+	}
+
+	err := service.storage.PutOrderDeployResult(order.ID, result)
+	if err != nil {
+		service.logger.Error(err)
+	}
+}
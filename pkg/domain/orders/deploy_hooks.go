@@ -0,0 +1,190 @@
+package orders
+
+import (
+	"bytes"
+	"context"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"legocerthub-backend/pkg/cmdoutput"
+	"legocerthub-backend/pkg/domain/certificates"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errDeployHookTimeout = errors.New("deploy hook timed out")
+
+// DeployHooksConfig configures running certificates' deploy hooks
+type DeployHooksConfig struct {
+	Enable                *bool `yaml:"enable"`
+	DefaultTimeoutSeconds *int  `yaml:"default_timeout_seconds"`
+}
+
+// deployHooksPolicy is the parsed deploy hooks configuration
+type deployHooksPolicy struct {
+	enable         bool
+	defaultTimeout time.Duration
+}
+
+// newDeployHooksPolicy returns the deploy hooks policy for the config
+func (service *Service) newDeployHooksPolicy(cfg DeployHooksConfig) deployHooksPolicy {
+	timeoutSeconds := *cfg.DefaultTimeoutSeconds
+	if timeoutSeconds < 1 {
+		service.logger.Warnf("orders deploy_hooks default_timeout_seconds (%d) is invalid, using 60", timeoutSeconds)
+		timeoutSeconds = 60
+	}
+
+	return deployHooksPolicy{
+		enable:         *cfg.Enable,
+		defaultTimeout: time.Duration(timeoutSeconds) * time.Second,
+	}
+}
+
+// DeployHookResult is the result of running one deploy hook
+type DeployHookResult struct {
+	Command    string   `json:"command"`
+	Args       []string `json:"args"`
+	Succeeded  bool     `json:"succeeded"`
+	ExitCode   int      `json:"exit_code"`
+	Error      string   `json:"error,omitempty"`
+	Output     string   `json:"output"`
+	DurationMs int      `json:"duration_ms"`
+}
+
+// deployFiles are the temporary files hooks are given
+type deployFiles struct {
+	dir       string
+	key       string
+	cert      string
+	chain     string
+	fullchain string
+}
+
+// writeDeployFiles writes the key, cert, chain, and fullchain pem to a new
+// temporary directory (only readable by the owner). If there is no key (the cert
+// is defined by a csr), no key file is written and its path is blank.
+func writeDeployFiles(keyPem string, pemChain string) (files deployFiles, err error) {
+	// split the leaf cert from the rest of the chain
+	leaf, rest := pem.Decode([]byte(pemChain))
+	if leaf == nil {
+		return deployFiles{}, errors.New("failed to decode certificate pem")
+	}
+	certPem := pem.EncodeToMemory(leaf)
+	chainPem := strings.TrimSpace(string(rest))
+	if chainPem != "" {
+		chainPem += "\n"
+	}
+
+	files.dir, err = os.MkdirTemp("", "lego-deploy-")
+	if err != nil {
+		return deployFiles{}, err
+	}
+	files.cert = filepath.Join(files.dir, "cert.pem")
+	files.chain = filepath.Join(files.dir, "chain.pem")
+	files.fullchain = filepath.Join(files.dir, "fullchain.pem")
+
+	contents := map[string][]byte{
+		files.cert:      certPem,
+		files.chain:     []byte(chainPem),
+		files.fullchain: []byte(pemChain),
+	}
+
+	// certs defined by a csr have no key (key file is left blank)
+	if keyPem != "" {
+		files.key = filepath.Join(files.dir, "privkey.pem")
+		contents[files.key] = []byte(keyPem)
+	}
+	for path, content := range contents {
+		err = os.WriteFile(path, content, 0600)
+		if err != nil {
+			_ = os.RemoveAll(files.dir)
+			return deployFiles{}, err
+		}
+	}
+
+	return files, nil
+}
+
+// runDeployHooks runs each of the certificate's deploy hooks for the order and
+// returns their results. Hooks run in order and a failed hook does not stop the
+// remaining hooks.
+func (service *Service) runDeployHooks(order Order, pemChain string) []DeployHookResult {
+	cert := order.Certificate
+	results := []DeployHookResult{}
+
+	files, err := writeDeployFiles(cert.CertificateKey.Pem, pemChain)
+	if err != nil {
+		service.logger.Errorf("failed to write deploy files for order %d (%s)", order.ID, err)
+		return append(results, DeployHookResult{Error: err.Error()})
+	}
+	defer os.RemoveAll(files.dir)
+
+	env := append(os.Environ(),
+		"LEGO_CERTIFICATE_ID="+strconv.Itoa(cert.ID),
+		"LEGO_CERTIFICATE_NAME="+cert.Name,
+		"LEGO_ORDER_ID="+strconv.Itoa(order.ID),
+		"LEGO_KEY_FILE="+files.key,
+		"LEGO_CERT_FILE="+files.cert,
+		"LEGO_CHAIN_FILE="+files.chain,
+		"LEGO_FULLCHAIN_FILE="+files.fullchain,
+	)
+
+	for _, hook := range cert.DeployHooks {
+		hookResult := service.runDeployHook(hook, env)
+		if !hookResult.Succeeded {
+			service.logger.Errorf("deploy hook %s for certificate %s (order %d) failed (%s)", hook.Command, cert.Name, order.ID, hookResult.Error)
+		} else {
+			service.logger.Infof("deploy hook %s for certificate %s (order %d) succeeded", hook.Command, cert.Name, order.ID)
+		}
+		results = append(results, hookResult)
+	}
+
+	return results
+}
+
+// runDeployHook runs a single hook with env and captures its combined output
+func (service *Service) runDeployHook(hook certificates.DeployHook, env []string) DeployHookResult {
+	timeout := service.deployHooks.defaultTimeout
+	if hook.TimeoutSeconds > 0 {
+		timeout = time.Duration(hook.TimeoutSeconds) * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(service.shutdownContext, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, hook.Command, hook.Args...)
+	cmd.Env = env
+	output := new(bytes.Buffer)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	// don't wait on children that inherited output after the hook is killed
+	cmd.WaitDelay = 5 * time.Second
+
+	start := time.Now()
+	err := cmd.Run()
+
+	result := DeployHookResult{
+		Command:    hook.Command,
+		Args:       hook.Args,
+		Succeeded:  err == nil,
+		ExitCode:   -1,
+		Output:     cmdoutput.Tail(output.String()),
+		DurationMs: int(time.Since(start).Milliseconds()),
+	}
+	if cmd.ProcessState != nil {
+		result.ExitCode = cmd.ProcessState.ExitCode()
+	}
+
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("%w (after %s)", errDeployHookTimeout, timeout)
+		}
+		result.Error = err.Error()
+	}
+
+	return result
+}
@@ -0,0 +1,105 @@
+package orders
+
+import (
+	"context"
+	"legocerthub-backend/pkg/domain/certificates"
+	"legocerthub-backend/pkg/domain/private_keys"
+	"legocerthub-backend/pkg/internal/certtest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// deployStorage records deploy results (only the functions used by deploy hooks
+// are implemented)
+type deployStorage struct {
+	Storage
+	results map[int]DeployResult
+}
+
+func (store *deployStorage) PutOrderDeployResult(orderId int, result DeployResult) error {
+	store.results[orderId] = result
+	return nil
+}
+
+func TestDeployHooks_Run(t *testing.T) {
+	store := &deployStorage{results: make(map[int]DeployResult)}
+	service := &Service{
+		shutdownContext: context.Background(),
+		logger:          zap.NewNop().Sugar(),
+		storage:         store,
+		deployHooks:     deployHooksPolicy{enable: true, defaultTimeout: 10 * time.Second},
+	}
+
+	order := Order{
+		ID: 7,
+		Certificate: certificates.Certificate{
+			ID:             3,
+			Name:           "my-cert",
+			CertificateKey: private_keys.Key{Pem: "key pem\n"},
+			DeployHooks: []certificates.DeployHook{
+				// output the files and env
+				{Command: "/bin/sh", Args: []string{"-c", `cat "$LEGO_KEY_FILE"; for f in "$LEGO_CERT_FILE" "$LEGO_CHAIN_FILE" "$LEGO_FULLCHAIN_FILE"; do grep -c "BEGIN CERTIFICATE" "$f"; done; echo "$LEGO_CERTIFICATE_NAME $LEGO_ORDER_ID"`}},
+				// failure doesn't stop later hooks
+				{Command: "/bin/sh", Args: []string{"-c", "echo broken >&2; exit 3"}},
+				{Command: "/bin/sh", Args: []string{"-c", "exec sleep 5"}, TimeoutSeconds: 1},
+			},
+		},
+	}
+
+	service.runDeploy(order, certtest.IssueForNames(t, time.Hour, "leaf.example.com").ChainPem)
+
+	result, ok := store.results[order.ID]
+	if !ok {
+		t.Fatal("deploy result was not saved")
+	}
+	if result.Status != deployStatusFailed || len(result.Hooks) != 3 {
+		t.Fatalf("deploy result is %+v (expected failed with 3 hooks)", result)
+	}
+
+	first := result.Hooks[0]
+	expectedOutput := "key pem\n1\n1\n2\nmy-cert 7\n"
+	if !first.Succeeded || first.Output != expectedOutput {
+		t.Errorf("first hook result is %+v (expected success with output %q)", first, expectedOutput)
+	}
+
+	second := result.Hooks[1]
+	if second.Succeeded || second.ExitCode != 3 || second.Output != "broken\n" {
+		t.Errorf("second hook result is %+v (expected exit code 3 with output)", second)
+	}
+
+	third := result.Hooks[2]
+	if third.Succeeded || !strings.Contains(third.Error, errDeployHookTimeout.Error()) {
+		t.Errorf("third hook result is %+v (expected timeout)", third)
+	}
+}
+
+func TestDeployHooks_Disabled(t *testing.T) {
+	store := &deployStorage{results: make(map[int]DeployResult)}
+	service := &Service{
+		shutdownContext: context.Background(),
+		logger:          zap.NewNop().Sugar(),
+		storage:         store,
+		deployHooks:     deployHooksPolicy{enable: false},
+	}
+
+	service.runDeploy(Order{
+		ID: 1,
+		Certificate: certificates.Certificate{
+			DeployHooks: []certificates.DeployHook{{Command: "/bin/false"}},
+		},
+	}, certtest.IssueForNames(t, time.Hour, "leaf.example.com").ChainPem)
+
+	if len(store.results) != 0 {
+		t.Errorf("deploy hooks ran while disabled (%+v)", store.results)
+	}
+}
+
+func TestDeployHooks_writeDeployFilesBadPem(t *testing.T) {
+	_, err := writeDeployFiles("key", "not a pem")
+	if err == nil {
+		t.Error("expected error for invalid pem chain")
+	}
+}
@@ -0,0 +1,41 @@
+package orders
+
+import (
+	"legocerthub-backend/pkg/output"
+	"net/http"
+)
+
+// jobsResponse is the api response for the order job queue
+type jobsResponse struct {
+	Jobs      []jobResponse `json:"jobs"`
+	TotalJobs int           `json:"total_records"`
+}
+
+// GetOrderJobs is an http handler that returns all of the order jobs (queued,
+// running, and failed)
+func (service *Service) GetOrderJobs(w http.ResponseWriter, r *http.Request) (err error) {
+	jobs, err := service.storage.GetOrderJobs()
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// response
+	response := jobsResponse{
+		Jobs:      []jobResponse{},
+		TotalJobs: len(jobs),
+	}
+
+	for i := range jobs {
+		response.Jobs = append(response.Jobs, jobs[i].response())
+	}
+
+	// return response to client
+	_, err = service.output.WriteJSON(w, http.StatusOK, response, "order_jobs")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
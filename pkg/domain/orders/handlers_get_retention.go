@@ -0,0 +1,41 @@
+package orders
+
+import (
+	"legocerthub-backend/pkg/output"
+	"net/http"
+)
+
+// prunableOrdersResponse is the api response for the retention preview
+type prunableOrdersResponse struct {
+	Orders      []prunableOrderResponse `json:"orders"`
+	TotalOrders int                     `json:"total_records"`
+}
+
+// GetRetentionPreview is an http handler that returns the orders the retention
+// policy would delete if it ran now (dry run, nothing is deleted)
+func (service *Service) GetRetentionPreview(w http.ResponseWriter, r *http.Request) (err error) {
+	prunable, err := service.prunableOrders()
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// response
+	response := prunableOrdersResponse{
+		Orders:      []prunableOrderResponse{},
+		TotalOrders: len(prunable),
+	}
+
+	for i := range prunable {
+		response.Orders = append(response.Orders, prunable[i].response())
+	}
+
+	// return response to client
+	_, err = service.output.WriteJSON(w, http.StatusOK, response, "prunable_orders")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
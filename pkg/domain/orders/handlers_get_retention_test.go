@@ -0,0 +1,126 @@
+package orders
+
+import (
+	"encoding/json"
+	"errors"
+	"legocerthub-backend/pkg/output"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// retentionTestApp is used to create the output service for the handler
+type retentionTestApp struct{}
+
+func (retentionTestApp) GetDevMode() bool              { return false }
+func (retentionTestApp) GetLogger() *zap.SugaredLogger { return zap.NewNop().Sugar() }
+
+// retentionTestStorage records the retention query and returns the configured
+// prunable orders. Any other Storage method is not implemented.
+type retentionTestStorage struct {
+	Storage
+	prunable             []PrunableOrder
+	err                  error
+	keepValidOrders      int
+	invalidCreatedBefore int
+}
+
+func (store *retentionTestStorage) GetPrunableOrders(keepValidOrders int, invalidCreatedBefore int) ([]PrunableOrder, error) {
+	store.keepValidOrders = keepValidOrders
+	store.invalidCreatedBefore = invalidCreatedBefore
+	return store.prunable, store.err
+}
+
+func newRetentionTestService(t *testing.T, store *retentionTestStorage) *Service {
+	out, err := output.NewService(retentionTestApp{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Service{
+		logger:  zap.NewNop().Sugar(),
+		output:  out,
+		storage: store,
+		retention: retentionPolicy{
+			keepValidOrders: 2,
+			invalidMaxAge:   30 * 24 * time.Hour,
+		},
+	}
+}
+
+func TestGetRetentionPreview(t *testing.T) {
+	validTo := 1700000000
+	store := &retentionTestStorage{
+		prunable: []PrunableOrder{
+			{ID: 3, CertificateID: 1, CertificateName: "cert-a", Status: "valid", ValidTo: &validTo, CreatedAt: 100},
+			{ID: 7, CertificateID: 2, CertificateName: "cert-b", Status: "invalid", CreatedAt: 200},
+		},
+	}
+	service := newRetentionTestService(t, store)
+
+	w := httptest.NewRecorder()
+	err := service.GetRetentionPreview(w, httptest.NewRequest(http.MethodGet, "/api/v1/orders/retention/preview", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// policy is passed to storage
+	expectedBefore := int(time.Now().Add(-30 * 24 * time.Hour).Unix())
+	if store.keepValidOrders != 2 || store.invalidCreatedBefore < expectedBefore-5 || store.invalidCreatedBefore > expectedBefore {
+		t.Errorf("storage queried with keep %d and invalid before %d, expected keep 2 and invalid before ~%d",
+			store.keepValidOrders, store.invalidCreatedBefore, expectedBefore)
+	}
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d, expected %d", w.Code, http.StatusOK)
+	}
+
+	var response struct {
+		PrunableOrders prunableOrdersResponse `json:"prunable_orders"`
+	}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if response.PrunableOrders.TotalOrders != 2 || len(response.PrunableOrders.Orders) != 2 {
+		t.Fatalf("response has %d of %d orders, expected 2 of 2", len(response.PrunableOrders.Orders),
+			response.PrunableOrders.TotalOrders)
+	}
+	first := response.PrunableOrders.Orders[0]
+	if first.ID != 3 || first.CertificateName != "cert-a" || first.ValidTo == nil || *first.ValidTo != validTo {
+		t.Errorf("first order %+v, expected order 3 of cert-a valid to %d", first, validTo)
+	}
+	if response.PrunableOrders.Orders[1].ValidTo != nil {
+		t.Errorf("invalid order should not have valid_to")
+	}
+}
+
+func TestGetRetentionPreview_Empty(t *testing.T) {
+	service := newRetentionTestService(t, &retentionTestStorage{})
+
+	w := httptest.NewRecorder()
+	err := service.GetRetentionPreview(w, httptest.NewRequest(http.MethodGet, "/api/v1/orders/retention/preview", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// empty list, not null
+	expected := `{"prunable_orders":{"orders":[],"total_records":0}}`
+	if w.Body.String() != expected {
+		t.Errorf("body '%s', expected '%s'", w.Body.String(), expected)
+	}
+}
+
+func TestGetRetentionPreview_StorageError(t *testing.T) {
+	service := newRetentionTestService(t, &retentionTestStorage{err: errors.New("db error")})
+
+	w := httptest.NewRecorder()
+	err := service.GetRetentionPreview(w, httptest.NewRequest(http.MethodGet, "/api/v1/orders/retention/preview", nil))
+	if err != output.ErrStorageGeneric {
+		t.Errorf("error '%v', expected '%v'", err, output.ErrStorageGeneric)
+	}
+}
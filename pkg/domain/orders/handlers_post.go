@@ -175,6 +175,9 @@ func (service *Service) RevokeOrder(w http.ResponseWriter, r *http.Request) (err
 		return output.ErrInternal
 	}
 
+	service.notifications.OrderRevoked(orderId)
+	service.webhooks.CertificateRevoked(orderId)
+
 	// update certificate timestamp
 	err = service.storage.UpdateCertUpdatedTime(certId)
 	if err != nil {
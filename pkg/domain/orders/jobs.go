@@ -0,0 +1,79 @@
+package orders
+
+import (
+	"errors"
+)
+
+var ErrOrderAlreadyProcessing = errors.New("order is already being processed")
+
+// order job states
+const (
+	JobStateQueued  = "queued"
+	JobStateRunning = "running"
+	JobStateFailed  = "failed"
+)
+
+// order job priorities, high priority jobs are always claimed before low priority
+// jobs. The intent is for automated tasks to be low priority, vs manual user
+// initiated tasks being high priority.
+const (
+	jobPriorityLow  = 0
+	jobPriorityHigh = 1
+)
+
+// Job is an order job, jobs are stored so they survive restarts
+type Job struct {
+	ID              int
+	OrderID         int
+	CertificateID   int
+	CertificateName string
+	Priority        int
+	State           string
+	Attempts        int
+	NextRunAt       int
+	LastError       string
+	CreatedAt       int
+	UpdatedAt       int
+}
+
+// jobResponse is the api response for an order job
+type jobResponse struct {
+	ID              int    `json:"id"`
+	OrderID         int    `json:"order_id"`
+	CertificateID   int    `json:"certificate_id"`
+	CertificateName string `json:"certificate_name"`
+	HighPriority    bool   `json:"high_priority"`
+	State           string `json:"state"`
+	Attempts        int    `json:"attempts"`
+	NextRunAt       int    `json:"next_run_at"`
+	LastError       string `json:"last_error,omitempty"`
+	CreatedAt       int    `json:"created_at"`
+	UpdatedAt       int    `json:"updated_at"`
+}
+
+func (job Job) response() jobResponse {
+	return jobResponse{
+		ID:              job.ID,
+		OrderID:         job.OrderID,
+		CertificateID:   job.CertificateID,
+		CertificateName: job.CertificateName,
+		HighPriority:    job.Priority == jobPriorityHigh,
+		State:           job.State,
+		Attempts:        job.Attempts,
+		NextRunAt:       job.NextRunAt,
+		LastError:       job.LastError,
+		CreatedAt:       job.CreatedAt,
+		UpdatedAt:       job.UpdatedAt,
+	}
+}
+
+// NewJobPayload is used to add an order job to storage. If the order already has
+// a failed job, that job is reset instead. If the order already has a queued or
+// running job, ErrOrderAlreadyProcessing is returned.
+type NewJobPayload struct {
+	OrderID   int
+	Priority  int
+	NextRunAt int
+	CreatedAt int
+	UpdatedAt int
+}
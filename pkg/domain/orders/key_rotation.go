@@ -0,0 +1,163 @@
+package orders
+
+import (
+	"errors"
+	"fmt"
+	"legocerthub-backend/pkg/diagnostics"
+	"legocerthub-backend/pkg/domain/private_keys"
+	"legocerthub-backend/pkg/domain/private_keys/key_crypto"
+	"legocerthub-backend/pkg/storage"
+	"sync"
+	"time"
+)
+
+// keyRetireInterval is how often keys replaced by key rotation are checked to
+// see if they can be deleted
+const keyRetireInterval = time.Hour
+
+// KeyRotationConfig configures what happens to keys replaced by key rotation
+type KeyRotationConfig struct {
+	GracePeriodDays *int `yaml:"grace_period_days"`
+}
+
+// RotateKeyPayload is the payload to save a cert's new key and retire its old key
+type RotateKeyPayload struct {
+	CertificateID  int
+	OldKeyID       int
+	Name           string
+	Description    string
+	AlgorithmValue string
+	Pem            string
+	RetireAt       int
+	UpdatedAt      int
+}
+
+// newKeyGracePeriod returns the grace period for the config
+func (service *Service) newKeyGracePeriod(cfg KeyRotationConfig) time.Duration {
+	days := *cfg.GracePeriodDays
+	if days < 0 {
+		service.logger.Warnf("orders key_rotation grace_period_days (%d) is invalid, using 0", days)
+		days = 0
+	}
+
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// rotateKey replaces the order's cert's key with a newly generated key if the cert
+// rotates its key and a certificate was already issued with its current key (so
+// retrying an order doesn't rotate again). The old key is retired after the grace
+// period. The order's cert is updated with the new key. Certs defined by a CSR
+// have no key to rotate.
+func (service *Service) rotateKey(order *Order, diag *diagnostics.Log) error {
+	cert := &order.Certificate
+	if !cert.KeyRotation.Enable || cert.HasCsr() {
+		return nil
+	}
+
+	oldKey := cert.CertificateKey
+	issued, err := service.storage.KeyIssuedCert(cert.ID, oldKey.ID)
+	if err != nil {
+		return err
+	}
+	if !issued {
+		return nil
+	}
+
+	// generate
+	alg := cert.KeyRotation.Algorithm(oldKey.Algorithm)
+	if alg == key_crypto.UnknownAlgorithm {
+		return fmt.Errorf("key rotation: unknown algorithm for certificate %s", cert.Name)
+	}
+	keyPem, err := alg.GeneratePrivateKeyPem()
+	if err != nil {
+		return fmt.Errorf("key rotation: failed to generate key (%w)", err)
+	}
+
+	// save
+	now := time.Now()
+	payload := RotateKeyPayload{
+		CertificateID:  cert.ID,
+		OldKeyID:       oldKey.ID,
+		Name:           fmt.Sprintf("%s_rotated_%d", cert.Name, now.Unix()),
+		Description:    fmt.Sprintf("generated by key rotation for certificate %s (replaces key %s)", cert.Name, oldKey.Name),
+		AlgorithmValue: alg.StorageValue(),
+		Pem:            keyPem,
+		RetireAt:       int(now.Add(service.keyGracePeriod).Unix()),
+		UpdatedAt:      int(now.Unix()),
+	}
+
+	newKeyId, err := service.storage.PutCertRotatedKey(payload)
+	if err != nil {
+		return fmt.Errorf("key rotation: failed to save new key (%w)", err)
+	}
+
+	service.logger.Infof("rotated key for certificate %s (new key %s replaces %s)", cert.Name, payload.Name, oldKey.Name)
+	diag.Addf("key", "rotated key (new key %s replaces %s, which will be retired after %s)",
+		payload.Name, oldKey.Name, time.Unix(int64(payload.RetireAt), 0).Format(time.RFC3339))
+
+	cert.CertificateKey = private_keys.Key{
+		ID:             newKeyId,
+		Name:           payload.Name,
+		Description:    payload.Description,
+		Algorithm:      alg,
+		Pem:            keyPem,
+		ApiKey:         oldKey.ApiKey,
+		ApiKeyNew:      oldKey.ApiKeyNew,
+		ApiKeyDisabled: oldKey.ApiKeyDisabled,
+		ApiKeyViaUrl:   oldKey.ApiKeyViaUrl,
+		CreatedAt:      payload.UpdatedAt,
+		UpdatedAt:      payload.UpdatedAt,
+	}
+
+	return nil
+}
+
+// retireKeys deletes keys replaced by key rotation once their grace period is over.
+// Keys that are still in use (e.g. the cert's renewal failed so the key is still
+// needed for the current certificate) are kept until they are no longer in use.
+func (service *Service) retireKeys() error {
+	keyIds, err := service.storage.GetRetiredKeyIds(int(time.Now().Unix()))
+	if err != nil {
+		return err
+	}
+
+	for _, keyId := range keyIds {
+		err = service.storage.DeleteKey(keyId)
+		if errors.Is(err, storage.ErrInUse) {
+			service.logger.Debugf("key rotation: retired key %d is still in use, not deleting yet", keyId)
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		service.logger.Infof("key rotation: deleted retired key %d", keyId)
+	}
+
+	return nil
+}
+
+// startKeyRetireService starts a go routine that deletes retired keys
+func (service *Service) startKeyRetireService(wg *sync.WaitGroup) {
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		for {
+			err := service.retireKeys()
+			if err != nil {
+				service.logger.Errorf("key rotation: failed to delete retired keys (%s)", err)
+			}
+
+			// sleep or wait for shutdown context to be done
+			select {
+			case <-service.shutdownContext.Done():
+				service.logger.Info("key retire service shutdown complete")
+				return
+
+			case <-time.After(keyRetireInterval):
+				// sleep until next run
+			}
+		}
+	}()
+}
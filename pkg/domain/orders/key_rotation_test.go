@@ -0,0 +1,137 @@
+package orders
+
+import (
+	"context"
+	"legocerthub-backend/pkg/diagnostics"
+	"legocerthub-backend/pkg/domain/certificates"
+	"legocerthub-backend/pkg/domain/private_keys"
+	"legocerthub-backend/pkg/domain/private_keys/key_crypto"
+	"legocerthub-backend/pkg/storage"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// rotationStorage records key rotations (only the functions used by key rotation
+// are implemented)
+type rotationStorage struct {
+	Storage
+	issued    bool
+	rotations []RotateKeyPayload
+	retired   []int
+	inUse     map[int]bool
+	deleted   []int
+}
+
+func (store *rotationStorage) KeyIssuedCert(certId int, keyId int) (bool, error) {
+	return store.issued, nil
+}
+
+func (store *rotationStorage) PutCertRotatedKey(payload RotateKeyPayload) (int, error) {
+	store.rotations = append(store.rotations, payload)
+	return 100 + len(store.rotations), nil
+}
+
+func (store *rotationStorage) GetRetiredKeyIds(now int) ([]int, error) {
+	return store.retired, nil
+}
+
+func (store *rotationStorage) DeleteKey(id int) error {
+	if store.inUse[id] {
+		return storage.ErrInUse
+	}
+	store.deleted = append(store.deleted, id)
+	return nil
+}
+
+func rotationOrder(rotation certificates.KeyRotation) Order {
+	return Order{
+		ID: 1,
+		Certificate: certificates.Certificate{
+			ID:   2,
+			Name: "my-cert",
+			CertificateKey: private_keys.Key{
+				ID:        3,
+				Name:      "my-key",
+				Algorithm: key_crypto.AlgorithmByStorageValue("ecdsap256"),
+				Pem:       "old pem",
+				ApiKey:    "key-api-key",
+			},
+			KeyRotation: rotation,
+		},
+	}
+}
+
+func TestKeyRotation_Rotate(t *testing.T) {
+	store := &rotationStorage{issued: true}
+	service := &Service{
+		shutdownContext: context.Background(),
+		logger:          zap.NewNop().Sugar(),
+		storage:         store,
+		keyGracePeriod:  7 * 24 * time.Hour,
+	}
+
+	order := rotationOrder(certificates.KeyRotation{Enable: true, AlgorithmValue: "ecdsap384"})
+	err := service.rotateKey(&order, diagnostics.NewLog())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(store.rotations) != 1 {
+		t.Fatalf("expected 1 rotation, got %d", len(store.rotations))
+	}
+	payload := store.rotations[0]
+	if payload.CertificateID != 2 || payload.OldKeyID != 3 || payload.AlgorithmValue != "ecdsap384" {
+		t.Errorf("rotation payload is %+v", payload)
+	}
+	if retireIn := payload.RetireAt - payload.UpdatedAt; retireIn != 7*24*60*60 {
+		t.Errorf("old key retires in %d seconds (expected 7 days)", retireIn)
+	}
+
+	newKey := order.Certificate.CertificateKey
+	if newKey.ID != 101 || newKey.Pem != payload.Pem || newKey.ApiKey != "key-api-key" {
+		t.Errorf("cert key was not replaced with the new key (%+v)", newKey)
+	}
+	_, alg, err := key_crypto.ValidateAndStandardizeKeyPem(newKey.Pem)
+	if err != nil || alg.StorageValue() != "ecdsap384" {
+		t.Errorf("new key is not a valid ecdsap384 key (%v)", err)
+	}
+}
+
+func TestKeyRotation_Skip(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		rotation certificates.KeyRotation
+		issued   bool
+	}{
+		{"disabled", certificates.KeyRotation{Enable: false}, true},
+		{"key not used yet", certificates.KeyRotation{Enable: true}, false},
+	} {
+		store := &rotationStorage{issued: test.issued}
+		service := &Service{logger: zap.NewNop().Sugar(), storage: store}
+
+		order := rotationOrder(test.rotation)
+		err := service.rotateKey(&order, diagnostics.NewLog())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(store.rotations) != 0 || order.Certificate.CertificateKey.ID != 3 {
+			t.Errorf("%s: key was rotated", test.name)
+		}
+	}
+}
+
+func TestKeyRotation_RetireKeys(t *testing.T) {
+	store := &rotationStorage{retired: []int{4, 5, 6}, inUse: map[int]bool{5: true}}
+	service := &Service{logger: zap.NewNop().Sugar(), storage: store}
+
+	err := service.retireKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(store.deleted) != 2 || store.deleted[0] != 4 || store.deleted[1] != 6 {
+		t.Errorf("deleted keys %v (expected [4 6])", store.deleted)
+	}
+}
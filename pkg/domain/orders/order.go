@@ -3,6 +3,7 @@ package orders
 import (
 	"legocerthub-backend/pkg/acme"
 	"legocerthub-backend/pkg/challenges"
+	"legocerthub-backend/pkg/diagnostics"
 	"legocerthub-backend/pkg/domain/certificates"
 	"legocerthub-backend/pkg/domain/private_keys"
 )
@@ -25,6 +26,8 @@ type Order struct {
 	Pem            *string
 	ValidFrom      *int
 	ValidTo        *int
+	Diagnostics    []diagnostics.Entry
+	DeployResult   *DeployResult
 	CreatedAt      int
 	UpdatedAt      int
 }
@@ -41,6 +44,8 @@ type orderSummaryResponse struct {
 	FinalizedKey   *orderKeySummaryResponse        `json:"finalized_key"`
 	ValidFrom      *int                            `json:"valid_from"`
 	ValidTo        *int                            `json:"valid_to"`
+	Diagnostics    []diagnostics.Entry             `json:"diagnostics"`
+	DeployResult   *DeployResult                   `json:"deploy_result"`
 	CreatedAt      int                             `json:"created_at"`
 	UpdatedAt      int                             `json:"updated_at"`
 }
@@ -108,6 +113,8 @@ func (order Order) summaryResponse(service *Service) orderSummaryResponse {
 		FinalizedKey:   finalKey,
 		ValidFrom:      order.ValidFrom,
 		ValidTo:        order.ValidTo,
+		Diagnostics:    order.Diagnostics,
+		DeployResult:   order.DeployResult,
 		CreatedAt:      order.CreatedAt,
 		UpdatedAt:      order.UpdatedAt,
 	}
@@ -1,32 +1,53 @@
 package orders
 
-// orderFromAcme updates inProcess to indicate the specified order is being
-// processed and launches a go routine that sends the order job to a worker.
-// Priority allows high priority orders to always be processed before low priority
-// orders. The intent is for automated tasks to be low priority, vs manual user
-// initiated tasks being high priority.
+import (
+	"legocerthub-backend/pkg/events"
+	"time"
+)
+
+// orderFromAcme adds a job to the order job queue for the specified order and wakes
+// a worker to process it. Priority allows high priority orders to always be processed
+// before low priority orders. The intent is for automated tasks to be low priority,
+// vs manual user initiated tasks being high priority.
 func (service *Service) orderFromAcme(orderId int, highPriority bool) (err error) {
-	// add to working to indicate order is being worked
-	err = service.inProcess.add(orderId)
-	// error indicates already inProcess
+	priority := jobPriorityLow
+	if highPriority {
+		priority = jobPriorityHigh
+	}
+
+	now := int(time.Now().Unix())
+	payload := NewJobPayload{
+		OrderID:   orderId,
+		Priority:  priority,
+		NextRunAt: now,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	// save job, error indicates already queued or running
+	_, err = service.storage.PostOrderJob(payload)
 	if err != nil {
 		return err
 	}
 
-	// send the order job to a worker which will try and fulfill order and then remove the order
-	// from inProcess after it is complete
-	go func(service *Service, orderId int, highPriority bool) {
-		job := orderJob{
-			orderId: orderId,
-		}
-
-		// add job, based on priority
-		if highPriority {
-			service.highJobs <- job
-		} else {
-			service.lowJobs <- job
-		}
-	}(service, orderId, highPriority)
+	service.wakeWorkers()
+
+	// queued event (needs the order's cert for clients filtering by cert)
+	order, err := service.storage.GetOneOrder(orderId)
+	if err != nil {
+		service.logger.Error(err)
+		return nil
+	}
+	service.events.Emitter(order.Certificate.ID, orderId).Emit(events.TypeOrderQueued, "order queued")
 
 	return nil
 }
+
+// wakeWorkers signals idle workers that a job is ready
+func (service *Service) wakeWorkers() {
+	select {
+	case service.jobsWake <- struct{}{}:
+	default:
+		// a wake signal is already pending
+	}
+}
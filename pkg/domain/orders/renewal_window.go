@@ -0,0 +1,145 @@
+package orders
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var errRenewalWindowBad = errors.New("renewal window is invalid")
+
+// RenewalWindow is a period of time when automatic ordering is allowed. Days is
+// a list of day names (e.g. mon, tuesday) or weekdays / weekends, if Days is
+// empty the window applies every day. Start and End are HH:MM (24 hour, local
+// time). If End is not after Start the window ends on the following day.
+type RenewalWindow struct {
+	Days  []string `yaml:"days"`
+	Start string   `yaml:"start"`
+	End   string   `yaml:"end"`
+}
+
+// renewalWindow is a parsed RenewalWindow
+type renewalWindow struct {
+	days  [7]bool
+	start time.Duration
+	end   time.Duration
+}
+
+// renewalWindowDays maps config day names to the days they include
+var renewalWindowDays = map[string][]time.Weekday{
+	"sun":       {time.Sunday},
+	"sunday":    {time.Sunday},
+	"mon":       {time.Monday},
+	"monday":    {time.Monday},
+	"tue":       {time.Tuesday},
+	"tuesday":   {time.Tuesday},
+	"wed":       {time.Wednesday},
+	"wednesday": {time.Wednesday},
+	"thu":       {time.Thursday},
+	"thursday":  {time.Thursday},
+	"fri":       {time.Friday},
+	"friday":    {time.Friday},
+	"sat":       {time.Saturday},
+	"saturday":  {time.Saturday},
+	"weekdays":  {time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	"weekends":  {time.Saturday, time.Sunday},
+}
+
+// parseRenewalWindows parses and validates the configured renewal windows
+func parseRenewalWindows(cfgWindows []RenewalWindow) (windows []renewalWindow, err error) {
+	for i := range cfgWindows {
+		var window renewalWindow
+
+		// days (empty is every day)
+		if len(cfgWindows[i].Days) == 0 {
+			for day := range window.days {
+				window.days[day] = true
+			}
+		}
+		for _, dayName := range cfgWindows[i].Days {
+			days, ok := renewalWindowDays[strings.ToLower(strings.TrimSpace(dayName))]
+			if !ok {
+				return nil, fmt.Errorf("%w (unknown day %s)", errRenewalWindowBad, dayName)
+			}
+			for _, day := range days {
+				window.days[day] = true
+			}
+		}
+
+		// start and end
+		window.start, err = parseTimeOfDay(cfgWindows[i].Start)
+		if err != nil {
+			return nil, fmt.Errorf("%w (start: %s)", errRenewalWindowBad, err)
+		}
+		window.end, err = parseTimeOfDay(cfgWindows[i].End)
+		if err != nil {
+			return nil, fmt.Errorf("%w (end: %s)", errRenewalWindowBad, err)
+		}
+
+		windows = append(windows, window)
+	}
+
+	return windows, nil
+}
+
+// parseTimeOfDay parses HH:MM into the duration since midnight
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// occurrence returns the start and end of the window if it opens on the day of
+// t, ok is false if the window does not open on that day
+func (window renewalWindow) occurrence(t time.Time) (start time.Time, end time.Time, ok bool) {
+	if !window.days[t.Weekday()] {
+		return time.Time{}, time.Time{}, false
+	}
+
+	// use date for each (instead of adding to start) so DST changes are handled
+	year, month, day := t.Date()
+	start = time.Date(year, month, day, 0, 0, 0, 0, t.Location()).Add(window.start)
+	end = time.Date(year, month, day, 0, 0, 0, 0, t.Location()).Add(window.end)
+
+	// crosses midnight (or is the full day)
+	if window.end <= window.start {
+		end = time.Date(year, month, day+1, 0, 0, 0, 0, t.Location()).Add(window.end)
+	}
+
+	return start, end, true
+}
+
+// renewalAllowedAt returns t if t is inside of one of the windows, otherwise it
+// returns the time the next window opens. If there are no windows, ordering is
+// always allowed.
+func renewalAllowedAt(windows []renewalWindow, t time.Time) time.Time {
+	if len(windows) == 0 {
+		return t
+	}
+
+	var next time.Time
+	for _, window := range windows {
+		// yesterday (could still be open) through a week from now
+		for i := -1; i <= 7; i++ {
+			start, end, ok := window.occurrence(t.AddDate(0, 0, i))
+			if !ok {
+				continue
+			}
+
+			// inside window
+			if !t.Before(start) && t.Before(end) {
+				return t
+			}
+
+			if start.After(t) && (next.IsZero() || start.Before(next)) {
+				next = start
+			}
+		}
+	}
+
+	return next
+}
@@ -0,0 +1,62 @@
+package orders
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRenewalWindow_renewalAllowedAt(t *testing.T) {
+	windows, err := parseRenewalWindows([]RenewalWindow{
+		{Days: []string{"weekdays"}, Start: "01:00", End: "05:00"},
+		{Days: []string{"Sat"}, Start: "22:00", End: "02:00"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 2024-01-01 is a Monday
+	at := func(day int, hour int, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		t        time.Time
+		expected time.Time
+	}{
+		{at(1, 2, 30), at(1, 2, 30)},
+		{at(1, 0, 59), at(1, 1, 0)},
+		{at(1, 5, 0), at(2, 1, 0)},
+		// friday after window -> saturday night
+		{at(5, 12, 0), at(6, 22, 0)},
+		// saturday window continues into sunday
+		{at(7, 1, 30), at(7, 1, 30)},
+		{at(7, 2, 0), at(8, 1, 0)},
+	}
+
+	for _, test := range tests {
+		allowed := renewalAllowedAt(windows, test.t)
+		if !allowed.Equal(test.expected) {
+			t.Errorf("allowed at for %s is %s (expected %s)", test.t, allowed, test.expected)
+		}
+	}
+
+	// no windows is always allowed
+	if allowed := renewalAllowedAt(nil, at(1, 12, 0)); !allowed.Equal(at(1, 12, 0)) {
+		t.Errorf("allowed at with no windows is %s (expected %s)", allowed, at(1, 12, 0))
+	}
+}
+
+func TestRenewalWindow_parseRenewalWindows(t *testing.T) {
+	tests := []RenewalWindow{
+		{Days: []string{"someday"}, Start: "01:00", End: "05:00"},
+		{Start: "1am", End: "05:00"},
+		{Start: "01:00", End: "25:00"},
+	}
+
+	for _, test := range tests {
+		_, err := parseRenewalWindows([]RenewalWindow{test})
+		if err == nil {
+			t.Errorf("window %v parsed (expected error)", test)
+		}
+	}
+}
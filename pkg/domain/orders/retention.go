@@ -0,0 +1,154 @@
+package orders
+
+import (
+	"sync"
+	"time"
+)
+
+// RetentionConfig configures the pruning of old orders
+type RetentionConfig struct {
+	Enable            *bool `yaml:"enable"`
+	KeepValidOrders   *int  `yaml:"keep_valid_orders"`
+	InvalidMaxAgeDays *int  `yaml:"invalid_max_age_days"`
+	IntervalHours     *int  `yaml:"interval_hours"`
+}
+
+// retentionPolicy is the parsed retention configuration
+type retentionPolicy struct {
+	keepValidOrders int
+	invalidMaxAge   time.Duration
+}
+
+// PrunableOrder is an order that the retention policy would delete
+type PrunableOrder struct {
+	ID              int
+	CertificateID   int
+	CertificateName string
+	Status          string
+	ValidTo         *int
+	CreatedAt       int
+}
+
+// prunableOrderResponse is the api response for a prunable order
+type prunableOrderResponse struct {
+	ID              int    `json:"id"`
+	CertificateID   int    `json:"certificate_id"`
+	CertificateName string `json:"certificate_name"`
+	Status          string `json:"status"`
+	ValidTo         *int   `json:"valid_to,omitempty"`
+	CreatedAt       int    `json:"created_at"`
+}
+
+func (order PrunableOrder) response() prunableOrderResponse {
+	return prunableOrderResponse{
+		ID:              order.ID,
+		CertificateID:   order.CertificateID,
+		CertificateName: order.CertificateName,
+		Status:          order.Status,
+		ValidTo:         order.ValidTo,
+		CreatedAt:       order.CreatedAt,
+	}
+}
+
+// newRetentionPolicy returns the retention policy for the config. The current valid
+// order of each cert is always kept.
+func (service *Service) newRetentionPolicy(cfg RetentionConfig) retentionPolicy {
+	keepValid := *cfg.KeepValidOrders
+	if keepValid < 1 {
+		service.logger.Warnf("orders retention keep_valid_orders (%d) is invalid, using 1", keepValid)
+		keepValid = 1
+	}
+
+	invalidMaxAgeDays := *cfg.InvalidMaxAgeDays
+	if invalidMaxAgeDays < 0 {
+		service.logger.Warnf("orders retention invalid_max_age_days (%d) is invalid, using 0", invalidMaxAgeDays)
+		invalidMaxAgeDays = 0
+	}
+
+	return retentionPolicy{
+		keepValidOrders: keepValid,
+		invalidMaxAge:   time.Duration(invalidMaxAgeDays) * 24 * time.Hour,
+	}
+}
+
+// prunableOrders returns the orders the retention policy would currently delete
+func (service *Service) prunableOrders() (prunable []PrunableOrder, err error) {
+	invalidCreatedBefore := time.Now().Add(-service.retention.invalidMaxAge).Unix()
+	return service.storage.GetPrunableOrders(service.retention.keepValidOrders, int(invalidCreatedBefore))
+}
+
+// pruneOrders deletes the orders the retention policy doesn't keep and then vacuums
+// storage to reclaim the space
+func (service *Service) pruneOrders() (err error) {
+	prunable, err := service.prunableOrders()
+	if err != nil {
+		return err
+	}
+
+	// nothing to do
+	if len(prunable) == 0 {
+		service.logger.Debug("order retention: no orders to prune")
+		return nil
+	}
+
+	orderIds := []int{}
+	for i := range prunable {
+		orderIds = append(orderIds, prunable[i].ID)
+	}
+
+	err = service.storage.DeleteOrders(orderIds)
+	if err != nil {
+		return err
+	}
+	service.logger.Infof("order retention: pruned %d order(s)", len(orderIds))
+
+	err = service.storage.Vacuum()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// startRetentionService starts a go routine that prunes orders at the configured
+// interval
+func (service *Service) startRetentionService(cfg RetentionConfig, wg *sync.WaitGroup) {
+	// dont run if not enabled
+	if !*cfg.Enable {
+		return
+	}
+
+	interval := time.Duration(*cfg.IntervalHours) * time.Hour
+	if interval <= 0 {
+		service.logger.Warnf("orders retention interval_hours (%d) is invalid, using 24", *cfg.IntervalHours)
+		interval = 24 * time.Hour
+	}
+
+	// log start and update wg
+	service.logger.Infof("starting order retention service; keeping %d valid order(s) per certificate; "+
+		"pruning invalid orders after %d day(s)", service.retention.keepValidOrders, int(service.retention.invalidMaxAge.Hours()/24))
+	wg.Add(1)
+
+	// service routine
+	go func() {
+		defer wg.Done()
+
+		for {
+			err := service.pruneOrders()
+			if err != nil {
+				service.logger.Errorf("order retention: failed to prune orders (%s)", err)
+			}
+
+			// sleep or wait for shutdown context to be done
+			select {
+			case <-service.shutdownContext.Done():
+				// close routine
+				service.logger.Info("order retention service shutdown complete")
+				return
+
+			case <-time.After(interval):
+				// sleep until next run
+			}
+		}
+	}()
+}
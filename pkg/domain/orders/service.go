@@ -4,9 +4,15 @@ import (
 	"context"
 	"errors"
 	"legocerthub-backend/pkg/challenges"
+	"legocerthub-backend/pkg/diagnostics"
 	"legocerthub-backend/pkg/domain/acme_servers"
 	"legocerthub-backend/pkg/domain/authorizations"
 	"legocerthub-backend/pkg/domain/certificates"
+	"legocerthub-backend/pkg/domain/deploy_targets"
+	"legocerthub-backend/pkg/domain/webhooks"
+	"legocerthub-backend/pkg/events"
+	"legocerthub-backend/pkg/export"
+	"legocerthub-backend/pkg/notifications"
 	"legocerthub-backend/pkg/output"
 	"legocerthub-backend/pkg/pagination_sort"
 	"sync"
@@ -26,6 +32,11 @@ type App interface {
 	GetChallengesService() *challenges.Service
 	GetCertificatesService() *certificates.Service
 	GetAuthsService() *authorizations.Service
+	GetEventsService() *events.Service
+	GetNotificationsService() *notifications.Service
+	GetWebhooksService() *webhooks.Service
+	GetDeployTargetsService() *deploy_targets.Service
+	GetExportService() *export.Service
 	GetShutdownContext() context.Context
 	GetShutdownWaitGroup() *sync.WaitGroup
 }
@@ -43,23 +54,50 @@ type Storage interface {
 	PutOrderInvalid(orderId int) (err error)
 	UpdateFinalizedKey(orderId int, keyId int) (err error)
 	UpdateOrderCert(orderId int, CertPayload CertPayload) (err error)
+	PutOrderDiagnostics(orderId int, entries []diagnostics.Entry) (err error)
+	PutOrderDeployResult(orderId int, result DeployResult) (err error)
 	RevokeOrder(orderId int) (err error)
 
 	GetAllValidCurrentOrders(q pagination_sort.Query) (orders []Order, totalRows int, err error)
 	GetAllIncompleteOrderIds() (orderIds []int, err error)
-	GetExpiringCertIds(maxTimeRemaining time.Duration) (certIds []int, err error)
+	GetCertRenewalTimes(defaultRemaining time.Duration) (renewals []CertRenewal, err error)
 	GetNewestIncompleteCertOrderId(certId int) (orderId int, err error)
 
+	GetPrunableOrders(keepValidOrders int, invalidCreatedBefore int) (prunable []PrunableOrder, err error)
+	DeleteOrders(orderIds []int) (err error)
+	Vacuum() (err error)
+
 	// certs
 	UpdateCertUpdatedTime(certId int) (err error)
+
+	// key rotation
+	KeyIssuedCert(certId int, keyId int) (issued bool, err error)
+	PutCertRotatedKey(payload RotateKeyPayload) (newKeyId int, err error)
+	GetRetiredKeyIds(now int) (keyIds []int, err error)
+	DeleteKey(id int) (err error)
+
+	// order jobs
+	GetOrderJobs() (jobs []Job, err error)
+	PostOrderJob(payload NewJobPayload) (id int, err error)
+	ClaimOrderJob(now int) (job Job, err error)
+	PutOrderJobRetry(jobId int, nextRunAt int, lastError string, updatedAt int) (err error)
+	PutOrderJobFailed(jobId int, lastError string, updatedAt int) (err error)
+	PutOrderJobDeferred(jobId int, nextRunAt int, updatedAt int) (err error)
+	ResetRunningOrderJobs() (count int, err error)
+	DeleteOrderJob(jobId int) (err error)
 }
 
 // Configuration options
 type Config struct {
-	AutomaticOrderingEnable     *bool `yaml:"auto_order_enable"`
-	ValidRemainingDaysThreshold *int  `yaml:"valid_remaining_days_threshold"`
-	RefreshTimeHour             *int  `yaml:"refresh_time_hour"`
-	RefreshTimeMinute           *int  `yaml:"refresh_time_minute"`
+	AutomaticOrderingEnable     *bool             `yaml:"auto_order_enable"`
+	ValidRemainingDaysThreshold *int              `yaml:"valid_remaining_days_threshold"`
+	RenewalJitterMinutes        *int              `yaml:"renewal_jitter_minutes"`
+	RenewalWindows              []RenewalWindow   `yaml:"renewal_windows"`
+	NewOrderDelaySeconds        *int              `yaml:"new_order_delay_seconds"`
+	WorkerCount                 *int              `yaml:"worker_count"`
+	Retention                   RetentionConfig   `yaml:"retention"`
+	DeployHooks                 DeployHooksConfig `yaml:"deploy_hooks"`
+	KeyRotation                 KeyRotationConfig `yaml:"key_rotation"`
 }
 
 // Keys service struct
@@ -72,9 +110,16 @@ type Service struct {
 	challenges        *challenges.Service
 	certificates      *certificates.Service
 	authorizations    *authorizations.Service
-	inProcess         *inProcess
-	highJobs          chan orderJob
-	lowJobs           chan orderJob
+	events            *events.Service
+	notifications     *notifications.Service
+	webhooks          *webhooks.Service
+	export            *export.Service
+	deployTargets     *deploy_targets.Service
+	jobsWake          chan struct{}
+	renewalReplan     chan struct{}
+	retention         retentionPolicy
+	deployHooks       deployHooksPolicy
+	keyGracePeriod    time.Duration
 }
 
 // NewService creates a new private_key service
@@ -126,22 +171,84 @@ func NewService(app App, cfg *Config) (*Service, error) {
 		return nil, errServiceComponent
 	}
 
-	// initialize inProcess (tracker)
-	service.inProcess = newInProcess()
+	// order progress events
+	service.events = app.GetEventsService()
+	if service.events == nil {
+		return nil, errServiceComponent
+	}
+
+	// notifications
+	service.notifications = app.GetNotificationsService()
+	if service.notifications == nil {
+		return nil, errServiceComponent
+	}
+
+	// webhooks
+	service.webhooks = app.GetWebhooksService()
+	if service.webhooks == nil {
+		return nil, errServiceComponent
+	}
+
+	// certificate export
+	service.export = app.GetExportService()
+	if service.export == nil {
+		return nil, errServiceComponent
+	}
+
+	// deploy targets
+	service.deployTargets = app.GetDeployTargetsService()
+	if service.deployTargets == nil {
+		return nil, errServiceComponent
+	}
+
+	// jobs that were running when LeGo stopped are resumed
+	resumed, err := service.storage.ResetRunningOrderJobs()
+	if err != nil {
+		service.logger.Errorf("failed to reset interrupted order jobs (%s)", err)
+		return nil, err
+	}
+	if resumed > 0 {
+		service.logger.Infof("resuming %d interrupted order job(s)", resumed)
+	}
+
+	// deploy hooks
+	service.deployHooks = service.newDeployHooksPolicy(cfg.DeployHooks)
+
+	// key rotation (start service to delete retired keys)
+	service.keyGracePeriod = service.newKeyGracePeriod(cfg.KeyRotation)
+	service.startKeyRetireService(app.GetShutdownWaitGroup())
 
 	// workers
-	// make job channels for order workers
-	service.highJobs = make(chan orderJob)
-	service.lowJobs = make(chan orderJob)
-	workerCount := 3
+	// wake signal for idle workers
+	service.jobsWake = make(chan struct{}, 1)
+	workerCount := *cfg.WorkerCount
+	if workerCount < 1 {
+		service.logger.Warnf("orders worker_count (%d) is invalid, using 1", workerCount)
+		workerCount = 1
+	}
 
 	// make workers
 	for i := 0; i < workerCount; i++ {
-		go service.makeOrderWorker(i, service.highJobs, service.lowJobs, app.GetShutdownWaitGroup())
+		go service.makeOrderWorker(i, app.GetShutdownWaitGroup())
+	}
+
+	// automatic ordering
+	renewalWindows, err := parseRenewalWindows(cfg.RenewalWindows)
+	if err != nil {
+		service.logger.Errorf("failed to configure orders renewal windows (%s)", err)
+		return nil, err
 	}
 
+	// replan renewals when certificates change
+	service.renewalReplan = make(chan struct{}, 1)
+	service.certificates.OnChange(service.replanRenewals)
+
 	// start service to automatically place and complete orders
-	service.startAutoOrderService(cfg, service.shutdownContext, app.GetShutdownWaitGroup())
+	service.startAutoOrderService(cfg, renewalWindows, app.GetShutdownWaitGroup())
+
+	// start service to prune old orders
+	service.retention = service.newRetentionPolicy(cfg.Retention)
+	service.startRetentionService(cfg.Retention, app.GetShutdownWaitGroup())
 
 	return service, nil
 }
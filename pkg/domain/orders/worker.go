@@ -2,82 +2,186 @@ package orders
 
 import (
 	"errors"
+	"fmt"
 	"legocerthub-backend/pkg/acme"
+	"legocerthub-backend/pkg/diagnostics"
+	"legocerthub-backend/pkg/events"
+	"legocerthub-backend/pkg/storage"
 	"net/http"
 	"sync"
 	"time"
 )
 
-// orderJob contains the info the worker needs to do a job
-type orderJob struct {
-	orderId int
-}
+// jobBusyDelay is how long a job waits before being claimed again if its ACME
+// server is already processing its maximum number of orders
+const jobBusyDelay = 30 * time.Second
+
+// jobPollInterval is how often idle workers check for jobs that are due (e.g. a
+// retry) without being woken
+const jobPollInterval = 15 * time.Second
+
+// failed jobs are retried (with increasing delay) until they have been attempted
+// jobMaxAttempts times
+const (
+	jobMaxAttempts  = 3
+	jobRetryBackoff = 5 * time.Minute
+)
+
+// errOrderNotFinal is returned when an order is still not in a final state (valid or
+// invalid) after the job's last refresh of it (e.g. it is still processing)
+var errOrderNotFinal = errors.New("order did not reach a final state")
 
-// makeOrderWorker creates a indefinite thread to process incoming orderJobs
-func (service *Service) makeOrderWorker(id int, highPriorityJobs <-chan orderJob, lowPriorityJobs <-chan orderJob, wg *sync.WaitGroup) {
+// makeOrderWorker creates a indefinite thread that claims and processes order jobs
+// from storage
+func (service *Service) makeOrderWorker(id int, wg *sync.WaitGroup) {
 	service.logger.Debugf("starting order worker (%d)", id)
 	// acme directory refresh service shutdown complete
 	wg.Add(1)
 	defer wg.Done()
 
-doingWork:
 	for {
+		// claim the next job that is due (highest priority first)
+		job, err := service.storage.ClaimOrderJob(int(time.Now().Unix()))
+		if err == nil {
+			// another job may be ready too, let another idle worker check
+			service.wakeWorkers()
+
+			service.runOrderJob(job)
+			service.logger.Debugf("worker %d: end of order job %d (orderId: %d)", id, job.ID, job.OrderID)
+
+			continue
+		} else if !errors.Is(err, storage.ErrNoRecord) {
+			service.logger.Errorf("worker %d: failed to claim order job (%s)", id, err)
+		}
+
+		// no job, wait
 		select {
 		case <-service.shutdownContext.Done():
-			// break to shutdown
-			break doingWork
-		case highJob := <-highPriorityJobs:
-			service.doOrderJob(highJob)
-			service.logger.Debugf("worker %d: end of high priority order fulfiller (orderId: %d)", id, highJob.orderId)
-		case lowJob := <-lowPriorityJobs:
-		lower:
-			for {
-				select {
-				case <-service.shutdownContext.Done():
-					// break to shutdown
-					break doingWork
-				case highJob := <-highPriorityJobs:
-					service.doOrderJob(highJob)
-					service.logger.Debugf("worker %d: end of high priority order fulfiller (orderId: %d)", id, highJob.orderId)
-				default:
-					break lower
-				}
-			}
-			service.doOrderJob(lowJob)
-			service.logger.Debugf("worker %d: end of low priority order fulfiller (orderId: %d)", id, lowJob.orderId)
+			service.logger.Debugf("order worker (%d) shutdown complete", id)
+			return
+		case <-service.jobsWake:
+		case <-time.After(jobPollInterval):
 		}
 	}
-
-	service.logger.Debugf("order worker (%d) shutdown complete", id)
 }
 
-// doOrderJob works the orderJob specified. Once complete, the job is removed from the inProcess
-// list to indicate completion. No results are returned as results are saved directly to storage
-// as part of doing the job.
-func (service *Service) doOrderJob(job orderJob) {
-	// remove id from inProcess when goroutine is done
-	defer func(orderId int, service *Service) {
-		err := service.inProcess.remove(orderId)
+// runOrderJob does the order job and then updates the job in storage. A successful
+// job is removed. A failed job is retried later, unless it is out of attempts. A
+// job interrupted by shutdown is left running and is resumed on the next start.
+func (service *Service) runOrderJob(job Job) {
+	jobErr := service.doOrderJob(job.OrderID)
+
+	// the cert's renewal time may have changed (or it needs a retry)
+	defer service.replanRenewals()
+
+	// ACME server busy, requeue without counting the attempt
+	if errors.Is(jobErr, acme.ErrOrderLimitReached) {
+		service.logger.Debugf("order job %d (orderId: %d) deferred (%s)", job.ID, job.OrderID, jobErr)
+
+		now := time.Now()
+		err := service.storage.PutOrderJobDeferred(job.ID, int(now.Add(jobBusyDelay).Unix()), int(now.Unix()))
 		if err != nil {
 			service.logger.Error(err)
 		}
-	}(job.orderId, service)
+		return
+	}
+
+	// done
+	if jobErr == nil {
+		err := service.storage.DeleteOrderJob(job.ID)
+		if err != nil {
+			service.logger.Error(err)
+		}
+		return
+	}
 
+	// shutdown
+	if service.shutdownContext.Err() != nil {
+		return
+	}
+
+	// failed, retry or give up
+	now := time.Now()
+	if job.Attempts < jobMaxAttempts {
+		nextRun := now.Add(time.Duration(job.Attempts) * jobRetryBackoff)
+		service.logger.Infof("order job %d (orderId: %d) failed, will retry after %s (%s)", job.ID, job.OrderID, nextRun.Format(time.RFC3339), jobErr)
+
+		err := service.storage.PutOrderJobRetry(job.ID, int(nextRun.Unix()), jobErr.Error(), int(now.Unix()))
+		if err != nil {
+			service.logger.Error(err)
+		}
+		return
+	}
+
+	service.logger.Errorf("order job %d (orderId: %d) failed after %d attempts (%s)", job.ID, job.OrderID, job.Attempts, jobErr)
+	service.notifications.OrderFailed(job.OrderID, jobErr.Error())
+
+	// include the acme error (if that was the cause)
+	var acmeErr *acme.Error
+	var jobAcmeErr acme.Error
+	if errors.As(jobErr, &jobAcmeErr) {
+		acmeErr = &jobAcmeErr
+	}
+	service.webhooks.OrderFailed(job.OrderID, jobErr.Error(), acmeErr)
+
+	err := service.storage.PutOrderJobFailed(job.ID, jobErr.Error(), int(now.Unix()))
+	if err != nil {
+		service.logger.Error(err)
+	}
+}
+
+// doOrderJob works the order specified. No results are returned as results are saved
+// directly to storage as part of doing the job. An error is returned if the order
+// could not be moved to a final state.
+func (service *Service) doOrderJob(orderId int) (err error) {
 	// fetch the relevant order
-	orderDb, err := service.storage.GetOneOrder(job.orderId)
+	orderDb, err := service.storage.GetOneOrder(orderId)
+	if err != nil {
+		service.logger.Error(err)
+		return // done, failed
+	}
+
+	// acmeService to avoid repeated logic
+	acmeService, err := service.acmeServerService.AcmeService(orderDb.Certificate.CertificateAccount.AcmeServer.ID)
 	if err != nil {
 		service.logger.Error(err)
 		return // done, failed
 	}
 
+	// reserve one of the ACME server's order slots (if it is at its limit, the
+	// job is deferred so other servers' orders aren't held up)
+	releaseSlot, err := acmeService.AcquireOrderSlot()
+	if err != nil {
+		return err
+	}
+	defer releaseSlot()
+
 	// update certificate timestamp after fulfiller is done
 	defer func(certId int) {
-		err = service.storage.UpdateCertUpdatedTime(certId)
+		err := service.storage.UpdateCertUpdatedTime(certId)
 		if err != nil {
 			service.logger.Error(err)
 		}
 	}(orderDb.Certificate.ID)
 
+	// progress events for clients following the order
+	emitter := service.events.Emitter(orderDb.Certificate.ID, orderId)
+
+	// collect diagnostics for this job and save them to the order when done
+	diag := diagnostics.NewLog()
+	defer func() {
+		// record any error that ended the job
+		if err != nil {
+			diag.Add("order", err.Error())
+			emitter.Emitf(events.TypeOrderFailed, "order failed (%s)", err)
+		}
+
+		err := service.storage.PutOrderDiagnostics(orderId, diag.Entries())
+		if err != nil {
+			service.logger.Error(err)
+		}
+	}()
+
 	// get account key
 	key, err := orderDb.Certificate.CertificateAccount.AcmeAccountKey()
 	if err != nil {
@@ -85,22 +189,10 @@ func (service *Service) doOrderJob(job orderJob) {
 		return // done, failed
 	}
 
-	// make cert CSR
-	csr, err := orderDb.Certificate.MakeCsrDer()
-	if err != nil {
-		service.logger.Error(err)
-		return // done, failed
-	}
-
 	// acmeOrder to hold the Order responses and to later update storage
 	var acmeOrder acme.Order
-
-	// acmeService to avoid repeated logic
-	acmeService, err := service.acmeServerService.AcmeService(orderDb.Certificate.CertificateAccount.AcmeServer.ID)
-	if err != nil {
-		service.logger.Error(err)
-		return // done, failed
-	}
+	// final is set once the order is valid (and downloaded) or invalid
+	final := false
 
 	// Use loop to retry order. Cap retries to avoid indefinite loop.
 	maxTries := 5
@@ -115,7 +207,7 @@ fulfillLoop:
 			// actions before the "expires" time, then the server SHOULD change the
 			// status of the order to "invalid" and MAY delete the order resource.")
 			if acmeErr, ok := err.(acme.Error); ok && acmeErr.Status == http.StatusNotFound {
-				service.storage.PutOrderInvalid(job.orderId)
+				service.storage.PutOrderInvalid(orderId)
 			}
 			service.logger.Error(err)
 			return // done, failed
@@ -125,7 +217,7 @@ fulfillLoop:
 		switch acmeOrder.Status {
 		case "pending": // needs to be authed
 			var authStatus string
-			authStatus, err = service.authorizations.FulfillAuths(acmeOrder.Authorizations, orderDb.Certificate.ChallengeMethod, key, orderDb.Certificate.CertificateAccount.AcmeServer.ID)
+			authStatus, err = service.authorizations.FulfillAuths(acmeOrder.Authorizations, orderDb.Certificate.ChallengeMethod, orderDb.Certificate.Http01SelfCheck, key, orderDb.Certificate.CertificateAccount.AcmeServer.ID, diag, emitter)
 			if err != nil {
 				service.logger.Error(err)
 				return // done, failed
@@ -140,19 +232,38 @@ fulfillLoop:
 			fallthrough
 
 		case "ready": // needs to be finalized
-			// save finalized_key_id in storage
-			err = service.storage.UpdateFinalizedKey(orderDb.ID, orderDb.Certificate.CertificateKey.ID)
+			// generate a new key for the cert (if it rotates its key)
+			err = service.rotateKey(&orderDb, diag)
+			if err != nil {
+				service.logger.Error(err)
+				return // done, failed
+			}
+
+			// make cert CSR (or use the uploaded CSR)
+			var csr []byte
+			csr, err = orderDb.Certificate.MakeCsrDer()
 			if err != nil {
 				service.logger.Error(err)
 				return // done, failed
 			}
 
+			// save finalized_key_id in storage (certs defined by a csr have no key)
+			if !orderDb.Certificate.HasCsr() {
+				err = service.storage.UpdateFinalizedKey(orderDb.ID, orderDb.Certificate.CertificateKey.ID)
+				if err != nil {
+					service.logger.Error(err)
+					return // done, failed
+				}
+				orderDb.FinalizedKey = &orderDb.Certificate.CertificateKey
+			}
+
 			// finalize the order
 			acmeOrder, err = acmeService.FinalizeOrder(acmeOrder.Finalize, csr, key)
 			if err != nil {
 				service.logger.Error(err)
 				return // done, failed
 			}
+			emitter.Emit(events.TypeOrderFinalized, "order finalized")
 
 			// should now be valid, if not, probably processing
 			if acmeOrder.Status != "valid" {
@@ -166,7 +277,8 @@ fulfillLoop:
 			// download cert pem
 			// nil check (make sure there is a cert URL)
 			if acmeOrder.Certificate != nil {
-				certPemChain, err := acmeService.DownloadCertificate(*acmeOrder.Certificate, key)
+				var certPemChain string
+				certPemChain, err = acmeService.DownloadCertificate(*acmeOrder.Certificate, key)
 				if err != nil {
 					service.logger.Error(err)
 					return // done, failed
@@ -178,7 +290,21 @@ fulfillLoop:
 					service.logger.Error(err)
 					return
 				}
+				emitter.Emit(events.TypeOrderIssued, "certificate issued")
+
+				// deploy the key that finalized the order (the cert's key may have
+				// changed since, e.g. if the order was resumed)
+				if orderDb.FinalizedKey != nil {
+					orderDb.Certificate.CertificateKey = *orderDb.FinalizedKey
+				}
+				service.notifications.OrderIssued(orderId)
+				service.webhooks.CertificateIssued(orderId)
+				service.export.CertificateChanged(orderDb.Certificate.ID)
 
+				// run the cert's deploy hooks and push to its deploy targets
+				service.runDeploy(orderDb, certPemChain)
+
+				final = true
 				break fulfillLoop
 			}
 
@@ -191,7 +317,8 @@ fulfillLoop:
 				select {
 				case <-service.shutdownContext.Done():
 					// abort refreshing due to shutdown
-					service.logger.Error("order job canceled due to shutdown")
+					err = errors.New("order job canceled due to shutdown")
+					service.logger.Error(err)
 					return
 
 				case <-time.After(time.Duration(i) * 30 * time.Second):
@@ -201,19 +328,37 @@ fulfillLoop:
 
 		case "invalid": // break, irrecoverable
 			service.logger.Debugf("order status invalid; acme error: %s", acmeOrder.Error)
+			if acmeOrder.Error != nil {
+				diag.Addf("acme", "order invalid (%s)", acmeOrder.Error)
+			}
+			emitter.Emit(events.TypeOrderFailed, "order is invalid")
+			service.notifications.OrderFailed(orderId, "order is invalid")
+			service.webhooks.OrderFailed(orderId, "order is invalid", acmeOrder.Error)
+			final = true
 			break fulfillLoop
 
 		// Note: there is no 'expired' Status case. If the order expires it simply moves to 'invalid'.
 
 		default:
-			service.logger.Error(errors.New("order status unknown"))
+			err = errors.New("order status unknown")
+			service.logger.Error(err)
 			return // done, failed
 		}
 	}
 
 	// update order in storage
-	err = service.storage.PutOrderAcme(makeUpdateOrderAcmePayload(job.orderId, acmeOrder))
+	err = service.storage.PutOrderAcme(makeUpdateOrderAcmePayload(orderId, acmeOrder))
 	if err != nil {
 		service.logger.Error(err)
+		return err
 	}
+
+	// out of tries (e.g. still processing), fail so the job is retried
+	if !final {
+		err = fmt.Errorf("%w after %d tries (status: %s)", errOrderNotFinal, maxTries, acmeOrder.Status)
+		service.logger.Error(err)
+		return err
+	}
+
+	return nil
 }
@@ -2,6 +2,7 @@ package private_keys
 
 import (
 	"errors"
+	"legocerthub-backend/pkg/domain/webhooks"
 	"legocerthub-backend/pkg/output"
 	"net/http"
 	"strconv"
@@ -100,6 +101,8 @@ func (service *Service) RemoveOldApiKey(w http.ResponseWriter, r *http.Request)
 		return output.ErrStorageGeneric
 	}
 
+	service.webhooks.ApiKeyRotated(webhooks.ResourcePrivateKey, keyId, key.Name)
+
 	// return response to client
 	response := output.JsonResponse{
 		Status:  http.StatusOK,
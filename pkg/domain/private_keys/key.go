@@ -16,6 +16,7 @@ type Key struct {
 	ApiKeyNew      string
 	ApiKeyDisabled bool
 	ApiKeyViaUrl   bool
+	RetireAt       *int
 	CreatedAt      int
 	UpdatedAt      int
 }
@@ -48,6 +49,7 @@ type keyDetailedResponse struct {
 	KeySummaryResponse
 	ApiKey    string `json:"api_key"`
 	ApiKeyNew string `json:"api_key_new,omitempty"`
+	RetireAt  *int   `json:"retire_at,omitempty"`
 	CreatedAt int    `json:"created_at"`
 	UpdatedAt int    `json:"updated_at"`
 	// exclude PEM
@@ -70,6 +72,7 @@ func (key Key) detailedResponse(withSensitive bool) keyDetailedResponse {
 
 		ApiKey:    apiKey,
 		ApiKeyNew: apiKeyNew,
+		RetireAt:  key.RetireAt,
 		CreatedAt: key.CreatedAt,
 		UpdatedAt: key.UpdatedAt,
 	}
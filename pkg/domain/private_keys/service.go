@@ -2,6 +2,7 @@ package private_keys
 
 import (
 	"errors"
+	"legocerthub-backend/pkg/domain/webhooks"
 	"legocerthub-backend/pkg/output"
 	"legocerthub-backend/pkg/pagination_sort"
 
@@ -17,6 +18,7 @@ type App interface {
 	IsHttps() bool
 	GetOutputter() *output.Service
 	GetKeyStorage() Storage
+	GetWebhooksService() *webhooks.Service
 }
 
 // Storage interface for storage functions
@@ -40,11 +42,12 @@ type Storage interface {
 
 // Keys service struct
 type Service struct {
-	devMode bool
-	logger  *zap.SugaredLogger
-	https   bool
-	output  *output.Service
-	storage Storage
+	devMode  bool
+	logger   *zap.SugaredLogger
+	https    bool
+	output   *output.Service
+	storage  Storage
+	webhooks *webhooks.Service
 }
 
 // NewService creates a new private_key service
@@ -75,5 +78,11 @@ func NewService(app App) (*Service, error) {
 		return nil, errServiceComponent
 	}
 
+	// webhooks
+	service.webhooks = app.GetWebhooksService()
+	if service.webhooks == nil {
+		return nil, errServiceComponent
+	}
+
 	return service, nil
 }
@@ -73,15 +73,16 @@ func (service *Service) NameValid(keyName string, keyId *int) bool {
 }
 
 // GetAvailableKeys returns a list of all available keys; storage should
-// return keys that exist but are not already in use by an account or a
-// certificate
+// return keys that exist but are not already in use by an account, a
+// certificate, or a deploy target
 // TODO: Maybe move business logic here instead of in storage
 func (service *Service) AvailableKeys() (keys []Key, err error) {
 	return service.storage.GetAvailableKeys()
 }
 
 // KeyAvailable returns true if the specified keyId is available for
-// use (i.e. not already in use by an account or a certificate)
+// use (i.e. not already in use by an account, a certificate, an unmanaged
+// certificate, or a deploy target)
 func (service *Service) KeyAvailable(keyId int) bool {
 	// get available keys list
 	keys, err := service.AvailableKeys()
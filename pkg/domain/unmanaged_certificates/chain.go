@@ -0,0 +1,119 @@
+package unmanaged_certificates
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"legocerthub-backend/pkg/certinfo"
+	"legocerthub-backend/pkg/domain/private_keys/key_crypto"
+	"strings"
+)
+
+// chainDetails is the information parsed from an imported pem chain
+type chainDetails struct {
+	pem             string
+	subject         string
+	subjectAltNames []string
+	issuer          string
+	serial          string
+	validFrom       int
+	validTo         int
+	publicKey       crypto.PublicKey
+}
+
+// parseChain decodes and validates an imported pem chain (leaf first, followed by
+// any intermediates) and returns the details of the leaf. The subject is the
+// leaf's common name (or its first dns name if there is no common name) and the
+// subject alts are the rest of its dns names and ip addresses.
+func parseChain(pemChain string) (chainDetails, error) {
+	var certs []*x509.Certificate
+	rest := []byte(pemChain)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return chainDetails{}, ErrPemBad
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return chainDetails{}, ErrPemBad
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 || strings.TrimSpace(string(rest)) != "" {
+		return chainDetails{}, ErrPemBad
+	}
+	leaf := certs[0]
+
+	// names
+	names := append([]string{}, leaf.DNSNames...)
+	for _, ip := range leaf.IPAddresses {
+		names = append(names, ip.String())
+	}
+
+	subject := leaf.Subject.CommonName
+	if subject == "" {
+		if len(names) == 0 {
+			return chainDetails{}, ErrPemBad
+		}
+		subject = names[0]
+	}
+
+	alts := []string{}
+	seen := map[string]bool{strings.ToLower(subject): true}
+	for _, name := range names {
+		if seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		alts = append(alts, name)
+	}
+
+	// issuer (common name if there is one)
+	issuer := leaf.Issuer.CommonName
+	if issuer == "" {
+		issuer = leaf.Issuer.String()
+	}
+
+	// standardize pem
+	standardPem := ""
+	for _, cert := range certs {
+		standardPem += string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	}
+
+	return chainDetails{
+		pem:             standardPem,
+		subject:         subject,
+		subjectAltNames: alts,
+		issuer:          issuer,
+		serial:          certinfo.SerialString(leaf),
+		validFrom:       int(leaf.NotBefore.Unix()),
+		validTo:         int(leaf.NotAfter.Unix()),
+		publicKey:       leaf.PublicKey,
+	}, nil
+}
+
+// keyMatches returns true if the private key pem is the key for the public key
+func keyMatches(keyPem string, publicKey crypto.PublicKey) bool {
+	_, alg, err := key_crypto.ValidateAndStandardizeKeyPem(keyPem)
+	if err != nil {
+		return false
+	}
+
+	privateKey, err := key_crypto.PemStringToKey(keyPem, alg)
+	if err != nil {
+		return false
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return false
+	}
+
+	public, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool })
+	return ok && public.Equal(publicKey)
+}
@@ -0,0 +1,68 @@
+package unmanaged_certificates
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"legocerthub-backend/pkg/internal/certtest"
+	"math/big"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseChain(t *testing.T) {
+	cert := certtest.Issue(t, x509.Certificate{
+		SerialNumber: big.NewInt(0xabc123),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com", "www.example.com", "WWW.example.com"},
+		IPAddresses:  []net.IP{net.ParseIP("10.0.0.1")},
+		NotBefore:    time.Unix(1700000000, 0),
+		NotAfter:     time.Unix(1800000000, 0),
+	})
+
+	chain, err := parseChain("\n" + cert.ChainPem + "\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chain.subject != "example.com" ||
+		!reflect.DeepEqual(chain.subjectAltNames, []string{"www.example.com", "10.0.0.1"}) {
+		t.Errorf("names are %s %v", chain.subject, chain.subjectAltNames)
+	}
+	if chain.issuer != certtest.IssuerName || chain.serial != "abc123" {
+		t.Errorf("issuer is %s, serial is %s", chain.issuer, chain.serial)
+	}
+	if chain.validFrom != 1700000000 || chain.validTo != 1800000000 {
+		t.Errorf("validity is %d to %d", chain.validFrom, chain.validTo)
+	}
+	if chain.pem != cert.ChainPem {
+		t.Errorf("pem was not standardized (%q)", chain.pem)
+	}
+
+	// key
+	if !keyMatches(cert.KeyPem, chain.publicKey) {
+		t.Error("leaf key does not match")
+	}
+	other := certtest.Issue(t, x509.Certificate{SerialNumber: big.NewInt(2), DNSNames: []string{"example.com"}})
+	if keyMatches(other.KeyPem, chain.publicKey) {
+		t.Error("other key matches")
+	}
+}
+
+func TestParseChain_Invalid(t *testing.T) {
+	cert := certtest.Issue(t, x509.Certificate{SerialNumber: big.NewInt(1), DNSNames: []string{"example.com"}})
+	noNames := certtest.Issue(t, x509.Certificate{SerialNumber: big.NewInt(1)})
+
+	for name, pemChain := range map[string]string{
+		"empty":         "",
+		"not a cert":    "-----BEGIN CERTIFICATE-----\nAAAA\n-----END CERTIFICATE-----\n",
+		"includes key":  cert.ChainPem + cert.KeyPem,
+		"trailing junk": cert.ChainPem + "junk",
+		"no names":      noNames.ChainPem,
+	} {
+		_, err := parseChain(pemChain)
+		if err != ErrPemBad {
+			t.Errorf("%s: err is %v (expected ErrPemBad)", name, err)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package unmanaged_certificates
+
+import (
+	"legocerthub-backend/pkg/output"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// DeleteCert deletes an unmanaged cert from storage (its key, if any, is kept)
+func (service *Service) DeleteCert(w http.ResponseWriter, r *http.Request) (err error) {
+	// get id from param
+	idParam := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// verify cert exists
+	_, err = service.GetCertificate(id)
+	if err != nil {
+		return err
+	}
+
+	// delete from storage
+	err = service.storage.DeleteUnmanagedCert(id)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// return response to client
+	response := output.JsonResponse{
+		Status:  http.StatusOK,
+		Message: "deleted",
+		ID:      id,
+	}
+
+	_, err = service.output.WriteJSON(w, response.Status, response, "response")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
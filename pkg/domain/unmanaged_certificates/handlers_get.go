@@ -0,0 +1,76 @@
+package unmanaged_certificates
+
+import (
+	"legocerthub-backend/pkg/output"
+	"legocerthub-backend/pkg/pagination_sort"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// allCertsResponse provides the json response struct
+// to answer a query for a portion of the unmanaged certs
+type allCertsResponse struct {
+	Certificates      []certificateSummaryResponse `json:"unmanaged_certificates"`
+	TotalCertificates int                          `json:"total_records"`
+}
+
+// GetAllCerts fetches all unmanaged certs from storage and outputs them as JSON
+func (service *Service) GetAllCerts(w http.ResponseWriter, r *http.Request) (err error) {
+	// parse pagination and sorting
+	query := pagination_sort.ParseRequestToQuery(r)
+
+	// get certs from storage
+	certs, totalRows, err := service.storage.GetAllUnmanagedCerts(query)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// make response (for json output)
+	response := allCertsResponse{
+		Certificates:      []certificateSummaryResponse{},
+		TotalCertificates: totalRows,
+	}
+
+	// populate cert summaries for output
+	for i := range certs {
+		response.Certificates = append(response.Certificates, certs[i].summaryResponse())
+	}
+
+	// return response to client
+	_, err = service.output.WriteJSON(w, http.StatusOK, response, "all_unmanaged_certificates")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
+
+// GetOneCert returns a single unmanaged cert as JSON
+func (service *Service) GetOneCert(w http.ResponseWriter, r *http.Request) (err error) {
+	// get id from param
+	idParam := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// get from storage
+	cert, err := service.GetCertificate(id)
+	if err != nil {
+		return err
+	}
+
+	// return response to client
+	_, err = service.output.WriteJSON(w, http.StatusOK, cert.detailedResponse(service.https || service.devMode), "unmanaged_certificate")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package unmanaged_certificates
+
+import (
+	"encoding/json"
+	"legocerthub-backend/pkg/domain/private_keys"
+	"legocerthub-backend/pkg/domain/private_keys/key_crypto"
+	"legocerthub-backend/pkg/output"
+	"legocerthub-backend/pkg/randomness"
+	"net/http"
+	"time"
+)
+
+// NewPayload is the struct for importing a new unmanaged certificate. The key
+// is optional, either an existing (available) key or a key pem to import as a
+// new key (named the same as the certificate).
+type NewPayload struct {
+	Name               *string  `json:"name"`
+	Description        *string  `json:"description"`
+	Pem                *string  `json:"pem"`
+	PrivateKeyID       *int     `json:"private_key_id"`
+	KeyPem             *string  `json:"key_pem"`
+	NotificationEmails []string `json:"notification_emails"`
+	Subject            string   `json:"-"`
+	SubjectAltNames    []string `json:"-"`
+	Issuer             string   `json:"-"`
+	Serial             string   `json:"-"`
+	ValidFrom          int      `json:"-"`
+	ValidTo            int      `json:"-"`
+	ApiKey             string   `json:"-"`
+	ApiKeyViaUrl       bool     `json:"-"`
+	CreatedAt          int      `json:"-"`
+	UpdatedAt          int      `json:"-"`
+}
+
+// PostNewCert imports a new unmanaged certificate and saves it to storage
+func (service *Service) PostNewCert(w http.ResponseWriter, r *http.Request) (err error) {
+	var payload NewPayload
+
+	// decode body into payload
+	err = json.NewDecoder(r.Body).Decode(&payload)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// validation
+	// name
+	if payload.Name == nil || !service.nameValid(*payload.Name, nil) {
+		service.logger.Debug(ErrNameBad)
+		return output.ErrValidationFailed
+	}
+	// description (if none, set to blank)
+	if payload.Description == nil {
+		payload.Description = new(string)
+	}
+	// pem
+	if payload.Pem == nil {
+		service.logger.Debug(ErrPemBad)
+		return output.ErrValidationFailed
+	}
+	chain, err := parseChain(*payload.Pem)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+	// private key (optional)
+	if payload.PrivateKeyID != nil && payload.KeyPem != nil {
+		service.logger.Debug(ErrKeyAndKeyPem)
+		return output.ErrValidationFailed
+	}
+	if payload.PrivateKeyID != nil && !service.privateKeyValid(*payload.PrivateKeyID, chain, nil) {
+		service.logger.Debug(ErrKeyBad)
+		return output.ErrValidationFailed
+	}
+	// key pem (optional, saved as a new key)
+	keyAlgorithmValue := ""
+	if payload.KeyPem != nil {
+		var alg key_crypto.Algorithm
+		*payload.KeyPem, alg, err = key_crypto.ValidateAndStandardizeKeyPem(*payload.KeyPem)
+		if err != nil || !keyMatches(*payload.KeyPem, chain.publicKey) {
+			service.logger.Debug(ErrKeyBad)
+			return output.ErrValidationFailed
+		}
+		keyAlgorithmValue = alg.StorageValue()
+		// confirm name is valid for a new key
+		if !service.keys.NameValid(*payload.Name, nil) {
+			service.logger.Debug(ErrKeyNameBad)
+			return output.ErrValidationFailed
+		}
+	}
+	// notification emails (optional)
+	if !notificationEmailsValid(payload.NotificationEmails) {
+		service.logger.Debug(ErrNotificationEmailsBad)
+		return output.ErrValidationFailed
+	}
+	// end validation
+
+	now := int(time.Now().Unix())
+
+	// if key pem was imported, it is saved to storage as a new key (along with
+	// the cert)
+	var newKeyPayload *private_keys.NewPayload
+	if payload.KeyPem != nil {
+		newKeyPayload = &private_keys.NewPayload{
+			Name:           payload.Name,
+			Description:    new(string),
+			AlgorithmValue: &keyAlgorithmValue,
+			PemContent:     payload.KeyPem,
+			ApiKeyDisabled: new(bool),
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		*newKeyPayload.Description = "imported with unmanaged certificate " + *payload.Name
+		newKeyPayload.ApiKey, err = randomness.GenerateApiKey()
+		if err != nil {
+			service.logger.Error(err)
+			return output.ErrInternal
+		}
+	}
+
+	// add additional details to the payload before saving
+	payload.Pem = &chain.pem
+	payload.Subject = chain.subject
+	payload.SubjectAltNames = chain.subjectAltNames
+	payload.Issuer = chain.issuer
+	payload.Serial = chain.serial
+	payload.ValidFrom = chain.validFrom
+	payload.ValidTo = chain.validTo
+	payload.ApiKey, err = randomness.GenerateApiKey()
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrInternal
+	}
+	payload.ApiKeyViaUrl = false
+	payload.CreatedAt = now
+	payload.UpdatedAt = now
+
+	// save to storage (and the new key, if any)
+	id, err := service.storage.PostNewUnmanagedCert(payload, newKeyPayload)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// return response to client
+	response := output.JsonResponse{
+		Status:  http.StatusCreated,
+		Message: "created",
+		ID:      id,
+	}
+
+	_, err = service.output.WriteJSON(w, response.Status, response, "response")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package unmanaged_certificates
+
+import (
+	"encoding/json"
+	"legocerthub-backend/pkg/output"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// UpdatePayload is the struct for editing an existing unmanaged cert. Only fields
+// received in the payload (non-nil) are updated. A new pem replaces the chain
+// (e.g. after the cert was renewed elsewhere) and the details parsed from it.
+type UpdatePayload struct {
+	ID                 int      `json:"-"`
+	Name               *string  `json:"name"`
+	Description        *string  `json:"description"`
+	Pem                *string  `json:"pem"`
+	PrivateKeyID       *int     `json:"private_key_id"`
+	NotificationEmails []string `json:"notification_emails"`
+	ApiKey             *string  `json:"api_key"`
+	ApiKeyNew          *string  `json:"api_key_new"`
+	ApiKeyViaUrl       *bool    `json:"api_key_via_url"`
+	Subject            *string  `json:"-"`
+	SubjectAltNames    []string `json:"-"`
+	Issuer             *string  `json:"-"`
+	Serial             *string  `json:"-"`
+	ValidFrom          *int     `json:"-"`
+	ValidTo            *int     `json:"-"`
+	UpdatedAt          int      `json:"-"`
+}
+
+// PutCertUpdate updates an unmanaged cert that already exists in storage
+func (service *Service) PutCertUpdate(w http.ResponseWriter, r *http.Request) (err error) {
+	// parse payload
+	var payload UpdatePayload
+	err = json.NewDecoder(r.Body).Decode(&payload)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// get id from param
+	idParam := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	payload.ID, err = strconv.Atoi(idParam)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// validation
+	// id
+	cert, err := service.GetCertificate(payload.ID)
+	if err != nil {
+		return err
+	}
+	// name (optional)
+	if payload.Name != nil && !service.nameValid(*payload.Name, &payload.ID) {
+		service.logger.Debug(ErrNameBad)
+		return output.ErrValidationFailed
+	}
+	// pem (optional)
+	pem := cert.Pem
+	if payload.Pem != nil {
+		pem = *payload.Pem
+	}
+	chain, err := parseChain(pem)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+	// private key (optional), if the key or pem changes the key must still match
+	if payload.PrivateKeyID != nil {
+		if !service.privateKeyValid(*payload.PrivateKeyID, chain, &cert) {
+			service.logger.Debug(ErrKeyBad)
+			return output.ErrValidationFailed
+		}
+	} else if payload.Pem != nil && cert.PrivateKey != nil && !keyMatches(cert.PrivateKey.Pem, chain.publicKey) {
+		service.logger.Debug(ErrKeyBad)
+		return output.ErrValidationFailed
+	}
+	// notification emails (optional, empty clears them)
+	if !notificationEmailsValid(payload.NotificationEmails) {
+		service.logger.Debug(ErrNotificationEmailsBad)
+		return output.ErrValidationFailed
+	}
+	// fail if trying to set something sensitive
+	if (payload.ApiKey != nil || payload.ApiKeyNew != nil) && !(service.https || service.devMode) {
+		service.logger.Debug("cant put apikey when not running as https or in devmode")
+		return output.ErrUnavailableHttp
+	}
+	// api key must be at least 10 characters long
+	if payload.ApiKey != nil && len(*payload.ApiKey) < 10 {
+		service.logger.Debug(ErrApiKeyBad)
+		return output.ErrValidationFailed
+	}
+	// api key new must be at least 10 characters long
+	if payload.ApiKeyNew != nil && *payload.ApiKeyNew != "" && len(*payload.ApiKeyNew) < 10 {
+		service.logger.Debug(ErrApiKeyNewBad)
+		return output.ErrValidationFailed
+	}
+	// end validation
+
+	// add details parsed from the new pem to the payload before saving
+	if payload.Pem != nil {
+		payload.Pem = &chain.pem
+		payload.Subject = &chain.subject
+		payload.SubjectAltNames = chain.subjectAltNames
+		payload.Issuer = &chain.issuer
+		payload.Serial = &chain.serial
+		payload.ValidFrom = &chain.validFrom
+		payload.ValidTo = &chain.validTo
+	}
+	payload.UpdatedAt = int(time.Now().Unix())
+
+	// save to storage
+	err = service.storage.PutUnmanagedCertUpdate(payload)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// return response to client
+	response := output.JsonResponse{
+		Status:  http.StatusOK,
+		Message: "updated",
+		ID:      payload.ID,
+	}
+
+	_, err = service.output.WriteJSON(w, response.Status, response, "response")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
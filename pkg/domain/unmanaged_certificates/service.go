@@ -0,0 +1,83 @@
+package unmanaged_certificates
+
+import (
+	"errors"
+	"legocerthub-backend/pkg/domain/private_keys"
+	"legocerthub-backend/pkg/output"
+	"legocerthub-backend/pkg/pagination_sort"
+
+	"go.uber.org/zap"
+)
+
+var errServiceComponent = errors.New("necessary unmanaged certificates service component is missing")
+
+// App interface is for connecting to the main app
+type App interface {
+	GetDevMode() bool
+	GetLogger() *zap.SugaredLogger
+	IsHttps() bool
+	GetOutputter() *output.Service
+	GetUnmanagedCertificatesStorage() Storage
+	GetKeysService() *private_keys.Service
+}
+
+// Storage interface for storage functions
+type Storage interface {
+	GetAllUnmanagedCerts(q pagination_sort.Query) (certs []Certificate, totalRowCount int, err error)
+	GetOneUnmanagedCertById(id int) (cert Certificate, err error)
+	GetOneUnmanagedCertByName(name string) (cert Certificate, err error)
+	ManagedCertNameInUse(name string) (inUse bool, err error)
+
+	PostNewUnmanagedCert(payload NewPayload, newKey *private_keys.NewPayload) (id int, err error)
+	PutUnmanagedCertUpdate(payload UpdatePayload) (err error)
+	DeleteUnmanagedCert(id int) (err error)
+
+	GetOneKeyById(id int) (private_keys.Key, error)
+}
+
+// Unmanaged certificates service struct
+type Service struct {
+	devMode bool
+	logger  *zap.SugaredLogger
+	https   bool
+	output  *output.Service
+	storage Storage
+	keys    *private_keys.Service
+}
+
+// NewService creates a new unmanaged certificates service
+func NewService(app App) (*Service, error) {
+	service := new(Service)
+
+	// devMode
+	service.devMode = app.GetDevMode()
+
+	// logger
+	service.logger = app.GetLogger()
+	if service.logger == nil {
+		return nil, errServiceComponent
+	}
+
+	// running as https?
+	service.https = app.IsHttps()
+
+	// output service
+	service.output = app.GetOutputter()
+	if service.output == nil {
+		return nil, errServiceComponent
+	}
+
+	// storage
+	service.storage = app.GetUnmanagedCertificatesStorage()
+	if service.storage == nil {
+		return nil, errServiceComponent
+	}
+
+	// keys
+	service.keys = app.GetKeysService()
+	if service.keys == nil {
+		return nil, errServiceComponent
+	}
+
+	return service, nil
+}
@@ -0,0 +1,109 @@
+package unmanaged_certificates
+
+import (
+	"legocerthub-backend/pkg/domain/private_keys"
+)
+
+// Certificate is a certificate that was issued outside of LeGo (e.g. by another
+// CA or tool). It is imported so its expiration is monitored and so it can be
+// served by the download api, but LeGo never renews it. It can later be converted
+// to a managed certificate.
+type Certificate struct {
+	ID                 int
+	Name               string
+	Description        string
+	PrivateKey         *private_keys.Key
+	Pem                string
+	Subject            string
+	SubjectAltNames    []string
+	Issuer             string
+	Serial             string
+	ValidFrom          int
+	ValidTo            int
+	NotificationEmails []string
+	ApiKey             string
+	ApiKeyNew          string
+	ApiKeyViaUrl       bool
+	CreatedAt          int
+	UpdatedAt          int
+}
+
+// certificateSummaryResponse is a JSON response containing only
+// fields desired for the summary
+type certificateSummaryResponse struct {
+	ID              int                 `json:"id"`
+	Name            string              `json:"name"`
+	Description     string              `json:"description"`
+	PrivateKey      *privateKeyResponse `json:"private_key"`
+	Subject         string              `json:"subject"`
+	SubjectAltNames []string            `json:"subject_alts"`
+	Issuer          string              `json:"issuer"`
+	ValidFrom       int                 `json:"valid_from"`
+	ValidTo         int                 `json:"valid_to"`
+	ApiKeyViaUrl    bool                `json:"api_key_via_url"`
+}
+
+type privateKeyResponse struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func (cert Certificate) summaryResponse() certificateSummaryResponse {
+	var key *privateKeyResponse
+	if cert.PrivateKey != nil {
+		key = &privateKeyResponse{
+			ID:   cert.PrivateKey.ID,
+			Name: cert.PrivateKey.Name,
+		}
+	}
+
+	return certificateSummaryResponse{
+		ID:              cert.ID,
+		Name:            cert.Name,
+		Description:     cert.Description,
+		PrivateKey:      key,
+		Subject:         cert.Subject,
+		SubjectAltNames: cert.SubjectAltNames,
+		Issuer:          cert.Issuer,
+		ValidFrom:       cert.ValidFrom,
+		ValidTo:         cert.ValidTo,
+		ApiKeyViaUrl:    cert.ApiKeyViaUrl,
+	}
+}
+
+// certificateDetailedResponse is a JSON response containing all
+// fields that can be returned as JSON
+type certificateDetailedResponse struct {
+	certificateSummaryResponse
+	Serial             string   `json:"serial"`
+	Pem                string   `json:"pem"`
+	NotificationEmails []string `json:"notification_emails"`
+	ApiKey             string   `json:"api_key"`
+	ApiKeyNew          string   `json:"api_key_new,omitempty"`
+	CreatedAt          int      `json:"created_at"`
+	UpdatedAt          int      `json:"updated_at"`
+}
+
+func (cert Certificate) detailedResponse(withSensitive bool) certificateDetailedResponse {
+	// option to redact sensitive info
+	apiKey := cert.ApiKey
+	apiKeyNew := cert.ApiKeyNew
+	if !withSensitive {
+		apiKey = "[redacted]"
+		// redact if not empty
+		if apiKeyNew != "" {
+			apiKeyNew = "[redacted]"
+		}
+	}
+
+	return certificateDetailedResponse{
+		certificateSummaryResponse: cert.summaryResponse(),
+		Serial:                     cert.Serial,
+		Pem:                        cert.Pem,
+		NotificationEmails:         cert.NotificationEmails,
+		ApiKey:                     apiKey,
+		ApiKeyNew:                  apiKeyNew,
+		CreatedAt:                  cert.CreatedAt,
+		UpdatedAt:                  cert.UpdatedAt,
+	}
+}
@@ -0,0 +1,107 @@
+package unmanaged_certificates
+
+import (
+	"errors"
+	"legocerthub-backend/pkg/output"
+	"legocerthub-backend/pkg/storage"
+	"legocerthub-backend/pkg/validation"
+)
+
+var (
+	ErrIdBad                 = errors.New("unmanaged certificate id is invalid")
+	ErrNameBad               = errors.New("unmanaged certificate name is not valid (or is in use by a certificate)")
+	ErrPemBad                = errors.New("unmanaged certificate pem is not valid (must be one or more pem encoded certificates, leaf first)")
+	ErrKeyAndKeyPem          = errors.New("private key id and key pem both specified")
+	ErrKeyBad                = errors.New("private key is not valid (must be available and match the certificate)")
+	ErrKeyNameBad            = errors.New("name is not valid for a new key")
+	ErrApiKeyBad             = errors.New("api key is not valid (must be at least 10 chars in length)")
+	ErrApiKeyNewBad          = errors.New("api key (new) is not valid (must be at least 10 chars in length)")
+	ErrNotificationEmailsBad = errors.New("one or more notification emails are not valid")
+)
+
+// GetCertificate returns the unmanaged Certificate for the specified id
+func (service *Service) GetCertificate(id int) (Certificate, error) {
+	// if id is not in valid range, it is definitely not valid
+	if !validation.IsIdExistingValidRange(id) {
+		service.logger.Debug(ErrIdBad)
+		return Certificate{}, output.ErrValidationFailed
+	}
+
+	// get from storage
+	cert, err := service.storage.GetOneUnmanagedCertById(id)
+	if err != nil {
+		// special error case for no record found
+		if err == storage.ErrNoRecord {
+			service.logger.Debug(err)
+			return Certificate{}, output.ErrNotFound
+		} else {
+			service.logger.Error(err)
+			return Certificate{}, output.ErrStorageGeneric
+		}
+	}
+
+	return cert, nil
+}
+
+// nameValid returns true if the name is acceptable and not in use by another
+// unmanaged certificate or by any managed certificate (the download api looks up
+// both by name). If an id is specified, the name is also accepted if it is in use
+// by that id.
+func (service *Service) nameValid(name string, id *int) bool {
+	// basic check
+	if !validation.NameValid(name) {
+		return false
+	}
+
+	// managed certs
+	inUse, err := service.storage.ManagedCertNameInUse(name)
+	if err != nil || inUse {
+		return false
+	}
+
+	// unmanaged certs
+	cert, err := service.storage.GetOneUnmanagedCertByName(name)
+	if err == storage.ErrNoRecord {
+		return true
+	} else if err != nil {
+		return false
+	}
+
+	// if the returned cert is the cert being edited, name is ok
+	if id != nil && cert.ID == *id {
+		return true
+	}
+
+	return false
+}
+
+// privateKeyValid returns true if the key matches the chain and is available (or
+// is already the key of the cert being edited)
+func (service *Service) privateKeyValid(keyId int, chain chainDetails, current *Certificate) bool {
+	if !validation.IsIdExistingValidRange(keyId) {
+		return false
+	}
+
+	isCurrent := current != nil && current.PrivateKey != nil && current.PrivateKey.ID == keyId
+	if !isCurrent && !service.keys.KeyAvailable(keyId) {
+		return false
+	}
+
+	key, err := service.storage.GetOneKeyById(keyId)
+	if err != nil {
+		return false
+	}
+
+	return keyMatches(key.Pem, chain.publicKey)
+}
+
+// notificationEmailsValid validates each of the notification recipient emails
+func notificationEmailsValid(emails []string) bool {
+	for _, email := range emails {
+		if !validation.EmailValid(email) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,284 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// delivery states
+const (
+	deliveryStatePending   = "pending"
+	deliveryStateSucceeded = "succeeded"
+	deliveryStateFailed    = "failed"
+)
+
+const (
+	// deliveryMaxAttempts is the number of attempts before a delivery is failed
+	deliveryMaxAttempts = 8
+	// deliveryBackoffBase is the delay before the first retry, it doubles after
+	// each failed attempt (up to deliveryBackoffMax)
+	deliveryBackoffBase = 30 * time.Second
+	deliveryBackoffMax  = 1 * time.Hour
+	// deliveryPollInterval is how often due deliveries are checked for when the
+	// service isn't woken by a new event
+	deliveryPollInterval = 30 * time.Second
+	// deliveryBatchSize is the max number of deliveries attempted at once
+	deliveryBatchSize = 20
+	// deliveryRequestTimeout is the max time to wait for a webhook response
+	deliveryRequestTimeout = 15 * time.Second
+	// deliveryLogRetention is how long finished deliveries are kept
+	deliveryLogRetention = 30 * 24 * time.Hour
+	// deliveryLastErrorMaxLength limits the size of the saved error / response
+	deliveryLastErrorMaxLength = 512
+)
+
+// Delivery is an event to be (or that was) POSTed to a webhook
+type Delivery struct {
+	ID             int
+	Webhook        Webhook
+	EventType      string
+	Payload        string
+	State          string
+	Attempts       int
+	NextAttemptAt  int
+	ResponseStatus *int
+	LastError      string
+	CreatedAt      int
+	UpdatedAt      int
+}
+
+// deliveryResponse is the api response for a delivery
+type deliveryResponse struct {
+	ID             int    `json:"id"`
+	WebhookID      int    `json:"webhook_id"`
+	EventType      string `json:"event_type"`
+	Payload        string `json:"payload"`
+	State          string `json:"state"`
+	Attempts       int    `json:"attempts"`
+	NextAttemptAt  int    `json:"next_attempt_at"`
+	ResponseStatus *int   `json:"response_status"`
+	LastError      string `json:"last_error"`
+	CreatedAt      int    `json:"created_at"`
+	UpdatedAt      int    `json:"updated_at"`
+}
+
+func (delivery Delivery) response() deliveryResponse {
+	return deliveryResponse{
+		ID:             delivery.ID,
+		WebhookID:      delivery.Webhook.ID,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		State:          delivery.State,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		ResponseStatus: delivery.ResponseStatus,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt,
+		UpdatedAt:      delivery.UpdatedAt,
+	}
+}
+
+// NewDeliveryPayload is used to save a new (pending) delivery
+type NewDeliveryPayload struct {
+	WebhookID     int
+	EventType     string
+	Payload       string
+	NextAttemptAt int
+	CreatedAt     int
+	UpdatedAt     int
+}
+
+// DeliveryResultPayload is used to save the result of a delivery attempt
+type DeliveryResultPayload struct {
+	ID             int
+	State          string
+	Attempts       int
+	NextAttemptAt  int
+	ResponseStatus *int
+	LastError      string
+	UpdatedAt      int
+}
+
+// signature returns the value of the signature header for the body. The
+// signature is the hex HMAC-SHA256 of "<timestamp>.<body>" using the webhook's
+// secret.
+func signature(secret []byte, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// deliveryBackoff returns how long to wait before the next attempt after the
+// specified number of failed attempts
+func deliveryBackoff(attempts int) time.Duration {
+	backoff := deliveryBackoffBase
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= deliveryBackoffMax {
+			return deliveryBackoffMax
+		}
+	}
+
+	return backoff
+}
+
+// truncate limits s to deliveryLastErrorMaxLength
+func truncate(s string) string {
+	if len(s) > deliveryLastErrorMaxLength {
+		return s[:deliveryLastErrorMaxLength]
+	}
+
+	return s
+}
+
+// wakeDelivery signals the delivery service to check for due deliveries
+func (service *Service) wakeDelivery() {
+	select {
+	case service.deliveryWake <- struct{}{}:
+	default:
+		// already signaled
+	}
+}
+
+// send POSTs the delivery to its webhook and returns the response status (if
+// there was a response)
+func (service *Service) send(delivery Delivery) (*int, error) {
+	secret, err := service.cipher.Decrypt(delivery.Webhook.EncryptedSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(service.shutdownContext, deliveryRequestTimeout)
+	defer cancel()
+
+	body := []byte(delivery.Payload)
+	request, err := service.httpClient.NewRequest(http.MethodPost, delivery.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request = request.WithContext(ctx)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-LeGo-Event", delivery.EventType)
+	request.Header.Set("X-LeGo-Delivery", strconv.Itoa(delivery.ID))
+	request.Header.Set("X-LeGo-Signature", signature(secret, time.Now().Unix(), body))
+
+	response, err := service.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	status := response.StatusCode
+	if status < 200 || status > 299 {
+		respBody, _ := io.ReadAll(io.LimitReader(response.Body, deliveryLastErrorMaxLength))
+		return &status, fmt.Errorf("webhook responded with status %d: %s", status, respBody)
+	}
+
+	return &status, nil
+}
+
+// attempt tries to send the delivery and saves the result
+func (service *Service) attempt(delivery Delivery) {
+	status, err := service.send(delivery)
+
+	now := time.Now()
+	result := DeliveryResultPayload{
+		ID:             delivery.ID,
+		State:          deliveryStateSucceeded,
+		Attempts:       delivery.Attempts + 1,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		ResponseStatus: status,
+		UpdatedAt:      int(now.Unix()),
+	}
+
+	if err != nil {
+		result.LastError = truncate(err.Error())
+
+		if result.Attempts >= deliveryMaxAttempts {
+			result.State = deliveryStateFailed
+			service.logger.Errorf("webhooks: delivery %d to webhook %d failed after %d attempts (%s)", delivery.ID, delivery.Webhook.ID, result.Attempts, err)
+		} else {
+			result.State = deliveryStatePending
+			result.NextAttemptAt = int(now.Add(deliveryBackoff(result.Attempts)).Unix())
+			service.logger.Debugf("webhooks: delivery %d to webhook %d failed, will retry (%s)", delivery.ID, delivery.Webhook.ID, err)
+		}
+	}
+
+	err = service.storage.PutWebhookDeliveryResult(result)
+	if err != nil {
+		service.logger.Errorf("webhooks: failed to save result of delivery %d (%s)", delivery.ID, err)
+	}
+}
+
+// deliverDue attempts all of the deliveries that are due
+func (service *Service) deliverDue() {
+	for {
+		deliveries, err := service.storage.GetDueWebhookDeliveries(int(time.Now().Unix()), deliveryBatchSize)
+		if err != nil {
+			service.logger.Errorf("webhooks: failed to get due deliveries (%s)", err)
+			return
+		}
+
+		for i := range deliveries {
+			// stop if shutting down
+			if service.shutdownContext.Err() != nil {
+				return
+			}
+
+			service.attempt(deliveries[i])
+		}
+
+		// done if batch wasn't full
+		if len(deliveries) < deliveryBatchSize {
+			return
+		}
+	}
+}
+
+// startDeliveryService starts a go routine that delivers pending events to their
+// webhooks, retrying failed deliveries with backoff
+func (service *Service) startDeliveryService(wg *sync.WaitGroup) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		nextCleanup := time.Now()
+
+		for {
+			service.deliverDue()
+
+			// remove old deliveries from the log
+			if time.Now().After(nextCleanup) {
+				err := service.storage.DeleteOldWebhookDeliveries(int(time.Now().Add(-deliveryLogRetention).Unix()))
+				if err != nil {
+					service.logger.Errorf("webhooks: failed to delete old deliveries (%s)", err)
+				}
+				nextCleanup = time.Now().Add(24 * time.Hour)
+			}
+
+			select {
+			case <-service.shutdownContext.Done():
+				service.logger.Info("webhooks delivery service shutdown complete")
+				return
+
+			case <-service.deliveryWake:
+				// new event(s)
+
+			case <-time.After(deliveryPollInterval):
+				// check for retries
+			}
+		}
+	}()
+}
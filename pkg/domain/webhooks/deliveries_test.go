@@ -0,0 +1,153 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"legocerthub-backend/pkg/encryption"
+	"legocerthub-backend/pkg/httpclient"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// deliveryStorage records delivery results (only the functions used by attempt
+// are implemented)
+type deliveryStorage struct {
+	Storage
+	results []DeliveryResultPayload
+}
+
+func (store *deliveryStorage) PutWebhookDeliveryResult(payload DeliveryResultPayload) error {
+	store.results = append(store.results, payload)
+	return nil
+}
+
+func newTestService(t *testing.T) (*Service, *deliveryStorage) {
+	cipher, err := encryption.NewCipher(filepath.Join(t.TempDir(), "encryption.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := &deliveryStorage{}
+	return &Service{
+		shutdownContext: context.Background(),
+		logger:          zap.NewNop().Sugar(),
+		storage:         store,
+		httpClient:      httpclient.New("lego-test", false),
+		cipher:          cipher,
+	}, store
+}
+
+func TestDelivery_SignedPost(t *testing.T) {
+	service, store := newTestService(t)
+	secret := "0123456789abcdef"
+
+	var gotSignature, gotEvent string
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSignature = r.Header.Get("X-LeGo-Signature")
+		gotEvent = r.Header.Get("X-LeGo-Event")
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	encryptedSecret, err := service.cipher.Encrypt([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service.attempt(Delivery{
+		ID:        3,
+		Webhook:   Webhook{ID: 1, URL: server.URL, EncryptedSecret: encryptedSecret},
+		EventType: EventCertificateIssued,
+		Payload:   `{"type":"certificate_issued"}`,
+	})
+
+	if len(store.results) != 1 || store.results[0].State != deliveryStateSucceeded {
+		t.Fatalf("delivery results are %+v (expected one succeeded)", store.results)
+	}
+	if gotEvent != EventCertificateIssued {
+		t.Errorf("event header is %s (expected %s)", gotEvent, EventCertificateIssued)
+	}
+
+	// verify signature the way a receiver would
+	parts := strings.Split(gotSignature, ",")
+	if len(parts) != 2 || !strings.HasPrefix(parts[0], "t=") || !strings.HasPrefix(parts[1], "v1=") {
+		t.Fatalf("signature header %s is malformed", gotSignature)
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.TrimPrefix(parts[0], "t=") + "." + string(gotBody)))
+	if !hmac.Equal([]byte(hex.EncodeToString(mac.Sum(nil))), []byte(strings.TrimPrefix(parts[1], "v1="))) {
+		t.Errorf("signature %s does not match body", gotSignature)
+	}
+}
+
+func TestDelivery_RetryAndFail(t *testing.T) {
+	service, store := newTestService(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	encryptedSecret, err := service.cipher.Encrypt([]byte("0123456789abcdef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	delivery := Delivery{
+		ID:      4,
+		Webhook: Webhook{ID: 1, URL: server.URL, EncryptedSecret: encryptedSecret},
+		Payload: `{}`,
+	}
+
+	// first failure is retried with backoff
+	before := time.Now()
+	service.attempt(delivery)
+	result := store.results[0]
+	if result.State != deliveryStatePending || result.Attempts != 1 {
+		t.Errorf("first attempt result is %+v (expected pending, 1 attempt)", result)
+	}
+	if result.ResponseStatus == nil || *result.ResponseStatus != http.StatusInternalServerError {
+		t.Errorf("first attempt response status is %v (expected 500)", result.ResponseStatus)
+	}
+	if result.NextAttemptAt < int(before.Add(deliveryBackoffBase).Unix()) {
+		t.Errorf("next attempt at %d is before backoff", result.NextAttemptAt)
+	}
+
+	// last attempt fails the delivery
+	delivery.Attempts = deliveryMaxAttempts - 1
+	service.attempt(delivery)
+	if store.results[1].State != deliveryStateFailed {
+		t.Errorf("last attempt state is %s (expected %s)", store.results[1].State, deliveryStateFailed)
+	}
+}
+
+func TestDelivery_Backoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, 1 * time.Minute},
+		{4, 4 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, 1 * time.Hour},
+		{20, 1 * time.Hour},
+	}
+
+	for _, test := range tests {
+		backoff := deliveryBackoff(test.attempts)
+		if backoff != test.expected {
+			t.Errorf("backoff after %d attempts is %s (expected %s)", test.attempts, backoff, test.expected)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package webhooks
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"legocerthub-backend/pkg/acme"
+	"legocerthub-backend/pkg/certinfo"
+	"time"
+)
+
+// event types
+const (
+	EventCertificateIssued   = "certificate_issued"
+	EventOrderFailed         = "order_failed"
+	EventCertificateRevoked  = "certificate_revoked"
+	EventCertificateExpiring = "certificate_expiring"
+	EventApiKeyRotated       = "api_key_rotated"
+)
+
+// eventTypes are all of the valid event types
+var eventTypes = []string{
+	EventCertificateIssued,
+	EventOrderFailed,
+	EventCertificateRevoked,
+	EventCertificateExpiring,
+	EventApiKeyRotated,
+}
+
+// api key resource types
+const (
+	ResourceCertificate = "certificate"
+	ResourcePrivateKey  = "private_key"
+)
+
+// OrderCertificate is the certificate of an order, Pem is nil if the order
+// doesn't have a certificate (yet)
+type OrderCertificate struct {
+	CertificateID   int
+	CertificateName string
+	Pem             *string
+}
+
+// ExpiringCert is a certificate whose newest valid order expires at ValidTo
+type ExpiringCert struct {
+	CertificateID   int
+	CertificateName string
+	ValidTo         int
+}
+
+// eventPayload is the JSON body POSTed to webhooks
+type eventPayload struct {
+	Type string      `json:"type"`
+	Time int         `json:"time"`
+	Data interface{} `json:"data"`
+}
+
+// event data
+type certificateIssuedData struct {
+	CertificateID   int    `json:"certificate_id"`
+	CertificateName string `json:"certificate_name"`
+	OrderID         int    `json:"order_id"`
+	Serial          string `json:"serial"`
+	ValidFrom       int    `json:"valid_from"`
+	ValidTo         int    `json:"valid_to"`
+}
+
+type orderFailedData struct {
+	CertificateID   int         `json:"certificate_id"`
+	CertificateName string      `json:"certificate_name"`
+	OrderID         int         `json:"order_id"`
+	Reason          string      `json:"reason"`
+	AcmeError       *acme.Error `json:"acme_error,omitempty"`
+}
+
+type certificateRevokedData struct {
+	CertificateID   int    `json:"certificate_id"`
+	CertificateName string `json:"certificate_name"`
+	OrderID         int    `json:"order_id"`
+	Serial          string `json:"serial"`
+}
+
+type certificateExpiringData struct {
+	CertificateID   int    `json:"certificate_id"`
+	CertificateName string `json:"certificate_name"`
+	ValidTo         int    `json:"valid_to"`
+	DaysRemaining   int    `json:"days_remaining"`
+}
+
+type apiKeyRotatedData struct {
+	Resource string `json:"resource"`
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+}
+
+// emit saves a delivery of the event for each enabled webhook that is subscribed
+// to the event type
+func (service *Service) emit(eventType string, data interface{}) {
+	hooks, err := service.storage.GetEnabledWebhooks()
+	if err != nil {
+		service.logger.Errorf("webhooks: failed to get webhooks for %s event (%s)", eventType, err)
+		return
+	}
+
+	now := int(time.Now().Unix())
+	payload, err := json.Marshal(eventPayload{
+		Type: eventType,
+		Time: now,
+		Data: data,
+	})
+	if err != nil {
+		service.logger.Errorf("webhooks: failed to encode %s event (%s)", eventType, err)
+		return
+	}
+
+	queued := false
+	for _, hook := range hooks {
+		if !hook.subscribed(eventType) {
+			continue
+		}
+
+		_, err = service.storage.PostWebhookDelivery(NewDeliveryPayload{
+			WebhookID:     hook.ID,
+			EventType:     eventType,
+			Payload:       string(payload),
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+		if err != nil {
+			service.logger.Errorf("webhooks: failed to queue %s event for webhook %d (%s)", eventType, hook.ID, err)
+			continue
+		}
+		queued = true
+	}
+
+	if queued {
+		service.wakeDelivery()
+	}
+}
+
+// orderCertificate fetches the order's certificate and parses its pem (if the
+// order has one)
+func (service *Service) orderCertificate(orderId int) (OrderCertificate, *x509.Certificate, error) {
+	orderCert, err := service.storage.GetOrderCertificate(orderId)
+	if err != nil {
+		return OrderCertificate{}, nil, err
+	}
+
+	if orderCert.Pem == nil {
+		return orderCert, nil, nil
+	}
+
+	leaf, err := certinfo.LeafFromPem(*orderCert.Pem)
+	if err != nil {
+		return OrderCertificate{}, nil, err
+	}
+
+	return orderCert, leaf, nil
+}
+
+// CertificateIssued sends the certificate_issued event for the order
+func (service *Service) CertificateIssued(orderId int) {
+	// no-op if service isn't running
+	if service == nil {
+		return
+	}
+
+	orderCert, leaf, err := service.orderCertificate(orderId)
+	if err != nil || leaf == nil {
+		service.logger.Errorf("webhooks: failed to get issued certificate of order %d (%v)", orderId, err)
+		return
+	}
+
+	service.emit(EventCertificateIssued, certificateIssuedData{
+		CertificateID:   orderCert.CertificateID,
+		CertificateName: orderCert.CertificateName,
+		OrderID:         orderId,
+		Serial:          certinfo.SerialString(leaf),
+		ValidFrom:       int(leaf.NotBefore.Unix()),
+		ValidTo:         int(leaf.NotAfter.Unix()),
+	})
+}
+
+// OrderFailed sends the order_failed event for the order. If the failure was an
+// error from the ACME server, it is included.
+func (service *Service) OrderFailed(orderId int, reason string, acmeErr *acme.Error) {
+	// no-op if service isn't running
+	if service == nil {
+		return
+	}
+
+	orderCert, _, err := service.orderCertificate(orderId)
+	if err != nil {
+		service.logger.Errorf("webhooks: failed to get certificate of failed order %d (%s)", orderId, err)
+		return
+	}
+
+	service.emit(EventOrderFailed, orderFailedData{
+		CertificateID:   orderCert.CertificateID,
+		CertificateName: orderCert.CertificateName,
+		OrderID:         orderId,
+		Reason:          reason,
+		AcmeError:       acmeErr,
+	})
+}
+
+// CertificateRevoked sends the certificate_revoked event for the order
+func (service *Service) CertificateRevoked(orderId int) {
+	// no-op if service isn't running
+	if service == nil {
+		return
+	}
+
+	orderCert, leaf, err := service.orderCertificate(orderId)
+	if err != nil || leaf == nil {
+		service.logger.Errorf("webhooks: failed to get revoked certificate of order %d (%v)", orderId, err)
+		return
+	}
+
+	service.emit(EventCertificateRevoked, certificateRevokedData{
+		CertificateID:   orderCert.CertificateID,
+		CertificateName: orderCert.CertificateName,
+		OrderID:         orderId,
+		Serial:          certinfo.SerialString(leaf),
+	})
+}
+
+// ApiKeyRotated sends the api_key_rotated event for the resource (certificate or
+// private_key), it should be called once the old api key no longer works
+func (service *Service) ApiKeyRotated(resource string, id int, name string) {
+	// no-op if service isn't running
+	if service == nil {
+		return
+	}
+
+	service.emit(EventApiKeyRotated, apiKeyRotatedData{
+		Resource: resource,
+		ID:       id,
+		Name:     name,
+	})
+}
@@ -0,0 +1,79 @@
+package webhooks
+
+import (
+	"sync"
+	"time"
+)
+
+// expiringCheckInterval is how often certificates are checked for upcoming
+// expiration
+const expiringCheckInterval = 1 * time.Hour
+
+// startExpiringService starts a go routine that sends the certificate_expiring
+// event for certificates that are within the configured days of expiring. If
+// expiring is 0, the service is not started.
+func (service *Service) startExpiringService(wg *sync.WaitGroup) {
+	if service.expiring <= 0 {
+		return
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		// certs that already had an event sent (cert id -> valid_to)
+		sent := make(map[int]int)
+
+		for {
+			err := service.sendExpiring(time.Now(), sent)
+			if err != nil {
+				service.logger.Errorf("webhooks: failed to check for expiring certificates (%s)", err)
+			}
+
+			select {
+			case <-service.shutdownContext.Done():
+				service.logger.Info("webhooks expiring service shutdown complete")
+				return
+
+			case <-time.After(expiringCheckInterval):
+				// sleep until next check
+			}
+		}
+	}()
+}
+
+// sendExpiring sends the certificate_expiring event for each certificate whose
+// newest valid order expires within the configured threshold. The event is only
+// sent once for each certificate valid_to.
+func (service *Service) sendExpiring(now time.Time, sent map[int]int) error {
+	expiring, err := service.storage.GetExpiringCerts(int(now.Add(service.expiring).Unix()))
+	if err != nil {
+		return err
+	}
+
+	current := make(map[int]int)
+	for _, cert := range expiring {
+		current[cert.CertificateID] = cert.ValidTo
+
+		if sent[cert.CertificateID] == cert.ValidTo {
+			continue
+		}
+
+		service.emit(EventCertificateExpiring, certificateExpiringData{
+			CertificateID:   cert.CertificateID,
+			CertificateName: cert.CertificateName,
+			ValidTo:         cert.ValidTo,
+			DaysRemaining:   int(time.Unix(int64(cert.ValidTo), 0).Sub(now).Hours() / 24),
+		})
+	}
+
+	// forget certs that are no longer expiring (e.g. renewed)
+	for id := range sent {
+		delete(sent, id)
+	}
+	for id, validTo := range current {
+		sent[id] = validTo
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package webhooks
+
+import (
+	"legocerthub-backend/pkg/output"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// DeleteWebhook deletes a webhook (and its delivery log) from storage
+func (service *Service) DeleteWebhook(w http.ResponseWriter, r *http.Request) (err error) {
+	// get params
+	idParam := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// validate webhook exists
+	_, err = service.getWebhook(id)
+	if err != nil {
+		return err
+	}
+
+	// delete from storage
+	err = service.storage.DeleteWebhook(id)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// return response to client
+	response := output.JsonResponse{
+		Status:  http.StatusOK,
+		Message: "deleted",
+		ID:      id,
+	}
+
+	_, err = service.output.WriteJSON(w, response.Status, response, "response")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
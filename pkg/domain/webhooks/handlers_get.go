@@ -0,0 +1,126 @@
+package webhooks
+
+import (
+	"legocerthub-backend/pkg/output"
+	"legocerthub-backend/pkg/pagination_sort"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// allWebhooksResponse provides the json response struct
+// to answer a query for a portion of the webhooks
+type allWebhooksResponse struct {
+	Webhooks      []webhookResponse `json:"webhooks"`
+	TotalWebhooks int               `json:"total_records"`
+}
+
+// GetAllWebhooks returns all of the webhooks in storage as JSON
+func (service *Service) GetAllWebhooks(w http.ResponseWriter, r *http.Request) (err error) {
+	// parse pagination and sorting
+	query := pagination_sort.ParseRequestToQuery(r)
+
+	// get webhooks from storage
+	hooks, totalRows, err := service.storage.GetAllWebhooks(query)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// assemble response
+	response := allWebhooksResponse{
+		Webhooks:      []webhookResponse{},
+		TotalWebhooks: totalRows,
+	}
+	for i := range hooks {
+		response.Webhooks = append(response.Webhooks, hooks[i].response())
+	}
+
+	// return response to client
+	_, err = service.output.WriteJSON(w, http.StatusOK, response, "all_webhooks")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
+
+// GetOneWebhook returns a single webhook as JSON
+func (service *Service) GetOneWebhook(w http.ResponseWriter, r *http.Request) (err error) {
+	// params
+	idParam := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// get the webhook from storage (and validate id)
+	hook, err := service.getWebhook(id)
+	if err != nil {
+		return err
+	}
+
+	// return response to client
+	_, err = service.output.WriteJSON(w, http.StatusOK, hook.response(), "webhook")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
+
+// webhookDeliveriesResponse provides the json response struct
+// to answer a query for a portion of a webhook's deliveries
+type webhookDeliveriesResponse struct {
+	Deliveries      []deliveryResponse `json:"deliveries"`
+	TotalDeliveries int                `json:"total_records"`
+}
+
+// GetWebhookDeliveries returns the delivery log of a webhook as JSON
+func (service *Service) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) (err error) {
+	// params
+	idParam := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// validate webhook exists
+	_, err = service.getWebhook(id)
+	if err != nil {
+		return err
+	}
+
+	// parse pagination and sorting
+	query := pagination_sort.ParseRequestToQuery(r)
+
+	// get deliveries from storage
+	deliveries, totalRows, err := service.storage.GetWebhookDeliveries(id, query)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// assemble response
+	response := webhookDeliveriesResponse{
+		Deliveries:      []deliveryResponse{},
+		TotalDeliveries: totalRows,
+	}
+	for i := range deliveries {
+		response.Deliveries = append(response.Deliveries, deliveries[i].response())
+	}
+
+	// return response to client
+	_, err = service.output.WriteJSON(w, http.StatusOK, response, "webhook_deliveries")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"legocerthub-backend/pkg/output"
+	"net/http"
+	"time"
+)
+
+// NewPayload is a struct for posting a new webhook
+type NewPayload struct {
+	Name            *string  `json:"name"`
+	Description     *string  `json:"description"`
+	URL             *string  `json:"url"`
+	Secret          *string  `json:"secret"`
+	EncryptedSecret string   `json:"-"`
+	EventTypes      []string `json:"event_types"`
+	Enabled         *bool    `json:"enabled"`
+	CreatedAt       int      `json:"-"`
+	UpdatedAt       int      `json:"-"`
+}
+
+// PostNewWebhook creates a new webhook and saves it to storage
+func (service *Service) PostNewWebhook(w http.ResponseWriter, r *http.Request) (err error) {
+	var payload NewPayload
+
+	// decode body into payload
+	err = json.NewDecoder(r.Body).Decode(&payload)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// do validation
+	// name
+	if payload.Name == nil || !service.nameValid(*payload.Name, nil) {
+		service.logger.Debug(ErrNameBad)
+		return output.ErrValidationFailed
+	}
+	// description (if none, set to blank)
+	if payload.Description == nil {
+		payload.Description = new(string)
+	}
+	// url
+	if payload.URL == nil || !urlValid(*payload.URL) {
+		service.logger.Debug(ErrUrlBad)
+		return output.ErrValidationFailed
+	}
+	// secret
+	if payload.Secret == nil || len(*payload.Secret) < minSecretLength {
+		service.logger.Debug(ErrSecretBad)
+		return output.ErrValidationFailed
+	}
+	// event types (none = all events)
+	if !eventTypesValid(payload.EventTypes) {
+		service.logger.Debug(ErrEventTypesBad)
+		return output.ErrValidationFailed
+	}
+	// enabled (default true)
+	if payload.Enabled == nil {
+		payload.Enabled = new(bool)
+		*payload.Enabled = true
+	}
+	// end validation
+
+	// add additional details to the payload before saving
+	payload.EncryptedSecret, err = service.cipher.Encrypt([]byte(*payload.Secret))
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrInternal
+	}
+	payload.CreatedAt = int(time.Now().Unix())
+	payload.UpdatedAt = payload.CreatedAt
+
+	// save new webhook to storage, which also returns the new id
+	id, err := service.storage.PostNewWebhook(payload)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// return response to client
+	response := output.JsonResponse{
+		Status:  http.StatusCreated,
+		Message: "created",
+		ID:      id,
+	}
+
+	_, err = service.output.WriteJSON(w, response.Status, response, "response")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
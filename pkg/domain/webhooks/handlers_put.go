@@ -0,0 +1,106 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"legocerthub-backend/pkg/output"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// UpdatePayload is the struct for editing an existing webhook. Only fields
+// received in the payload (non-nil) are updated.
+type UpdatePayload struct {
+	ID              int       `json:"-"`
+	Name            *string   `json:"name"`
+	Description     *string   `json:"description"`
+	URL             *string   `json:"url"`
+	Secret          *string   `json:"secret"`
+	EncryptedSecret *string   `json:"-"`
+	EventTypes      *[]string `json:"event_types"`
+	Enabled         *bool     `json:"enabled"`
+	UpdatedAt       int       `json:"-"`
+}
+
+// PutWebhookUpdate updates a webhook that already exists in storage
+func (service *Service) PutWebhookUpdate(w http.ResponseWriter, r *http.Request) (err error) {
+	// parse payload
+	var payload UpdatePayload
+	err = json.NewDecoder(r.Body).Decode(&payload)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// get id param
+	idParam := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	payload.ID, err = strconv.Atoi(idParam)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// validation
+	// id
+	_, err = service.getWebhook(payload.ID)
+	if err != nil {
+		return err
+	}
+	// name (optional)
+	if payload.Name != nil && !service.nameValid(*payload.Name, &payload.ID) {
+		service.logger.Debug(ErrNameBad)
+		return output.ErrValidationFailed
+	}
+	// url (optional)
+	if payload.URL != nil && !urlValid(*payload.URL) {
+		service.logger.Debug(ErrUrlBad)
+		return output.ErrValidationFailed
+	}
+	// secret (optional)
+	if payload.Secret != nil && len(*payload.Secret) < minSecretLength {
+		service.logger.Debug(ErrSecretBad)
+		return output.ErrValidationFailed
+	}
+	// event types (optional)
+	if payload.EventTypes != nil && !eventTypesValid(*payload.EventTypes) {
+		service.logger.Debug(ErrEventTypesBad)
+		return output.ErrValidationFailed
+	}
+	// Description and Enabled do not need validation
+	// end validation
+
+	// add additional details to the payload before saving
+	if payload.Secret != nil {
+		payload.EncryptedSecret = new(string)
+		*payload.EncryptedSecret, err = service.cipher.Encrypt([]byte(*payload.Secret))
+		if err != nil {
+			service.logger.Error(err)
+			return output.ErrInternal
+		}
+	}
+	payload.UpdatedAt = int(time.Now().Unix())
+
+	// save updated webhook to storage
+	err = service.storage.PutWebhookUpdate(payload)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// return response to client
+	response := output.JsonResponse{
+		Status:  http.StatusOK,
+		Message: "updated",
+		ID:      payload.ID,
+	}
+
+	_, err = service.output.WriteJSON(w, response.Status, response, "response")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
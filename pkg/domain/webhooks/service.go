@@ -0,0 +1,116 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"legocerthub-backend/pkg/encryption"
+	"legocerthub-backend/pkg/httpclient"
+	"legocerthub-backend/pkg/output"
+	"legocerthub-backend/pkg/pagination_sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var errServiceComponent = errors.New("necessary webhooks service component is missing")
+
+// App interface is for connecting to the main app
+type App interface {
+	GetLogger() *zap.SugaredLogger
+	GetOutputter() *output.Service
+	GetWebhooksStorage() Storage
+	GetHttpClient() *httpclient.Client
+	GetCipher() *encryption.Cipher
+	GetShutdownContext() context.Context
+	GetShutdownWaitGroup() *sync.WaitGroup
+}
+
+// Storage interface for storage functions
+type Storage interface {
+	// webhooks
+	GetAllWebhooks(q pagination_sort.Query) (hooks []Webhook, totalRows int, err error)
+	GetOneWebhookById(id int) (hook Webhook, err error)
+	GetOneWebhookByName(name string) (hook Webhook, err error)
+	GetEnabledWebhooks() (hooks []Webhook, err error)
+
+	PostNewWebhook(payload NewPayload) (id int, err error)
+	PutWebhookUpdate(payload UpdatePayload) (err error)
+	DeleteWebhook(id int) (err error)
+
+	// event details
+	GetOrderCertificate(orderId int) (orderCert OrderCertificate, err error)
+	GetExpiringCerts(validToBefore int) (expiring []ExpiringCert, err error)
+
+	// deliveries
+	GetWebhookDeliveries(webhookId int, q pagination_sort.Query) (deliveries []Delivery, totalRows int, err error)
+	GetDueWebhookDeliveries(now int, limit int) (deliveries []Delivery, err error)
+	PostWebhookDelivery(payload NewDeliveryPayload) (id int, err error)
+	PutWebhookDeliveryResult(payload DeliveryResultPayload) (err error)
+	DeleteOldWebhookDeliveries(before int) (err error)
+}
+
+// Configuration options
+type Config struct {
+	ExpiringDays *int `yaml:"expiring_days"`
+}
+
+// Webhooks service struct
+type Service struct {
+	shutdownContext context.Context
+	logger          *zap.SugaredLogger
+	output          *output.Service
+	storage         Storage
+	httpClient      *httpclient.Client
+	cipher          *encryption.Cipher
+	deliveryWake    chan struct{}
+	expiring        time.Duration
+}
+
+// NewService creates a new webhooks service
+func NewService(app App, cfg *Config) (*Service, error) {
+	service := new(Service)
+
+	// logger
+	service.logger = app.GetLogger()
+	if service.logger == nil {
+		return nil, errServiceComponent
+	}
+
+	// output service
+	service.output = app.GetOutputter()
+	if service.output == nil {
+		return nil, errServiceComponent
+	}
+
+	// storage
+	service.storage = app.GetWebhooksStorage()
+	if service.storage == nil {
+		return nil, errServiceComponent
+	}
+
+	// http client
+	service.httpClient = app.GetHttpClient()
+	if service.httpClient == nil {
+		return nil, errServiceComponent
+	}
+
+	// cipher (webhook secrets are encrypted in storage)
+	service.cipher = app.GetCipher()
+	if service.cipher == nil {
+		return nil, errServiceComponent
+	}
+
+	// shutdown context
+	service.shutdownContext = app.GetShutdownContext()
+
+	// deliver events
+	service.deliveryWake = make(chan struct{}, 1)
+	service.startDeliveryService(app.GetShutdownWaitGroup())
+
+	// send expiring events
+	service.expiring = time.Duration(*cfg.ExpiringDays) * 24 * time.Hour
+	service.startExpiringService(app.GetShutdownWaitGroup())
+
+	return service, nil
+}
@@ -0,0 +1,97 @@
+package webhooks
+
+import (
+	"errors"
+	"legocerthub-backend/pkg/output"
+	"legocerthub-backend/pkg/storage"
+	"legocerthub-backend/pkg/validation"
+	"net/url"
+)
+
+// minSecretLength is the minimum length of a webhook secret
+const minSecretLength = 16
+
+var (
+	ErrIdBad         = errors.New("webhook id is invalid")
+	ErrNameBad       = errors.New("webhook name is not valid")
+	ErrUrlBad        = errors.New("webhook url is not valid (must be http or https)")
+	ErrSecretBad     = errors.New("webhook secret is not valid (must be at least 16 chars in length)")
+	ErrEventTypesBad = errors.New("webhook event types are not valid")
+)
+
+// getWebhook returns the Webhook for the specified id or an error
+func (service *Service) getWebhook(id int) (Webhook, error) {
+	// basic check
+	if !validation.IsIdExistingValidRange(id) {
+		service.logger.Debug(ErrIdBad)
+		return Webhook{}, output.ErrValidationFailed
+	}
+
+	// get the webhook from storage
+	hook, err := service.storage.GetOneWebhookById(id)
+	if err != nil {
+		// special error case for no record found
+		if err == storage.ErrNoRecord {
+			service.logger.Debug(err)
+			return Webhook{}, output.ErrNotFound
+		} else {
+			service.logger.Error(err)
+			return Webhook{}, output.ErrStorageGeneric
+		}
+	}
+
+	return hook, nil
+}
+
+// nameValid returns true if the specified webhook name is acceptable and not
+// already in use by another webhook. If an id is specified, the name is also
+// accepted if it is in use by that id.
+func (service *Service) nameValid(name string, id *int) bool {
+	// basic character/length check
+	if !validation.NameValid(name) {
+		return false
+	}
+
+	// make sure the name isn't already in use in storage
+	hook, err := service.storage.GetOneWebhookByName(name)
+	if err == storage.ErrNoRecord {
+		return true
+	} else if err != nil {
+		return false
+	}
+
+	// if the returned webhook is the webhook being edited, name is ok
+	if id != nil && hook.ID == *id {
+		return true
+	}
+
+	return false
+}
+
+// urlValid returns true if the url is an absolute http or https url
+func urlValid(u string) bool {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return false
+	}
+
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// eventTypesValid returns true if all of the event types are known event types
+func eventTypesValid(types []string) bool {
+	for _, t := range types {
+		found := false
+		for _, known := range eventTypes {
+			if t == known {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,55 @@
+package webhooks
+
+// Webhook is a subscription to events, each event is POSTed to the URL and signed
+// with the secret. If EventTypes is empty, the webhook receives all events.
+type Webhook struct {
+	ID              int
+	Name            string
+	Description     string
+	URL             string
+	EncryptedSecret string
+	EventTypes      []string
+	Enabled         bool
+	CreatedAt       int
+	UpdatedAt       int
+}
+
+// webhookResponse is the api response for a webhook (the secret is never returned)
+type webhookResponse struct {
+	ID          int      `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	URL         string   `json:"url"`
+	EventTypes  []string `json:"event_types"`
+	Enabled     bool     `json:"enabled"`
+	CreatedAt   int      `json:"created_at"`
+	UpdatedAt   int      `json:"updated_at"`
+}
+
+func (hook Webhook) response() webhookResponse {
+	return webhookResponse{
+		ID:          hook.ID,
+		Name:        hook.Name,
+		Description: hook.Description,
+		URL:         hook.URL,
+		EventTypes:  hook.EventTypes,
+		Enabled:     hook.Enabled,
+		CreatedAt:   hook.CreatedAt,
+		UpdatedAt:   hook.UpdatedAt,
+	}
+}
+
+// subscribed returns true if the webhook receives the event type
+func (hook Webhook) subscribed(eventType string) bool {
+	if len(hook.EventTypes) == 0 {
+		return true
+	}
+
+	for _, t := range hook.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,95 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"os"
+)
+
+// keyLength is the length of the AES-256 key
+const keyLength = 32
+
+var (
+	errBadKeyFile    = errors.New("encryption key file is not the correct length")
+	errBadCiphertext = errors.New("encrypted data is malformed")
+)
+
+// Cipher encrypts and decrypts data (such as credentials) so it can be
+// stored at rest. It uses AES-256-GCM with a key that is stored in a file
+// outside of the database.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher creates a Cipher using the key in the keyPath file. If the file
+// does not exist, a new random key is generated and saved to keyPath.
+func NewCipher(keyPath string) (*Cipher, error) {
+	key, err := os.ReadFile(keyPath)
+	if errors.Is(err, os.ErrNotExist) {
+		// generate new key
+		key = make([]byte, keyLength)
+		_, err = io.ReadFull(rand.Reader, key)
+		if err != nil {
+			return nil, err
+		}
+
+		// save key (only owner can read)
+		err = os.WriteFile(keyPath, key, 0600)
+		if err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if len(key) != keyLength {
+		return nil, errBadKeyFile
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cipher{
+		aead: aead,
+	}, nil
+}
+
+// Encrypt encrypts plaintext and returns it as a base64 encoded string
+// (nonce followed by the sealed data)
+func (c *Cipher) Encrypt(plaintext []byte) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	_, err := io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return "", err
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, plaintext, nil)
+
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a string that was created by Encrypt
+func (c *Cipher) Decrypt(ciphertext string) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := c.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, errBadCiphertext
+	}
+
+	return c.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+}
@@ -0,0 +1,102 @@
+package events
+
+import (
+	"fmt"
+	"time"
+)
+
+// Type is the type of an order progress event
+type Type string
+
+// order progress event types
+const (
+	TypeOrderQueued          Type = "order_queued"
+	TypeAuthzPending         Type = "authz_pending"
+	TypeChallengeProvisioned Type = "challenge_provisioned"
+	TypeDnsPropagated        Type = "dns_propagated"
+	TypeOrderFinalized       Type = "order_finalized"
+	TypeOrderIssued          Type = "order_issued"
+	TypeOrderFailed          Type = "order_failed"
+)
+
+// Event is a single order progress event
+type Event struct {
+	ID            int    `json:"id"`
+	Time          int    `json:"time"`
+	Type          Type   `json:"type"`
+	CertificateID int    `json:"certificate_id"`
+	OrderID       int    `json:"order_id"`
+	Message       string `json:"message"`
+}
+
+// publish assigns the event an id, adds it to the replay buffer, and sends it
+// to all subscribers that want it. Subscribers that can't keep up are dropped.
+func (service *Service) publish(event Event) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	event.ID = service.nextId
+	service.nextId++
+
+	// replay buffer (drop oldest when full)
+	if len(service.replay) >= replayBufferSize {
+		service.replay = append(service.replay[:0], service.replay[1:]...)
+	}
+	service.replay = append(service.replay, event)
+
+	// send to subscribers
+	for sub := range service.subscribers {
+		if !sub.wants(event) {
+			continue
+		}
+
+		select {
+		case sub.events <- event:
+		default:
+			// subscriber is too slow, disconnect it
+			delete(service.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// Emitter publishes events for one order. A nil Emitter is valid and discards
+// any events emitted with it.
+type Emitter struct {
+	service       *Service
+	certificateId int
+	orderId       int
+}
+
+// Emitter returns an Emitter for the specified certificate's order
+func (service *Service) Emitter(certId int, orderId int) *Emitter {
+	if service == nil {
+		return nil
+	}
+
+	return &Emitter{
+		service:       service,
+		certificateId: certId,
+		orderId:       orderId,
+	}
+}
+
+// Emit publishes an event of the specified type with message
+func (emitter *Emitter) Emit(eventType Type, message string) {
+	if emitter == nil {
+		return
+	}
+
+	emitter.service.publish(Event{
+		Time:          int(time.Now().Unix()),
+		Type:          eventType,
+		CertificateID: emitter.certificateId,
+		OrderID:       emitter.orderId,
+		Message:       message,
+	})
+}
+
+// Emitf formats a message and then emits the event
+func (emitter *Emitter) Emitf(eventType Type, format string, args ...interface{}) {
+	emitter.Emit(eventType, fmt.Sprintf(format, args...))
+}
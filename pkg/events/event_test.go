@@ -0,0 +1,72 @@
+package events
+
+import (
+	"testing"
+)
+
+func newTestService() *Service {
+	return &Service{
+		nextId:      1,
+		replay:      make([]Event, 0, replayBufferSize),
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+func TestEvents_FilterAndReplay(t *testing.T) {
+	service := newTestService()
+
+	service.Emitter(1, 10).Emit(TypeOrderQueued, "a")
+	service.Emitter(2, 20).Emit(TypeOrderQueued, "b")
+	service.Emitter(1, 10).Emit(TypeOrderIssued, "c")
+
+	// replay for cert 1 only
+	sub, replay := service.subscribe(1, 0)
+	if len(replay) != 2 || replay[0].Message != "a" || replay[1].Message != "c" {
+		t.Fatalf("unexpected replay for cert 1: %v", replay)
+	}
+
+	// replay after last id
+	_, replay = service.subscribe(0, 2)
+	if len(replay) != 1 || replay[0].ID != 3 {
+		t.Fatalf("unexpected replay after id 2: %v", replay)
+	}
+
+	// live events are filtered
+	service.Emitter(2, 20).Emit(TypeOrderFailed, "d")
+	service.Emitter(1, 11).Emit(TypeOrderQueued, "e")
+
+	event := <-sub.events
+	if event.Message != "e" || event.OrderID != 11 {
+		t.Fatalf("unexpected live event: %v", event)
+	}
+
+	service.unsubscribe(sub)
+	_, open := <-sub.events
+	if open {
+		t.Fatal("subscriber channel should be closed after unsubscribe")
+	}
+}
+
+func TestEvents_ReplayBufferAndSlowSubscriber(t *testing.T) {
+	service := newTestService()
+	sub, _ := service.subscribe(0, 0)
+
+	emitter := service.Emitter(1, 1)
+	for i := 0; i < replayBufferSize+10; i++ {
+		emitter.Emit(TypeAuthzPending, "x")
+	}
+
+	// oldest events are dropped from the replay buffer
+	if len(service.replay) != replayBufferSize || service.replay[0].ID != 11 {
+		t.Fatalf("unexpected replay buffer (len %d, first id %d)", len(service.replay), service.replay[0].ID)
+	}
+
+	// subscriber that never read is dropped
+	if _, exists := service.subscribers[sub]; exists {
+		t.Fatal("slow subscriber should have been dropped")
+	}
+
+	// nil emitter is a no-op
+	var nilEmitter *Emitter
+	nilEmitter.Emit(TypeOrderFailed, "ignored")
+}
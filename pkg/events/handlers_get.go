@@ -0,0 +1,122 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"legocerthub-backend/pkg/output"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// keepAliveInterval is how often a comment is sent to idle clients so proxies
+// don't close the stream
+const keepAliveInterval = 30 * time.Second
+
+// StreamOrderEvents is an http handler that streams order progress events to the
+// client as Server-Sent Events. Events can be limited to one certificate with the
+// certificate_id query param. Buffered events newer than the Last-Event-ID header
+// (or all buffered events if not specified) are sent first.
+func (service *Service) StreamOrderEvents(w http.ResponseWriter, r *http.Request) (err error) {
+	query := r.URL.Query()
+
+	// optional certificate filter
+	certId := 0
+	certIdParam := query.Get("certificate_id")
+	if certIdParam != "" {
+		certId, err = strconv.Atoi(certIdParam)
+		if err != nil || certId < 0 {
+			service.logger.Debug(err)
+			return output.ErrValidationFailed
+		}
+	}
+
+	// replay position
+	lastEventId := 0
+	lastEventIdParam := r.Header.Get("Last-Event-ID")
+	if lastEventIdParam != "" {
+		lastEventId, err = strconv.Atoi(lastEventIdParam)
+		if err != nil {
+			service.logger.Debug(err)
+			return output.ErrValidationFailed
+		}
+	}
+	// end validation
+
+	// stream is long lived, remove the server's write timeout
+	rc := http.NewResponseController(w)
+	err = rc.SetWriteDeadline(time.Time{})
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrInternal
+	}
+
+	sub, replay := service.subscribe(certId, lastEventId)
+	defer service.unsubscribe(sub)
+
+	// headers
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// send replay
+	for i := range replay {
+		err = writeEvent(w, replay[i])
+		if err != nil {
+			return nil
+		}
+	}
+	err = rc.Flush()
+	if err != nil {
+		return nil
+	}
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	// stream until the client or server is done
+	for {
+		select {
+		case <-r.Context().Done():
+			return nil
+
+		case <-service.shutdownContext.Done():
+			return nil
+
+		case event, ok := <-sub.events:
+			// closed if client couldn't keep up
+			if !ok {
+				service.logger.Debugf("client %s dropped from order events (too slow)", r.RemoteAddr)
+				return nil
+			}
+
+			err = writeEvent(w, event)
+			if err != nil {
+				return nil
+			}
+
+		case <-keepAlive.C:
+			_, err = fmt.Fprint(w, ": keep-alive\n\n")
+			if err != nil {
+				return nil
+			}
+		}
+
+		err = rc.Flush()
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+// writeEvent writes the event in the Server-Sent Events format
+func writeEvent(w http.ResponseWriter, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
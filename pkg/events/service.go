@@ -0,0 +1,65 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"legocerthub-backend/pkg/output"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+var errServiceComponent = errors.New("necessary events service component is missing")
+
+// replayBufferSize is how many of the most recent events are kept so clients
+// that (re)connect can catch up
+const replayBufferSize = 200
+
+// subscriberBufferSize is how many events can be waiting to be sent to a slow
+// client before the client is disconnected
+const subscriberBufferSize = 64
+
+// App interface is for connecting to the main app
+type App interface {
+	GetLogger() *zap.SugaredLogger
+	GetOutputter() *output.Service
+	GetShutdownContext() context.Context
+}
+
+// Events service struct
+type Service struct {
+	shutdownContext context.Context
+	logger          *zap.SugaredLogger
+	output          *output.Service
+	nextId          int
+	replay          []Event
+	subscribers     map[*subscriber]struct{}
+	mu              sync.RWMutex
+}
+
+// NewService creates a new events service
+func NewService(app App) (*Service, error) {
+	service := new(Service)
+
+	// logger
+	service.logger = app.GetLogger()
+	if service.logger == nil {
+		return nil, errServiceComponent
+	}
+
+	// output service
+	service.output = app.GetOutputter()
+	if service.output == nil {
+		return nil, errServiceComponent
+	}
+
+	// shutdown context
+	service.shutdownContext = app.GetShutdownContext()
+
+	// broker
+	service.nextId = 1
+	service.replay = make([]Event, 0, replayBufferSize)
+	service.subscribers = make(map[*subscriber]struct{})
+
+	return service, nil
+}
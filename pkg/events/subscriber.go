@@ -0,0 +1,46 @@
+package events
+
+// subscriber is a client receiving events. If certificateId is 0, events for all
+// certificates are sent.
+type subscriber struct {
+	certificateId int
+	events        chan Event
+}
+
+// wants returns true if the event should be sent to the subscriber
+func (sub *subscriber) wants(event Event) bool {
+	return sub.certificateId == 0 || sub.certificateId == event.CertificateID
+}
+
+// subscribe adds a new subscriber and returns it along with any buffered events
+// the subscriber wants that are newer than lastEventId
+func (service *Service) subscribe(certId int, lastEventId int) (sub *subscriber, replay []Event) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	sub = &subscriber{
+		certificateId: certId,
+		events:        make(chan Event, subscriberBufferSize),
+	}
+	service.subscribers[sub] = struct{}{}
+
+	for _, event := range service.replay {
+		if event.ID > lastEventId && sub.wants(event) {
+			replay = append(replay, event)
+		}
+	}
+
+	return sub, replay
+}
+
+// unsubscribe removes the subscriber (if it hasn't already been dropped)
+func (service *Service) unsubscribe(sub *subscriber) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	_, exists := service.subscribers[sub]
+	if exists {
+		delete(service.subscribers, sub)
+		close(sub.events)
+	}
+}
@@ -0,0 +1,278 @@
+package export
+
+import (
+	"bytes"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"legocerthub-backend/pkg/storage"
+	"legocerthub-backend/pkg/validation"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Cert is a certificate's current key and the pem chain of its newest valid order
+type Cert struct {
+	CertificateID   int
+	CertificateName string
+	KeyPem          string
+	CertPem         string
+}
+
+// exportFile is one of the files maintained for each certificate
+type exportFile struct {
+	base    string
+	content []byte
+}
+
+// versionRegex matches archived cert files to find the current version
+var versionRegex = regexp.MustCompile(`^cert(\d+)\.pem$`)
+
+// CertificateChanged exports the certificate's current key and newest valid
+// order, if they have changed since the last export.
+func (service *Service) CertificateChanged(certId int) {
+	// no-op if not enabled
+	if service == nil || !service.enabled {
+		return
+	}
+
+	cert, err := service.storage.GetCertExport(certId)
+	if err != nil {
+		// no valid order yet, nothing to export
+		if errors.Is(err, storage.ErrNoRecord) {
+			service.logger.Debugf("certificate %d not exported (no valid order)", certId)
+			return
+		}
+		service.logger.Errorf("failed to get certificate %d for export (%s)", certId, err)
+		return
+	}
+
+	service.export(cert)
+}
+
+// exportAll exports all certificates that have a valid order
+func (service *Service) exportAll() {
+	certs, err := service.storage.GetAllCertExports()
+	if err != nil {
+		service.logger.Errorf("failed to get certificates for export (%s)", err)
+		return
+	}
+
+	for _, cert := range certs {
+		service.export(cert)
+	}
+}
+
+// export writes the cert and logs the outcome
+func (service *Service) export(cert Cert) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	version, err := service.writeCert(cert)
+	if err != nil {
+		service.logger.Errorf("failed to export certificate %s (%s)", cert.CertificateName, err)
+		return
+	}
+
+	if version > 0 {
+		service.logger.Infof("exported certificate %s (version %d)", cert.CertificateName, version)
+	}
+}
+
+// writeCert writes new versions of the cert's files to archive/<name>/ and points
+// the symlinks in live/<name>/ at them. If the live files already have the same
+// content nothing is written and version 0 is returned.
+func (service *Service) writeCert(cert Cert) (version int, err error) {
+	// name is used as a directory
+	if !validation.NameValid(cert.CertificateName) || strings.Trim(cert.CertificateName, ".") == "" {
+		return 0, fmt.Errorf("name %s can't be used as a directory", cert.CertificateName)
+	}
+
+	files, err := exportFiles(cert)
+	if err != nil {
+		return 0, err
+	}
+
+	liveDir := filepath.Join(service.directory, "live", cert.CertificateName)
+	archiveDir := filepath.Join(service.directory, "archive", cert.CertificateName)
+	for _, dir := range []string{service.directory, filepath.Dir(liveDir), filepath.Dir(archiveDir), liveDir, archiveDir} {
+		err = service.makeDir(dir)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	// skip if unchanged
+	if liveFilesMatch(liveDir, files) {
+		return 0, nil
+	}
+
+	version, err = nextVersion(archiveDir)
+	if err != nil {
+		return 0, err
+	}
+
+	// archive files
+	for _, file := range files {
+		err = service.writeFileAtomic(filepath.Join(archiveDir, versionedName(file.base, version)), file.content)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	// swap live symlinks (relative, so the export directory can be moved or mounted)
+	for _, file := range files {
+		target := filepath.Join("..", "..", "archive", cert.CertificateName, versionedName(file.base, version))
+		err = replaceSymlink(target, filepath.Join(liveDir, file.base+".pem"))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return version, nil
+}
+
+// exportFiles splits the cert's pem chain into the files certbot provides (there is
+// no privkey file for certs defined by a csr)
+func exportFiles(cert Cert) ([]exportFile, error) {
+	leaf, rest := pem.Decode([]byte(cert.CertPem))
+	if leaf == nil {
+		return nil, errors.New("failed to decode certificate pem")
+	}
+	chain := strings.TrimSpace(string(rest))
+	if chain != "" {
+		chain += "\n"
+	}
+
+	files := []exportFile{
+		{base: "cert", content: pem.EncodeToMemory(leaf)},
+		{base: "chain", content: []byte(chain)},
+		{base: "fullchain", content: []byte(cert.CertPem)},
+	}
+
+	// certs defined by a csr have no key
+	if cert.KeyPem != "" {
+		files = append([]exportFile{{base: "privkey", content: []byte(cert.KeyPem)}}, files...)
+	}
+
+	return files, nil
+}
+
+// liveFilesMatch returns true if every live file exists and has the content
+func liveFilesMatch(liveDir string, files []exportFile) bool {
+	for _, file := range files {
+		content, err := os.ReadFile(filepath.Join(liveDir, file.base+".pem"))
+		if err != nil || !bytes.Equal(content, file.content) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// nextVersion returns one more than the highest version in the archive directory
+func nextVersion(archiveDir string) (int, error) {
+	entries, err := os.ReadDir(archiveDir)
+	if err != nil {
+		return 0, err
+	}
+
+	highest := 0
+	for _, entry := range entries {
+		match := versionRegex.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.Atoi(match[1])
+		if err == nil && version > highest {
+			highest = version
+		}
+	}
+
+	return highest + 1, nil
+}
+
+// versionedName returns the archive file name for base and version
+func versionedName(base string, version int) string {
+	return base + strconv.Itoa(version) + ".pem"
+}
+
+// makeDir creates dir if needed and sets its mode and ownership
+func (service *Service) makeDir(dir string) error {
+	err := os.MkdirAll(dir, service.dirMode)
+	if err != nil {
+		return err
+	}
+
+	err = os.Chmod(dir, service.dirMode)
+	if err != nil {
+		return err
+	}
+
+	return service.chown(dir)
+}
+
+// writeFileAtomic writes content to a temp file in the same directory and then
+// renames it to path, so readers never see a partial file
+func (service *Service) writeFileAtomic(path string, content []byte) (err error) {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	_, err = f.Write(content)
+	if err == nil {
+		err = f.Chmod(service.fileMode)
+	}
+	if err == nil {
+		err = service.chown(f.Name())
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	closeErr := f.Close()
+	if err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// replaceSymlink atomically replaces path with a symlink to target
+func replaceSymlink(target string, path string) error {
+	tmp := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	_ = os.Remove(tmp)
+
+	err := os.Symlink(target, tmp)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tmp, path)
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	return nil
+}
+
+// chown sets the configured ownership of path (if any)
+func (service *Service) chown(path string) error {
+	if service.uid == -1 && service.gid == -1 {
+		return nil
+	}
+
+	return os.Chown(path, service.uid, service.gid)
+}
@@ -0,0 +1,139 @@
+package export
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"legocerthub-backend/pkg/internal/certtest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// checkLiveFiles checks that the live files of the export are the key, leaf, and
+// issuer of the exported cert
+func checkLiveFiles(t *testing.T, liveDir string, cert Cert) {
+	// key and fullchain pair (read through the symlinks)
+	_, err := tls.LoadX509KeyPair(filepath.Join(liveDir, "fullchain.pem"), filepath.Join(liveDir, "privkey.pem"))
+	if err != nil {
+		t.Errorf("live fullchain.pem and privkey.pem are not a key pair (%s)", err)
+	}
+	_, err = tls.LoadX509KeyPair(filepath.Join(liveDir, "cert.pem"), filepath.Join(liveDir, "privkey.pem"))
+	if err != nil {
+		t.Errorf("live cert.pem and privkey.pem are not a key pair (%s)", err)
+	}
+
+	// cert is the exported leaf and chain is its issuer
+	leaf := readOneCert(t, filepath.Join(liveDir, "cert.pem"))
+	issuer := readOneCert(t, filepath.Join(liveDir, "chain.pem"))
+	block, _ := pem.Decode([]byte(cert.CertPem))
+	if block == nil || !bytes.Equal(leaf.Raw, block.Bytes) {
+		t.Error("live cert.pem is not the exported leaf")
+	}
+	if err = leaf.CheckSignatureFrom(issuer); err != nil {
+		t.Errorf("live chain.pem is not the issuer of cert.pem (%s)", err)
+	}
+}
+
+// readOneCert reads a pem file that must contain exactly one certificate
+func readOneCert(t *testing.T, path string) *x509.Certificate {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, rest := pem.Decode(content)
+	if block == nil || strings.Contains(string(rest), "BEGIN CERTIFICATE") {
+		t.Fatalf("%s does not contain exactly one certificate", filepath.Base(path))
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert
+}
+
+func TestExport_WriteCert(t *testing.T) {
+	service := &Service{
+		logger:    zap.NewNop().Sugar(),
+		enabled:   true,
+		directory: t.TempDir(),
+		uid:       -1,
+		gid:       -1,
+		fileMode:  0640,
+		dirMode:   dirModeFor(0640),
+	}
+	issued := certtest.IssueForNames(t, time.Hour, "my-cert.example.com")
+	cert := Cert{CertificateID: 1, CertificateName: "my-cert", KeyPem: issued.KeyPem, CertPem: issued.ChainPem}
+	liveDir := filepath.Join(service.directory, "live", "my-cert")
+
+	version, err := service.writeCert(cert)
+	if err != nil || version != 1 {
+		t.Fatalf("first export returned version %d, err %v (expected version 1)", version, err)
+	}
+
+	// live files are relative symlinks into the archive
+	target, err := os.Readlink(filepath.Join(liveDir, "privkey.pem"))
+	if err != nil || target != filepath.Join("..", "..", "archive", "my-cert", "privkey1.pem") {
+		t.Errorf("privkey.pem links to %s, err %v", target, err)
+	}
+	checkLiveFiles(t, liveDir, cert)
+	info, err := os.Stat(filepath.Join(liveDir, "privkey.pem"))
+	if err != nil || info.Mode().Perm() != 0640 {
+		t.Errorf("privkey.pem mode is %v, err %v (expected 0640)", info.Mode().Perm(), err)
+	}
+
+	// unchanged is not rewritten
+	version, err = service.writeCert(cert)
+	if err != nil || version != 0 {
+		t.Errorf("unchanged export returned version %d, err %v (expected 0)", version, err)
+	}
+
+	// renewal with a new key makes a new version and the old version stays in
+	// the archive
+	oldKeyPem := cert.KeyPem
+	renewed := certtest.IssueForNames(t, time.Hour, "my-cert.example.com")
+	cert.KeyPem, cert.CertPem = renewed.KeyPem, renewed.ChainPem
+	version, err = service.writeCert(cert)
+	if err != nil || version != 2 {
+		t.Fatalf("changed export returned version %d, err %v (expected version 2)", version, err)
+	}
+	target, err = os.Readlink(filepath.Join(liveDir, "privkey.pem"))
+	if err != nil || target != filepath.Join("..", "..", "archive", "my-cert", "privkey2.pem") {
+		t.Errorf("privkey.pem links to %s, err %v", target, err)
+	}
+	checkLiveFiles(t, liveDir, cert)
+	content, err := os.ReadFile(filepath.Join(service.directory, "archive", "my-cert", "privkey1.pem"))
+	if err != nil || string(content) != oldKeyPem {
+		t.Errorf("archived privkey1.pem changed, err %v", err)
+	}
+}
+
+func TestExport_WriteCertBadName(t *testing.T) {
+	service := &Service{directory: t.TempDir(), uid: -1, gid: -1, fileMode: 0600, dirMode: 0700}
+
+	issued := certtest.IssueForNames(t, time.Hour, "example.com")
+	_, err := service.writeCert(Cert{CertificateName: "..", KeyPem: issued.KeyPem, CertPem: issued.ChainPem})
+	if err == nil {
+		t.Error("expected error for name that isn't a safe directory")
+	}
+}
+
+func TestExport_DirModeFor(t *testing.T) {
+	tests := map[os.FileMode]os.FileMode{
+		0600: 0700,
+		0640: 0750,
+		0644: 0755,
+	}
+
+	for fileMode, expected := range tests {
+		if dirMode := dirModeFor(fileMode); dirMode != expected {
+			t.Errorf("dir mode for %o is %o (expected %o)", fileMode, dirMode, expected)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+var errServiceComponent = errors.New("necessary export service component is missing")
+
+// App interface is for connecting to the main app
+type App interface {
+	GetLogger() *zap.SugaredLogger
+	GetExportStorage() Storage
+}
+
+// Storage interface for storage functions
+type Storage interface {
+	GetCertExport(certId int) (cert Cert, err error)
+	GetAllCertExports() (certs []Cert, err error)
+}
+
+// Configuration options
+type Config struct {
+	Enable    *bool   `yaml:"enable"`
+	Directory *string `yaml:"directory"`
+	Owner     *string `yaml:"owner"`
+	Group     *string `yaml:"group"`
+	FileMode  *string `yaml:"file_mode"`
+}
+
+// Export service struct
+type Service struct {
+	logger    *zap.SugaredLogger
+	storage   Storage
+	enabled   bool
+	directory string
+	uid       int
+	gid       int
+	fileMode  os.FileMode
+	dirMode   os.FileMode
+
+	// only one export writes at a time
+	mu sync.Mutex
+}
+
+// NewService creates a new export service. If export is not enabled, the
+// service is still created but does not write anything.
+func NewService(app App, cfg *Config) (*Service, error) {
+	service := new(Service)
+	var err error
+
+	// logger
+	service.logger = app.GetLogger()
+	if service.logger == nil {
+		return nil, errServiceComponent
+	}
+
+	// storage
+	service.storage = app.GetExportStorage()
+	if service.storage == nil {
+		return nil, errServiceComponent
+	}
+
+	// dont configure further if not enabled
+	service.enabled = *cfg.Enable
+	if !service.enabled {
+		return service, nil
+	}
+
+	// directory
+	if *cfg.Directory == "" {
+		return nil, errors.New("export directory must be specified")
+	}
+	service.directory, err = filepath.Abs(*cfg.Directory)
+	if err != nil {
+		return nil, err
+	}
+
+	// ownership (-1 leaves it unchanged)
+	service.uid, err = lookupId(*cfg.Owner, func(name string) (string, error) {
+		u, err := user.Lookup(name)
+		if err != nil {
+			return "", err
+		}
+		return u.Uid, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("export owner invalid (%s)", err)
+	}
+	service.gid, err = lookupId(*cfg.Group, func(name string) (string, error) {
+		g, err := user.LookupGroup(name)
+		if err != nil {
+			return "", err
+		}
+		return g.Gid, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("export group invalid (%s)", err)
+	}
+
+	// modes
+	service.fileMode, err = parseFileMode(*cfg.FileMode)
+	if err != nil {
+		return nil, err
+	}
+	service.dirMode = dirModeFor(service.fileMode)
+
+	// export everything that is already issued
+	service.exportAll()
+
+	service.logger.Infof("certificate export enabled; writing to %s", service.directory)
+
+	return service, nil
+}
+
+// lookupId returns the numeric id for nameOrId. If nameOrId is not a number, it is
+// looked up with lookup. If nameOrId is blank, -1 is returned.
+func lookupId(nameOrId string, lookup func(name string) (string, error)) (int, error) {
+	if nameOrId == "" {
+		return -1, nil
+	}
+
+	id, err := strconv.Atoi(nameOrId)
+	if err == nil {
+		return id, nil
+	}
+
+	idString, err := lookup(nameOrId)
+	if err != nil {
+		return -1, err
+	}
+
+	return strconv.Atoi(idString)
+}
+
+// parseFileMode parses an octal permission string (e.g. 0640)
+func parseFileMode(mode string) (os.FileMode, error) {
+	parsed, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil || parsed > 0777 {
+		return 0, fmt.Errorf("export file_mode %s is invalid (must be octal permissions, e.g. 0600)", mode)
+	}
+
+	return os.FileMode(parsed), nil
+}
+
+// dirModeFor returns the file mode plus execute (search) permission for any
+// class that can read the files
+func dirModeFor(fileMode os.FileMode) os.FileMode {
+	dirMode := fileMode | 0700
+	for _, read := range []os.FileMode{0040, 0004} {
+		if fileMode&read != 0 {
+			dirMode |= read >> 2
+		}
+	}
+
+	return dirMode
+}
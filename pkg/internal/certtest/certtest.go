@@ -0,0 +1,113 @@
+// Package certtest makes certificates for tests
+package certtest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// IssuerName is the common name of the CA that issues test certs
+const IssuerName = "Test CA"
+
+// Cert is a test leaf certificate, its key, and the CA that issued it
+type Cert struct {
+	Leaf   *x509.Certificate
+	Key    *ecdsa.PrivateKey
+	Issuer *x509.Certificate
+
+	// ChainPem is the leaf followed by the issuer
+	ChainPem string
+	// KeyPem is the leaf's key (EC PRIVATE KEY)
+	KeyPem string
+}
+
+// Issue creates a new CA and uses it to issue a leaf certificate from template
+func Issue(t testing.TB, template x509.Certificate) Cert {
+	t.Helper()
+
+	caKey := newKey(t)
+	caTemplate := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: IssuerName},
+		NotBefore:             time.Unix(946684800, 0),  // 2000-01-01
+		NotAfter:              time.Unix(4102444800, 0), // 2100-01-01
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDer, err := x509.CreateCertificate(rand.Reader, &caTemplate, &caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := x509.ParseCertificate(caDer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leafKey := newKey(t)
+	leafDer, err := x509.CreateCertificate(rand.Reader, &template, ca, &leafKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(leafDer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(leafKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return Cert{
+		Leaf:   leaf,
+		Key:    leafKey,
+		Issuer: ca,
+		ChainPem: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafDer})) +
+			string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDer})),
+		KeyPem: string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})),
+	}
+}
+
+// IssueForNames issues a cert for the dns names that is valid from now until
+// validFor from now
+func IssueForNames(t testing.TB, validFor time.Duration, names ...string) Cert {
+	t.Helper()
+
+	return Issue(t, x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: names[0]},
+		DNSNames:     names,
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(validFor),
+	})
+}
+
+// TLSCertificate returns the cert (and its issuer) for serving
+func (cert Cert) TLSCertificate() tls.Certificate {
+	return tls.Certificate{
+		Certificate: [][]byte{cert.Leaf.Raw, cert.Issuer.Raw},
+		PrivateKey:  cert.Key,
+		Leaf:        cert.Leaf,
+	}
+}
+
+// newKey generates a P-256 key
+func newKey(t testing.TB) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
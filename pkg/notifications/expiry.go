@@ -0,0 +1,185 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// expiryCheckInterval is how often certificates are checked for upcoming
+// expiration
+const expiryCheckInterval = 1 * time.Hour
+
+// CertExpiration is a certificate and the valid_to (unix time) of its newest valid
+// order. ValidTo is nil if the certificate has no valid order. For an unmanaged
+// certificate ValidTo is the valid_to of its imported pem.
+type CertExpiration struct {
+	CertificateID      int
+	CertificateName    string
+	Unmanaged          bool
+	NotificationEmails []string
+	ValidTo            *int
+}
+
+// expirationKey identifies a certificate (managed and unmanaged certificate ids
+// overlap)
+type expirationKey struct {
+	unmanaged bool
+	id        int
+}
+
+// toExpiryData converts the expiration to template data
+func (expiration CertExpiration) toExpiryData(now time.Time) expiryData {
+	data := expiryData{
+		CertificateID:   expiration.CertificateID,
+		CertificateName: expiration.CertificateName,
+		Unmanaged:       expiration.Unmanaged,
+	}
+
+	if expiration.ValidTo != nil {
+		validTo := time.Unix(int64(*expiration.ValidTo), 0)
+		data.ValidTo = &validTo
+		data.DaysRemaining = int(validTo.Sub(now).Hours() / 24)
+	}
+
+	return data
+}
+
+// parseWeekday parses a day name (e.g. monday or mon)
+func parseWeekday(day string) (time.Weekday, error) {
+	day = strings.ToLower(strings.TrimSpace(day))
+	for i := time.Sunday; i <= time.Saturday; i++ {
+		name := strings.ToLower(i.String())
+		if day == name || day == name[:3] {
+			return i, nil
+		}
+	}
+
+	return time.Sunday, fmt.Errorf("notifications weekly_digest_day (%s) is not a valid day", day)
+}
+
+// nextDigest returns the next time the weekly digest should be sent after t
+func (service *Service) nextDigest(t time.Time) time.Time {
+	year, month, day := t.Date()
+	next := time.Date(year, month, day, service.digestHour, 0, 0, 0, t.Location())
+	next = next.AddDate(0, 0, int(service.digestDay-next.Weekday()+7)%7)
+
+	if !next.After(t) {
+		next = next.AddDate(0, 0, 7)
+	}
+
+	return next
+}
+
+// startExpiryService starts a go routine that sends warnings for certificates that
+// are close to expiring and haven't been renewed, and sends the weekly digest
+func (service *Service) startExpiryService(wg *sync.WaitGroup) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		// certs that were already warned about (cert -> valid_to)
+		warned := make(map[expirationKey]int)
+		nextDigest := service.nextDigest(time.Now())
+
+		for {
+			now := time.Now()
+
+			err := service.warnExpiring(now, warned)
+			if err != nil {
+				service.logger.Errorf("failed to check for expiring certificates (%s)", err)
+			}
+
+			if service.digestEnabled && !now.Before(nextDigest) {
+				err = service.sendDigest(now)
+				if err != nil {
+					service.logger.Errorf("failed to send weekly digest (%s)", err)
+				}
+				nextDigest = service.nextDigest(now)
+			}
+
+			// sleep until next check or digest
+			wake := now.Add(expiryCheckInterval)
+			if service.digestEnabled && nextDigest.Before(wake) {
+				wake = nextDigest
+			}
+
+			select {
+			case <-service.shutdownContext.Done():
+				service.logger.Info("notifications expiry service shutdown complete")
+				return
+
+			case <-time.After(time.Until(wake)):
+				// sleep until wake
+			}
+		}
+	}()
+}
+
+// expirations returns the expirations of all managed certificates followed by
+// all unmanaged certificates
+func (service *Service) expirations() ([]CertExpiration, error) {
+	expirations, err := service.storage.GetCertExpirations()
+	if err != nil {
+		return nil, err
+	}
+
+	unmanaged, err := service.storage.GetUnmanagedCertExpirations()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(expirations, unmanaged...), nil
+}
+
+// warnExpiring sends a warning for each certificate whose newest valid order (or
+// imported pem, if unmanaged) is within the warning threshold of expiring. Each
+// certificate is only warned about once for each valid_to.
+func (service *Service) warnExpiring(now time.Time, warned map[expirationKey]int) error {
+	expirations, err := service.expirations()
+	if err != nil {
+		return err
+	}
+
+	for _, expiration := range expirations {
+		if expiration.ValidTo == nil {
+			continue
+		}
+		key := expirationKey{unmanaged: expiration.Unmanaged, id: expiration.CertificateID}
+
+		// not expiring, forget any previous warning
+		if time.Unix(int64(*expiration.ValidTo), 0).Sub(now) > service.expiryWarning {
+			delete(warned, key)
+			continue
+		}
+
+		if warned[key] == *expiration.ValidTo {
+			continue
+		}
+		warned[key] = *expiration.ValidTo
+
+		service.enqueue(templateExpiryWarning, service.recipientsFor(expiration.NotificationEmails), expiration.toExpiryData(now))
+	}
+
+	return nil
+}
+
+// sendDigest sends the weekly digest of all certificates to the global recipients
+func (service *Service) sendDigest(now time.Time) error {
+	expirations, err := service.expirations()
+	if err != nil {
+		return err
+	}
+
+	data := digestData{
+		Certificates: []expiryData{},
+		Time:         now,
+	}
+	for _, expiration := range expirations {
+		data.Certificates = append(data.Certificates, expiration.toExpiryData(now))
+	}
+
+	service.enqueue(templateWeeklyDigest, service.recipients, data)
+	return nil
+}
@@ -0,0 +1,43 @@
+package notifications
+
+import (
+	"legocerthub-backend/pkg/output"
+	"net/http"
+	"time"
+)
+
+// SendTestNotification is an http handler that immediately sends a test notification
+// to the global recipients, so the smtp configuration can be confirmed
+func (service *Service) SendTestNotification(w http.ResponseWriter, r *http.Request) (err error) {
+	if !service.enabled {
+		service.logger.Debug(errNotEnabled)
+		return output.ErrBadRequest
+	}
+
+	subject, body, err := service.render(templateTest, testData{Time: time.Now()})
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrInternal
+	}
+
+	// send now (instead of queueing) so the result can be returned
+	err = service.smtp.send(message{to: service.recipients, subject: subject, body: body})
+	if err != nil {
+		service.logger.Errorf("failed to send test notification (%s)", err)
+		return output.ErrInternal
+	}
+
+	// return response to client
+	response := output.JsonResponse{
+		Status:  http.StatusOK,
+		Message: "test notification sent",
+	}
+
+	_, err = service.output.WriteJSON(w, response.Status, response, "response")
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrWriteJsonFailed
+	}
+
+	return nil
+}
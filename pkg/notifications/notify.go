@@ -0,0 +1,149 @@
+package notifications
+
+import (
+	"sync"
+	"time"
+)
+
+// message is a rendered notification
+type message struct {
+	to      []string
+	subject string
+	body    string
+}
+
+// Target is the certificate a notification is about and its recipients (in
+// addition to the global recipients)
+type Target struct {
+	CertificateID      int
+	CertificateName    string
+	NotificationEmails []string
+}
+
+// recipientsFor returns the global recipients plus any of the target's recipients
+// that aren't already included
+func (service *Service) recipientsFor(certEmails []string) []string {
+	to := append([]string{}, service.recipients...)
+	for _, email := range certEmails {
+		found := false
+		for i := range to {
+			if to[i] == email {
+				found = true
+				break
+			}
+		}
+		if !found {
+			to = append(to, email)
+		}
+	}
+
+	return to
+}
+
+// enqueue renders the template and queues the message for sending. If the queue
+// is full the message is dropped.
+func (service *Service) enqueue(templateName string, to []string, data interface{}) {
+	if len(to) == 0 {
+		service.logger.Debugf("notification %s not sent (no recipients)", templateName)
+		return
+	}
+
+	subject, body, err := service.render(templateName, data)
+	if err != nil {
+		service.logger.Errorf("failed to render notification %s (%s)", templateName, err)
+		return
+	}
+
+	select {
+	case service.queue <- message{to: to, subject: subject, body: body}:
+	default:
+		service.logger.Errorf("notification queue full, dropped notification: %s", subject)
+	}
+}
+
+// startSender starts a go routine that sends queued messages
+func (service *Service) startSender(wg *sync.WaitGroup) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for {
+			select {
+			case <-service.shutdownContext.Done():
+				service.logger.Info("notifications sender shutdown complete")
+				return
+
+			case msg := <-service.queue:
+				err := service.smtp.send(msg)
+				if err != nil {
+					service.logger.Errorf("failed to send notification '%s' (%s)", msg.subject, err)
+				} else {
+					service.logger.Debugf("sent notification '%s' to %d recipient(s)", msg.subject, len(msg.to))
+				}
+			}
+		}
+	}()
+}
+
+// notifyOrder sends an order notification using the specified template
+func (service *Service) notifyOrder(templateName string, orderId int, reason string) {
+	// no-op if not enabled
+	if service == nil || !service.enabled {
+		return
+	}
+
+	target, err := service.storage.GetOrderNotificationTarget(orderId)
+	if err != nil {
+		service.logger.Errorf("failed to get notification target for order %d (%s)", orderId, err)
+		return
+	}
+
+	service.enqueue(templateName, service.recipientsFor(target.NotificationEmails), orderData{
+		CertificateID:   target.CertificateID,
+		CertificateName: target.CertificateName,
+		OrderID:         orderId,
+		Reason:          reason,
+		Time:            time.Now(),
+	})
+}
+
+// OrderFailed sends a notification that the order failed
+func (service *Service) OrderFailed(orderId int, reason string) {
+	service.notifyOrder(templateOrderFailed, orderId, reason)
+}
+
+// OrderIssued sends a notification that the order's certificate was issued
+func (service *Service) OrderIssued(orderId int) {
+	service.notifyOrder(templateOrderIssued, orderId, "")
+}
+
+// OrderRevoked sends a notification that the order's certificate was revoked
+func (service *Service) OrderRevoked(orderId int) {
+	service.notifyOrder(templateOrderRevoked, orderId, "")
+}
+
+// DeployEndpointFlagged sends a notification that a certificate's deploy endpoint
+// is serving a stale or mismatched certificate
+func (service *Service) DeployEndpointFlagged(certId int, address string, serverName string, status string, servedSerial string, expectedSerial string) {
+	// no-op if not enabled
+	if service == nil || !service.enabled {
+		return
+	}
+
+	target, err := service.storage.GetCertNotificationTarget(certId)
+	if err != nil {
+		service.logger.Errorf("failed to get notification target for certificate %d (%s)", certId, err)
+		return
+	}
+
+	service.enqueue(templateEndpoint, service.recipientsFor(target.NotificationEmails), endpointData{
+		CertificateID:   target.CertificateID,
+		CertificateName: target.CertificateName,
+		Address:         address,
+		ServerName:      serverName,
+		Status:          status,
+		ServedSerial:    servedSerial,
+		ExpectedSerial:  expectedSerial,
+		Time:            time.Now(),
+	})
+}
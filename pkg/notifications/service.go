@@ -0,0 +1,138 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"legocerthub-backend/pkg/output"
+	"sync"
+	"text/template"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var errServiceComponent = errors.New("necessary notifications service component is missing")
+
+// queueSize is how many messages can be waiting to be sent before new messages
+// are dropped
+const queueSize = 100
+
+// App interface is for connecting to the main app
+type App interface {
+	GetLogger() *zap.SugaredLogger
+	GetOutputter() *output.Service
+	GetNotificationsStorage() Storage
+	GetShutdownContext() context.Context
+	GetShutdownWaitGroup() *sync.WaitGroup
+}
+
+// Storage interface for storage functions
+type Storage interface {
+	GetOrderNotificationTarget(orderId int) (target Target, err error)
+	GetCertNotificationTarget(certId int) (target Target, err error)
+	GetCertExpirations() (expirations []CertExpiration, err error)
+	GetUnmanagedCertExpirations() (expirations []CertExpiration, err error)
+}
+
+// Configuration options
+type Config struct {
+	Enable             *bool      `yaml:"enable"`
+	Smtp               SmtpConfig `yaml:"smtp"`
+	Recipients         []string   `yaml:"recipients"`
+	ExpiryWarningDays  *int       `yaml:"expiry_warning_days"`
+	WeeklyDigestEnable *bool      `yaml:"weekly_digest_enable"`
+	WeeklyDigestDay    *string    `yaml:"weekly_digest_day"`
+	WeeklyDigestHour   *int       `yaml:"weekly_digest_hour"`
+	TemplatesDirectory *string    `yaml:"templates_directory"`
+}
+
+// Notifications service struct
+type Service struct {
+	shutdownContext context.Context
+	logger          *zap.SugaredLogger
+	output          *output.Service
+	storage         Storage
+	enabled         bool
+	smtp            *smtpSender
+	recipients      []string
+	templates       map[string]*template.Template
+	queue           chan message
+
+	expiryWarning time.Duration
+	digestEnabled bool
+	digestDay     time.Weekday
+	digestHour    int
+}
+
+// NewService creates a new notifications service. If notifications are not
+// enabled, the service is still created but does not send anything.
+func NewService(app App, cfg *Config) (*Service, error) {
+	service := new(Service)
+	var err error
+
+	// logger
+	service.logger = app.GetLogger()
+	if service.logger == nil {
+		return nil, errServiceComponent
+	}
+
+	// output service
+	service.output = app.GetOutputter()
+	if service.output == nil {
+		return nil, errServiceComponent
+	}
+
+	// storage
+	service.storage = app.GetNotificationsStorage()
+	if service.storage == nil {
+		return nil, errServiceComponent
+	}
+
+	// shutdown context
+	service.shutdownContext = app.GetShutdownContext()
+
+	// dont configure further if not enabled
+	service.enabled = *cfg.Enable
+	if !service.enabled {
+		return service, nil
+	}
+
+	// smtp
+	service.smtp, err = newSmtpSender(cfg.Smtp)
+	if err != nil {
+		return nil, err
+	}
+
+	// global recipients
+	service.recipients, err = parseRecipients(cfg.Recipients)
+	if err != nil {
+		return nil, err
+	}
+
+	// templates (defaults, optionally overridden by files)
+	service.templates, err = loadTemplates(*cfg.TemplatesDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	// expiry warnings and digest
+	service.expiryWarning = time.Duration(*cfg.ExpiryWarningDays) * 24 * time.Hour
+	service.digestEnabled = *cfg.WeeklyDigestEnable
+	service.digestDay, err = parseWeekday(*cfg.WeeklyDigestDay)
+	if err != nil {
+		return nil, err
+	}
+	service.digestHour = *cfg.WeeklyDigestHour
+	if service.digestHour < 0 || service.digestHour > 23 {
+		return nil, errors.New("notifications weekly_digest_hour must be 0 - 23")
+	}
+
+	// start sending and checking
+	service.queue = make(chan message, queueSize)
+	service.startSender(app.GetShutdownWaitGroup())
+	service.startExpiryService(app.GetShutdownWaitGroup())
+
+	service.logger.Infof("notifications enabled; sending via %s", service.smtp.address())
+
+	return service, nil
+}
@@ -0,0 +1,213 @@
+package notifications
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"legocerthub-backend/pkg/validation"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// smtpTimeout is the maximum time to connect to and send a message to the smtp
+// server
+const smtpTimeout = 30 * time.Second
+
+// smtp connection security options
+const (
+	smtpSecurityStartTls = "starttls"
+	smtpSecurityTls      = "tls"
+	smtpSecurityNone     = "none"
+)
+
+var (
+	errSmtpConfigBad   = errors.New("notifications smtp config is invalid")
+	errSmtpNoStartTls  = errors.New("smtp server does not support STARTTLS")
+	errSmtpNoAuth      = errors.New("smtp server does not support AUTH")
+	errRecipientBad    = errors.New("notification recipient email is invalid")
+	errNoRecipients    = errors.New("notification has no recipients")
+	errNotEnabled      = errors.New("notifications are not enabled")
+	errMessageTemplate = errors.New("notification template failed")
+)
+
+// SmtpConfig is the smtp server used to send notifications. Security is one of
+// starttls, tls (implicit), or none.
+type SmtpConfig struct {
+	Host     *string `yaml:"host"`
+	Port     *int    `yaml:"port"`
+	Security *string `yaml:"security"`
+	Username *string `yaml:"username"`
+	Password *string `yaml:"password"`
+	From     *string `yaml:"from"`
+}
+
+// smtpSender sends messages to an smtp server
+type smtpSender struct {
+	host     string
+	port     int
+	security string
+	username string
+	password string
+	from     string
+
+	// tlsConfig is only overridden for testing
+	tlsConfig *tls.Config
+}
+
+// newSmtpSender validates the smtp config and returns a sender
+func newSmtpSender(cfg SmtpConfig) (*smtpSender, error) {
+	sender := &smtpSender{
+		host:     *cfg.Host,
+		port:     *cfg.Port,
+		security: strings.ToLower(*cfg.Security),
+		username: *cfg.Username,
+		password: *cfg.Password,
+		from:     *cfg.From,
+	}
+
+	if sender.host == "" {
+		return nil, fmt.Errorf("%w (host missing)", errSmtpConfigBad)
+	}
+	if sender.port < 1 || sender.port > 65535 {
+		return nil, fmt.Errorf("%w (port %d)", errSmtpConfigBad, sender.port)
+	}
+	switch sender.security {
+	case smtpSecurityStartTls, smtpSecurityTls, smtpSecurityNone:
+	default:
+		return nil, fmt.Errorf("%w (unknown security %s)", errSmtpConfigBad, sender.security)
+	}
+	if !validation.EmailValid(sender.from) {
+		return nil, fmt.Errorf("%w (from %s)", errSmtpConfigBad, sender.from)
+	}
+
+	sender.tlsConfig = &tls.Config{ServerName: sender.host}
+
+	return sender, nil
+}
+
+// address returns the smtp server's address
+func (sender *smtpSender) address() string {
+	return net.JoinHostPort(sender.host, strconv.Itoa(sender.port))
+}
+
+// send connects to the smtp server and sends the message
+func (sender *smtpSender) send(msg message) (err error) {
+	if len(msg.to) == 0 {
+		return errNoRecipients
+	}
+
+	// connect (implicit tls or plain)
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+	var conn net.Conn
+	if sender.security == smtpSecurityTls {
+		conn, err = tls.DialWithDialer(dialer, "tcp", sender.address(), sender.tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", sender.address())
+	}
+	if err != nil {
+		return err
+	}
+	err = conn.SetDeadline(time.Now().Add(smtpTimeout))
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, sender.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	// upgrade connection
+	if sender.security == smtpSecurityStartTls {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errSmtpNoStartTls
+		}
+		err = client.StartTLS(sender.tlsConfig)
+		if err != nil {
+			return err
+		}
+	}
+
+	// auth (if configured)
+	if sender.username != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errSmtpNoAuth
+		}
+		err = client.Auth(smtp.PlainAuth("", sender.username, sender.password, sender.host))
+		if err != nil {
+			return err
+		}
+	}
+
+	// envelope
+	err = client.Mail(sender.from)
+	if err != nil {
+		return err
+	}
+	for _, to := range msg.to {
+		err = client.Rcpt(to)
+		if err != nil {
+			return err
+		}
+	}
+
+	// content
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(sender.format(msg))
+	if err != nil {
+		return err
+	}
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// format returns the message with headers, ready to send
+func (sender *smtpSender) format(msg message) []byte {
+	buf := new(bytes.Buffer)
+
+	// headers
+	fmt.Fprintf(buf, "From: %s\r\n", sender.from)
+	fmt.Fprintf(buf, "To: %s\r\n", strings.Join(msg.to, ", "))
+	fmt.Fprintf(buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.subject))
+	fmt.Fprintf(buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+	buf.WriteString("\r\n")
+
+	// body
+	qp := quotedprintable.NewWriter(buf)
+	_, _ = qp.Write([]byte(strings.ReplaceAll(msg.body, "\n", "\r\n")))
+	_ = qp.Close()
+
+	return buf.Bytes()
+}
+
+// parseRecipients validates a list of recipient emails
+func parseRecipients(emails []string) ([]string, error) {
+	recipients := []string{}
+	for _, email := range emails {
+		if !validation.EmailValid(email) {
+			return nil, fmt.Errorf("%w (%s)", errRecipientBad, email)
+		}
+		recipients = append(recipients, email)
+	}
+
+	return recipients, nil
+}
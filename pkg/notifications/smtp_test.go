@@ -0,0 +1,177 @@
+package notifications
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpSink is a minimal local smtp server that records what it receives
+type smtpSink struct {
+	listener net.Listener
+	rcpts    []string
+	data     string
+	done     chan struct{}
+}
+
+func newSmtpSink(t *testing.T) *smtpSink {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := &smtpSink{listener: listener, done: make(chan struct{})}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		defer close(sink.done)
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		write := func(s string) { _, _ = conn.Write([]byte(s + "\r\n")) }
+		write("220 sink ready")
+
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"):
+				write("250-sink")
+				write("250 8BITMIME")
+			case strings.HasPrefix(cmd, "MAIL FROM"):
+				write("250 ok")
+			case strings.HasPrefix(cmd, "RCPT TO"):
+				sink.rcpts = append(sink.rcpts, strings.TrimSpace(line[len("RCPT TO:"):]))
+				write("250 ok")
+			case cmd == "DATA":
+				write("354 go ahead")
+				data := new(strings.Builder)
+				for {
+					dataLine, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if dataLine == ".\r\n" {
+						break
+					}
+					data.WriteString(dataLine)
+				}
+				sink.data = data.String()
+				write("250 ok")
+			case cmd == "QUIT":
+				write("221 bye")
+				return
+			default:
+				write("502 not implemented")
+			}
+		}
+	}()
+
+	return sink
+}
+
+func (sink *smtpSink) sender(security string) *smtpSender {
+	addr := sink.listener.Addr().(*net.TCPAddr)
+	host := "127.0.0.1"
+	port := addr.Port
+	empty := ""
+	from := "lego@example.com"
+
+	sender, err := newSmtpSender(SmtpConfig{
+		Host:     &host,
+		Port:     &port,
+		Security: &security,
+		Username: &empty,
+		Password: &empty,
+		From:     &from,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return sender
+}
+
+func TestSmtp_SendToSink(t *testing.T) {
+	sink := newSmtpSink(t)
+
+	templates, err := loadTemplates("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := &Service{templates: templates}
+	subject, body, err := service.render(templateOrderFailed, orderData{
+		CertificateID:   4,
+		CertificateName: "my-cert",
+		OrderID:         12,
+		Reason:          "dns record did not propagate",
+		Time:            time.Now(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = sink.sender(smtpSecurityNone).send(message{
+		to:      []string{"admin@example.com", "owner@example.com"},
+		subject: subject,
+		body:    body,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-sink.done
+
+	if len(sink.rcpts) != 2 || sink.rcpts[0] != "<admin@example.com>" {
+		t.Errorf("sink recipients are %v (expected admin and owner)", sink.rcpts)
+	}
+	if !strings.Contains(sink.data, "Subject: [LeGo] Order failed for my-cert\r\n") {
+		t.Errorf("sink data missing subject:\n%s", sink.data)
+	}
+	if !strings.Contains(sink.data, "Reason: dns record did not propagate") {
+		t.Errorf("sink data missing reason:\n%s", sink.data)
+	}
+}
+
+func TestSmtp_StartTlsRequired(t *testing.T) {
+	sink := newSmtpSink(t)
+
+	err := sink.sender(smtpSecurityStartTls).send(message{
+		to:      []string{"admin@example.com"},
+		subject: "test",
+		body:    "test",
+	})
+	if !errors.Is(err, errSmtpNoStartTls) {
+		t.Errorf("send error is %v (expected %v)", err, errSmtpNoStartTls)
+	}
+}
+
+func TestService_nextDigest(t *testing.T) {
+	service := &Service{digestDay: time.Monday, digestHour: 8}
+
+	// 2024-01-01 is a Monday
+	tests := []struct {
+		t        time.Time
+		expected time.Time
+	}{
+		{time.Date(2024, 1, 1, 7, 0, 0, 0, time.UTC), time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC), time.Date(2024, 1, 8, 8, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC), time.Date(2024, 1, 8, 8, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		next := service.nextDigest(test.t)
+		if !next.Equal(test.expected) {
+			t.Errorf("next digest after %s is %s (expected %s)", test.t, next, test.expected)
+		}
+	}
+}
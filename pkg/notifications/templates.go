@@ -0,0 +1,180 @@
+package notifications
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"text/template"
+	"time"
+)
+
+// template names, a file named <name>.tmpl in the templates directory replaces
+// the default template. each template must define "subject" and "body".
+const (
+	templateOrderFailed   = "order_failed"
+	templateOrderIssued   = "order_issued"
+	templateOrderRevoked  = "order_revoked"
+	templateExpiryWarning = "expiry_warning"
+	templateWeeklyDigest  = "weekly_digest"
+	templateEndpoint      = "deploy_endpoint_flagged"
+	templateTest          = "test"
+)
+
+// defaultTemplates are used unless a template file overrides them
+var defaultTemplates = map[string]string{
+	templateOrderFailed: `{{define "subject"}}[LeGo] Order failed for {{.CertificateName}}{{end}}
+{{- define "body"}}The order {{.OrderID}} for certificate {{.CertificateName}} (id {{.CertificateID}}) failed.
+
+Reason: {{.Reason}}
+
+Time: {{.Time.Format "2006-01-02 15:04:05 MST"}}
+{{end}}`,
+
+	templateOrderIssued: `{{define "subject"}}[LeGo] Certificate issued for {{.CertificateName}}{{end}}
+{{- define "body"}}A new certificate was issued for {{.CertificateName}} (id {{.CertificateID}}) by order {{.OrderID}}.
+
+Time: {{.Time.Format "2006-01-02 15:04:05 MST"}}
+{{end}}`,
+
+	templateOrderRevoked: `{{define "subject"}}[LeGo] Certificate revoked for {{.CertificateName}}{{end}}
+{{- define "body"}}The certificate from order {{.OrderID}} for {{.CertificateName}} (id {{.CertificateID}}) was revoked.
+
+Time: {{.Time.Format "2006-01-02 15:04:05 MST"}}
+{{end}}`,
+
+	templateExpiryWarning: `{{define "subject"}}[LeGo] {{.CertificateName}} expires in {{.DaysRemaining}} day(s){{end}}
+{{- define "body"}}{{if .Unmanaged -}}
+The unmanaged certificate {{.CertificateName}} (id {{.CertificateID}}) expires on {{.ValidTo.Format "2006-01-02 15:04:05 MST"}} ({{.DaysRemaining}} day(s) remaining). It is not managed by LeGo and must be renewed where it was issued.
+{{- else -}}
+The certificate {{.CertificateName}} (id {{.CertificateID}}) has not been renewed and expires on {{.ValidTo.Format "2006-01-02 15:04:05 MST"}} ({{.DaysRemaining}} day(s) remaining).
+{{- end}}
+{{end}}`,
+
+	templateWeeklyDigest: `{{define "subject"}}[LeGo] Weekly certificate digest{{end}}
+{{- define "body"}}Certificates as of {{.Time.Format "2006-01-02 15:04:05 MST"}}:
+{{range .Certificates}}
+- {{.CertificateName}} ({{if .Unmanaged}}unmanaged, {{end}}id {{.CertificateID}}): {{if .ValidTo}}expires {{.ValidTo.Format "2006-01-02"}} ({{.DaysRemaining}} day(s)){{else}}no valid certificate{{end}}
+{{- else}}
+No certificates.
+{{- end}}
+{{end}}`,
+
+	templateEndpoint: `{{define "subject"}}[LeGo] {{.CertificateName}} is {{.Status}} on {{.Address}}{{end}}
+{{- define "body"}}The endpoint {{.Address}}{{if .ServerName}} (SNI {{.ServerName}}){{end}} for certificate {{.CertificateName}} (id {{.CertificateID}}) is {{.Status}}.
+
+{{if eq .Status "stale" -}}
+It is serving an older certificate; the newest certificate was probably not deployed (or the server was not reloaded).
+{{- else -}}
+It is serving a certificate that is not this certificate.
+{{- end}}
+
+Served serial: {{.ServedSerial}}
+Expected serial: {{.ExpectedSerial}}
+
+Time: {{.Time.Format "2006-01-02 15:04:05 MST"}}
+{{end}}`,
+
+	templateTest: `{{define "subject"}}[LeGo] Test notification{{end}}
+{{- define "body"}}This is a test notification sent at {{.Time.Format "2006-01-02 15:04:05 MST"}}.
+{{end}}`,
+}
+
+// orderData is the template data for order notifications
+type orderData struct {
+	CertificateID   int
+	CertificateName string
+	OrderID         int
+	Reason          string
+	Time            time.Time
+}
+
+// expiryData is the template data for a certificate's expiration
+type expiryData struct {
+	CertificateID   int
+	CertificateName string
+	Unmanaged       bool
+	ValidTo         *time.Time
+	DaysRemaining   int
+}
+
+// digestData is the template data for the weekly digest
+type digestData struct {
+	Certificates []expiryData
+	Time         time.Time
+}
+
+// endpointData is the template data for a flagged deploy endpoint
+type endpointData struct {
+	CertificateID   int
+	CertificateName string
+	Address         string
+	ServerName      string
+	Status          string
+	ServedSerial    string
+	ExpectedSerial  string
+	Time            time.Time
+}
+
+// testData is the template data for a test notification
+type testData struct {
+	Time time.Time
+}
+
+// loadTemplates parses the default templates and any overrides in directory
+// (if directory is not blank)
+func loadTemplates(directory string) (map[string]*template.Template, error) {
+	templates := make(map[string]*template.Template)
+
+	for name, text := range defaultTemplates {
+		// override from file
+		if directory != "" {
+			content, err := os.ReadFile(filepath.Join(directory, name+".tmpl"))
+			if err == nil {
+				text = string(content)
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				return nil, err
+			}
+		}
+
+		tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("%w (%s: %s)", errMessageTemplate, name, err)
+		}
+		for _, part := range []string{"subject", "body"} {
+			if tmpl.Lookup(part) == nil {
+				return nil, fmt.Errorf("%w (%s: does not define %s)", errMessageTemplate, name, part)
+			}
+		}
+
+		templates[name] = tmpl
+	}
+
+	return templates, nil
+}
+
+// render executes the named template's subject and body with data
+func (service *Service) render(name string, data interface{}) (subject string, body string, err error) {
+	tmpl, ok := service.templates[name]
+	if !ok {
+		return "", "", fmt.Errorf("%w (%s: not found)", errMessageTemplate, name)
+	}
+
+	buf := new(bytes.Buffer)
+	err = tmpl.ExecuteTemplate(buf, "subject", data)
+	if err != nil {
+		return "", "", err
+	}
+	subject = buf.String()
+
+	buf.Reset()
+	err = tmpl.ExecuteTemplate(buf, "body", data)
+	if err != nil {
+		return "", "", err
+	}
+	body = buf.String()
+
+	return subject, body, nil
+}
@@ -20,8 +20,9 @@ var (
 	ErrWriteZipFailed  = Error{Status: 500, Message: "zip write failed"}
 
 	// validation
-	ErrValidationFailed = Error{Status: 400, Message: "request validation (param or payload) invalid"}
-	ErrBadDirectoryURL  = Error{Status: 400, Message: "specified acme directory url is not https or did not return a valid directory json response"}
+	ErrValidationFailed  = Error{Status: 400, Message: "request validation (param or payload) invalid"}
+	ErrBadDirectoryURL   = Error{Status: 400, Message: "specified acme directory url is not https or did not return a valid directory json response"}
+	ErrBadProviderConfig = Error{Status: 400, Message: "challenge provider could not be configured using the specified config"}
 
 	// order
 	ErrOrderInvalid     = Error{Status: 400, Message: "order status is invalid (which cannot be recovered from)"}
@@ -1,17 +1,23 @@
 package sqlite
 
-import "legocerthub-backend/pkg/domain/acme_servers"
+import (
+	"legocerthub-backend/pkg/acme"
+	"legocerthub-backend/pkg/domain/acme_servers"
+)
 
 // acmeServerDb is a single acme server, as database table fields
 // corresponds to acme_servers.Server
 type acmeServerDb struct {
-	id           int
-	name         string
-	description  string
-	directoryUrl string
-	isStaging    bool
-	createdAt    int
-	updatedAt    int
+	id                  int
+	name                string
+	description         string
+	directoryUrl        string
+	isStaging           bool
+	maxConcurrentOrders int
+	requestsPerSecond   float64
+	requestBurst        int
+	createdAt           int
+	updatedAt           int
 }
 
 // toServer maps the database acme server info to the acme_servers
@@ -23,7 +29,12 @@ func (serv acmeServerDb) toServer() acme_servers.Server {
 		Description:  serv.description,
 		DirectoryURL: serv.directoryUrl,
 		IsStaging:    serv.isStaging,
-		CreatedAt:    serv.createdAt,
-		UpdatedAt:    serv.updatedAt,
+		Limits: acme.Limits{
+			MaxConcurrentOrders: serv.maxConcurrentOrders,
+			RequestsPerSecond:   serv.requestsPerSecond,
+			RequestBurst:        serv.requestBurst,
+		},
+		CreatedAt: serv.createdAt,
+		UpdatedAt: serv.updatedAt,
 	}
 }
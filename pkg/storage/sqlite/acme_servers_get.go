@@ -39,7 +39,8 @@ func (store *Storage) GetAllAcmeServers(q pagination_sort.Query) (accounts []acm
 	// validated prior to this query being assembled!
 	query := fmt.Sprintf(`
 	SELECT
-		aserv.id, aserv.name, aserv.description, aserv.directory_url, aserv.is_staging, aserv.created_at,
+		aserv.id, aserv.name, aserv.description, aserv.directory_url, aserv.is_staging,
+		aserv.max_concurrent_orders, aserv.requests_per_second, aserv.request_burst, aserv.created_at,
 		aserv.updated_at,
 
 		count(*) OVER() AS full_count
@@ -74,6 +75,9 @@ func (store *Storage) GetAllAcmeServers(q pagination_sort.Query) (accounts []acm
 			&oneServer.description,
 			&oneServer.directoryUrl,
 			&oneServer.isStaging,
+			&oneServer.maxConcurrentOrders,
+			&oneServer.requestsPerSecond,
+			&oneServer.requestBurst,
 			&oneServer.createdAt,
 			&oneServer.updatedAt,
 
@@ -107,7 +111,8 @@ func (store Storage) dbGetOneServer(acmeServerId int, name string) (acme_servers
 
 	query := `
 	SELECT
-		aserv.id, aserv.name, aserv.description, aserv.directory_url, aserv.is_staging, aserv.created_at,
+		aserv.id, aserv.name, aserv.description, aserv.directory_url, aserv.is_staging,
+		aserv.max_concurrent_orders, aserv.requests_per_second, aserv.request_burst, aserv.created_at,
 		aserv.updated_at
 	FROM
 		acme_servers aserv
@@ -126,6 +131,9 @@ func (store Storage) dbGetOneServer(acmeServerId int, name string) (acme_servers
 		&oneServerDb.description,
 		&oneServerDb.directoryUrl,
 		&oneServerDb.isStaging,
+		&oneServerDb.maxConcurrentOrders,
+		&oneServerDb.requestsPerSecond,
+		&oneServerDb.requestBurst,
 		&oneServerDb.createdAt,
 		&oneServerDb.updatedAt,
 	)
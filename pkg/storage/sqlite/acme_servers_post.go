@@ -12,8 +12,9 @@ func (store *Storage) PostNewServer(payload acme_servers.NewPayload) (acmeServer
 	defer cancel()
 
 	query := `
-	INSERT INTO acme_servers (name, description, directory_url, is_staging, created_at, updated_at)
-	VALUES ($1, $2, $3, $4, $5, $6)
+	INSERT INTO acme_servers (name, description, directory_url, is_staging, max_concurrent_orders,
+		requests_per_second, request_burst, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	RETURNING id
 	`
 
@@ -23,6 +24,9 @@ func (store *Storage) PostNewServer(payload acme_servers.NewPayload) (acmeServer
 		payload.Description,
 		payload.DirectoryURL,
 		payload.IsStaging,
+		payload.MaxConcurrentOrders,
+		payload.RequestsPerSecond,
+		payload.RequestBurst,
 		payload.CreatedAt,
 		payload.UpdatedAt,
 	).Scan(&acmeServerId)
@@ -19,9 +19,12 @@ func (store *Storage) PutServerUpdate(payload acme_servers.UpdatePayload) (err e
 		description = case when $2 is null then description else $2 end,
 		directory_url = case when $3 is null then directory_url else $3 end,
 		is_staging = case when $4 is null then is_staging else $4 end,
-		updated_at = $5
+		max_concurrent_orders = case when $5 is null then max_concurrent_orders else $5 end,
+		requests_per_second = case when $6 is null then requests_per_second else $6 end,
+		request_burst = case when $7 is null then request_burst else $7 end,
+		updated_at = $8
 	WHERE
-		id = $6
+		id = $9
 	`
 
 	_, err = store.db.ExecContext(ctx, query,
@@ -29,6 +32,9 @@ func (store *Storage) PutServerUpdate(payload acme_servers.UpdatePayload) (err e
 		payload.Description,
 		payload.DirectoryURL,
 		payload.IsStaging,
+		payload.MaxConcurrentOrders,
+		payload.RequestsPerSecond,
+		payload.RequestBurst,
 		payload.UpdatedAt,
 		payload.ID,
 	)
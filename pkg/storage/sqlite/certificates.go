@@ -1,8 +1,11 @@
 package sqlite
 
 import (
+	"database/sql"
+	"encoding/json"
 	"legocerthub-backend/pkg/challenges"
 	"legocerthub-backend/pkg/domain/certificates"
+	"legocerthub-backend/pkg/domain/private_keys"
 )
 
 // certificateDb is a single certificate, as database table fields
@@ -26,14 +29,28 @@ type certificateDb struct {
 	apiKey               string
 	apiKeyNew            string
 	apiKeyViaUrl         bool
+	http01SelfCheck      bool
+	renewalDays          sql.NullInt32
+	renewalFraction      sql.NullFloat64
+	notificationEmails   commaJoinedStrings
+	deployHooks          string // stored as json array
+	rotateKey            bool
+	rotateKeyAlgorithm   string
+	csrPem               sql.NullString
 }
 
 func (cert certificateDb) toCertificate(store *Storage) certificates.Certificate {
+	// certs defined by a csr don't have a key (COALESCE id is -2)
+	var certKey private_keys.Key
+	if cert.certificateKeyDb.id >= 0 {
+		certKey = cert.certificateKeyDb.toKey()
+	}
+
 	return certificates.Certificate{
 		ID:                 cert.id,
 		Name:               cert.name,
 		Description:        cert.description,
-		CertificateKey:     cert.certificateKeyDb.toKey(),
+		CertificateKey:     certKey,
 		CertificateAccount: cert.certificateAccountDb.toAccount(),
 		Subject:            cert.subject,
 		SubjectAltNames:    cert.subjectAltNames.toSlice(),
@@ -48,5 +65,31 @@ func (cert certificateDb) toCertificate(store *Storage) certificates.Certificate
 		ApiKey:             cert.apiKey,
 		ApiKeyNew:          cert.apiKeyNew,
 		ApiKeyViaUrl:       cert.apiKeyViaUrl,
+		Http01SelfCheck:    cert.http01SelfCheck,
+		RenewalPolicy: certificates.RenewalPolicy{
+			DaysRemaining:    nullInt32ToInt(cert.renewalDays),
+			LifetimeFraction: nullFloat64ToFloat64(cert.renewalFraction),
+		},
+		NotificationEmails: cert.notificationEmails.toSlice(),
+		DeployHooks:        certificates.DeployHooksFromJson(cert.deployHooks),
+		KeyRotation: certificates.KeyRotation{
+			Enable:         cert.rotateKey,
+			AlgorithmValue: cert.rotateKeyAlgorithm,
+		},
+		CsrPem: cert.csrPem.String,
+	}
+}
+
+// makeDeployHooksJson converts deploy hooks into the json array stored in the db
+func makeDeployHooksJson(hooks []certificates.DeployHook) (string, error) {
+	if hooks == nil {
+		hooks = []certificates.DeployHook{}
+	}
+
+	hooksJson, err := json.Marshal(hooks)
+	if err != nil {
+		return "", err
 	}
+
+	return string(hooksJson), nil
 }
@@ -46,10 +46,13 @@ func (store *Storage) GetAllCerts(q pagination_sort.Query) (certs []certificates
 	SELECT 
 		c.id, c.name, c.description, c.subject, c.subject_alts, c.challenge_method, 
 		c.csr_org, c.csr_ou, c.csr_country, c.csr_state, c.csr_city, c.created_at, c.updated_at,
-		c.api_key, c.api_key_new, c.api_key_via_url,
+		c.api_key, c.api_key_new, c.api_key_via_url, c.http01_self_check, c.renewal_days_remaining,
+		c.renewal_lifetime_fraction, c.notification_emails, c.deploy_hooks, c.rotate_key, c.rotate_key_algorithm, c.csr_pem,
 		
-		pk.id, pk.name, pk.description, pk.algorithm, pk.pem, pk.api_key, pk.api_key_new,
-		pk.api_key_disabled, pk.api_key_via_url, pk.created_at, pk.updated_at,
+		COALESCE(pk.id, -2), COALESCE(pk.name, 'null'), COALESCE(pk.description, 'null'),
+		COALESCE(pk.algorithm, 'null'), COALESCE(pk.pem, 'null'), COALESCE(pk.api_key, 'null'),
+		COALESCE(pk.api_key_new, 'null'), COALESCE(pk.api_key_disabled, false),
+		COALESCE(pk.api_key_via_url, false), COALESCE(pk.created_at, -2), COALESCE(pk.updated_at, -2),
 
 		aa.id, aa.name, aa.description, aa.status, aa.email, aa.accepted_tos,
 		aa.created_at, aa.updated_at, aa.kid,
@@ -107,6 +110,14 @@ func (store *Storage) GetAllCerts(q pagination_sort.Query) (certs []certificates
 			&oneCert.apiKey,
 			&oneCert.apiKeyNew,
 			&oneCert.apiKeyViaUrl,
+			&oneCert.http01SelfCheck,
+			&oneCert.renewalDays,
+			&oneCert.renewalFraction,
+			&oneCert.notificationEmails,
+			&oneCert.deployHooks,
+			&oneCert.rotateKey,
+			&oneCert.rotateKeyAlgorithm,
+			&oneCert.csrPem,
 
 			&oneCert.certificateKeyDb.id,
 			&oneCert.certificateKeyDb.name,
@@ -182,10 +193,13 @@ func (store *Storage) getOneCert(id int, name string) (cert certificates.Certifi
 	SELECT
 		c.id, c.name, c.description, c.subject, c.subject_alts, c.challenge_method, 
 		c.csr_org, c.csr_ou, c.csr_country, c.csr_state, c.csr_city, c.created_at, c.updated_at,
-		c.api_key, c.api_key_new, c.api_key_via_url,
+		c.api_key, c.api_key_new, c.api_key_via_url, c.http01_self_check, c.renewal_days_remaining,
+		c.renewal_lifetime_fraction, c.notification_emails, c.deploy_hooks, c.rotate_key, c.rotate_key_algorithm, c.csr_pem,
 		
-		pk.id, pk.name, pk.description, pk.algorithm, pk.pem, pk.api_key, pk.api_key_new,
-		pk.api_key_disabled, pk.api_key_via_url, pk.created_at, pk.updated_at,
+		COALESCE(pk.id, -2), COALESCE(pk.name, 'null'), COALESCE(pk.description, 'null'),
+		COALESCE(pk.algorithm, 'null'), COALESCE(pk.pem, 'null'), COALESCE(pk.api_key, 'null'),
+		COALESCE(pk.api_key_new, 'null'), COALESCE(pk.api_key_disabled, false),
+		COALESCE(pk.api_key_via_url, false), COALESCE(pk.created_at, -2), COALESCE(pk.updated_at, -2),
 
 		aa.id, aa.name, aa.description, aa.status, aa.email, aa.accepted_tos,
 		aa.created_at, aa.updated_at, aa.kid,
@@ -227,6 +241,14 @@ func (store *Storage) getOneCert(id int, name string) (cert certificates.Certifi
 		&oneCert.apiKey,
 		&oneCert.apiKeyNew,
 		&oneCert.apiKeyViaUrl,
+		&oneCert.http01SelfCheck,
+		&oneCert.renewalDays,
+		&oneCert.renewalFraction,
+		&oneCert.notificationEmails,
+		&oneCert.deployHooks,
+		&oneCert.rotateKey,
+		&oneCert.rotateKeyAlgorithm,
+		&oneCert.csrPem,
 
 		&oneCert.certificateKeyDb.id,
 		&oneCert.certificateKeyDb.name,
@@ -296,6 +318,56 @@ func (store *Storage) GetCertPemByName(name string) (pem string, err error) {
 	return pem, err
 }
 
+// GetCertPemAndKeyNameById returns the pem from the most recent valid order for the
+// specified cert id and the name of the key that finalized that order (if the
+// order has no finalized key, the cert's current key is returned; blank if the
+// cert is defined by a csr, even if the order was finalized with a key before the
+// csr was added)
+func (store *Storage) GetCertPemAndKeyNameById(certId int) (pem string, keyName string, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	SELECT
+		ao.pem,
+		CASE WHEN c.csr_pem IS NOT NULL THEN '' ELSE COALESCE(fk.name, ck.name, '') END
+	FROM
+		acme_orders ao
+		LEFT JOIN certificates c on (ao.certificate_id = c.id)
+		LEFT JOIN private_keys fk on (ao.finalized_key_id = fk.id)
+		LEFT JOIN private_keys ck on (c.private_key_id = ck.id)
+	WHERE
+		ao.status = "valid"
+		AND
+		ao.known_revoked = 0
+		AND
+		ao.valid_to > $1
+		AND
+		ao.pem NOT NULL
+		AND
+		ao.certificate_id = $2
+	GROUP BY
+		ao.certificate_id
+	HAVING
+		MAX(ao.valid_to)
+	`
+
+	row := store.db.QueryRowContext(ctx, query,
+		time.Now().Unix(),
+		certId,
+	)
+
+	err = row.Scan(&pem, &keyName)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = storage.ErrNoRecord
+		}
+		return "", "", err
+	}
+
+	return pem, keyName, nil
+}
+
 // GetCertPem returns the pem for the most recent valid order of the specified
 // cert (id or name)
 func (store *Storage) getCertPem(certId int, inName string) (outName string, pem string, err error) {
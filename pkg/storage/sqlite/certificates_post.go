@@ -2,7 +2,10 @@ package sqlite
 
 import (
 	"context"
+	"database/sql"
 	"legocerthub-backend/pkg/domain/certificates"
+	"legocerthub-backend/pkg/domain/private_keys"
+	"legocerthub-backend/pkg/storage"
 )
 
 // PostNewAccount inserts a new cert into the db
@@ -14,15 +17,103 @@ func (store *Storage) PostNewCert(payload certificates.NewPayload) (id int, err
 	// don't check for in use in storage. main app business logic should
 	// take care of it
 
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return -2, err
+	}
+	defer tx.Rollback()
+
+	id, err = insertCert(ctx, tx, payload)
+	if err != nil {
+		return -2, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return -2, err
+	}
+
+	return id, nil
+}
+
+// PostConvertedUnmanagedCert replaces the specified unmanaged cert with a new
+// (managed) cert. If newKey is not nil, it is saved as a new key and used as the
+// cert's key. All of this happens in one transaction so the name (and key, if
+// any) move to the new cert and nothing is left behind if any step fails.
+func (store *Storage) PostConvertedUnmanagedCert(payload certificates.NewPayload, newKey *private_keys.NewPayload, unmanagedId int) (id int, err error) {
+	// database update
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return -2, err
+	}
+	defer tx.Rollback()
+
+	// remove the unmanaged cert
+	query := `
+	DELETE FROM
+		unmanaged_certificates
+	WHERE
+		id = $1
+	`
+
+	result, err := tx.ExecContext(ctx, query, unmanagedId)
+	if err != nil {
+		return -2, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return -2, err
+	}
+	if rows == 0 {
+		return -2, storage.ErrNoRecord
+	}
+
+	// insert the new key (if any)
+	if newKey != nil {
+		keyId, err := insertKey(ctx, tx, *newKey)
+		if err != nil {
+			return -2, err
+		}
+		payload.PrivateKeyID = &keyId
+	}
+
+	// insert the managed cert
+	id, err = insertCert(ctx, tx, payload)
+	if err != nil {
+		return -2, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return -2, err
+	}
+
+	return id, nil
+}
+
+// insertCert inserts a new cert as part of the transaction
+func insertCert(ctx context.Context, tx *sql.Tx, payload certificates.NewPayload) (id int, err error) {
+	// deploy hooks are stored as json
+	deployHooks, err := makeDeployHooksJson(payload.DeployHooks)
+	if err != nil {
+		return -2, err
+	}
+
 	// insert the new cert
 	query := `
-	INSERT INTO certificates (name, description, private_key_id, acme_account_id, challenge_method, subject, subject_alts, 
-		csr_org, csr_ou, csr_country, csr_state, csr_city, created_at, updated_at, api_key, api_key_via_url)
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+	INSERT INTO certificates (name, description, private_key_id, acme_account_id, challenge_method, subject, subject_alts,
+		csr_org, csr_ou, csr_country, csr_state, csr_city, created_at, updated_at, api_key, api_key_via_url, http01_self_check,
+		renewal_days_remaining, renewal_lifetime_fraction, notification_emails, deploy_hooks, rotate_key, rotate_key_algorithm,
+		csr_pem)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
 	RETURNING id
 	`
 
-	err = store.db.QueryRowContext(ctx, query,
+	err = tx.QueryRowContext(ctx, query,
 		payload.Name,
 		payload.Description,
 		payload.PrivateKeyID,
@@ -39,6 +130,14 @@ func (store *Storage) PostNewCert(payload certificates.NewPayload) (id int, err
 		payload.UpdatedAt,
 		payload.ApiKey,
 		payload.ApiKeyViaUrl,
+		payload.Http01SelfCheck,
+		payload.RenewalPolicy.DaysRemaining,
+		payload.RenewalPolicy.LifetimeFraction,
+		makeCommaJoinedString(payload.NotificationEmails),
+		deployHooks,
+		payload.KeyRotation.Enable,
+		payload.KeyRotation.AlgorithmValue,
+		payload.Csr,
 	).Scan(&id)
 
 	if err != nil {
@@ -3,6 +3,8 @@ package sqlite
 import (
 	"context"
 	"legocerthub-backend/pkg/domain/certificates"
+	"legocerthub-backend/pkg/domain/orders"
+	"legocerthub-backend/pkg/storage"
 	"time"
 )
 
@@ -30,11 +32,49 @@ func (store *Storage) PutDetailsCert(payload certificates.DetailsUpdatePayload)
 			api_key = case when $11 is null then api_key else $11 end,
 			api_key_new = case when $12 is null then api_key_new else $12 end,
 			api_key_via_url = case when $13 is null then api_key_via_url else $13 end,
-			updated_at = $14
+			http01_self_check = case when $14 is null then http01_self_check else $14 end,
+			renewal_days_remaining = case when $15 then $16 else renewal_days_remaining end,
+			renewal_lifetime_fraction = case when $15 then $17 else renewal_lifetime_fraction end,
+			notification_emails = case when $18 is null then notification_emails else $18 end,
+			deploy_hooks = case when $19 is null then deploy_hooks else $19 end,
+			rotate_key = case when $20 then $21 else rotate_key end,
+			rotate_key_algorithm = case when $20 then $22 else rotate_key_algorithm end,
+			updated_at = $23
 		WHERE
-			id = $15
+			id = $24
 		`
 
+	// renewal policy is replaced (including clearing options) only if specified
+	updateRenewalPolicy := payload.RenewalPolicy != nil
+	renewalPolicy := certificates.RenewalPolicy{}
+	if updateRenewalPolicy {
+		renewalPolicy = *payload.RenewalPolicy
+	}
+
+	// key rotation is replaced only if specified
+	updateKeyRotation := payload.KeyRotation != nil
+	keyRotation := certificates.KeyRotation{}
+	if updateKeyRotation {
+		keyRotation = *payload.KeyRotation
+	}
+
+	// notification emails are replaced only if specified (empty clears them)
+	var notificationEmails *commaJoinedStrings
+	if payload.NotificationEmails != nil {
+		cjs := makeCommaJoinedString(payload.NotificationEmails)
+		notificationEmails = &cjs
+	}
+
+	// deploy hooks are replaced only if specified (empty clears them)
+	var deployHooks *string
+	if payload.DeployHooks != nil {
+		hooksJson, err := makeDeployHooksJson(payload.DeployHooks)
+		if err != nil {
+			return err
+		}
+		deployHooks = &hooksJson
+	}
+
 	_, err = store.db.ExecContext(ctx, query,
 		payload.Name,
 		payload.Description,
@@ -49,6 +89,15 @@ func (store *Storage) PutDetailsCert(payload certificates.DetailsUpdatePayload)
 		payload.ApiKey,
 		payload.ApiKeyNew,
 		payload.ApiKeyViaUrl,
+		payload.Http01SelfCheck,
+		updateRenewalPolicy,
+		renewalPolicy.DaysRemaining,
+		renewalPolicy.LifetimeFraction,
+		notificationEmails,
+		deployHooks,
+		updateKeyRotation,
+		keyRotation.Enable,
+		keyRotation.AlgorithmValue,
 		payload.UpdatedAt,
 		payload.ID,
 	)
@@ -60,6 +109,157 @@ func (store *Storage) PutDetailsCert(payload certificates.DetailsUpdatePayload)
 	return nil
 }
 
+// PutCertCsr replaces the csr of a cert defined by a csr, along with the subject,
+// subject alts, and csr fields derived from it
+func (store *Storage) PutCertCsr(payload certificates.CsrUpdatePayload) (err error) {
+	// database update
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+		UPDATE
+			certificates
+		SET
+			csr_pem = $1,
+			subject = $2,
+			subject_alts = $3,
+			csr_org = $4,
+			csr_ou = $5,
+			csr_country = $6,
+			csr_state = $7,
+			csr_city = $8,
+			updated_at = $9
+		WHERE
+			id = $10
+			AND
+			csr_pem IS NOT NULL
+		`
+
+	result, err := store.db.ExecContext(ctx, query,
+		payload.Csr,
+		payload.Subject,
+		makeCommaJoinedString(payload.SubjectAltNames),
+		payload.Organization,
+		payload.OrganizationalUnit,
+		payload.Country,
+		payload.State,
+		payload.City,
+		payload.UpdatedAt,
+		payload.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return storage.ErrNoRecord
+	}
+
+	return nil
+}
+
+// PutCertRotatedKey saves a new key (with the same api key settings as the key it
+// replaces), sets it as the cert's key, and schedules the old key to be retired.
+// If the cert's key is no longer the old key, nothing is saved and ErrNoRecord
+// is returned.
+func (store *Storage) PutCertRotatedKey(payload orders.RotateKeyPayload) (newKeyId int, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// create sql transaction to roll back in the event an error occurs
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return -2, err
+	}
+	defer tx.Rollback()
+
+	// new key (copy api key settings from the old key so clients keep access)
+	query := `
+	INSERT INTO private_keys (name, description, algorithm, pem, api_key, api_key_new, api_key_disabled,
+		api_key_via_url, created_at, updated_at)
+	SELECT
+		$1, $2, $3, $4, api_key, api_key_new, api_key_disabled, api_key_via_url, $5, $5
+	FROM
+		private_keys
+	WHERE
+		id = $6
+	RETURNING id
+	`
+
+	err = tx.QueryRowContext(ctx, query,
+		payload.Name,
+		payload.Description,
+		payload.AlgorithmValue,
+		payload.Pem,
+		payload.UpdatedAt,
+		payload.OldKeyID,
+	).Scan(&newKeyId)
+	if err != nil {
+		return -2, err
+	}
+
+	// set cert key
+	query = `
+	UPDATE
+		certificates
+	SET
+		private_key_id = $1,
+		updated_at = $2
+	WHERE
+		id = $3
+		AND
+		private_key_id = $4
+	`
+
+	result, err := tx.ExecContext(ctx, query,
+		newKeyId,
+		payload.UpdatedAt,
+		payload.CertificateID,
+		payload.OldKeyID,
+	)
+	if err != nil {
+		return -2, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return -2, err
+	}
+	if rows == 0 {
+		return -2, storage.ErrNoRecord
+	}
+
+	// retire old key
+	query = `
+	UPDATE
+		private_keys
+	SET
+		retire_at = $1,
+		updated_at = $2
+	WHERE
+		id = $3
+	`
+
+	_, err = tx.ExecContext(ctx, query,
+		payload.RetireAt,
+		payload.UpdatedAt,
+		payload.OldKeyID,
+	)
+	if err != nil {
+		return -2, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return -2, err
+	}
+
+	return newKeyId, nil
+}
+
 // UpdateCertUpdatedTime sets the specified order's updated_at to now
 func (store *Storage) UpdateCertUpdatedTime(certId int) (err error) {
 	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
@@ -0,0 +1,31 @@
+package sqlite
+
+import (
+	"legocerthub-backend/pkg/challenges"
+)
+
+// challengeProviderDb is a single challenge provider, as database table fields
+// corresponds to challenges.Provider
+type challengeProviderDb struct {
+	id        int
+	provType  string
+	name      string
+	enabled   bool
+	config    string
+	createdAt int
+	updatedAt int
+}
+
+// toProvider maps the database challenge provider info to the challenges
+// Provider object
+func (prov challengeProviderDb) toProvider() challenges.Provider {
+	return challenges.Provider{
+		ID:              prov.id,
+		Type:            challenges.MethodValue(prov.provType),
+		Name:            prov.name,
+		Enabled:         prov.enabled,
+		EncryptedConfig: prov.config,
+		CreatedAt:       prov.createdAt,
+		UpdatedAt:       prov.updatedAt,
+	}
+}
@@ -0,0 +1,105 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"legocerthub-backend/pkg/challenges"
+	"legocerthub-backend/pkg/storage"
+)
+
+// GetAllProviders returns a slice of all of the challenge providers in the database
+func (store *Storage) GetAllProviders() (providers []challenges.Provider, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	SELECT
+		cp.id, cp.type, cp.name, cp.enabled, cp.config, cp.created_at, cp.updated_at
+	FROM
+		challenge_providers cp
+	ORDER BY
+		cp.type, cp.name
+	`
+
+	rows, err := store.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var oneProvider challengeProviderDb
+		err = rows.Scan(
+			&oneProvider.id,
+			&oneProvider.provType,
+			&oneProvider.name,
+			&oneProvider.enabled,
+			&oneProvider.config,
+			&oneProvider.createdAt,
+			&oneProvider.updatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		providers = append(providers, oneProvider.toProvider())
+	}
+
+	return providers, nil
+}
+
+// GetOneProvider returns a challenge provider from the db based on its id
+func (store *Storage) GetOneProvider(id int) (provider challenges.Provider, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	SELECT
+		cp.id, cp.type, cp.name, cp.enabled, cp.config, cp.created_at, cp.updated_at
+	FROM
+		challenge_providers cp
+	WHERE
+		cp.id = $1
+	`
+
+	row := store.db.QueryRowContext(ctx, query, id)
+
+	var oneProvider challengeProviderDb
+	err = row.Scan(
+		&oneProvider.id,
+		&oneProvider.provType,
+		&oneProvider.name,
+		&oneProvider.enabled,
+		&oneProvider.config,
+		&oneProvider.createdAt,
+		&oneProvider.updatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = storage.ErrNoRecord
+		}
+		return challenges.Provider{}, err
+	}
+
+	return oneProvider.toProvider(), nil
+}
+
+// ChallengeMethodInUse returns true if any certificate in the db uses the
+// specified challenge method
+func (store *Storage) ChallengeMethodInUse(methodValue challenges.MethodValue) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	SELECT id
+	FROM certificates
+	WHERE challenge_method = $1
+	`
+
+	row := store.db.QueryRowContext(ctx, query, methodValue)
+	temp := -2
+
+	err := row.Scan(&temp)
+	// error means no certificates use the method (includes error no rows)
+	return err == nil
+}
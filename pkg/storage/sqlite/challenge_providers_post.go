@@ -0,0 +1,35 @@
+package sqlite
+
+import (
+	"context"
+	"legocerthub-backend/pkg/challenges"
+)
+
+// PostNewProvider saves a new challenge provider to the db
+func (store *Storage) PostNewProvider(payload challenges.NewProviderPayload) (id int, err error) {
+	// database action
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	INSERT INTO challenge_providers (type, name, enabled, config, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING id
+	`
+
+	// insert and scan the new id
+	err = store.db.QueryRowContext(ctx, query,
+		payload.Type,
+		payload.Name,
+		payload.Enabled,
+		payload.EncryptedConfig,
+		payload.CreatedAt,
+		payload.UpdatedAt,
+	).Scan(&id)
+
+	if err != nil {
+		return -2, err
+	}
+
+	return id, nil
+}
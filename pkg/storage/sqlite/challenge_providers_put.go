@@ -0,0 +1,37 @@
+package sqlite
+
+import (
+	"context"
+	"legocerthub-backend/pkg/challenges"
+)
+
+// PutProviderUpdate updates details about a challenge provider
+func (store *Storage) PutProviderUpdate(payload challenges.UpdateProviderPayload) (err error) {
+	// database update
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	UPDATE
+		challenge_providers
+	SET
+		enabled = case when $1 is null then enabled else $1 end,
+		config = case when $2 is null then config else $2 end,
+		updated_at = $3
+	WHERE
+		id = $4
+	`
+
+	_, err = store.db.ExecContext(ctx, query,
+		payload.Enabled,
+		payload.EncryptedConfig,
+		payload.UpdatedAt,
+		payload.ID,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
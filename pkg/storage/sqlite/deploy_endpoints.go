@@ -0,0 +1,36 @@
+package sqlite
+
+import (
+	"database/sql"
+	"legocerthub-backend/pkg/domain/deploy_endpoints"
+)
+
+// deployEndpointDb is a single deploy endpoint, as database table fields
+// corresponds to deploy_endpoints.Endpoint
+type deployEndpointDb struct {
+	id            int
+	certificateId int
+	host          string
+	port          int
+	serverName    string
+	enabled       bool
+	lastCheck     sql.NullString
+	createdAt     int
+	updatedAt     int
+}
+
+// toEndpoint maps the database deploy endpoint info to the deploy_endpoints
+// Endpoint object
+func (endpoint deployEndpointDb) toEndpoint() deploy_endpoints.Endpoint {
+	return deploy_endpoints.Endpoint{
+		ID:            endpoint.id,
+		CertificateID: endpoint.certificateId,
+		Host:          endpoint.host,
+		Port:          endpoint.port,
+		ServerName:    endpoint.serverName,
+		Enabled:       endpoint.enabled,
+		LastCheck:     deploy_endpoints.CheckFromJson(nullStringToString(endpoint.lastCheck)),
+		CreatedAt:     endpoint.createdAt,
+		UpdatedAt:     endpoint.updatedAt,
+	}
+}
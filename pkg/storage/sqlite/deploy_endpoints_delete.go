@@ -0,0 +1,34 @@
+package sqlite
+
+import (
+	"context"
+	"legocerthub-backend/pkg/storage"
+)
+
+// DeleteDeployEndpoint deletes a deploy endpoint from the database
+func (store *Storage) DeleteDeployEndpoint(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	DELETE FROM
+		deploy_endpoints
+	WHERE
+		id = $1
+	`
+
+	result, err := store.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	// verify a record was actually deleted
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	} else if rowsAffected == 0 {
+		return storage.ErrNoRecord
+	}
+
+	return nil
+}
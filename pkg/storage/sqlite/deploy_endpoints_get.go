@@ -0,0 +1,109 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"legocerthub-backend/pkg/domain/deploy_endpoints"
+	"legocerthub-backend/pkg/storage"
+)
+
+// deployEndpointFields are the deploy endpoint columns, in the order scanned by
+// scanDeployEndpoint
+const deployEndpointFields = `de.id, de.certificate_id, de.host, de.port, de.server_name, de.enabled,
+		de.last_check, de.created_at, de.updated_at`
+
+// scanDeployEndpoint scans the deployEndpointFields from a row
+func scanDeployEndpoint(scanner interface{ Scan(...interface{}) error }) (deployEndpointDb, error) {
+	var oneEndpoint deployEndpointDb
+	err := scanner.Scan(
+		&oneEndpoint.id,
+		&oneEndpoint.certificateId,
+		&oneEndpoint.host,
+		&oneEndpoint.port,
+		&oneEndpoint.serverName,
+		&oneEndpoint.enabled,
+		&oneEndpoint.lastCheck,
+		&oneEndpoint.createdAt,
+		&oneEndpoint.updatedAt,
+	)
+
+	return oneEndpoint, err
+}
+
+// getDeployEndpoints returns the deploy endpoints from the db that match the
+// where clause and args
+func (store *Storage) getDeployEndpoints(where string, args ...interface{}) (endpoints []deploy_endpoints.Endpoint, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := fmt.Sprintf(`
+	SELECT
+		%s
+	FROM
+		deploy_endpoints de
+	WHERE
+		%s
+	ORDER BY
+		de.certificate_id, de.host, de.port, de.server_name
+	`, deployEndpointFields, where)
+
+	rows, err := store.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		oneEndpoint, err := scanDeployEndpoint(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		endpoints = append(endpoints, oneEndpoint.toEndpoint())
+	}
+
+	return endpoints, nil
+}
+
+// GetDeployEndpointsByCert returns all of the deploy endpoints of a cert
+func (store *Storage) GetDeployEndpointsByCert(certId int) (endpoints []deploy_endpoints.Endpoint, err error) {
+	return store.getDeployEndpoints("de.certificate_id = $1", certId)
+}
+
+// GetEnabledDeployEndpoints returns all of the enabled deploy endpoints
+func (store *Storage) GetEnabledDeployEndpoints() (endpoints []deploy_endpoints.Endpoint, err error) {
+	return store.getDeployEndpoints("de.enabled = 1")
+}
+
+// GetFlaggedDeployEndpoints returns the enabled deploy endpoints whose last check
+// was stale or mismatched
+func (store *Storage) GetFlaggedDeployEndpoints() (endpoints []deploy_endpoints.Endpoint, err error) {
+	return store.getDeployEndpoints("de.enabled = 1 AND json_extract(de.last_check, '$.status') IN ($1, $2)",
+		deploy_endpoints.StatusStale, deploy_endpoints.StatusMismatch)
+}
+
+// GetOneDeployEndpointById returns a deploy endpoint from the db based on its id
+func (store *Storage) GetOneDeployEndpointById(id int) (deploy_endpoints.Endpoint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := fmt.Sprintf(`
+	SELECT
+		%s
+	FROM
+		deploy_endpoints de
+	WHERE
+		de.id = $1
+	`, deployEndpointFields)
+
+	oneEndpoint, err := scanDeployEndpoint(store.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = storage.ErrNoRecord
+		}
+		return deploy_endpoints.Endpoint{}, err
+	}
+
+	return oneEndpoint.toEndpoint(), nil
+}
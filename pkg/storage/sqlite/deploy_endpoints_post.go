@@ -0,0 +1,36 @@
+package sqlite
+
+import (
+	"context"
+	"legocerthub-backend/pkg/domain/deploy_endpoints"
+)
+
+// PostNewDeployEndpoint saves a new deploy endpoint to the db
+func (store *Storage) PostNewDeployEndpoint(payload deploy_endpoints.NewPayload) (id int, err error) {
+	// database action
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	INSERT INTO deploy_endpoints (certificate_id, host, port, server_name, enabled, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING id
+	`
+
+	// insert and scan the new id
+	err = store.db.QueryRowContext(ctx, query,
+		payload.CertificateID,
+		payload.Host,
+		payload.Port,
+		payload.ServerName,
+		payload.Enabled,
+		payload.CreatedAt,
+		payload.UpdatedAt,
+	).Scan(&id)
+
+	if err != nil {
+		return -2, err
+	}
+
+	return id, nil
+}
@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"legocerthub-backend/pkg/domain/deploy_endpoints"
+)
+
+// PutDeployEndpointUpdate updates details about a deploy endpoint. If the host,
+// port, or server name changes, the last check is cleared (it was of the old
+// endpoint).
+func (store *Storage) PutDeployEndpointUpdate(payload deploy_endpoints.UpdatePayload) (err error) {
+	// database update
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	UPDATE
+		deploy_endpoints
+	SET
+		last_check = case when $1 is null and $2 is null and $3 is null then last_check else null end,
+		host = case when $1 is null then host else $1 end,
+		port = case when $2 is null then port else $2 end,
+		server_name = case when $3 is null then server_name else $3 end,
+		enabled = case when $4 is null then enabled else $4 end,
+		updated_at = $5
+	WHERE
+		id = $6
+	`
+
+	_, err = store.db.ExecContext(ctx, query,
+		payload.Host,
+		payload.Port,
+		payload.ServerName,
+		payload.Enabled,
+		payload.UpdatedAt,
+		payload.ID,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PutDeployEndpointCheck saves the result of the most recent check of the
+// endpoint
+func (store *Storage) PutDeployEndpointCheck(id int, check deploy_endpoints.Check) (err error) {
+	checkJson, err := json.Marshal(check)
+	if err != nil {
+		return err
+	}
+
+	// database update
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	UPDATE
+		deploy_endpoints
+	SET
+		last_check = $1
+	WHERE
+		id = $2
+	`
+
+	_, err = store.db.ExecContext(ctx, query,
+		string(checkJson),
+		id,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package sqlite
+
+import (
+	"database/sql"
+	"legocerthub-backend/pkg/domain/deploy_targets"
+)
+
+// deployTargetDb is a single deploy target, as database table fields
+// corresponds to deploy_targets.Target
+type deployTargetDb struct {
+	id            int
+	certificateId int
+	name          string
+	description   string
+	host          string
+	port          int
+	username      string
+	authKeyId     int
+	authKeyName   string
+	authKeyPem    string
+	hostKey       string
+	keyPath       string
+	fullchainPath string
+	postCommand   string
+	enabled       bool
+	lastResult    sql.NullString
+	createdAt     int
+	updatedAt     int
+}
+
+// toTarget maps the database deploy target info to the deploy_targets Target object
+func (target deployTargetDb) toTarget() deploy_targets.Target {
+	return deploy_targets.Target{
+		ID:            target.id,
+		CertificateID: target.certificateId,
+		Name:          target.name,
+		Description:   target.description,
+		Host:          target.host,
+		Port:          target.port,
+		User:          target.username,
+		AuthKey: deploy_targets.AuthKey{
+			ID:   target.authKeyId,
+			Name: target.authKeyName,
+			Pem:  target.authKeyPem,
+		},
+		HostKey:       target.hostKey,
+		KeyPath:       target.keyPath,
+		FullchainPath: target.fullchainPath,
+		PostCommand:   target.postCommand,
+		Enabled:       target.enabled,
+		LastResult:    deploy_targets.ResultFromJson(nullStringToString(target.lastResult)),
+		CreatedAt:     target.createdAt,
+		UpdatedAt:     target.updatedAt,
+	}
+}
@@ -0,0 +1,134 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"legocerthub-backend/pkg/domain/deploy_targets"
+	"legocerthub-backend/pkg/storage"
+)
+
+// deployTargetFields are the deploy target columns, in the order scanned by
+// scanDeployTarget
+const deployTargetFields = `dt.id, dt.certificate_id, dt.name, dt.description, dt.host, dt.port,
+		dt.username, pk.id, pk.name, pk.pem, dt.host_key, dt.key_path, dt.fullchain_path,
+		dt.post_command, dt.enabled, dt.last_result, dt.created_at, dt.updated_at`
+
+// scanDeployTarget scans the deployTargetFields from a row
+func scanDeployTarget(scanner interface{ Scan(...interface{}) error }) (deployTargetDb, error) {
+	var oneTarget deployTargetDb
+	err := scanner.Scan(
+		&oneTarget.id,
+		&oneTarget.certificateId,
+		&oneTarget.name,
+		&oneTarget.description,
+		&oneTarget.host,
+		&oneTarget.port,
+		&oneTarget.username,
+		&oneTarget.authKeyId,
+		&oneTarget.authKeyName,
+		&oneTarget.authKeyPem,
+		&oneTarget.hostKey,
+		&oneTarget.keyPath,
+		&oneTarget.fullchainPath,
+		&oneTarget.postCommand,
+		&oneTarget.enabled,
+		&oneTarget.lastResult,
+		&oneTarget.createdAt,
+		&oneTarget.updatedAt,
+	)
+
+	return oneTarget, err
+}
+
+// GetDeployTargetsByCert returns all of the deploy targets of a cert
+func (store *Storage) GetDeployTargetsByCert(certId int) (targets []deploy_targets.Target, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := fmt.Sprintf(`
+	SELECT
+		%s
+	FROM
+		deploy_targets dt
+		JOIN private_keys pk on (dt.private_key_id = pk.id)
+	WHERE
+		dt.certificate_id = $1
+	ORDER BY
+		dt.name
+	`, deployTargetFields)
+
+	rows, err := store.db.QueryContext(ctx, query, certId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		oneTarget, err := scanDeployTarget(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		targets = append(targets, oneTarget.toTarget())
+	}
+
+	return targets, nil
+}
+
+// getOneDeployTarget returns a deploy target from the db based on the where clause
+// and arg
+func (store *Storage) getOneDeployTarget(where string, arg interface{}) (deploy_targets.Target, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := fmt.Sprintf(`
+	SELECT
+		%s
+	FROM
+		deploy_targets dt
+		JOIN private_keys pk on (dt.private_key_id = pk.id)
+	WHERE
+		%s = $1
+	`, deployTargetFields, where)
+
+	oneTarget, err := scanDeployTarget(store.db.QueryRowContext(ctx, query, arg))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = storage.ErrNoRecord
+		}
+		return deploy_targets.Target{}, err
+	}
+
+	return oneTarget.toTarget(), nil
+}
+
+// GetOneDeployTargetById returns a deploy target from the db based on its id
+func (store *Storage) GetOneDeployTargetById(id int) (deploy_targets.Target, error) {
+	return store.getOneDeployTarget("dt.id", id)
+}
+
+// GetOneDeployTargetByName returns a deploy target from the db based on its name
+func (store *Storage) GetOneDeployTargetByName(name string) (deploy_targets.Target, error) {
+	return store.getOneDeployTarget("dt.name", name)
+}
+
+// KeyInUseByDeployTarget returns true if any deploy target uses the key
+func (store *Storage) KeyInUseByDeployTarget(keyId int) (inUse bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	SELECT
+		EXISTS(
+			SELECT id FROM deploy_targets WHERE private_key_id = $1
+		)
+	`
+
+	err = store.db.QueryRowContext(ctx, query, keyId).Scan(&inUse)
+	if err != nil {
+		return false, err
+	}
+
+	return inUse, nil
+}
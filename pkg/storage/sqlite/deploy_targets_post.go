@@ -0,0 +1,44 @@
+package sqlite
+
+import (
+	"context"
+	"legocerthub-backend/pkg/domain/deploy_targets"
+)
+
+// PostNewDeployTarget saves a new deploy target to the db
+func (store *Storage) PostNewDeployTarget(payload deploy_targets.NewPayload) (id int, err error) {
+	// database action
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	INSERT INTO deploy_targets (certificate_id, name, description, host, port, username, private_key_id,
+		host_key, key_path, fullchain_path, post_command, enabled, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	RETURNING id
+	`
+
+	// insert and scan the new id
+	err = store.db.QueryRowContext(ctx, query,
+		payload.CertificateID,
+		payload.Name,
+		payload.Description,
+		payload.Host,
+		payload.Port,
+		payload.User,
+		payload.PrivateKeyID,
+		payload.HostKey,
+		payload.KeyPath,
+		payload.FullchainPath,
+		payload.PostCommand,
+		payload.Enabled,
+		payload.CreatedAt,
+		payload.UpdatedAt,
+	).Scan(&id)
+
+	if err != nil {
+		return -2, err
+	}
+
+	return id, nil
+}
@@ -0,0 +1,88 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"legocerthub-backend/pkg/domain/deploy_targets"
+)
+
+// PutDeployTargetUpdate updates details about a deploy target
+func (store *Storage) PutDeployTargetUpdate(payload deploy_targets.UpdatePayload) (err error) {
+	// database update
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	UPDATE
+		deploy_targets
+	SET
+		name = case when $1 is null then name else $1 end,
+		description = case when $2 is null then description else $2 end,
+		host = case when $3 is null then host else $3 end,
+		port = case when $4 is null then port else $4 end,
+		username = case when $5 is null then username else $5 end,
+		private_key_id = case when $6 is null then private_key_id else $6 end,
+		host_key = case when $7 is null then host_key else $7 end,
+		key_path = case when $8 is null then key_path else $8 end,
+		fullchain_path = case when $9 is null then fullchain_path else $9 end,
+		post_command = case when $10 is null then post_command else $10 end,
+		enabled = case when $11 is null then enabled else $11 end,
+		updated_at = $12
+	WHERE
+		id = $13
+	`
+
+	_, err = store.db.ExecContext(ctx, query,
+		payload.Name,
+		payload.Description,
+		payload.Host,
+		payload.Port,
+		payload.User,
+		payload.PrivateKeyID,
+		payload.HostKey,
+		payload.KeyPath,
+		payload.FullchainPath,
+		payload.PostCommand,
+		payload.Enabled,
+		payload.UpdatedAt,
+		payload.ID,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PutDeployTargetResult saves the result of the most recent deploy to the target
+func (store *Storage) PutDeployTargetResult(id int, result deploy_targets.Result) (err error) {
+	resultJson, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	// database update
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	UPDATE
+		deploy_targets
+	SET
+		last_result = $1
+	WHERE
+		id = $2
+	`
+
+	_, err = store.db.ExecContext(ctx, query,
+		string(resultJson),
+		id,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package sqlite
+
+import (
+	"context"
+	"legocerthub-backend/pkg/export"
+	"legocerthub-backend/pkg/storage"
+	"time"
+)
+
+// GetCertExport returns the pem of the cert's most recent valid order and the key
+// that finalized it
+func (store *Storage) GetCertExport(certId int) (cert export.Cert, err error) {
+	certs, err := store.getCertExports(certId)
+	if err != nil {
+		return export.Cert{}, err
+	}
+
+	if len(certs) == 0 {
+		return export.Cert{}, storage.ErrNoRecord
+	}
+
+	return certs[0], nil
+}
+
+// GetAllCertExports returns the key and most recent valid order pem of every cert
+// that has a valid order
+func (store *Storage) GetAllCertExports() (certs []export.Cert, err error) {
+	return store.getCertExports(-1)
+}
+
+// getCertExports returns the most recent valid order pem (and the key that finalized
+// it, or the cert's key if none) of the specified cert, or of all certs if certId
+// is -1. The key is blank for certs defined by a csr.
+func (store *Storage) getCertExports(certId int) (certs []export.Cert, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	SELECT
+		c.id, c.name, CASE WHEN c.csr_pem IS NOT NULL THEN '' ELSE COALESCE(fk.pem, pk.pem, '') END, ao.pem
+	FROM
+		acme_orders ao
+		JOIN certificates c on (ao.certificate_id = c.id)
+		LEFT JOIN private_keys pk on (c.private_key_id = pk.id)
+		LEFT JOIN private_keys fk on (ao.finalized_key_id = fk.id)
+	WHERE
+		ao.status = "valid"
+		AND
+		ao.known_revoked = 0
+		AND
+		ao.valid_to > $1
+		AND
+		ao.pem NOT NULL
+		AND
+		(
+			$2 = -1
+			OR
+			ao.certificate_id = $2
+		)
+	GROUP BY
+		ao.certificate_id
+	HAVING
+		MAX(ao.valid_to)
+	ORDER BY
+		c.name
+	`
+
+	rows, err := store.db.QueryContext(ctx, query,
+		time.Now().Unix(),
+		certId,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cert export.Cert
+		err = rows.Scan(
+			&cert.CertificateID,
+			&cert.CertificateName,
+			&cert.KeyPem,
+			&cert.CertPem,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		certs = append(certs, cert)
+	}
+
+	return certs, nil
+}
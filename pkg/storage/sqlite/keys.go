@@ -1,6 +1,7 @@
 package sqlite
 
 import (
+	"database/sql"
 	"legocerthub-backend/pkg/domain/private_keys"
 	"legocerthub-backend/pkg/domain/private_keys/key_crypto"
 )
@@ -17,6 +18,7 @@ type keyDb struct {
 	apiKeyNew      string
 	apiKeyDisabled bool
 	apiKeyViaUrl   bool
+	retireAt       sql.NullInt32
 	createdAt      int
 	updatedAt      int
 }
@@ -34,6 +36,7 @@ func (key keyDb) toKey() private_keys.Key {
 		ApiKeyNew:      key.apiKeyNew,
 		ApiKeyDisabled: key.apiKeyDisabled,
 		ApiKeyViaUrl:   key.apiKeyViaUrl,
+		RetireAt:       nullInt32ToInt(key.retireAt),
 		CreatedAt:      key.createdAt,
 		UpdatedAt:      key.updatedAt,
 	}
@@ -35,7 +35,7 @@ func (store Storage) GetAllKeys(q pagination_sort.Query) (keys []private_keys.Ke
 	query := fmt.Sprintf(`
 	SELECT
 		id, name, description, algorithm, pem, api_key, api_key_new, api_key_disabled,
-		api_key_via_url, created_at, updated_at,
+		api_key_via_url, retire_at, created_at, updated_at,
 
 		count(*) OVER() AS full_count
 	FROM
@@ -73,6 +73,7 @@ func (store Storage) GetAllKeys(q pagination_sort.Query) (keys []private_keys.Ke
 			&oneKeyDb.apiKeyNew,
 			&oneKeyDb.apiKeyDisabled,
 			&oneKeyDb.apiKeyViaUrl,
+			&oneKeyDb.retireAt,
 			&oneKeyDb.createdAt,
 			&oneKeyDb.updatedAt,
 
@@ -108,7 +109,7 @@ func (store Storage) getOneKey(id int, name string) (private_keys.Key, error) {
 	query := `
 	SELECT
 		id, name, description, algorithm, pem, api_key, api_key_new, api_key_disabled,
-		api_key_via_url, created_at, updated_at
+		api_key_via_url, retire_at, created_at, updated_at
 	FROM
 		private_keys
 	WHERE
@@ -130,6 +131,7 @@ func (store Storage) getOneKey(id int, name string) (private_keys.Key, error) {
 		&oneKeyDb.apiKeyNew,
 		&oneKeyDb.apiKeyDisabled,
 		&oneKeyDb.apiKeyViaUrl,
+		&oneKeyDb.retireAt,
 		&oneKeyDb.createdAt,
 		&oneKeyDb.updatedAt,
 	)
@@ -152,7 +154,7 @@ func (store Storage) getOneKey(id int, name string) (private_keys.Key, error) {
 }
 
 // GetAvailableKeys returns a slice of private keys that exist but are not already associated
-// with a known ACME account or certificate
+// with a known ACME account, certificate, or deploy target, and are not being retired
 func (store *Storage) GetAvailableKeys() ([]private_keys.Key, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
 	defer cancel()
@@ -181,6 +183,26 @@ func (store *Storage) GetAvailableKeys() ([]private_keys.Key, error) {
 				WHERE
 					pk.id = c.private_key_id
 			)
+			AND
+			NOT EXISTS(
+				SELECT
+					dt.private_key_id
+				FROM
+					deploy_targets dt
+				WHERE
+					pk.id = dt.private_key_id
+			)
+			AND
+			NOT EXISTS(
+				SELECT
+					uc.private_key_id
+				FROM
+					unmanaged_certificates uc
+				WHERE
+					pk.id = uc.private_key_id
+			)
+			AND
+			pk.retire_at IS NULL
 		ORDER BY name
 	`
 
@@ -265,3 +287,41 @@ func (store Storage) getKeyPem(id int, inName string) (outName string, pem strin
 
 	return outName, pem, nil
 }
+
+// GetRetiredKeyIds returns the ids of keys that were replaced by key rotation and
+// whose retire time has passed
+func (store *Storage) GetRetiredKeyIds(now int) (keyIds []int, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	SELECT
+		id
+	FROM
+		private_keys
+	WHERE
+		retire_at NOT NULL
+		AND
+		retire_at <= $1
+	ORDER BY
+		id
+	`
+
+	rows, err := store.db.QueryContext(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var keyId int
+		err = rows.Scan(&keyId)
+		if err != nil {
+			return nil, err
+		}
+
+		keyIds = append(keyIds, keyId)
+	}
+
+	return keyIds, nil
+}
@@ -2,6 +2,7 @@ package sqlite
 
 import (
 	"context"
+	"database/sql"
 	"legocerthub-backend/pkg/domain/private_keys"
 )
 
@@ -11,6 +12,27 @@ func (store *Storage) PostNewKey(payload private_keys.NewPayload) (id int, err e
 	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
 	defer cancel()
 
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return -2, err
+	}
+	defer tx.Rollback()
+
+	id, err = insertKey(ctx, tx, payload)
+	if err != nil {
+		return -2, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return -2, err
+	}
+
+	return id, nil
+}
+
+// insertKey inserts a new key as part of the transaction
+func insertKey(ctx context.Context, tx *sql.Tx, payload private_keys.NewPayload) (id int, err error) {
 	query := `
 	INSERT INTO private_keys (name, description, algorithm, pem, api_key, api_key_disabled, api_key_via_url, created_at, updated_at)
 	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
@@ -18,7 +40,7 @@ func (store *Storage) PostNewKey(payload private_keys.NewPayload) (id int, err e
 	`
 
 	// insert and scan the new id
-	err = store.db.QueryRowContext(ctx, query,
+	err = tx.QueryRowContext(ctx, query,
 		payload.Name,
 		payload.Description,
 		payload.AlgorithmValue,
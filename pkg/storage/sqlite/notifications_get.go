@@ -0,0 +1,174 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"legocerthub-backend/pkg/notifications"
+	"legocerthub-backend/pkg/storage"
+)
+
+// GetOrderNotificationTarget returns the certificate of the specified order and the
+// certificate's notification recipients
+func (store *Storage) GetOrderNotificationTarget(orderId int) (target notifications.Target, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	SELECT
+		c.id, c.name, c.notification_emails
+	FROM
+		acme_orders ao
+		JOIN certificates c on (ao.certificate_id = c.id)
+	WHERE
+		ao.id = $1
+	`
+
+	var emails commaJoinedStrings
+	err = store.db.QueryRowContext(ctx, query, orderId).Scan(
+		&target.CertificateID,
+		&target.CertificateName,
+		&emails,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = storage.ErrNoRecord
+		}
+		return notifications.Target{}, err
+	}
+	target.NotificationEmails = emails.toSlice()
+
+	return target, nil
+}
+
+// GetCertNotificationTarget returns the certificate and the certificate's
+// notification recipients
+func (store *Storage) GetCertNotificationTarget(certId int) (target notifications.Target, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	SELECT
+		c.id, c.name, c.notification_emails
+	FROM
+		certificates c
+	WHERE
+		c.id = $1
+	`
+
+	var emails commaJoinedStrings
+	err = store.db.QueryRowContext(ctx, query, certId).Scan(
+		&target.CertificateID,
+		&target.CertificateName,
+		&emails,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = storage.ErrNoRecord
+		}
+		return notifications.Target{}, err
+	}
+	target.NotificationEmails = emails.toSlice()
+
+	return target, nil
+}
+
+// GetCertExpirations returns each certificate and the valid_to of the certificate's
+// newest valid (and not revoked) order
+func (store *Storage) GetCertExpirations() (expirations []notifications.CertExpiration, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	SELECT
+		c.id, c.name, c.notification_emails, MAX(ao.valid_to)
+	FROM
+		certificates c
+		LEFT JOIN acme_orders ao on (
+			ao.certificate_id = c.id
+			AND
+			ao.status = "valid"
+			AND
+			ao.known_revoked = 0
+			AND
+			ao.pem NOT NULL
+		)
+	GROUP BY
+		c.id
+	ORDER BY
+		c.name
+	`
+
+	rows, err := store.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var expiration notifications.CertExpiration
+		var emails commaJoinedStrings
+		var validTo sql.NullInt32
+
+		err = rows.Scan(
+			&expiration.CertificateID,
+			&expiration.CertificateName,
+			&emails,
+			&validTo,
+		)
+		if err != nil {
+			return nil, err
+		}
+		expiration.NotificationEmails = emails.toSlice()
+		expiration.ValidTo = nullInt32ToInt(validTo)
+
+		expirations = append(expirations, expiration)
+	}
+
+	return expirations, nil
+}
+
+// GetUnmanagedCertExpirations returns each unmanaged certificate and the valid_to
+// of its imported pem
+func (store *Storage) GetUnmanagedCertExpirations() (expirations []notifications.CertExpiration, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	SELECT
+		uc.id, uc.name, uc.notification_emails, uc.valid_to
+	FROM
+		unmanaged_certificates uc
+	ORDER BY
+		uc.name
+	`
+
+	rows, err := store.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		expiration := notifications.CertExpiration{
+			Unmanaged: true,
+			ValidTo:   new(int),
+		}
+		var emails commaJoinedStrings
+
+		err = rows.Scan(
+			&expiration.CertificateID,
+			&expiration.CertificateName,
+			&emails,
+			expiration.ValidTo,
+		)
+		if err != nil {
+			return nil, err
+		}
+		expiration.NotificationEmails = emails.toSlice()
+
+		expirations = append(expirations, expiration)
+	}
+
+	return expirations, nil
+}
@@ -18,6 +18,18 @@ func nullInt32ToInt(nullInt sql.NullInt32) *int {
 	return nil
 }
 
+// nullFloat64ToFloat64 converts a NullFloat64 into a float64 pointer
+func nullFloat64ToFloat64(nullFloat sql.NullFloat64) *float64 {
+	if nullFloat.Valid {
+		f := new(float64)
+		*f = nullFloat.Float64
+
+		return f
+	}
+
+	return nil
+}
+
 // nullStringToString converts the nullstring to a string pointer
 func nullStringToString(nullString sql.NullString) *string {
 	if nullString.Valid {
@@ -0,0 +1,38 @@
+package sqlite
+
+import (
+	"database/sql"
+	"legocerthub-backend/pkg/domain/orders"
+)
+
+// orderJobDb is a single order job, as database table fields
+// corresponds to orders.Job
+type orderJobDb struct {
+	id              int
+	orderId         int
+	certificateId   int
+	certificateName string
+	priority        int
+	state           string
+	attempts        int
+	nextRunAt       int
+	lastError       sql.NullString
+	createdAt       int
+	updatedAt       int
+}
+
+func (job orderJobDb) toJob() orders.Job {
+	return orders.Job{
+		ID:              job.id,
+		OrderID:         job.orderId,
+		CertificateID:   job.certificateId,
+		CertificateName: job.certificateName,
+		Priority:        job.priority,
+		State:           job.state,
+		Attempts:        job.attempts,
+		NextRunAt:       job.nextRunAt,
+		LastError:       job.lastError.String,
+		CreatedAt:       job.createdAt,
+		UpdatedAt:       job.updatedAt,
+	}
+}
@@ -0,0 +1,25 @@
+package sqlite
+
+import (
+	"context"
+)
+
+// DeleteOrderJob deletes an order job from storage
+func (store *Storage) DeleteOrderJob(jobId int) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	DELETE FROM
+		order_jobs
+	WHERE
+		id = $1
+	`
+
+	_, err = store.db.ExecContext(ctx, query, jobId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package sqlite
+
+import (
+	"context"
+	"legocerthub-backend/pkg/domain/orders"
+)
+
+// GetOrderJobs returns all of the order jobs, in the order they will be claimed
+func (store *Storage) GetOrderJobs() (jobs []orders.Job, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	SELECT
+		oj.id, oj.order_id, c.id, c.name, oj.priority, oj.state, oj.attempts,
+		oj.next_run_at, oj.last_error, oj.created_at, oj.updated_at
+	FROM
+		order_jobs oj
+		JOIN acme_orders ao on (oj.order_id = ao.id)
+		JOIN certificates c on (ao.certificate_id = c.id)
+	ORDER BY
+		oj.priority DESC,
+		oj.next_run_at,
+		oj.id
+	`
+
+	rows, err := store.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var oneJob orderJobDb
+		err = rows.Scan(
+			&oneJob.id,
+			&oneJob.orderId,
+			&oneJob.certificateId,
+			&oneJob.certificateName,
+			&oneJob.priority,
+			&oneJob.state,
+			&oneJob.attempts,
+			&oneJob.nextRunAt,
+			&oneJob.lastError,
+			&oneJob.createdAt,
+			&oneJob.updatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		jobs = append(jobs, oneJob.toJob())
+	}
+
+	return jobs, nil
+}
@@ -0,0 +1,62 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"legocerthub-backend/pkg/domain/orders"
+)
+
+// PostOrderJob adds a queued job for the order. If the order already has a
+// failed job, that job is reset and queued again. If the order already has a
+// queued or running job, orders.ErrOrderAlreadyProcessing is returned.
+func (store *Storage) PostOrderJob(payload orders.NewJobPayload) (id int, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	INSERT INTO
+		order_jobs (order_id, priority, state, attempts, next_run_at, created_at, updated_at)
+	VALUES (
+		$1,
+		$2,
+		$3,
+		0,
+		$4,
+		$5,
+		$6
+	)
+	ON CONFLICT (order_id) DO UPDATE SET
+		priority = excluded.priority,
+		state = excluded.state,
+		attempts = 0,
+		next_run_at = excluded.next_run_at,
+		last_error = NULL,
+		updated_at = excluded.updated_at
+	WHERE
+		order_jobs.state = $7
+	RETURNING
+		id
+	`
+
+	err = store.db.QueryRowContext(ctx, query,
+		payload.OrderID,
+		payload.Priority,
+		orders.JobStateQueued,
+		payload.NextRunAt,
+		payload.CreatedAt,
+		payload.UpdatedAt,
+		orders.JobStateFailed,
+	).Scan(&id)
+
+	if err != nil {
+		// no row means the conflicting job is not failed (i.e. it is still
+		// queued or running)
+		if errors.Is(err, sql.ErrNoRows) {
+			return -2, orders.ErrOrderAlreadyProcessing
+		}
+		return -2, err
+	}
+
+	return id, nil
+}
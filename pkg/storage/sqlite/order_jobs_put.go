@@ -0,0 +1,198 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"legocerthub-backend/pkg/domain/orders"
+	"legocerthub-backend/pkg/storage"
+)
+
+// ClaimOrderJob marks the next queued job that is due as running, increments its
+// attempts and returns it. If there is no job to claim, storage.ErrNoRecord is
+// returned.
+func (store *Storage) ClaimOrderJob(now int) (job orders.Job, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	UPDATE
+		order_jobs
+	SET
+		state = $1,
+		attempts = attempts + 1,
+		updated_at = $2
+	WHERE
+		id = (
+			SELECT
+				id
+			FROM
+				order_jobs
+			WHERE
+				state = $3
+				AND
+				next_run_at <= $2
+			ORDER BY
+				priority DESC,
+				next_run_at,
+				id
+			LIMIT 1
+		)
+	RETURNING
+		id, order_id, priority, state, attempts, next_run_at, last_error,
+		created_at, updated_at
+	`
+
+	var oneJob orderJobDb
+	err = store.db.QueryRowContext(ctx, query,
+		orders.JobStateRunning,
+		now,
+		orders.JobStateQueued,
+	).Scan(
+		&oneJob.id,
+		&oneJob.orderId,
+		&oneJob.priority,
+		&oneJob.state,
+		&oneJob.attempts,
+		&oneJob.nextRunAt,
+		&oneJob.lastError,
+		&oneJob.createdAt,
+		&oneJob.updatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = storage.ErrNoRecord
+		}
+		return orders.Job{}, err
+	}
+
+	return oneJob.toJob(), nil
+}
+
+// PutOrderJobRetry puts a job back in the queue to be run again at nextRunAt
+func (store *Storage) PutOrderJobRetry(jobId int, nextRunAt int, lastError string, updatedAt int) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	UPDATE
+		order_jobs
+	SET
+		state = $1,
+		next_run_at = $2,
+		last_error = $3,
+		updated_at = $4
+	WHERE
+		id = $5
+	`
+
+	_, err = store.db.ExecContext(ctx, query,
+		orders.JobStateQueued,
+		nextRunAt,
+		lastError,
+		updatedAt,
+		jobId,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PutOrderJobFailed marks a job as failed, failed jobs are not run again unless
+// a new job is posted for the same order
+func (store *Storage) PutOrderJobFailed(jobId int, lastError string, updatedAt int) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	UPDATE
+		order_jobs
+	SET
+		state = $1,
+		last_error = $2,
+		updated_at = $3
+	WHERE
+		id = $4
+	`
+
+	_, err = store.db.ExecContext(ctx, query,
+		orders.JobStateFailed,
+		lastError,
+		updatedAt,
+		jobId,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ResetRunningOrderJobs puts any jobs that were running (e.g. when LeGo was
+// stopped) back in the queue. The interrupted attempt is not counted.
+func (store *Storage) ResetRunningOrderJobs() (count int, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	UPDATE
+		order_jobs
+	SET
+		state = $1,
+		attempts = max(attempts - 1, 0)
+	WHERE
+		state = $2
+	`
+
+	result, err := store.db.ExecContext(ctx, query,
+		orders.JobStateQueued,
+		orders.JobStateRunning,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(rows), nil
+}
+
+// PutOrderJobDeferred puts a job back in the queue to be run at nextRunAt without
+// counting the attempt (e.g. when the job's ACME server was busy)
+func (store *Storage) PutOrderJobDeferred(jobId int, nextRunAt int, updatedAt int) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	UPDATE
+		order_jobs
+	SET
+		state = $1,
+		attempts = max(attempts - 1, 0),
+		next_run_at = $2,
+		updated_at = $3
+	WHERE
+		id = $4
+	`
+
+	_, err = store.db.ExecContext(ctx, query,
+		orders.JobStateQueued,
+		nextRunAt,
+		updatedAt,
+		jobId,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,224 @@
+package sqlite
+
+import (
+	"errors"
+	"legocerthub-backend/pkg/domain/orders"
+	"legocerthub-backend/pkg/storage"
+	"testing"
+)
+
+// testPostJob posts a new job for the order and returns its id
+func testPostJob(t *testing.T, store *Storage, orderId int, priority int, nextRunAt int) int {
+	id, err := store.PostOrderJob(orders.NewJobPayload{
+		OrderID:   orderId,
+		Priority:  priority,
+		NextRunAt: nextRunAt,
+		CreatedAt: 1,
+		UpdatedAt: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return id
+}
+
+// testJob returns the job with the specified id
+func testJob(t *testing.T, store *Storage, jobId int) orders.Job {
+	jobs, err := store.GetOrderJobs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, job := range jobs {
+		if job.ID == jobId {
+			return job
+		}
+	}
+
+	t.Fatalf("job %d not found", jobId)
+	return orders.Job{}
+}
+
+func TestOrderJobs_Claim(t *testing.T) {
+	store := newTestStorage(t)
+	certId := testInsertCert(t, store, "claim")
+
+	lowId := testPostJob(t, store, testInsertOrder(t, store, certId, "pending", false, 0, 1), 0, 100)
+	highId := testPostJob(t, store, testInsertOrder(t, store, certId, "pending", false, 0, 2), 1, 100)
+	futureId := testPostJob(t, store, testInsertOrder(t, store, certId, "pending", false, 0, 3), 1, 500)
+
+	// nothing is due yet
+	_, err := store.ClaimOrderJob(50)
+	if !errors.Is(err, storage.ErrNoRecord) {
+		t.Fatalf("claim before due: error '%v', expected '%v'", err, storage.ErrNoRecord)
+	}
+
+	// high priority first, then low, a job that isn't due is never claimed
+	for _, expectedId := range []int{highId, lowId} {
+		job, err := store.ClaimOrderJob(200)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.ID != expectedId {
+			t.Errorf("claimed job %d, expected %d", job.ID, expectedId)
+		}
+		if job.State != orders.JobStateRunning || job.Attempts != 1 || job.UpdatedAt != 200 {
+			t.Errorf("claimed job %d is %s with %d attempts (updated %d), expected running with 1 attempt (updated 200)",
+				job.ID, job.State, job.Attempts, job.UpdatedAt)
+		}
+	}
+
+	// a running job is not claimed again
+	_, err = store.ClaimOrderJob(200)
+	if !errors.Is(err, storage.ErrNoRecord) {
+		t.Errorf("claim with only running and future jobs: error '%v', expected '%v'", err, storage.ErrNoRecord)
+	}
+
+	if job := testJob(t, store, futureId); job.State != orders.JobStateQueued || job.Attempts != 0 {
+		t.Errorf("future job is %s with %d attempts, expected queued with 0 attempts", job.State, job.Attempts)
+	}
+}
+
+func TestOrderJobs_RetryAndFail(t *testing.T) {
+	store := newTestStorage(t)
+	certId := testInsertCert(t, store, "retry")
+	jobId := testPostJob(t, store, testInsertOrder(t, store, certId, "pending", false, 0, 1), 0, 100)
+
+	_, err := store.ClaimOrderJob(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// retry puts the job back in the queue, keeping its attempts
+	err = store.PutOrderJobRetry(jobId, 400, "first error", 150)
+	if err != nil {
+		t.Fatal(err)
+	}
+	job := testJob(t, store, jobId)
+	if job.State != orders.JobStateQueued || job.NextRunAt != 400 || job.LastError != "first error" || job.Attempts != 1 {
+		t.Errorf("retried job: %+v", job)
+	}
+
+	// not due until the retry time, then attempts is incremented again
+	_, err = store.ClaimOrderJob(300)
+	if !errors.Is(err, storage.ErrNoRecord) {
+		t.Errorf("claim before retry time: error '%v', expected '%v'", err, storage.ErrNoRecord)
+	}
+	job, err = store.ClaimOrderJob(400)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.ID != jobId || job.Attempts != 2 {
+		t.Errorf("claimed job %d with %d attempts, expected job %d with 2 attempts", job.ID, job.Attempts, jobId)
+	}
+
+	// failed job is never claimed
+	err = store.PutOrderJobFailed(jobId, "second error", 450)
+	if err != nil {
+		t.Fatal(err)
+	}
+	job = testJob(t, store, jobId)
+	if job.State != orders.JobStateFailed || job.LastError != "second error" || job.UpdatedAt != 450 {
+		t.Errorf("failed job: %+v", job)
+	}
+	_, err = store.ClaimOrderJob(1000)
+	if !errors.Is(err, storage.ErrNoRecord) {
+		t.Errorf("claim with only a failed job: error '%v', expected '%v'", err, storage.ErrNoRecord)
+	}
+}
+
+func TestOrderJobs_PostConflict(t *testing.T) {
+	store := newTestStorage(t)
+	certId := testInsertCert(t, store, "conflict")
+	orderId := testInsertOrder(t, store, certId, "pending", false, 0, 1)
+	jobId := testPostJob(t, store, orderId, 0, 100)
+
+	newPayload := orders.NewJobPayload{
+		OrderID:   orderId,
+		Priority:  1,
+		NextRunAt: 200,
+		CreatedAt: 2,
+		UpdatedAt: 2,
+	}
+
+	// queued and running jobs are not replaced
+	_, err := store.PostOrderJob(newPayload)
+	if !errors.Is(err, orders.ErrOrderAlreadyProcessing) {
+		t.Errorf("post with queued job: error '%v', expected '%v'", err, orders.ErrOrderAlreadyProcessing)
+	}
+	_, err = store.ClaimOrderJob(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.PostOrderJob(newPayload)
+	if !errors.Is(err, orders.ErrOrderAlreadyProcessing) {
+		t.Errorf("post with running job: error '%v', expected '%v'", err, orders.ErrOrderAlreadyProcessing)
+	}
+
+	// a failed job is reset and queued again
+	err = store.PutOrderJobFailed(jobId, "some error", 150)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := store.PostOrderJob(newPayload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != jobId {
+		t.Errorf("post with failed job returned job %d, expected existing job %d", id, jobId)
+	}
+
+	job := testJob(t, store, jobId)
+	if job.State != orders.JobStateQueued || job.Attempts != 0 || job.LastError != "" ||
+		job.Priority != 1 || job.NextRunAt != 200 || job.CreatedAt != 1 || job.UpdatedAt != 2 {
+		t.Errorf("reset job: %+v", job)
+	}
+}
+
+func TestOrderJobs_ResetRunning(t *testing.T) {
+	store := newTestStorage(t)
+	certId := testInsertCert(t, store, "reset")
+
+	runningId := testPostJob(t, store, testInsertOrder(t, store, certId, "pending", false, 0, 1), 1, 100)
+	queuedId := testPostJob(t, store, testInsertOrder(t, store, certId, "pending", false, 0, 2), 0, 500)
+	failedId := testPostJob(t, store, testInsertOrder(t, store, certId, "pending", false, 0, 3), 0, 100)
+
+	_, err := store.ClaimOrderJob(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.ClaimOrderJob(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.PutOrderJobFailed(failedId, "some error", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := store.ResetRunningOrderJobs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("reset %d jobs, expected 1", count)
+	}
+
+	// interrupted attempt is not counted
+	expected := map[int]struct {
+		state    string
+		attempts int
+	}{
+		runningId: {orders.JobStateQueued, 0},
+		queuedId:  {orders.JobStateQueued, 0},
+		failedId:  {orders.JobStateFailed, 1},
+	}
+	for id, exp := range expected {
+		job := testJob(t, store, id)
+		if job.State != exp.state || job.Attempts != exp.attempts {
+			t.Errorf("job %d is %s with %d attempts, expected %s with %d attempts", id, job.State, job.Attempts,
+				exp.state, exp.attempts)
+		}
+	}
+}
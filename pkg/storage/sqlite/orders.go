@@ -3,6 +3,7 @@ package sqlite
 import (
 	"database/sql"
 	"legocerthub-backend/pkg/acme"
+	"legocerthub-backend/pkg/diagnostics"
 	"legocerthub-backend/pkg/domain/orders"
 	"legocerthub-backend/pkg/domain/private_keys"
 )
@@ -25,6 +26,8 @@ type orderDb struct {
 	pem            sql.NullString
 	validFrom      sql.NullInt32
 	validTo        sql.NullInt32
+	diagnostics    sql.NullString // stored as json array
+	deployResult   sql.NullString // stored as json object
 	createdAt      int
 	updatedAt      int
 }
@@ -59,6 +62,8 @@ func (order orderDb) toOrder(store *Storage) orders.Order {
 		Pem:            nullStringToString(order.pem),
 		ValidFrom:      nullInt32ToInt(order.validFrom),
 		ValidTo:        nullInt32ToInt(order.validTo),
+		Diagnostics:    diagnostics.EntriesFromJson(nullStringToString(order.diagnostics)),
+		DeployResult:   orders.DeployResultFromJson(nullStringToString(order.deployResult)),
 		CreatedAt:      order.createdAt,
 		UpdatedAt:      order.updatedAt,
 	}
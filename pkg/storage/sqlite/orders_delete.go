@@ -0,0 +1,104 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"legocerthub-backend/pkg/domain/orders"
+)
+
+// GetPrunableOrders returns the orders that the retention policy would delete. For
+// each certificate, valid orders beyond the newest keepValidOrders are returned,
+// as are invalid orders created before invalidCreatedBefore. Revoked orders and
+// orders that are not valid or invalid (i.e. still being worked) are never returned.
+func (store *Storage) GetPrunableOrders(keepValidOrders int, invalidCreatedBefore int) (prunable []orders.PrunableOrder, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// valid_rank 1 is the cert's newest (current) valid order
+	query := `
+	SELECT
+		ao.id, c.id, c.name, ao.status, ao.valid_to, ao.created_at
+	FROM
+		(
+			SELECT
+				id, certificate_id, status, valid_to, created_at,
+				ROW_NUMBER() OVER (
+					PARTITION BY certificate_id, status
+					ORDER BY valid_to DESC, id DESC
+				) AS valid_rank
+			FROM
+				acme_orders
+			WHERE
+				known_revoked = 0
+		) ao
+		JOIN certificates c on (ao.certificate_id = c.id)
+	WHERE
+		(ao.status = "valid" AND ao.valid_rank > $1)
+		OR
+		(ao.status = "invalid" AND ao.created_at < $2)
+	ORDER BY
+		c.id,
+		ao.id
+	`
+
+	rows, err := store.db.QueryContext(ctx, query, keepValidOrders, invalidCreatedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var order orders.PrunableOrder
+		var validTo sql.NullInt32
+
+		err = rows.Scan(
+			&order.ID,
+			&order.CertificateID,
+			&order.CertificateName,
+			&order.Status,
+			&validTo,
+			&order.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		order.ValidTo = nullInt32ToInt(validTo)
+
+		prunable = append(prunable, order)
+	}
+
+	return prunable, nil
+}
+
+// DeleteOrders deletes the specified orders (and any jobs for them)
+func (store *Storage) DeleteOrders(orderIds []int) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+	DELETE FROM
+		acme_orders
+	WHERE
+		id = $1
+	`
+
+	for _, orderId := range orderIds {
+		_, err = tx.ExecContext(ctx, query, orderId)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
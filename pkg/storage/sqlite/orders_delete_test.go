@@ -0,0 +1,71 @@
+package sqlite
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetPrunableOrders(t *testing.T) {
+	store := newTestStorage(t)
+
+	certA := testInsertCert(t, store, "cert-a")
+	aValidOld := testInsertOrder(t, store, certA, "valid", false, 100, 1)
+	aValidMid := testInsertOrder(t, store, certA, "valid", false, 200, 2)
+	testInsertOrder(t, store, certA, "valid", false, 300, 3) // current
+	testInsertOrder(t, store, certA, "valid", true, 400, 4)  // revoked
+	aInvalidOld := testInsertOrder(t, store, certA, "invalid", false, 0, 10)
+	testInsertOrder(t, store, certA, "invalid", false, 0, 1000)
+	testInsertOrder(t, store, certA, "pending", false, 0, 11)
+	testInsertOrder(t, store, certA, "processing", false, 0, 12)
+	testInsertOrder(t, store, certA, "ready", false, 0, 13)
+
+	certB := testInsertCert(t, store, "cert-b")
+	testInsertOrder(t, store, certB, "valid", true, 50, 21)   // revoked
+	testInsertOrder(t, store, certB, "valid", false, 500, 22) // current
+	bInvalidOld := testInsertOrder(t, store, certB, "invalid", false, 0, 20)
+	testInsertOrder(t, store, certB, "invalid", true, 0, 23) // revoked
+	testInsertOrder(t, store, certB, "processing", false, 0, 24)
+
+	tests := []struct {
+		keepValidOrders      int
+		invalidCreatedBefore int
+		expected             []int
+	}{
+		{1, 500, []int{aValidOld, aValidMid, aInvalidOld, bInvalidOld}},
+		{2, 500, []int{aValidOld, aInvalidOld, bInvalidOld}},
+		{3, 15, []int{aInvalidOld}},
+		{10, 0, nil},
+	}
+
+	for _, test := range tests {
+		prunable, err := store.GetPrunableOrders(test.keepValidOrders, test.invalidCreatedBefore)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var ids []int
+		for _, order := range prunable {
+			ids = append(ids, order.ID)
+
+			if order.Status != "valid" && order.Status != "invalid" {
+				t.Errorf("keep %d: order %d with status %s returned", test.keepValidOrders, order.ID, order.Status)
+			}
+		}
+
+		if !reflect.DeepEqual(ids, test.expected) {
+			t.Errorf("keep %d, invalid before %d: prunable %v, expected %v", test.keepValidOrders,
+				test.invalidCreatedBefore, ids, test.expected)
+		}
+	}
+
+	// returned fields
+	prunable, err := store.GetPrunableOrders(1, 500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := prunable[0]
+	if first.CertificateID != certA || first.CertificateName != "cert-a" || first.ValidTo == nil ||
+		*first.ValidTo != 100 || first.CreatedAt != 1 {
+		t.Errorf("first prunable order: %+v", first)
+	}
+}
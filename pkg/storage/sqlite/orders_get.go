@@ -44,11 +44,14 @@ func (store *Storage) GetAllValidCurrentOrders(q pagination_sort.Query) (orders
 		/* order's cert */
 		c.id, c.name, c.description, c.subject, c.subject_alts, c.challenge_method, 
 		c.csr_org, c.csr_ou, c.csr_country, c.csr_state, c.csr_city, c.created_at, c.updated_at,
-		c.api_key, c.api_key_new, c.api_key_via_url,
+		c.api_key, c.api_key_new, c.api_key_via_url, c.http01_self_check, c.deploy_hooks,
+		c.rotate_key, c.rotate_key_algorithm, c.csr_pem,
 		
 		/* cert's key */
-		ck.id, ck.name, ck.description, ck.algorithm, ck.pem, ck.api_key, ck.api_key_new,
-		ck.api_key_disabled, ck.api_key_via_url, ck.created_at, ck.updated_at,
+		COALESCE(ck.id, -2), COALESCE(ck.name, 'null'), COALESCE(ck.description, 'null'),
+		COALESCE(ck.algorithm, 'null'), COALESCE(ck.pem, 'null'), COALESCE(ck.api_key, 'null'),
+		COALESCE(ck.api_key_new, 'null'), COALESCE(ck.api_key_disabled, false),
+		COALESCE(ck.api_key_via_url, false), COALESCE(ck.created_at, -2), COALESCE(ck.updated_at, -2),
 
 		/* cert's account */
 		ca.id, ca.name, ca.description, ca.status, ca.email, ca.accepted_tos,
@@ -82,7 +85,7 @@ func (store *Storage) GetAllValidCurrentOrders(q pagination_sort.Query) (orders
 		AND
 		ao.known_revoked = 0
 		AND
-		ao.valid_to > $1
+		ao.valid_to > $2
 		AND
 		ao.pem NOT NULL
 		AND
@@ -149,6 +152,11 @@ func (store *Storage) GetAllValidCurrentOrders(q pagination_sort.Query) (orders
 			&oneOrder.certificate.apiKey,
 			&oneOrder.certificate.apiKeyNew,
 			&oneOrder.certificate.apiKeyViaUrl,
+			&oneOrder.certificate.http01SelfCheck,
+			&oneOrder.certificate.deployHooks,
+			&oneOrder.certificate.rotateKey,
+			&oneOrder.certificate.rotateKeyAlgorithm,
+			&oneOrder.certificate.csrPem,
 
 			&oneOrder.certificate.certificateKeyDb.id,
 			&oneOrder.certificate.certificateKeyDb.name,
@@ -249,17 +257,20 @@ func (store *Storage) GetOrdersByCert(certId int, q pagination_sort.Query) (orde
 	SELECT
 		/* order */
 		ao.id, ao.acme_location, ao.status, ao.known_revoked, ao.error, ao.expires, ao.dns_identifiers, 
-		ao.authorizations, ao.finalize, ao.certificate_url, ao.pem, ao.valid_from, ao.valid_to, ao.created_at,
-		ao.updated_at, 
+		ao.authorizations, ao.finalize, ao.certificate_url, ao.pem, ao.valid_from, ao.valid_to, ao.diagnostics,
+		ao.deploy_result, ao.created_at, ao.updated_at, 
 
 		/* order's cert */
 		c.id, c.name, c.description, c.subject, c.subject_alts, c.challenge_method, 
 		c.csr_org, c.csr_ou, c.csr_country, c.csr_state, c.csr_city, c.created_at, c.updated_at,
-		c.api_key, c.api_key_new, c.api_key_via_url,
+		c.api_key, c.api_key_new, c.api_key_via_url, c.http01_self_check, c.deploy_hooks,
+		c.rotate_key, c.rotate_key_algorithm, c.csr_pem,
 		
 		/* cert's key */
-		ck.id, ck.name, ck.description, ck.algorithm, ck.pem, ck.api_key, ck.api_key_new, ck.api_key_disabled,
-		ck.api_key_via_url,	ck.created_at, ck.updated_at,
+		COALESCE(ck.id, -2), COALESCE(ck.name, 'null'), COALESCE(ck.description, 'null'),
+		COALESCE(ck.algorithm, 'null'), COALESCE(ck.pem, 'null'), COALESCE(ck.api_key, 'null'),
+		COALESCE(ck.api_key_new, 'null'), COALESCE(ck.api_key_disabled, false),
+		COALESCE(ck.api_key_via_url, false), COALESCE(ck.created_at, -2), COALESCE(ck.updated_at, -2),
 
 		/* cert's account */
 		ca.id, ca.name, ca.description, ca.status, ca.email, ca.accepted_tos,
@@ -328,6 +339,8 @@ func (store *Storage) GetOrdersByCert(certId int, q pagination_sort.Query) (orde
 			&oneOrder.pem,
 			&oneOrder.validFrom,
 			&oneOrder.validTo,
+			&oneOrder.diagnostics,
+			&oneOrder.deployResult,
 			&oneOrder.createdAt,
 			&oneOrder.updatedAt,
 
@@ -347,6 +360,11 @@ func (store *Storage) GetOrdersByCert(certId int, q pagination_sort.Query) (orde
 			&oneOrder.certificate.apiKey,
 			&oneOrder.certificate.apiKeyNew,
 			&oneOrder.certificate.apiKeyViaUrl,
+			&oneOrder.certificate.http01SelfCheck,
+			&oneOrder.certificate.deployHooks,
+			&oneOrder.certificate.rotateKey,
+			&oneOrder.certificate.rotateKeyAlgorithm,
+			&oneOrder.certificate.csrPem,
 
 			&oneOrder.certificate.certificateKeyDb.id,
 			&oneOrder.certificate.certificateKeyDb.name,
@@ -456,24 +474,38 @@ func (store *Storage) GetAllIncompleteOrderIds() (orderIds []int, err error) {
 	return orderIds, nil
 }
 
-// GetExpiringCertIds returns a slice of certificate ids for certificates that are valid for less
-// than the specified maxTimeRemaining. If a cert does not have a valid order, it is excluded.
-func (store *Storage) GetExpiringCertIds(maxTimeRemaining time.Duration) (certIds []int, err error) {
+// GetCertRenewalTimes returns when each certificate that has a valid order is due to be
+// renewed. Each cert's renewal policy is used (days remaining or fraction of lifetime remaining)
+// and if a cert does not have a policy, it is due when valid for less than the specified
+// defaultRemaining. If a cert does not have a valid order, it is excluded.
+func (store *Storage) GetCertRenewalTimes(defaultRemaining time.Duration) (renewals []orders.CertRenewal, err error) {
 	// query
 	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
 	defer cancel()
 
+	// valid_from and valid_to are from the row with MAX(valid_to)
 	query := `
 		SELECT
-			ao.certificate_id
+			ao.certificate_id,
+			CAST(
+				CASE
+					WHEN c.renewal_days_remaining IS NOT NULL
+						THEN ao.valid_to - (c.renewal_days_remaining * 86400)
+					WHEN c.renewal_lifetime_fraction IS NOT NULL AND ao.valid_from IS NOT NULL
+						THEN ao.valid_to - (c.renewal_lifetime_fraction * (ao.valid_to - ao.valid_from))
+					ELSE
+						ao.valid_to - $1
+				END
+			AS integer) AS renew_at
 		FROM
 			acme_orders ao
+			LEFT JOIN certificates c on (ao.certificate_id = c.id)
 		WHERE 
 			ao.status = "valid"
 			AND
 			ao.known_revoked = 0
 			AND
-			ao.valid_to > $1
+			ao.valid_to > $2
 			AND
 			ao.pem NOT NULL
 			AND
@@ -482,17 +514,14 @@ func (store *Storage) GetExpiringCertIds(maxTimeRemaining time.Duration) (certId
 			ao.certificate_id
 		HAVING
 			MAX(ao.valid_to)
-			AND
-			ao.valid_to < $2
+		ORDER BY
+			renew_at
 		`
 
-	// calculate the max expiration (unix) for the query
-	maxExpirationUnix := time.Now().Add(maxTimeRemaining).Unix()
-
 	// get records
 	rows, err := store.db.QueryContext(ctx, query,
+		int(defaultRemaining.Seconds()),
 		time.Now().Unix(),
-		maxExpirationUnix,
 	)
 	if err != nil {
 		return nil, err
@@ -500,17 +529,20 @@ func (store *Storage) GetExpiringCertIds(maxTimeRemaining time.Duration) (certId
 	defer rows.Close()
 
 	for rows.Next() {
-		var certId int
+		var renewal orders.CertRenewal
 
-		err = rows.Scan(&certId)
+		err = rows.Scan(
+			&renewal.CertificateID,
+			&renewal.RenewAt,
+		)
 		if err != nil {
 			return nil, err
 		}
 
-		certIds = append(certIds, certId)
+		renewals = append(renewals, renewal)
 	}
 
-	return certIds, nil
+	return renewals, nil
 }
 
 // GetNewestIncompleteCertOrderId returns the most recent incomplete order for a specified certId,
@@ -557,6 +589,36 @@ func (store *Storage) GetNewestIncompleteCertOrderId(certId int) (orderId int, e
 	return orderId, nil
 }
 
+// KeyIssuedCert returns true if the key finalized any valid order for the cert
+// (i.e. a certificate has already been issued with the key)
+func (store *Storage) KeyIssuedCert(certId int, keyId int) (issued bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	SELECT
+		EXISTS(
+			SELECT
+				id
+			FROM
+				acme_orders
+			WHERE
+				certificate_id = $1
+				AND
+				finalized_key_id = $2
+				AND
+				status = "valid"
+		)
+	`
+
+	err = store.db.QueryRowContext(ctx, query, certId, keyId).Scan(&issued)
+	if err != nil {
+		return false, err
+	}
+
+	return issued, nil
+}
+
 // GetOneOrder fetches a specific Order by ID
 func (store *Storage) GetOneOrder(orderId int) (order orders.Order, err error) {
 	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
@@ -566,17 +628,20 @@ func (store *Storage) GetOneOrder(orderId int) (order orders.Order, err error) {
 	SELECT
 		/* order */
 		ao.id, ao.acme_location, ao.status, ao.known_revoked, ao.error, ao.expires, ao.dns_identifiers, 
-		ao.authorizations, ao.finalize, ao.certificate_url, ao.pem, ao.valid_from, ao.valid_to, ao.created_at,
-		ao.updated_at, 
+		ao.authorizations, ao.finalize, ao.certificate_url, ao.pem, ao.valid_from, ao.valid_to, ao.diagnostics,
+		ao.deploy_result, ao.created_at, ao.updated_at, 
 
 		/* order's cert */
 		c.id, c.name, c.description, c.subject, c.subject_alts, c.challenge_method, 
 		c.csr_org, c.csr_ou, c.csr_country, c.csr_state, c.csr_city, c.created_at, c.updated_at,
-		c.api_key, c.api_key_new, c.api_key_via_url,
+		c.api_key, c.api_key_new, c.api_key_via_url, c.http01_self_check, c.deploy_hooks,
+		c.rotate_key, c.rotate_key_algorithm, c.csr_pem,
 		
 		/* cert's key */
-		ck.id, ck.name, ck.description, ck.algorithm, ck.pem, ck.api_key, ak.api_key_new, ck.api_key_disabled,
-		ck.api_key_via_url,	ck.created_at, ck.updated_at,
+		COALESCE(ck.id, -2), COALESCE(ck.name, 'null'), COALESCE(ck.description, 'null'),
+		COALESCE(ck.algorithm, 'null'), COALESCE(ck.pem, 'null'), COALESCE(ck.api_key, 'null'),
+		COALESCE(ck.api_key_new, 'null'), COALESCE(ck.api_key_disabled, false),
+		COALESCE(ck.api_key_via_url, false), COALESCE(ck.created_at, -2), COALESCE(ck.updated_at, -2),
 
 		/* cert's account */
 		ca.id, ca.name, ca.description, ca.status, ca.email, ca.accepted_tos,
@@ -627,6 +692,8 @@ func (store *Storage) GetOneOrder(orderId int) (order orders.Order, err error) {
 		&oneOrder.pem,
 		&oneOrder.validFrom,
 		&oneOrder.validTo,
+		&oneOrder.diagnostics,
+		&oneOrder.deployResult,
 		&oneOrder.createdAt,
 		&oneOrder.updatedAt,
 
@@ -646,6 +713,11 @@ func (store *Storage) GetOneOrder(orderId int) (order orders.Order, err error) {
 		&oneOrder.certificate.apiKey,
 		&oneOrder.certificate.apiKeyNew,
 		&oneOrder.certificate.apiKeyViaUrl,
+		&oneOrder.certificate.http01SelfCheck,
+		&oneOrder.certificate.deployHooks,
+		&oneOrder.certificate.rotateKey,
+		&oneOrder.certificate.rotateKeyAlgorithm,
+		&oneOrder.certificate.csrPem,
 
 		&oneOrder.certificate.certificateKeyDb.id,
 		&oneOrder.certificate.certificateKeyDb.name,
@@ -2,7 +2,10 @@ package sqlite
 
 import (
 	"context"
+	"encoding/json"
+	"legocerthub-backend/pkg/diagnostics"
 	"legocerthub-backend/pkg/domain/orders"
+	"legocerthub-backend/pkg/storage"
 )
 
 // UpdateOrderAcme updates the specified order ID with acme.Order response
@@ -180,3 +183,77 @@ func (store *Storage) RevokeOrder(orderId int) (err error) {
 
 	return nil
 }
+
+// PutOrderDiagnostics replaces the diagnostics of the specified order ID
+func (store *Storage) PutOrderDiagnostics(orderId int, entries []diagnostics.Entry) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// store as json
+	entriesJson, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	// update existing record
+	query := `
+		UPDATE
+			acme_orders
+		SET
+			diagnostics = $1
+		WHERE
+			id = $2
+		`
+
+	result, err := store.db.ExecContext(ctx, query,
+		string(entriesJson),
+		orderId,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return storage.ErrNoRecord
+	}
+
+	return nil
+}
+
+// PutOrderDeployResult saves the result of running the order's deploy hooks
+func (store *Storage) PutOrderDeployResult(orderId int, result orders.DeployResult) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// store as json
+	resultJson, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	// update existing record
+	query := `
+		UPDATE
+			acme_orders
+		SET
+			deploy_result = $1
+		WHERE
+			id = $2
+		`
+
+	_, err = store.db.ExecContext(ctx, query,
+		string(resultJson),
+		orderId,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -7,7 +7,6 @@ import (
 	"legocerthub-backend/pkg/domain/app/auth"
 	"net/url"
 	"os"
-	"strconv"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -18,7 +17,7 @@ import (
 // config for DB
 const dbTimeout = time.Duration(5 * time.Second)
 const dbFilename = "/lego-certhub.db"
-const DbCurrentUserVersion = 1
+const DbCurrentUserVersion = 16
 
 var dbOptions = url.Values{
 	"_fk": []string{"true"},
@@ -107,39 +106,69 @@ func OpenStorage(app App, dataPath string) (*Storage, error) {
 			store.logger.Errorf("failed to populate new database file", err)
 			return nil, err
 		}
-	} else {
-		// check and do db schema upgrades, if needed
-		fileUserVersion := -1
-		// try upgrading until version matches or an error occurs
-		for fileUserVersion != DbCurrentUserVersion && err == nil {
-			// get db file user_version
-			query := `PRAGMA user_version`
-			row := store.db.QueryRowContext(ctx, query)
-			err = row.Scan(
-				&fileUserVersion,
-			)
-			if err != nil {
-				return nil, err
-			}
-
-			// take incremental migration action, if needed
-			switch fileUserVersion {
-			case 0:
-				err = store.migrateV0toV1()
-			case DbCurrentUserVersion:
-				store.logger.Debugf("database user_version is current (%d)", fileUserVersion)
-				// no-op, loop will end due to version ==
-			default:
-				err = errors.New("unsupported user_version found in db file")
-			}
-		}
+	}
 
-		// err check from upgrade loop
+	// check and do db schema upgrades, if needed (a new db is populated with
+	// the v1 schema and then upgraded the same way an existing db would be)
+	fileUserVersion := -1
+	// try upgrading until version matches or an error occurs
+	for fileUserVersion != DbCurrentUserVersion && err == nil {
+		// get db file user_version
+		query := `PRAGMA user_version`
+		row := store.db.QueryRowContext(ctx, query)
+		err = row.Scan(
+			&fileUserVersion,
+		)
 		if err != nil {
-			store.logger.Errorf("failed to update database file to latest user_version (%d), currently %d (%s)", DbCurrentUserVersion, fileUserVersion, err)
 			return nil, err
 		}
 
+		// take incremental migration action, if needed
+		switch fileUserVersion {
+		case 0:
+			err = store.migrateV0toV1()
+		case 1:
+			err = store.migrateV1toV2()
+		case 2:
+			err = store.migrateV2toV3()
+		case 3:
+			err = store.migrateV3toV4()
+		case 4:
+			err = store.migrateV4toV5()
+		case 5:
+			err = store.migrateV5toV6()
+		case 6:
+			err = store.migrateV6toV7()
+		case 7:
+			err = store.migrateV7toV8()
+		case 8:
+			err = store.migrateV8toV9()
+		case 9:
+			err = store.migrateV9toV10()
+		case 10:
+			err = store.migrateV10toV11()
+		case 11:
+			err = store.migrateV11toV12()
+		case 12:
+			err = store.migrateV12toV13()
+		case 13:
+			err = store.migrateV13toV14()
+		case 14:
+			err = store.migrateV14toV15()
+		case 15:
+			err = store.migrateV15toV16()
+		case DbCurrentUserVersion:
+			store.logger.Debugf("database user_version is current (%d)", fileUserVersion)
+			// no-op, loop will end due to version ==
+		default:
+			err = errors.New("unsupported user_version found in db file")
+		}
+	}
+
+	// err check from upgrade loop
+	if err != nil {
+		store.logger.Errorf("failed to update database file to latest user_version (%d), currently %d (%s)", DbCurrentUserVersion, fileUserVersion, err)
+		return nil, err
 	}
 
 	return store, nil
@@ -155,7 +184,8 @@ func (store *Storage) Close() error {
 	return nil
 }
 
-// populateNewDb creates the tables in the db file and sets the db version
+// populateNewDb creates the v1 tables in the db file and sets the db version
+// to 1. Any later schema changes are applied by the migration functions.
 func (store *Storage) populateNewDb() error {
 	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
 	defer cancel()
@@ -167,9 +197,8 @@ func (store *Storage) populateNewDb() error {
 	}
 	defer tx.Rollback()
 
-	// set db user_version
-	// No injection protection since const isn't user editable
-	query := `PRAGMA user_version = ` + strconv.Itoa(DbCurrentUserVersion)
+	// set db user_version (createDBTables is the v1 schema)
+	query := `PRAGMA user_version = 1`
 
 	_, err = tx.ExecContext(ctx, query)
 	if err != nil {
@@ -0,0 +1,89 @@
+package sqlite
+
+import (
+	"context"
+)
+
+// CHANGES v9 to v10:
+// - webhooks
+//     - New table, webhook subscriptions (url, encrypted secret, event types)
+// - webhook_deliveries
+//     - New table, each delivery of an event to a webhook (also serves as the
+//       delivery log)
+
+// updates the storage db from user_version 9 to user_version 10, if it cannot
+// do so, an error is returned and modification is aborted
+func (store *Storage) migrateV9toV10() error {
+	store.logger.Info("updating database user_version from 9 to 10")
+
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// create sql transaction to roll back in the event an error occurs
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// add webhooks
+	query := `CREATE TABLE IF NOT EXISTS webhooks (
+		id integer PRIMARY KEY AUTOINCREMENT NOT NULL UNIQUE,
+		name text NOT NULL UNIQUE COLLATE NOCASE,
+		description text NOT NULL,
+		url text NOT NULL,
+		secret text NOT NULL,
+		event_types text NOT NULL DEFAULT '',
+		enabled integer NOT NULL DEFAULT 1 CHECK(enabled IN (0,1)),
+		created_at integer NOT NULL,
+		updated_at integer NOT NULL
+	)`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// add webhook deliveries
+	query = `CREATE TABLE IF NOT EXISTS webhook_deliveries (
+		id integer PRIMARY KEY AUTOINCREMENT NOT NULL UNIQUE,
+		webhook_id integer NOT NULL,
+		event_type text NOT NULL,
+		payload text NOT NULL,
+		state text NOT NULL,
+		attempts integer NOT NULL DEFAULT 0,
+		next_attempt_at integer NOT NULL,
+		response_status integer,
+		last_error text NOT NULL DEFAULT '',
+		created_at integer NOT NULL,
+		updated_at integer NOT NULL,
+		FOREIGN KEY (webhook_id)
+			REFERENCES webhooks (id)
+				ON DELETE CASCADE
+				ON UPDATE NO ACTION
+	)`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// update user_version to 10
+	query = `
+		PRAGMA user_version = 10
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// no errors, commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	store.logger.Info("database user_version successfully upgraded from 9 to 10")
+	return nil
+}
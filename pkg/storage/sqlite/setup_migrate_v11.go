@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"context"
+)
+
+// CHANGES v10 to v11:
+// - certificates
+//     - Add deploy_hooks field (json array of commands to run after a new
+//       certificate is issued)
+// - acme_orders
+//     - Add deploy_result field (json object with the result of running the
+//       certificate's deploy hooks for the order)
+
+// updates the storage db from user_version 10 to user_version 11, if it cannot
+// do so, an error is returned and modification is aborted
+func (store *Storage) migrateV10toV11() error {
+	store.logger.Info("updating database user_version from 10 to 11")
+
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// create sql transaction to roll back in the event an error occurs
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// add deploy hooks to certificates
+	query := `
+		ALTER TABLE certificates ADD COLUMN deploy_hooks text NOT NULL DEFAULT '[]'
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// add deploy result to acme_orders
+	query = `
+		ALTER TABLE acme_orders ADD COLUMN deploy_result text
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// update user_version to 11
+	query = `
+		PRAGMA user_version = 11
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// no errors, commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	store.logger.Info("database user_version successfully upgraded from 10 to 11")
+	return nil
+}
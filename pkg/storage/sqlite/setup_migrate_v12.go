@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"context"
+)
+
+// CHANGES v11 to v12:
+// - deploy_targets
+//     - New table, remote hosts a certificate is pushed to over SSH/SFTP after
+//       it is issued (auth key is a private_keys row, last_result is json)
+
+// updates the storage db from user_version 11 to user_version 12, if it cannot
+// do so, an error is returned and modification is aborted
+func (store *Storage) migrateV11toV12() error {
+	store.logger.Info("updating database user_version from 11 to 12")
+
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// create sql transaction to roll back in the event an error occurs
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// add deploy targets
+	query := `CREATE TABLE IF NOT EXISTS deploy_targets (
+		id integer PRIMARY KEY AUTOINCREMENT NOT NULL UNIQUE,
+		certificate_id integer NOT NULL,
+		name text NOT NULL UNIQUE COLLATE NOCASE,
+		description text NOT NULL,
+		host text NOT NULL,
+		port integer NOT NULL DEFAULT 22,
+		username text NOT NULL,
+		private_key_id integer NOT NULL,
+		host_key text NOT NULL,
+		key_path text NOT NULL,
+		fullchain_path text NOT NULL,
+		post_command text NOT NULL DEFAULT '',
+		enabled integer NOT NULL DEFAULT 1 CHECK(enabled IN (0,1)),
+		last_result text,
+		created_at integer NOT NULL,
+		updated_at integer NOT NULL,
+		FOREIGN KEY (certificate_id)
+			REFERENCES certificates (id)
+				ON DELETE CASCADE
+				ON UPDATE NO ACTION,
+		FOREIGN KEY (private_key_id)
+			REFERENCES private_keys (id)
+				ON DELETE RESTRICT
+				ON UPDATE NO ACTION
+	)`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// update user_version to 12
+	query = `
+		PRAGMA user_version = 12
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// no errors, commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	store.logger.Info("database user_version successfully upgraded from 11 to 12")
+	return nil
+}
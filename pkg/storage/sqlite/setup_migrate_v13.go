@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"context"
+)
+
+// CHANGES v12 to v13:
+// - certificates
+//     - Add rotate_key and rotate_key_algorithm fields (generate a new private
+//       key each time the certificate is renewed, blank algorithm uses the
+//       current key's algorithm)
+// - private_keys
+//     - Add retire_at field (unix time after which a key replaced by rotation
+//       is deleted, once it is no longer in use)
+
+// updates the storage db from user_version 12 to user_version 13, if it cannot
+// do so, an error is returned and modification is aborted
+func (store *Storage) migrateV12toV13() error {
+	store.logger.Info("updating database user_version from 12 to 13")
+
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// create sql transaction to roll back in the event an error occurs
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// add key rotation to certificates
+	query := `
+		ALTER TABLE certificates ADD COLUMN rotate_key integer NOT NULL DEFAULT 0 CHECK(rotate_key IN (0,1))
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	query = `
+		ALTER TABLE certificates ADD COLUMN rotate_key_algorithm text NOT NULL DEFAULT ''
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// add retire time to private_keys
+	query = `
+		ALTER TABLE private_keys ADD COLUMN retire_at integer
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// update user_version to 13
+	query = `
+		PRAGMA user_version = 13
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// no errors, commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	store.logger.Info("database user_version successfully upgraded from 12 to 13")
+	return nil
+}
@@ -0,0 +1,151 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+)
+
+// CHANGES v13 to v14:
+// - certificates
+//     - Rebuild table to make private_key_id nullable (certificates defined by
+//       an uploaded CSR have no private key)
+//     - Add csr_pem field (the uploaded CSR, null if the certificate uses a
+//       private key)
+
+// updates the storage db from user_version 13 to user_version 14, if it cannot
+// do so, an error is returned and modification is aborted
+func (store *Storage) migrateV13toV14() error {
+	store.logger.Info("updating database user_version from 13 to 14")
+
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// foreign keys must be off while the table is rebuilt, otherwise dropping the
+	// old table would cascade to orders and deploy targets (the pragma can't be
+	// changed inside of a transaction, so use a dedicated connection)
+	conn, err := store.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, `PRAGMA foreign_keys = OFF`)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.Background(), `PRAGMA foreign_keys = ON`)
+	}()
+
+	// create sql transaction to roll back in the event an error occurs
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// new certificates table
+	query := `CREATE TABLE certificates_new (
+		id integer PRIMARY KEY AUTOINCREMENT NOT NULL UNIQUE,
+		private_key_id integer UNIQUE,
+		acme_account_id integer NOT NULL,
+		name text NOT NULL UNIQUE COLLATE NOCASE,
+		description text NOT NULL,
+		challenge_method text NOT NULL,
+		subject text NOT NULL,
+		subject_alts text NOT NULL,
+		csr_org text NOT NULL,
+		csr_ou text NOT NULL,
+		csr_country text NOT NULL,
+		csr_state text NOT NULL,
+		csr_city text NOT NULL,
+		api_key text NOT NULL,
+		api_key_new text NOT NULL DEFAULT '',
+		api_key_via_url integer NOT NULL DEFAULT 0 CHECK(api_key_via_url IN (0,1)),
+		created_at integer NOT NULL,
+		updated_at integer NOT NULL,
+		http01_self_check integer NOT NULL DEFAULT 0 CHECK(http01_self_check IN (0,1)),
+		renewal_days_remaining integer,
+		renewal_lifetime_fraction real,
+		notification_emails text NOT NULL DEFAULT '',
+		deploy_hooks text NOT NULL DEFAULT '[]',
+		rotate_key integer NOT NULL DEFAULT 0 CHECK(rotate_key IN (0,1)),
+		rotate_key_algorithm text NOT NULL DEFAULT '',
+		csr_pem text,
+		FOREIGN KEY (private_key_id)
+			REFERENCES private_keys (id)
+				ON DELETE RESTRICT
+				ON UPDATE NO ACTION,
+		FOREIGN KEY (acme_account_id)
+			REFERENCES acme_accounts (id)
+				ON DELETE RESTRICT
+				ON UPDATE NO ACTION,
+		CHECK(private_key_id IS NOT NULL OR csr_pem IS NOT NULL)
+	)`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// copy certificates
+	query = `
+		INSERT INTO certificates_new (id, private_key_id, acme_account_id, name, description,
+			challenge_method, subject, subject_alts, csr_org, csr_ou, csr_country, csr_state,
+			csr_city, api_key, api_key_new, api_key_via_url, created_at, updated_at,
+			http01_self_check, renewal_days_remaining, renewal_lifetime_fraction,
+			notification_emails, deploy_hooks, rotate_key, rotate_key_algorithm)
+		SELECT id, private_key_id, acme_account_id, name, description,
+			challenge_method, subject, subject_alts, csr_org, csr_ou, csr_country, csr_state,
+			csr_city, api_key, api_key_new, api_key_via_url, created_at, updated_at,
+			http01_self_check, renewal_days_remaining, renewal_lifetime_fraction,
+			notification_emails, deploy_hooks, rotate_key, rotate_key_algorithm
+		FROM certificates
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// replace old table
+	query = `
+		DROP TABLE certificates;
+		ALTER TABLE certificates_new RENAME TO certificates;
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// confirm references to certificates are still intact
+	rows, err := tx.Query(`PRAGMA foreign_key_check`)
+	if err != nil {
+		return err
+	}
+	fkViolation := rows.Next()
+	rows.Close()
+	if fkViolation {
+		return errors.New("foreign key violation after rebuilding certificates table")
+	}
+
+	// update user_version to 14
+	query = `
+		PRAGMA user_version = 14
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// no errors, commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	store.logger.Info("database user_version successfully upgraded from 13 to 14")
+	return nil
+}
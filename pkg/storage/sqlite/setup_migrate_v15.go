@@ -0,0 +1,76 @@
+package sqlite
+
+import (
+	"context"
+)
+
+// CHANGES v14 to v15:
+// - unmanaged_certificates
+//     - New table, certificates issued outside of LeGo that are imported to be
+//       monitored and served (optional key is a private_keys row, subject,
+//       issuer, and validity are parsed from the leaf of the pem chain)
+
+// updates the storage db from user_version 14 to user_version 15, if it cannot
+// do so, an error is returned and modification is aborted
+func (store *Storage) migrateV14toV15() error {
+	store.logger.Info("updating database user_version from 14 to 15")
+
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// create sql transaction to roll back in the event an error occurs
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// add unmanaged certificates
+	query := `CREATE TABLE IF NOT EXISTS unmanaged_certificates (
+		id integer PRIMARY KEY AUTOINCREMENT NOT NULL UNIQUE,
+		name text NOT NULL UNIQUE COLLATE NOCASE,
+		description text NOT NULL,
+		private_key_id integer UNIQUE,
+		pem text NOT NULL,
+		subject text NOT NULL,
+		subject_alts text NOT NULL,
+		issuer text NOT NULL,
+		serial text NOT NULL,
+		valid_from integer NOT NULL,
+		valid_to integer NOT NULL,
+		notification_emails text NOT NULL DEFAULT '',
+		api_key text NOT NULL,
+		api_key_new text NOT NULL DEFAULT '',
+		api_key_via_url integer NOT NULL DEFAULT 0 CHECK(api_key_via_url IN (0,1)),
+		created_at integer NOT NULL,
+		updated_at integer NOT NULL,
+		FOREIGN KEY (private_key_id)
+			REFERENCES private_keys (id)
+				ON DELETE RESTRICT
+				ON UPDATE NO ACTION
+	)`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// update user_version to 15
+	query = `
+		PRAGMA user_version = 15
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// no errors, commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	store.logger.Info("database user_version successfully upgraded from 14 to 15")
+	return nil
+}
@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"context"
+)
+
+// CHANGES v15 to v16:
+// - deploy_endpoints
+//     - New table, tls endpoints (host, port, and sni server name) that serve a
+//       certificate and are periodically checked against the certificate's
+//       newest valid order (last_check is the json result of the newest check)
+
+// updates the storage db from user_version 15 to user_version 16, if it cannot
+// do so, an error is returned and modification is aborted
+func (store *Storage) migrateV15toV16() error {
+	store.logger.Info("updating database user_version from 15 to 16")
+
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// create sql transaction to roll back in the event an error occurs
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// add deploy endpoints
+	query := `CREATE TABLE IF NOT EXISTS deploy_endpoints (
+		id integer PRIMARY KEY AUTOINCREMENT NOT NULL UNIQUE,
+		certificate_id integer NOT NULL,
+		host text NOT NULL,
+		port integer NOT NULL,
+		server_name text NOT NULL DEFAULT '',
+		enabled integer NOT NULL DEFAULT 1 CHECK(enabled IN (0,1)),
+		last_check text,
+		created_at integer NOT NULL,
+		updated_at integer NOT NULL,
+		UNIQUE(certificate_id, host, port, server_name),
+		FOREIGN KEY (certificate_id)
+			REFERENCES certificates (id)
+				ON DELETE CASCADE
+				ON UPDATE NO ACTION
+	)`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// update user_version to 16
+	query = `
+		PRAGMA user_version = 16
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// no errors, commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	store.logger.Info("database user_version successfully upgraded from 15 to 16")
+	return nil
+}
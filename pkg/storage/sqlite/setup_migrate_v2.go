@@ -0,0 +1,55 @@
+package sqlite
+
+import (
+	"context"
+)
+
+// CHANGES v1 to v2:
+// - acme_orders
+//     - Add diagnostics field (json array of diagnostic entries from the
+//       most recent order job)
+
+// updates the storage db from user_version 1 to user_version 2, if it cannot
+// do so, an error is returned and modification is aborted
+func (store *Storage) migrateV1toV2() error {
+	store.logger.Info("updating database user_version from 1 to 2")
+
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// create sql transaction to roll back in the event an error occurs
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// add diagnostics to acme_orders
+	query := `
+		ALTER TABLE acme_orders ADD COLUMN diagnostics text
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// update user_version to 2
+	query = `
+		PRAGMA user_version = 2
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// no errors, commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	store.logger.Info("database user_version successfully upgraded from 1 to 2")
+	return nil
+}
@@ -0,0 +1,72 @@
+package sqlite
+
+import (
+	"context"
+)
+
+// CHANGES v2 to v3:
+// - challenge_providers
+//     - New table to store challenge provider configs (previously only in
+//       the config file). config is encrypted as it contains credentials.
+
+// updates the storage db from user_version 2 to user_version 3, if it cannot
+// do so, an error is returned and modification is aborted
+func (store *Storage) migrateV2toV3() error {
+	store.logger.Info("updating database user_version from 2 to 3")
+
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// create sql transaction to roll back in the event an error occurs
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// create challenge_providers table
+	query := `
+	CREATE TABLE IF NOT EXISTS challenge_providers (
+		id integer PRIMARY KEY AUTOINCREMENT NOT NULL UNIQUE,
+		type text NOT NULL,
+		enabled integer NOT NULL DEFAULT 0 CHECK(enabled IN (0,1)),
+		config text NOT NULL,
+		created_at integer NOT NULL,
+		updated_at integer NOT NULL
+	)
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// only one provider of each type
+	query = `
+		CREATE UNIQUE INDEX challenge_providers_type ON challenge_providers (type)
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// update user_version to 3
+	query = `
+		PRAGMA user_version = 3
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// no errors, commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	store.logger.Info("database user_version successfully upgraded from 2 to 3")
+	return nil
+}
@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"context"
+)
+
+// CHANGES v3 to v4:
+// - challenge_providers
+//     - Add name field, multiple providers of the same type are allowed as
+//       long as each has a unique name
+
+// updates the storage db from user_version 3 to user_version 4, if it cannot
+// do so, an error is returned and modification is aborted
+func (store *Storage) migrateV3toV4() error {
+	store.logger.Info("updating database user_version from 3 to 4")
+
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// create sql transaction to roll back in the event an error occurs
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// add name to challenge_providers
+	query := `
+		ALTER TABLE challenge_providers ADD COLUMN name text NOT NULL DEFAULT ''
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// replace unique type index with unique type + name
+	query = `
+		DROP INDEX challenge_providers_type
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	query = `
+		CREATE UNIQUE INDEX challenge_providers_type_name ON challenge_providers (type, name)
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// update user_version to 4
+	query = `
+		PRAGMA user_version = 4
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// no errors, commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	store.logger.Info("database user_version successfully upgraded from 3 to 4")
+	return nil
+}
@@ -0,0 +1,55 @@
+package sqlite
+
+import (
+	"context"
+)
+
+// CHANGES v4 to v5:
+// - certificates
+//     - Add http01_self_check field, if true the http-01 challenge resource is
+//       fetched by LeGo before asking the ACME server to validate it
+
+// updates the storage db from user_version 4 to user_version 5, if it cannot
+// do so, an error is returned and modification is aborted
+func (store *Storage) migrateV4toV5() error {
+	store.logger.Info("updating database user_version from 4 to 5")
+
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// create sql transaction to roll back in the event an error occurs
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// add http01_self_check to certificates
+	query := `
+		ALTER TABLE certificates ADD COLUMN http01_self_check integer NOT NULL DEFAULT 0 CHECK(http01_self_check IN (0,1))
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// update user_version to 5
+	query = `
+		PRAGMA user_version = 5
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// no errors, commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	store.logger.Info("database user_version successfully upgraded from 4 to 5")
+	return nil
+}
@@ -0,0 +1,67 @@
+package sqlite
+
+import (
+	"context"
+)
+
+// CHANGES v5 to v6:
+// - order_jobs
+//     - New table, order jobs are persisted so queued and interrupted jobs are
+//       not lost when LeGo restarts
+
+// updates the storage db from user_version 5 to user_version 6, if it cannot
+// do so, an error is returned and modification is aborted
+func (store *Storage) migrateV5toV6() error {
+	store.logger.Info("updating database user_version from 5 to 6")
+
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// create sql transaction to roll back in the event an error occurs
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// add order_jobs
+	query := `CREATE TABLE IF NOT EXISTS order_jobs (
+		id integer PRIMARY KEY AUTOINCREMENT NOT NULL UNIQUE,
+		order_id integer NOT NULL UNIQUE,
+		priority integer NOT NULL DEFAULT 0,
+		state text NOT NULL,
+		attempts integer NOT NULL DEFAULT 0,
+		next_run_at integer NOT NULL,
+		last_error text,
+		created_at integer NOT NULL,
+		updated_at integer NOT NULL,
+		FOREIGN KEY (order_id)
+			REFERENCES acme_orders (id)
+				ON DELETE CASCADE
+				ON UPDATE NO ACTION
+	)`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// update user_version to 6
+	query = `
+		PRAGMA user_version = 6
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// no errors, commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	store.logger.Info("database user_version successfully upgraded from 5 to 6")
+	return nil
+}
@@ -0,0 +1,73 @@
+package sqlite
+
+import (
+	"context"
+)
+
+// CHANGES v6 to v7:
+// - acme_servers
+//     - Add max_concurrent_orders, requests_per_second, and request_burst fields
+//       to limit the load LeGo puts on each ACME server (0 is unlimited)
+
+// updates the storage db from user_version 6 to user_version 7, if it cannot
+// do so, an error is returned and modification is aborted
+func (store *Storage) migrateV6toV7() error {
+	store.logger.Info("updating database user_version from 6 to 7")
+
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// create sql transaction to roll back in the event an error occurs
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// add limits to acme_servers
+	query := `
+		ALTER TABLE acme_servers ADD COLUMN max_concurrent_orders integer NOT NULL DEFAULT 0
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	query = `
+		ALTER TABLE acme_servers ADD COLUMN requests_per_second real NOT NULL DEFAULT 0
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	query = `
+		ALTER TABLE acme_servers ADD COLUMN request_burst integer NOT NULL DEFAULT 0
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// update user_version to 7
+	query = `
+		PRAGMA user_version = 7
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// no errors, commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	store.logger.Info("database user_version successfully upgraded from 6 to 7")
+	return nil
+}
@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"context"
+)
+
+// CHANGES v7 to v8:
+// - certificates
+//     - Add renewal_days_remaining and renewal_lifetime_fraction fields to
+//       override when the certificate is automatically renewed (null uses the
+//       global threshold)
+
+// updates the storage db from user_version 7 to user_version 8, if it cannot
+// do so, an error is returned and modification is aborted
+func (store *Storage) migrateV7toV8() error {
+	store.logger.Info("updating database user_version from 7 to 8")
+
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// create sql transaction to roll back in the event an error occurs
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// add renewal policy to certificates
+	query := `
+		ALTER TABLE certificates ADD COLUMN renewal_days_remaining integer
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	query = `
+		ALTER TABLE certificates ADD COLUMN renewal_lifetime_fraction real
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// update user_version to 8
+	query = `
+		PRAGMA user_version = 8
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// no errors, commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	store.logger.Info("database user_version successfully upgraded from 7 to 8")
+	return nil
+}
@@ -0,0 +1,55 @@
+package sqlite
+
+import (
+	"context"
+)
+
+// CHANGES v8 to v9:
+// - certificates
+//     - Add notification_emails field for recipients of the certificate's
+//       notifications (in addition to the globally configured recipients)
+
+// updates the storage db from user_version 8 to user_version 9, if it cannot
+// do so, an error is returned and modification is aborted
+func (store *Storage) migrateV8toV9() error {
+	store.logger.Info("updating database user_version from 8 to 9")
+
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// create sql transaction to roll back in the event an error occurs
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// add notification recipients to certificates
+	query := `
+		ALTER TABLE certificates ADD COLUMN notification_emails text NOT NULL DEFAULT ''
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// update user_version to 9
+	query = `
+		PRAGMA user_version = 9
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	// no errors, commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	store.logger.Info("database user_version successfully upgraded from 8 to 9")
+	return nil
+}
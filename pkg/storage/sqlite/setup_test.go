@@ -0,0 +1,83 @@
+package sqlite
+
+import (
+	"fmt"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// testApp is the App used to open test storage
+type testApp struct{}
+
+func (testApp) GetLogger() *zap.SugaredLogger {
+	return zap.NewNop().Sugar()
+}
+
+// newTestStorage opens a new (fully migrated) database in a temp dir
+func newTestStorage(t *testing.T) *Storage {
+	store, err := OpenStorage(testApp{}, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	return store
+}
+
+// testExec runs query on the test storage and returns the last insert id
+func testExec(t *testing.T, store *Storage, query string, args ...interface{}) int {
+	result, err := store.db.Exec(query, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return int(id)
+}
+
+// testInsertKey inserts a private key and returns its id
+func testInsertKey(t *testing.T, store *Storage, name string) int {
+	return testExec(t, store, `
+		INSERT INTO private_keys (name, description, algorithm, pem, api_key, created_at, updated_at)
+		VALUES ($1, '', 'ecdsap256', $2, 'api-key', 0, 0)
+		`, name, "pem-"+name)
+}
+
+// testInsertCert inserts a certificate (along with its key, account, and the
+// account's key and acme server) and returns the cert's id
+func testInsertCert(t *testing.T, store *Storage, name string) int {
+	serverId := testExec(t, store, `
+		INSERT INTO acme_servers (name, description, directory_url, created_at, updated_at)
+		VALUES ($1, '', $2, 0, 0)
+		`, "server-"+name, "https://"+name+".example.com/directory")
+
+	accountId := testExec(t, store, `
+		INSERT INTO acme_accounts (name, private_key_id, description, email, created_at, updated_at, kid,
+			acme_server_id)
+		VALUES ($1, $2, '', '', 0, 0, '', $3)
+		`, "account-"+name, testInsertKey(t, store, "account-key-"+name), serverId)
+
+	return testExec(t, store, `
+		INSERT INTO certificates (private_key_id, acme_account_id, name, description, challenge_method,
+			subject, subject_alts, csr_org, csr_ou, csr_country, csr_state, csr_city, api_key, created_at,
+			updated_at)
+		VALUES ($1, $2, $3, '', 'http-01-internal', $4, '', '', '', '', '', '', 'api-key', 0, 0)
+		`, testInsertKey(t, store, "cert-key-"+name), accountId, name, name+".example.com")
+}
+
+// testInsertOrder inserts an order for the cert and returns its id
+func testInsertOrder(t *testing.T, store *Storage, certId int, status string, knownRevoked bool, validTo int, createdAt int) int {
+	return testExec(t, store, `
+		INSERT INTO acme_orders (acme_account_id, certificate_id, acme_location, status, known_revoked,
+			dns_identifiers, authorizations, finalize, valid_to, created_at, updated_at)
+		SELECT acme_account_id, id, $1, $2, $3, '', '', '', $4, $5, $5
+		FROM certificates
+		WHERE id = $6
+		`, fmt.Sprintf("https://acme.example.com/order/%d/%d", certId, createdAt), status, knownRevoked,
+		validTo, createdAt, certId)
+}
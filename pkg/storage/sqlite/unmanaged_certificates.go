@@ -0,0 +1,57 @@
+package sqlite
+
+import (
+	"legocerthub-backend/pkg/domain/private_keys"
+	"legocerthub-backend/pkg/domain/unmanaged_certificates"
+)
+
+// unmanagedCertificateDb is a single unmanaged certificate, as database table
+// fields corresponds to unmanaged_certificates.Certificate
+type unmanagedCertificateDb struct {
+	id                 int
+	name               string
+	description        string
+	privateKeyDb       keyDb
+	pem                string
+	subject            string
+	subjectAltNames    commaJoinedStrings
+	issuer             string
+	serial             string
+	validFrom          int
+	validTo            int
+	notificationEmails commaJoinedStrings
+	apiKey             string
+	apiKeyNew          string
+	apiKeyViaUrl       bool
+	createdAt          int
+	updatedAt          int
+}
+
+func (cert unmanagedCertificateDb) toUnmanagedCertificate() unmanaged_certificates.Certificate {
+	// key is optional (COALESCE id is -2)
+	var key *private_keys.Key
+	if cert.privateKeyDb.id >= 0 {
+		k := cert.privateKeyDb.toKey()
+		key = &k
+	}
+
+	return unmanaged_certificates.Certificate{
+		ID:                 cert.id,
+		Name:               cert.name,
+		Description:        cert.description,
+		PrivateKey:         key,
+		Pem:                cert.pem,
+		Subject:            cert.subject,
+		SubjectAltNames:    cert.subjectAltNames.toSlice(),
+		Issuer:             cert.issuer,
+		Serial:             cert.serial,
+		ValidFrom:          cert.validFrom,
+		ValidTo:            cert.validTo,
+		NotificationEmails: cert.notificationEmails.toSlice(),
+		ApiKey:             cert.apiKey,
+		ApiKeyNew:          cert.apiKeyNew,
+		ApiKeyViaUrl:       cert.apiKeyViaUrl,
+		CreatedAt:          cert.createdAt,
+		UpdatedAt:          cert.updatedAt,
+	}
+}
@@ -0,0 +1,35 @@
+package sqlite
+
+import (
+	"context"
+	"legocerthub-backend/pkg/storage"
+)
+
+// DeleteUnmanagedCert deletes an unmanaged cert from the database (its key, if
+// any, is not deleted)
+func (store *Storage) DeleteUnmanagedCert(id int) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	DELETE FROM
+		unmanaged_certificates
+	WHERE
+		id = $1
+	`
+
+	result, err := store.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return storage.ErrNoRecord
+	}
+
+	return nil
+}
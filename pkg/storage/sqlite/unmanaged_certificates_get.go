@@ -0,0 +1,238 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"legocerthub-backend/pkg/domain/unmanaged_certificates"
+	"legocerthub-backend/pkg/pagination_sort"
+	"legocerthub-backend/pkg/storage"
+)
+
+// GetAllUnmanagedCerts returns a page of unmanaged certs
+func (store *Storage) GetAllUnmanagedCerts(q pagination_sort.Query) (certs []unmanaged_certificates.Certificate, totalRowCount int, err error) {
+	// validate and set sort
+	sortField := q.SortField()
+
+	switch sortField {
+	// allow these
+	case "id":
+		sortField = "uc.id"
+	case "name":
+		sortField = "uc.name"
+	case "subject":
+		sortField = "uc.subject"
+	case "issuer":
+		sortField = "uc.issuer"
+	case "valid_to":
+		sortField = "uc.valid_to"
+	case "keyname":
+		sortField = "pk.name"
+	// default if not in allowed list
+	default:
+		sortField = "uc.name"
+	}
+
+	sort := sortField + " " + q.SortDirection()
+
+	// do query
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// WARNING: SQL Injection is possible if the variables are not properly
+	// validated prior to this query being assembled!
+	query := fmt.Sprintf(`
+	SELECT
+		uc.id, uc.name, uc.description, uc.pem, uc.subject, uc.subject_alts, uc.issuer, uc.serial,
+		uc.valid_from, uc.valid_to, uc.notification_emails, uc.api_key, uc.api_key_new,
+		uc.api_key_via_url, uc.created_at, uc.updated_at,
+
+		COALESCE(pk.id, -2), COALESCE(pk.name, 'null'), COALESCE(pk.description, 'null'),
+		COALESCE(pk.algorithm, 'null'), COALESCE(pk.pem, 'null'), COALESCE(pk.api_key, 'null'),
+		COALESCE(pk.api_key_new, 'null'), COALESCE(pk.api_key_disabled, false),
+		COALESCE(pk.api_key_via_url, false), COALESCE(pk.created_at, -2), COALESCE(pk.updated_at, -2),
+
+		count(*) OVER() AS full_count
+	FROM
+		unmanaged_certificates uc
+		LEFT JOIN private_keys pk on (uc.private_key_id = pk.id)
+	ORDER BY
+		%s
+	LIMIT
+		$1
+	OFFSET
+		$2
+	`, sort)
+
+	rows, err := store.db.QueryContext(ctx, query,
+		q.Limit(),
+		q.Offset(),
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	// for total row count
+	var totalRows int
+
+	for rows.Next() {
+		var oneCert unmanagedCertificateDb
+
+		err = rows.Scan(
+			&oneCert.id,
+			&oneCert.name,
+			&oneCert.description,
+			&oneCert.pem,
+			&oneCert.subject,
+			&oneCert.subjectAltNames,
+			&oneCert.issuer,
+			&oneCert.serial,
+			&oneCert.validFrom,
+			&oneCert.validTo,
+			&oneCert.notificationEmails,
+			&oneCert.apiKey,
+			&oneCert.apiKeyNew,
+			&oneCert.apiKeyViaUrl,
+			&oneCert.createdAt,
+			&oneCert.updatedAt,
+
+			&oneCert.privateKeyDb.id,
+			&oneCert.privateKeyDb.name,
+			&oneCert.privateKeyDb.description,
+			&oneCert.privateKeyDb.algorithmValue,
+			&oneCert.privateKeyDb.pem,
+			&oneCert.privateKeyDb.apiKey,
+			&oneCert.privateKeyDb.apiKeyNew,
+			&oneCert.privateKeyDb.apiKeyDisabled,
+			&oneCert.privateKeyDb.apiKeyViaUrl,
+			&oneCert.privateKeyDb.createdAt,
+			&oneCert.privateKeyDb.updatedAt,
+
+			&totalRows,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		// convert and append
+		certs = append(certs, oneCert.toUnmanagedCertificate())
+	}
+
+	return certs, totalRows, nil
+}
+
+// GetOneUnmanagedCertById returns an unmanaged cert based on its unique id
+func (store *Storage) GetOneUnmanagedCertById(id int) (cert unmanaged_certificates.Certificate, err error) {
+	return store.getOneUnmanagedCert(id, "")
+}
+
+// GetOneUnmanagedCertByName returns an unmanaged cert based on its unique name
+func (store *Storage) GetOneUnmanagedCertByName(name string) (cert unmanaged_certificates.Certificate, err error) {
+	return store.getOneUnmanagedCert(-1, name)
+}
+
+// getOneUnmanagedCert returns an unmanaged cert based on either its unique id or
+// its unique name
+func (store *Storage) getOneUnmanagedCert(id int, name string) (cert unmanaged_certificates.Certificate, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	SELECT
+		uc.id, uc.name, uc.description, uc.pem, uc.subject, uc.subject_alts, uc.issuer, uc.serial,
+		uc.valid_from, uc.valid_to, uc.notification_emails, uc.api_key, uc.api_key_new,
+		uc.api_key_via_url, uc.created_at, uc.updated_at,
+
+		COALESCE(pk.id, -2), COALESCE(pk.name, 'null'), COALESCE(pk.description, 'null'),
+		COALESCE(pk.algorithm, 'null'), COALESCE(pk.pem, 'null'), COALESCE(pk.api_key, 'null'),
+		COALESCE(pk.api_key_new, 'null'), COALESCE(pk.api_key_disabled, false),
+		COALESCE(pk.api_key_via_url, false), COALESCE(pk.created_at, -2), COALESCE(pk.updated_at, -2)
+	FROM
+		unmanaged_certificates uc
+		LEFT JOIN private_keys pk on (uc.private_key_id = pk.id)
+	WHERE
+		uc.id = $1 OR uc.name = $2
+	ORDER BY uc.name
+	`
+
+	row := store.db.QueryRowContext(ctx, query, id, name)
+
+	var oneCert unmanagedCertificateDb
+
+	err = row.Scan(
+		&oneCert.id,
+		&oneCert.name,
+		&oneCert.description,
+		&oneCert.pem,
+		&oneCert.subject,
+		&oneCert.subjectAltNames,
+		&oneCert.issuer,
+		&oneCert.serial,
+		&oneCert.validFrom,
+		&oneCert.validTo,
+		&oneCert.notificationEmails,
+		&oneCert.apiKey,
+		&oneCert.apiKeyNew,
+		&oneCert.apiKeyViaUrl,
+		&oneCert.createdAt,
+		&oneCert.updatedAt,
+
+		&oneCert.privateKeyDb.id,
+		&oneCert.privateKeyDb.name,
+		&oneCert.privateKeyDb.description,
+		&oneCert.privateKeyDb.algorithmValue,
+		&oneCert.privateKeyDb.pem,
+		&oneCert.privateKeyDb.apiKey,
+		&oneCert.privateKeyDb.apiKeyNew,
+		&oneCert.privateKeyDb.apiKeyDisabled,
+		&oneCert.privateKeyDb.apiKeyViaUrl,
+		&oneCert.privateKeyDb.createdAt,
+		&oneCert.privateKeyDb.updatedAt,
+	)
+
+	if err != nil {
+		// if no record exists
+		if err == sql.ErrNoRows {
+			err = storage.ErrNoRecord
+		}
+		return unmanaged_certificates.Certificate{}, err
+	}
+
+	// convert and return
+	return oneCert.toUnmanagedCertificate(), nil
+}
+
+// ManagedCertNameInUse returns true if a (managed) certificate already uses the
+// name
+func (store *Storage) ManagedCertNameInUse(name string) (inUse bool, err error) {
+	return store.nameInUse("certificates", name)
+}
+
+// UnmanagedCertNameInUse returns true if an unmanaged certificate already uses
+// the name
+func (store *Storage) UnmanagedCertNameInUse(name string) (inUse bool, err error) {
+	return store.nameInUse("unmanaged_certificates", name)
+}
+
+// nameInUse returns true if the name exists in the table's name column
+// (table must be a constant, it is not escaped)
+func (store *Storage) nameInUse(table string, name string) (inUse bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := fmt.Sprintf(`
+	SELECT EXISTS(
+		SELECT 1
+		FROM %s
+		WHERE name = $1
+	)
+	`, table)
+
+	err = store.db.QueryRowContext(ctx, query, name).Scan(&inUse)
+	if err != nil {
+		return false, err
+	}
+
+	return inUse, nil
+}
@@ -0,0 +1,72 @@
+package sqlite
+
+import (
+	"context"
+	"legocerthub-backend/pkg/domain/private_keys"
+	"legocerthub-backend/pkg/domain/unmanaged_certificates"
+)
+
+// PostNewUnmanagedCert inserts a new unmanaged cert into the db. If newKey is not
+// nil, it is saved as a new key (in the same transaction) and used as the cert's
+// key.
+func (store *Storage) PostNewUnmanagedCert(payload unmanaged_certificates.NewPayload, newKey *private_keys.NewPayload) (id int, err error) {
+	// database update
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// don't check for in use in storage. main app business logic should
+	// take care of it
+
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return -2, err
+	}
+	defer tx.Rollback()
+
+	// insert the new key (if any)
+	if newKey != nil {
+		keyId, err := insertKey(ctx, tx, *newKey)
+		if err != nil {
+			return -2, err
+		}
+		payload.PrivateKeyID = &keyId
+	}
+
+	// insert the new cert
+	query := `
+	INSERT INTO unmanaged_certificates (name, description, private_key_id, pem, subject, subject_alts,
+		issuer, serial, valid_from, valid_to, notification_emails, api_key, api_key_via_url, created_at,
+		updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	RETURNING id
+	`
+
+	err = tx.QueryRowContext(ctx, query,
+		payload.Name,
+		payload.Description,
+		payload.PrivateKeyID,
+		payload.Pem,
+		payload.Subject,
+		makeCommaJoinedString(payload.SubjectAltNames),
+		payload.Issuer,
+		payload.Serial,
+		payload.ValidFrom,
+		payload.ValidTo,
+		makeCommaJoinedString(payload.NotificationEmails),
+		payload.ApiKey,
+		payload.ApiKeyViaUrl,
+		payload.CreatedAt,
+		payload.UpdatedAt,
+	).Scan(&id)
+
+	if err != nil {
+		return -2, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return -2, err
+	}
+
+	return id, nil
+}
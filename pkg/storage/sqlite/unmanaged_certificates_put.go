@@ -0,0 +1,84 @@
+package sqlite
+
+import (
+	"context"
+	"legocerthub-backend/pkg/domain/unmanaged_certificates"
+	"legocerthub-backend/pkg/storage"
+)
+
+// PutUnmanagedCertUpdate updates an existing unmanaged cert. It only updates the
+// details which are provided
+func (store *Storage) PutUnmanagedCertUpdate(payload unmanaged_certificates.UpdatePayload) (err error) {
+	// database update
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+		UPDATE
+			unmanaged_certificates
+		SET
+			name = case when $1 is null then name else $1 end,
+			description = case when $2 is null then description else $2 end,
+			private_key_id = case when $3 is null then private_key_id else $3 end,
+			pem = case when $4 is null then pem else $4 end,
+			subject = case when $5 is null then subject else $5 end,
+			subject_alts = case when $6 is null then subject_alts else $6 end,
+			issuer = case when $7 is null then issuer else $7 end,
+			serial = case when $8 is null then serial else $8 end,
+			valid_from = case when $9 is null then valid_from else $9 end,
+			valid_to = case when $10 is null then valid_to else $10 end,
+			notification_emails = case when $11 is null then notification_emails else $11 end,
+			api_key = case when $12 is null then api_key else $12 end,
+			api_key_new = case when $13 is null then api_key_new else $13 end,
+			api_key_via_url = case when $14 is null then api_key_via_url else $14 end,
+			updated_at = $15
+		WHERE
+			id = $16
+		`
+
+	// subject alts are replaced only if a new pem was parsed
+	var subjectAlts *commaJoinedStrings
+	if payload.SubjectAltNames != nil {
+		cjs := makeCommaJoinedString(payload.SubjectAltNames)
+		subjectAlts = &cjs
+	}
+
+	// notification emails are replaced only if specified (empty clears them)
+	var notificationEmails *commaJoinedStrings
+	if payload.NotificationEmails != nil {
+		cjs := makeCommaJoinedString(payload.NotificationEmails)
+		notificationEmails = &cjs
+	}
+
+	result, err := store.db.ExecContext(ctx, query,
+		payload.Name,
+		payload.Description,
+		payload.PrivateKeyID,
+		payload.Pem,
+		payload.Subject,
+		subjectAlts,
+		payload.Issuer,
+		payload.Serial,
+		payload.ValidFrom,
+		payload.ValidTo,
+		notificationEmails,
+		payload.ApiKey,
+		payload.ApiKeyNew,
+		payload.ApiKeyViaUrl,
+		payload.UpdatedAt,
+		payload.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return storage.ErrNoRecord
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+)
+
+// vacuumTimeout is longer than the normal timeout since VACUUM rebuilds the
+// entire database file
+const vacuumTimeout = 5 * time.Minute
+
+// Vacuum rebuilds the database file, returning the space freed by deleted
+// records to the filesystem
+func (store *Storage) Vacuum() (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), vacuumTimeout)
+	defer cancel()
+
+	_, err = store.db.ExecContext(ctx, `VACUUM`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
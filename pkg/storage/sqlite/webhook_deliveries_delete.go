@@ -0,0 +1,28 @@
+package sqlite
+
+import (
+	"context"
+)
+
+// DeleteOldWebhookDeliveries deletes finished (succeeded or failed) deliveries
+// that were last updated before the specified time
+func (store *Storage) DeleteOldWebhookDeliveries(before int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	DELETE FROM
+		webhook_deliveries
+	WHERE
+		state != "pending"
+		AND
+		updated_at < $1
+	`
+
+	_, err := store.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"legocerthub-backend/pkg/domain/webhooks"
+	"legocerthub-backend/pkg/pagination_sort"
+)
+
+// deliveryFields are the webhook delivery columns, in the order scanned by
+// scanDelivery (webhook columns follow)
+const deliveryFields = `wd.id, wd.event_type, wd.payload, wd.state, wd.attempts, wd.next_attempt_at,
+		wd.response_status, wd.last_error, wd.created_at, wd.updated_at`
+
+// scanDelivery scans the deliveryFields, webhookFields (and any extra dest) from
+// a row
+func scanDelivery(scanner interface{ Scan(...interface{}) error }, extra ...interface{}) (webhookDeliveryDb, error) {
+	var oneDelivery webhookDeliveryDb
+	dest := []interface{}{
+		&oneDelivery.id,
+		&oneDelivery.eventType,
+		&oneDelivery.payload,
+		&oneDelivery.state,
+		&oneDelivery.attempts,
+		&oneDelivery.nextAttemptAt,
+		&oneDelivery.responseStatus,
+		&oneDelivery.lastError,
+		&oneDelivery.createdAt,
+		&oneDelivery.updatedAt,
+
+		&oneDelivery.webhook.id,
+		&oneDelivery.webhook.name,
+		&oneDelivery.webhook.description,
+		&oneDelivery.webhook.url,
+		&oneDelivery.webhook.secret,
+		&oneDelivery.webhook.eventTypes,
+		&oneDelivery.webhook.enabled,
+		&oneDelivery.webhook.createdAt,
+		&oneDelivery.webhook.updatedAt,
+	}
+
+	err := scanner.Scan(append(dest, extra...)...)
+	return oneDelivery, err
+}
+
+// GetWebhookDeliveries returns a slice of a webhook's deliveries from the db
+func (store *Storage) GetWebhookDeliveries(webhookId int, q pagination_sort.Query) (deliveries []webhooks.Delivery, totalRowCount int, err error) {
+	// validate and set sort
+	sortField := q.SortField()
+	switch sortField {
+	// allow these as-is
+	case "id":
+	case "event_type":
+	case "state":
+	case "attempts":
+	case "created_at":
+	case "updated_at":
+	// default if not in allowed list
+	default:
+		sortField = "id"
+	}
+
+	sort := "wd." + sortField + " " + q.SortDirection()
+
+	// do query
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// WARNING: SQL Injection is possible if the variables are not properly
+	// validated prior to this query being assembled!
+	query := fmt.Sprintf(`
+	SELECT
+		%s,
+		%s,
+
+		count(*) OVER() AS full_count
+	FROM
+		webhook_deliveries wd
+		JOIN webhooks w on (wd.webhook_id = w.id)
+	WHERE
+		wd.webhook_id = $1
+	ORDER BY
+		%s
+	LIMIT
+		$2
+	OFFSET
+		$3
+	`, deliveryFields, webhookFields, sort)
+
+	rows, err := store.db.QueryContext(ctx, query,
+		webhookId,
+		q.Limit(),
+		q.Offset(),
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		oneDelivery, err := scanDelivery(rows, &totalRowCount)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		deliveries = append(deliveries, oneDelivery.toDelivery())
+	}
+
+	return deliveries, totalRowCount, nil
+}
+
+// GetDueWebhookDeliveries returns up to limit pending deliveries whose next
+// attempt is due (oldest first). Deliveries of disabled webhooks are not returned.
+func (store *Storage) GetDueWebhookDeliveries(now int, limit int) (deliveries []webhooks.Delivery, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := fmt.Sprintf(`
+	SELECT
+		%s,
+		%s
+	FROM
+		webhook_deliveries wd
+		JOIN webhooks w on (wd.webhook_id = w.id)
+	WHERE
+		wd.state = "pending"
+		AND
+		wd.next_attempt_at <= $1
+		AND
+		w.enabled = 1
+	ORDER BY
+		wd.next_attempt_at, wd.id
+	LIMIT
+		$2
+	`, deliveryFields, webhookFields)
+
+	rows, err := store.db.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		oneDelivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		deliveries = append(deliveries, oneDelivery.toDelivery())
+	}
+
+	return deliveries, nil
+}
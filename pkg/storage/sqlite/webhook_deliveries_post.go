@@ -0,0 +1,36 @@
+package sqlite
+
+import (
+	"context"
+	"legocerthub-backend/pkg/domain/webhooks"
+)
+
+// PostWebhookDelivery saves a new pending delivery to the db
+func (store *Storage) PostWebhookDelivery(payload webhooks.NewDeliveryPayload) (id int, err error) {
+	// database action
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	INSERT INTO webhook_deliveries (webhook_id, event_type, payload, state, attempts, next_attempt_at,
+		created_at, updated_at)
+	VALUES ($1, $2, $3, "pending", 0, $4, $5, $6)
+	RETURNING id
+	`
+
+	// insert and scan the new id
+	err = store.db.QueryRowContext(ctx, query,
+		payload.WebhookID,
+		payload.EventType,
+		payload.Payload,
+		payload.NextAttemptAt,
+		payload.CreatedAt,
+		payload.UpdatedAt,
+	).Scan(&id)
+
+	if err != nil {
+		return -2, err
+	}
+
+	return id, nil
+}
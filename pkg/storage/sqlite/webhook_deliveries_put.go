@@ -0,0 +1,43 @@
+package sqlite
+
+import (
+	"context"
+	"legocerthub-backend/pkg/domain/webhooks"
+)
+
+// PutWebhookDeliveryResult saves the result of a delivery attempt
+func (store *Storage) PutWebhookDeliveryResult(payload webhooks.DeliveryResultPayload) (err error) {
+	// database update
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	UPDATE
+		webhook_deliveries
+	SET
+		state = $1,
+		attempts = $2,
+		next_attempt_at = $3,
+		response_status = $4,
+		last_error = $5,
+		updated_at = $6
+	WHERE
+		id = $7
+	`
+
+	_, err = store.db.ExecContext(ctx, query,
+		payload.State,
+		payload.Attempts,
+		payload.NextAttemptAt,
+		payload.ResponseStatus,
+		payload.LastError,
+		payload.UpdatedAt,
+		payload.ID,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
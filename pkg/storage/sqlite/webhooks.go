@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"database/sql"
+	"legocerthub-backend/pkg/domain/webhooks"
+)
+
+// webhookDb is a single webhook, as database table fields
+// corresponds to webhooks.Webhook
+type webhookDb struct {
+	id          int
+	name        string
+	description string
+	url         string
+	secret      string
+	eventTypes  commaJoinedStrings
+	enabled     bool
+	createdAt   int
+	updatedAt   int
+}
+
+// toWebhook maps the database webhook info to the webhooks Webhook object
+func (hook webhookDb) toWebhook() webhooks.Webhook {
+	return webhooks.Webhook{
+		ID:              hook.id,
+		Name:            hook.name,
+		Description:     hook.description,
+		URL:             hook.url,
+		EncryptedSecret: hook.secret,
+		EventTypes:      hook.eventTypes.toSlice(),
+		Enabled:         hook.enabled,
+		CreatedAt:       hook.createdAt,
+		UpdatedAt:       hook.updatedAt,
+	}
+}
+
+// webhookDeliveryDb is a single webhook delivery, as database table fields
+// corresponds to webhooks.Delivery
+type webhookDeliveryDb struct {
+	id             int
+	webhook        webhookDb
+	eventType      string
+	payload        string
+	state          string
+	attempts       int
+	nextAttemptAt  int
+	responseStatus sql.NullInt32
+	lastError      string
+	createdAt      int
+	updatedAt      int
+}
+
+// toDelivery maps the database delivery info to the webhooks Delivery object
+func (delivery webhookDeliveryDb) toDelivery() webhooks.Delivery {
+	return webhooks.Delivery{
+		ID:             delivery.id,
+		Webhook:        delivery.webhook.toWebhook(),
+		EventType:      delivery.eventType,
+		Payload:        delivery.payload,
+		State:          delivery.state,
+		Attempts:       delivery.attempts,
+		NextAttemptAt:  delivery.nextAttemptAt,
+		ResponseStatus: nullInt32ToInt(delivery.responseStatus),
+		LastError:      delivery.lastError,
+		CreatedAt:      delivery.createdAt,
+		UpdatedAt:      delivery.updatedAt,
+	}
+}
@@ -0,0 +1,249 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"legocerthub-backend/pkg/domain/webhooks"
+	"legocerthub-backend/pkg/pagination_sort"
+	"legocerthub-backend/pkg/storage"
+)
+
+// webhookFields are the webhook columns, in the order scanned by scanWebhook
+const webhookFields = `w.id, w.name, w.description, w.url, w.secret, w.event_types, w.enabled,
+		w.created_at, w.updated_at`
+
+// scanWebhook scans the webhookFields (and any extra dest) from a row
+func scanWebhook(scanner interface{ Scan(...interface{}) error }, extra ...interface{}) (webhookDb, error) {
+	var oneHook webhookDb
+	dest := []interface{}{
+		&oneHook.id,
+		&oneHook.name,
+		&oneHook.description,
+		&oneHook.url,
+		&oneHook.secret,
+		&oneHook.eventTypes,
+		&oneHook.enabled,
+		&oneHook.createdAt,
+		&oneHook.updatedAt,
+	}
+
+	err := scanner.Scan(append(dest, extra...)...)
+	return oneHook, err
+}
+
+// GetAllWebhooks returns a slice of webhooks from the db
+func (store *Storage) GetAllWebhooks(q pagination_sort.Query) (hooks []webhooks.Webhook, totalRowCount int, err error) {
+	// validate and set sort
+	sortField := q.SortField()
+	switch sortField {
+	// allow these as-is
+	case "id":
+	case "name":
+	case "description":
+	case "url":
+	case "enabled":
+	// default if not in allowed list
+	default:
+		sortField = "name"
+	}
+
+	sort := "w." + sortField + " " + q.SortDirection()
+
+	// do query
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// WARNING: SQL Injection is possible if the variables are not properly
+	// validated prior to this query being assembled!
+	query := fmt.Sprintf(`
+	SELECT
+		%s,
+
+		count(*) OVER() AS full_count
+	FROM
+		webhooks w
+	ORDER BY
+		%s
+	LIMIT
+		$1
+	OFFSET
+		$2
+	`, webhookFields, sort)
+
+	rows, err := store.db.QueryContext(ctx, query,
+		q.Limit(),
+		q.Offset(),
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		oneHook, err := scanWebhook(rows, &totalRowCount)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		hooks = append(hooks, oneHook.toWebhook())
+	}
+
+	return hooks, totalRowCount, nil
+}
+
+// getOneWebhook returns a webhook from the db based on the where clause and arg
+func (store *Storage) getOneWebhook(where string, arg interface{}) (webhooks.Webhook, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := fmt.Sprintf(`
+	SELECT
+		%s
+	FROM
+		webhooks w
+	WHERE
+		%s = $1
+	`, webhookFields, where)
+
+	oneHook, err := scanWebhook(store.db.QueryRowContext(ctx, query, arg))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = storage.ErrNoRecord
+		}
+		return webhooks.Webhook{}, err
+	}
+
+	return oneHook.toWebhook(), nil
+}
+
+// GetOneWebhookById returns a webhook from the db based on its id
+func (store *Storage) GetOneWebhookById(id int) (webhooks.Webhook, error) {
+	return store.getOneWebhook("w.id", id)
+}
+
+// GetOneWebhookByName returns a webhook from the db based on its name
+func (store *Storage) GetOneWebhookByName(name string) (webhooks.Webhook, error) {
+	return store.getOneWebhook("w.name", name)
+}
+
+// GetEnabledWebhooks returns all of the enabled webhooks
+func (store *Storage) GetEnabledWebhooks() (hooks []webhooks.Webhook, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := fmt.Sprintf(`
+	SELECT
+		%s
+	FROM
+		webhooks w
+	WHERE
+		w.enabled = 1
+	ORDER BY
+		w.id
+	`, webhookFields)
+
+	rows, err := store.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		oneHook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		hooks = append(hooks, oneHook.toWebhook())
+	}
+
+	return hooks, nil
+}
+
+// GetOrderCertificate returns the certificate of the specified order and the
+// order's pem (if it has one)
+func (store *Storage) GetOrderCertificate(orderId int) (orderCert webhooks.OrderCertificate, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	SELECT
+		c.id, c.name, ao.pem
+	FROM
+		acme_orders ao
+		JOIN certificates c on (ao.certificate_id = c.id)
+	WHERE
+		ao.id = $1
+	`
+
+	var pem sql.NullString
+	err = store.db.QueryRowContext(ctx, query, orderId).Scan(
+		&orderCert.CertificateID,
+		&orderCert.CertificateName,
+		&pem,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = storage.ErrNoRecord
+		}
+		return webhooks.OrderCertificate{}, err
+	}
+
+	if pem.Valid {
+		orderCert.Pem = &pem.String
+	}
+
+	return orderCert, nil
+}
+
+// GetExpiringCerts returns each certificate whose newest valid (and not revoked)
+// order expires before validToBefore
+func (store *Storage) GetExpiringCerts(validToBefore int) (expiring []webhooks.ExpiringCert, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	SELECT
+		c.id, c.name, MAX(ao.valid_to) AS newest_valid_to
+	FROM
+		certificates c
+		JOIN acme_orders ao on (
+			ao.certificate_id = c.id
+			AND
+			ao.status = "valid"
+			AND
+			ao.known_revoked = 0
+			AND
+			ao.pem NOT NULL
+		)
+	GROUP BY
+		c.id
+	HAVING
+		newest_valid_to < $1
+	ORDER BY
+		c.name
+	`
+
+	rows, err := store.db.QueryContext(ctx, query, validToBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cert webhooks.ExpiringCert
+		err = rows.Scan(
+			&cert.CertificateID,
+			&cert.CertificateName,
+			&cert.ValidTo,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		expiring = append(expiring, cert)
+	}
+
+	return expiring, nil
+}
@@ -0,0 +1,37 @@
+package sqlite
+
+import (
+	"context"
+	"legocerthub-backend/pkg/domain/webhooks"
+)
+
+// PostNewWebhook saves a new webhook to the db
+func (store *Storage) PostNewWebhook(payload webhooks.NewPayload) (id int, err error) {
+	// database action
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	INSERT INTO webhooks (name, description, url, secret, event_types, enabled, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	RETURNING id
+	`
+
+	// insert and scan the new id
+	err = store.db.QueryRowContext(ctx, query,
+		payload.Name,
+		payload.Description,
+		payload.URL,
+		payload.EncryptedSecret,
+		makeCommaJoinedString(payload.EventTypes),
+		payload.Enabled,
+		payload.CreatedAt,
+		payload.UpdatedAt,
+	).Scan(&id)
+
+	if err != nil {
+		return -2, err
+	}
+
+	return id, nil
+}
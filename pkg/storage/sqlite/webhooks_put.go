@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"context"
+	"legocerthub-backend/pkg/domain/webhooks"
+)
+
+// PutWebhookUpdate updates details about a webhook
+func (store *Storage) PutWebhookUpdate(payload webhooks.UpdatePayload) (err error) {
+	// database update
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	UPDATE
+		webhooks
+	SET
+		name = case when $1 is null then name else $1 end,
+		description = case when $2 is null then description else $2 end,
+		url = case when $3 is null then url else $3 end,
+		secret = case when $4 is null then secret else $4 end,
+		event_types = case when $5 is null then event_types else $5 end,
+		enabled = case when $6 is null then enabled else $6 end,
+		updated_at = $7
+	WHERE
+		id = $8
+	`
+
+	// nil event types leaves the existing value
+	var eventTypes *commaJoinedStrings
+	if payload.EventTypes != nil {
+		eventTypes = new(commaJoinedStrings)
+		*eventTypes = makeCommaJoinedString(*payload.EventTypes)
+	}
+
+	_, err = store.db.ExecContext(ctx, query,
+		payload.Name,
+		payload.Description,
+		payload.URL,
+		payload.EncryptedSecret,
+		eventTypes,
+		payload.Enabled,
+		payload.UpdatedAt,
+		payload.ID,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -4,6 +4,14 @@
 
 echo "Some script that deletes dns records"
 echo "Available Params:"
-echo "Domain (1): " "$1"
+echo "Zone (1): " "$1"
 echo "Record (2): " "$2"
 echo "Value (3): " "$3"
+
+# If protocol_version is 2, there are no params. Instead, a json document
+# is sent on stdin, e.g.:
+# {"version":2,"action":"create","zone":"example.com",
+#  "record_name":"_acme-challenge.www.example.com","record_type":"TXT",
+#  "value":"XKrxpRBosdIKFzxW_CT3KLZNf6q0HG9i01zxXp5CPBs","ttl":60}
+# The create script may print {"record_id":"..."} to stdout and the same
+# record_id will be included in the delete script's document.
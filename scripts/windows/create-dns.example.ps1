@@ -2,6 +2,6 @@
 
 Write-Host "Some script that creates dns records"
 Write-Host "Available Params:"
-Write-Host "Domain (args[0]): " $args[0] 
+Write-Host "Zone (args[0]): " $args[0] 
 Write-Host "Record (args[1]): " $args[1] 
 Write-Host "Value (args[2]): " $args[2]